@@ -47,6 +47,8 @@ type DockerCli struct {
 	isTerminalOut bool
 	// client is the http client that performs all API operations
 	client client.APIClient
+	// context is the name of the context the client connects to
+	context string
 	// state holds the terminal state
 	state *term.State
 }
@@ -65,6 +67,11 @@ func (cli *DockerCli) Client() client.APIClient {
 	return cli.client
 }
 
+// CurrentContext returns the name of the context the client connects to
+func (cli *DockerCli) CurrentContext() string {
+	return cli.context
+}
+
 // Out returns the writer used for stdout
 func (cli *DockerCli) Out() io.Writer {
 	return cli.out
@@ -164,6 +171,12 @@ func NewDockerCli(in io.ReadCloser, out, err io.Writer, clientFlags *cliflags.Cl
 		clientFlags.PostParse()
 		cli.configFile = LoadDefaultConfigFile(err)
 
+		context, err := contextName(clientFlags, cli.configFile)
+		if err != nil {
+			return err
+		}
+		cli.context = context
+
 		client, err := NewAPIClientFromFlags(clientFlags, cli.configFile)
 		if err != nil {
 			return err
@@ -199,7 +212,24 @@ func LoadDefaultConfigFile(err io.Writer) *configfile.ConfigFile {
 
 // NewAPIClientFromFlags creates a new APIClient from command line flags
 func NewAPIClientFromFlags(clientFlags *cliflags.ClientFlags, configFile *configfile.ConfigFile) (client.APIClient, error) {
-	host, err := getServerHost(clientFlags.Common.Hosts, clientFlags.Common.TLSOptions)
+	hosts, tlsOptions := clientFlags.Common.Hosts, clientFlags.Common.TLSOptions
+
+	name, err := contextName(clientFlags, configFile)
+	if err != nil {
+		return &client.Client{}, err
+	}
+	context, err := lookupContext(name, configFile)
+	if err != nil {
+		return &client.Client{}, err
+	}
+	if context != nil {
+		hosts = []string{context.Host}
+		if context.TLS || context.TLSVerify {
+			tlsOptions = contextTLSOptions(context)
+		}
+	}
+
+	host, err := getServerHost(hosts, tlsOptions)
 	if err != nil {
 		return &client.Client{}, err
 	}
@@ -213,9 +243,11 @@ func NewAPIClientFromFlags(clientFlags *cliflags.ClientFlags, configFile *config
 	verStr := api.DefaultVersion
 	if tmpStr := os.Getenv("DOCKER_API_VERSION"); tmpStr != "" {
 		verStr = tmpStr
+	} else if context != nil && context.APIVersion != "" {
+		verStr = context.APIVersion
 	}
 
-	httpClient, err := newHTTPClient(host, clientFlags.Common.TLSOptions)
+	httpClient, err := newHTTPClient(host, tlsOptions)
 	if err != nil {
 		return &client.Client{}, err
 	}
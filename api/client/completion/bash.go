@@ -0,0 +1,128 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// bashNouns completes the objects of the daemon. It is called with the global
+// flags of the command line, so that the objects are listed from the same
+// daemon.
+const bashNouns = `__docker_complete_noun() {
+	local words
+	case "$1" in
+		container)
+			words=$(docker "${global[@]}" ps -a --format '{{.Names}}' 2>/dev/null)
+			;;
+		context)
+			words=$(docker "${global[@]}" context ls -q 2>/dev/null)
+			;;
+		image)
+			words=$(docker "${global[@]}" images --format '{{.Repository}}:{{.Tag}}' 2>/dev/null | grep -v '<none>')
+			;;
+		network)
+			words=$(docker "${global[@]}" network ls 2>/dev/null | awk 'NR > 1 {print $2}')
+			;;
+		volume)
+			words=$(docker "${global[@]}" volume ls -q 2>/dev/null)
+			;;
+		*)
+			return
+			;;
+	esac
+	COMPREPLY=( $(compgen -W "$words" -- "$cur") )
+	declare -F __ltrim_colon_completions >/dev/null && __ltrim_colon_completions "$cur"
+}
+
+__docker_contains() {
+	local word=$1 w
+	shift
+	for w in "$@"; do
+		[ "$w" = "$word" ] && return 0
+	done
+	return 1
+}
+
+_docker() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+	local path="" global=() i word
+
+	for (( i = 1; i < COMP_CWORD; i++ )); do
+		word="${COMP_WORDS[i]}"
+		case "$word" in
+			-*)
+				[ -z "$path" ] && global+=( "$word" )
+				if __docker_contains "$word" $(__docker_flags_with_value "$path") && (( i + 1 < COMP_CWORD )); then
+					(( i++ ))
+					[ -z "$path" ] && global+=( "${COMP_WORDS[i]}" )
+				fi
+				;;
+			*)
+				if __docker_contains "$word" $(__docker_subcommands "$path"); then
+					path="${path:+${path}_}$word"
+				fi
+				;;
+		esac
+	done
+
+	if __docker_contains "$prev" $(__docker_flags_with_value "$path"); then
+		return
+	fi
+
+	case "$cur" in
+		-*)
+			COMPREPLY=( $(compgen -W "$(__docker_flags "$path")" -- "$cur") )
+			return
+			;;
+	esac
+
+	local subcommands=$(__docker_subcommands "$path")
+	if [ -n "$subcommands" ]; then
+		COMPREPLY=( $(compgen -W "$subcommands" -- "$cur") )
+		return
+	fi
+
+	__docker_complete_noun "$(__docker_noun "$path")"
+}
+
+complete -o default -F _docker docker
+`
+
+func writeBash(w io.Writer, specs []*spec) error {
+	var b bytes.Buffer
+	b.WriteString("# bash completion for docker, generated by `docker completion bash`\n\n")
+	writeBashCase(&b, "__docker_subcommands", specs, func(s *spec) []string { return s.subcommandNames() })
+	writeBashCase(&b, "__docker_flags", specs, func(s *spec) []string { return s.flagNames(false) })
+	writeBashCase(&b, "__docker_flags_with_value", specs, func(s *spec) []string { return s.flagNames(true) })
+	writeBashCase(&b, "__docker_noun", specs, func(s *spec) []string {
+		if s.noun == "" {
+			return nil
+		}
+		return []string{s.noun}
+	})
+	b.WriteString(bashNouns)
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// writeZsh writes the bash script, run with the bash completion of zsh.
+func writeZsh(w io.Writer, specs []*spec) error {
+	if _, err := fmt.Fprint(w, "#compdef docker\n\n# zsh completion for docker, generated by `docker completion zsh`\n\nautoload -U +X bashcompinit && bashcompinit\n\n"); err != nil {
+		return err
+	}
+	return writeBash(w, specs)
+}
+
+// writeBashCase writes a function echoing the words of the spec of the path it
+// is called with.
+func writeBashCase(b *bytes.Buffer, name string, specs []*spec, words func(*spec) []string) {
+	fmt.Fprintf(b, "%s() {\n\tcase \"$1\" in\n", name)
+	for _, s := range specs {
+		if w := words(s); len(w) > 0 {
+			fmt.Fprintf(b, "\t\t'%s')\n\t\t\techo \"%s\"\n\t\t\t;;\n", s.path, strings.Join(w, " "))
+		}
+	}
+	b.WriteString("\tesac\n}\n\n")
+}
@@ -0,0 +1,38 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/spf13/cobra"
+)
+
+// generators write the completion script of each shell.
+var generators = map[string]func(io.Writer, []*spec) error{
+	"bash": writeBash,
+	"fish": writeFish,
+	"zsh":  writeZsh,
+}
+
+// NewCompletionCommand returns a cobra command for `completion`
+func NewCompletionCommand(dockerCli *client.DockerCli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion SHELL",
+		Short: "Output the completion script of a shell (bash, fish or zsh)",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompletion(dockerCli, cmd.Root(), args[0])
+		},
+	}
+}
+
+func runCompletion(dockerCli *client.DockerCli, root *cobra.Command, shell string) error {
+	generate, ok := generators[shell]
+	if !ok {
+		return fmt.Errorf("Unsupported shell %q, use bash, fish or zsh", shell)
+	}
+	return generate(dockerCli.Out(), buildSpecs(root, flag.CommandLine, cli.DockerCommandUsage))
+}
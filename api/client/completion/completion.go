@@ -0,0 +1,198 @@
+// Package completion generates the shell completion scripts of the docker
+// command from the definitions of its commands and flags.
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// nouns are the kinds of objects completed as the first argument of the
+// commands, by the placeholder of the argument in their usage line.
+var nouns = map[string]string{
+	"CONTAINER":  "container",
+	"IMAGE":      "image",
+	"REPOSITORY": "image",
+	"NETWORK":    "network",
+	"VOLUME":     "volume",
+	"CONTEXT":    "context",
+}
+
+// spec is the completion specification of a command.
+type spec struct {
+	// path is the path of the command below docker, joined with "_", like
+	// "volume_rm". It is empty for docker itself.
+	path        string
+	subcommands []subcommand
+	flags       []flagSpec
+	// noun is the kind of object completed as the arguments of the command,
+	// if any.
+	noun string
+}
+
+type subcommand struct {
+	name, description string
+}
+
+type flagSpec struct {
+	long, short string
+	usage       string
+	takesValue  bool
+}
+
+// names returns the names of the flags, with their dashes. Only the flags
+// taking a value are returned if withValue is set.
+func (s *spec) flagNames(withValue bool) []string {
+	var names []string
+	for _, f := range s.flags {
+		if withValue && !f.takesValue {
+			continue
+		}
+		if f.long != "" {
+			names = append(names, "--"+f.long)
+		}
+		if f.short != "" {
+			names = append(names, "-"+f.short)
+		}
+	}
+	return names
+}
+
+func (s *spec) subcommandNames() []string {
+	var names []string
+	for _, c := range s.subcommands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// buildSpecs returns the specifications of root, which is docker, and of all
+// its subcommands. The global flags of docker are those of globalFlags, and
+// legacy are the commands of docker which are not cobra commands.
+func buildSpecs(root *cobra.Command, globalFlags *flag.FlagSet, legacy []cli.Command) []*spec {
+	rootSpec := &spec{flags: mflagSpecs(globalFlags)}
+	for _, c := range legacy {
+		rootSpec.subcommands = append(rootSpec.subcommands, subcommand{name: c.Name, description: c.Description})
+	}
+	specs := []*spec{rootSpec}
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		rootSpec.subcommands = append(rootSpec.subcommands, subcommand{name: c.Name(), description: c.Short})
+		specs = append(specs, commandSpecs("", c)...)
+	}
+	sortSubcommands(rootSpec)
+	return specs
+}
+
+func commandSpecs(parentPath string, cmd *cobra.Command) []*spec {
+	path := cmd.Name()
+	if parentPath != "" {
+		path = parentPath + "_" + path
+	}
+	s := &spec{path: path, flags: pflagSpecs(cmd), noun: commandNoun(cmd)}
+	specs := []*spec{s}
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		s.subcommands = append(s.subcommands, subcommand{name: c.Name(), description: c.Short})
+		specs = append(specs, commandSpecs(path, c)...)
+	}
+	sortSubcommands(s)
+	return specs
+}
+
+func sortSubcommands(s *spec) {
+	sort.Sort(bySubcommandName(s.subcommands))
+}
+
+type bySubcommandName []subcommand
+
+func (c bySubcommandName) Len() int           { return len(c) }
+func (c bySubcommandName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c bySubcommandName) Less(i, j int) bool { return c[i].name < c[j].name }
+
+// commandNoun returns the kind of object of the first argument of cmd, from
+// its usage line. The commands creating an object, like `volume create
+// VOLUME`, complete no existing object.
+func commandNoun(cmd *cobra.Command) string {
+	fields := strings.Fields(cmd.Use)
+	if len(fields) < 2 {
+		return ""
+	}
+	for _, field := range fields[1:] {
+		if field == "[OPTIONS]" {
+			continue
+		}
+		placeholder := strings.TrimLeft(field, "[")
+		if i := strings.IndexFunc(placeholder, func(r rune) bool { return (r < 'A' || r > 'Z') && r != '_' }); i >= 0 {
+			placeholder = placeholder[:i]
+		}
+		noun := nouns[placeholder]
+		if cmd.Name() == "create" && cmd.HasParent() && cmd.Parent().Name() == noun {
+			return ""
+		}
+		return noun
+	}
+	return ""
+}
+
+func pflagSpecs(cmd *cobra.Command) []flagSpec {
+	var specs []flagSpec
+	seen := make(map[string]bool)
+	add := func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" || seen[f.Name] {
+			return
+		}
+		seen[f.Name] = true
+		s := flagSpec{long: f.Name, usage: firstLine(f.Usage), takesValue: f.NoOptDefVal == ""}
+		if f.ShorthandDeprecated == "" {
+			s.short = f.Shorthand
+		}
+		specs = append(specs, s)
+	}
+	cmd.NonInheritedFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return specs
+}
+
+func mflagSpecs(fs *flag.FlagSet) []flagSpec {
+	var specs []flagSpec
+	fs.VisitAll(func(f *flag.Flag) {
+		var s flagSpec
+		for _, name := range f.Names {
+			switch {
+			case strings.HasPrefix(name, "#"):
+				// deprecated name
+			case strings.HasPrefix(name, "-"):
+				s.long = name[1:]
+			default:
+				s.short = name
+			}
+		}
+		if s.long == "" && s.short == "" {
+			return
+		}
+		s.usage = firstLine(f.Usage)
+		b, ok := f.Value.(interface {
+			IsBoolFlag() bool
+		})
+		s.takesValue = !ok || !b.IsBoolFlag()
+		specs = append(specs, s)
+	})
+	return specs
+}
+
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/cli"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/spf13/cobra"
+)
+
+func newTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	volume := &cobra.Command{Use: "volume COMMAND", Short: "Manage Docker volumes"}
+	create := &cobra.Command{Use: "create [OPTIONS] [VOLUME]", Short: "Create a volume", Run: func(*cobra.Command, []string) {}}
+	create.Flags().StringP("driver", "d", "local", "Specify volume driver name")
+	rm := &cobra.Command{Use: "rm [OPTIONS] VOLUME [VOLUME...]", Short: "Remove one or more volumes", Run: func(*cobra.Command, []string) {}}
+	rm.Flags().BoolP("force", "f", false, "Force the removal")
+	volume.AddCommand(create, rm)
+	root.AddCommand(volume)
+	return root
+}
+
+func newTestGlobalFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("docker", flag.ContinueOnError)
+	fs.Bool([]string{"D", "-debug"}, false, "Enable debug mode")
+	fs.String([]string{"H", "-host", "#-old-host"}, "", "Daemon socket to connect to")
+	return fs
+}
+
+func findSpec(specs []*spec, path string) *spec {
+	for _, s := range specs {
+		if s.path == path {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestBuildSpecs(t *testing.T) {
+	specs := buildSpecs(newTestRoot(), newTestGlobalFlags(), []cli.Command{{Name: "exec", Description: "Run a command in a running container"}})
+
+	root := findSpec(specs, "")
+	if root == nil {
+		t.Fatal("expected a spec of docker")
+	}
+	if names := strings.Join(root.subcommandNames(), " "); names != "exec volume" {
+		t.Fatalf("expected the subcommands exec and volume, got %q", names)
+	}
+	if names := strings.Join(root.flagNames(false), " "); names != "--debug -D --host -H" {
+		t.Fatalf("expected the global flags, got %q", names)
+	}
+	if names := strings.Join(root.flagNames(true), " "); names != "--host -H" {
+		t.Fatalf("expected the global flags taking a value, got %q", names)
+	}
+
+	rm := findSpec(specs, "volume_rm")
+	if rm == nil {
+		t.Fatal("expected a spec of volume rm")
+	}
+	if rm.noun != "volume" {
+		t.Fatalf("expected volume rm to complete volumes, got %q", rm.noun)
+	}
+	if names := strings.Join(rm.flagNames(true), " "); names != "" {
+		t.Fatalf("expected no flag taking a value, got %q", names)
+	}
+
+	create := findSpec(specs, "volume_create")
+	if create == nil {
+		t.Fatal("expected a spec of volume create")
+	}
+	if create.noun != "" {
+		t.Fatalf("expected volume create to complete no volume, got %q", create.noun)
+	}
+	if names := strings.Join(create.flagNames(true), " "); names != "--driver -d" {
+		t.Fatalf("expected the flags taking a value, got %q", names)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	specs := buildSpecs(newTestRoot(), newTestGlobalFlags(), nil)
+	cases := map[string][]string{
+		"bash": {
+			"\t\t'volume')\n\t\t\techo \"create rm\"\n",
+			"complete -o default -F _docker docker\n",
+		},
+		"zsh": {
+			"#compdef docker\n",
+			"bashcompinit\n",
+			"complete -o default -F _docker docker\n",
+		},
+		"fish": {
+			"complete -c docker -f -n '__fish_docker_at \\'volume\\'' -a 'rm' -d 'Remove one or more volumes'\n",
+			"complete -c docker -n '__fish_docker_at \\'volume_create\\'' -s d -l driver -r -d 'Specify volume driver name'\n",
+			"complete -c docker -f -n '__fish_docker_at \\'volume_rm\\'' -a '(__fish_docker_nouns volume)'\n",
+		},
+	}
+	for shell, expected := range cases {
+		var out bytes.Buffer
+		if err := generators[shell](&out, specs); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range expected {
+			if !strings.Contains(out.String(), e) {
+				t.Fatalf("expected the %s script to contain %q, got:\n%s", shell, e, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const fishFunctions = `function __fish_docker_path
+	set -l path ''
+	set -l skip 0
+	for word in (commandline -opc)[2..-1]
+		if test $skip = 1
+			set skip 0
+			continue
+		end
+		switch $word
+			case '-*'
+				if contains -- $word (string split ' ' (__fish_docker_flags_with_value $path))
+					set skip 1
+				end
+			case '*'
+				if contains -- $word (string split ' ' (__fish_docker_subcommands $path))
+					if test -z "$path"
+						set path $word
+					else
+						set path {$path}_$word
+					end
+				end
+		end
+	end
+	echo $path
+end
+
+function __fish_docker_at
+	set -l path (__fish_docker_path)
+	test "$path" = "$argv[1]"
+end
+
+function __fish_docker_nouns
+	switch $argv[1]
+		case container
+			docker ps -a --format '{{.Names}}' 2>/dev/null
+		case context
+			docker context ls -q 2>/dev/null
+		case image
+			docker images --format '{{.Repository}}:{{.Tag}}' 2>/dev/null | grep -v '<none>'
+		case network
+			docker network ls 2>/dev/null | awk 'NR > 1 {print $2}'
+		case volume
+			docker volume ls -q 2>/dev/null
+	end
+end
+
+`
+
+func writeFish(w io.Writer, specs []*spec) error {
+	var b bytes.Buffer
+	b.WriteString("# fish completion for docker, generated by `docker completion fish`\n\n")
+	writeFishSwitch(&b, "__fish_docker_subcommands", specs, func(s *spec) []string { return s.subcommandNames() })
+	writeFishSwitch(&b, "__fish_docker_flags_with_value", specs, func(s *spec) []string { return s.flagNames(true) })
+	b.WriteString(fishFunctions)
+
+	for _, s := range specs {
+		condition := fishQuote("__fish_docker_at '" + s.path + "'")
+		for _, c := range s.subcommands {
+			fmt.Fprintf(&b, "complete -c docker -f -n %s -a %s -d %s\n", condition, fishQuote(c.name), fishQuote(c.description))
+		}
+		for _, f := range s.flags {
+			fmt.Fprintf(&b, "complete -c docker -n %s", condition)
+			if f.short != "" {
+				fmt.Fprintf(&b, " -s %s", f.short)
+			}
+			if f.long != "" {
+				fmt.Fprintf(&b, " -l %s", f.long)
+			}
+			if f.takesValue {
+				b.WriteString(" -r")
+			}
+			fmt.Fprintf(&b, " -d %s\n", fishQuote(f.usage))
+		}
+		if s.noun != "" {
+			fmt.Fprintf(&b, "complete -c docker -f -n %s -a %s\n", condition, fishQuote("(__fish_docker_nouns "+s.noun+")"))
+		}
+	}
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// writeFishSwitch writes a function echoing the words of the spec of the path
+// it is called with.
+func writeFishSwitch(b *bytes.Buffer, name string, specs []*spec, words func(*spec) []string) {
+	fmt.Fprintf(b, "function %s\n\tswitch \"$argv[1]\"\n", name)
+	for _, s := range specs {
+		if w := words(s); len(w) > 0 {
+			fmt.Fprintf(b, "\t\tcase '%s'\n\t\t\techo %s\n", s.path, strings.Join(w, " "))
+		}
+	}
+	b.WriteString("\tend\nend\n\n")
+}
+
+// fishQuote quotes s in single quotes for fish.
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `'`, `\'`, -1)
+	return "'" + s + "'"
+}
@@ -0,0 +1,120 @@
+package container
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+type cloneOptions struct {
+	env               opts.ListOpts
+	labels            opts.ListOpts
+	pause             bool
+	cpuShares         int64
+	cpusetCpus        string
+	cpusetMems        string
+	memoryString      string
+	memoryReservation string
+	memorySwap        string
+
+	container string
+	name      string
+}
+
+// NewCloneCommand creates a new cobra.Command for `docker clone`
+func NewCloneCommand(dockerCli *client.DockerCli) *cobra.Command {
+	opts := cloneOptions{
+		env:    opts.NewListOpts(runconfigopts.ValidateEnv),
+		labels: opts.NewListOpts(runconfigopts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "clone [OPTIONS] CONTAINER NAME",
+		Short: "Create a new container from the configuration and the filesystem changes of a container",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.name = args[1]
+			return runClone(dockerCli, &opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&opts.env, "env", "e", "Set or override environment variables")
+	flags.VarP(&opts.labels, "label", "l", "Set or override labels")
+	flags.BoolVarP(&opts.pause, "pause", "p", true, "Pause the container while its filesystem is copied")
+	flags.Int64VarP(&opts.cpuShares, "cpu-shares", "c", 0, "CPU shares (relative weight)")
+	flags.StringVar(&opts.cpusetCpus, "cpuset-cpus", "", "CPUs in which to allow execution (0-3, 0,1)")
+	flags.StringVar(&opts.cpusetMems, "cpuset-mems", "", "MEMs in which to allow execution (0-3, 0,1)")
+	flags.StringVarP(&opts.memoryString, "memory", "m", "", "Memory limit")
+	flags.StringVar(&opts.memoryReservation, "memory-reservation", "", "Memory soft limit")
+	flags.StringVar(&opts.memorySwap, "memory-swap", "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
+
+	return cmd
+}
+
+func runClone(dockerCli *client.DockerCli, opts *cloneOptions) error {
+	var err error
+
+	var memory int64
+	if opts.memoryString != "" {
+		memory, err = units.RAMInBytes(opts.memoryString)
+		if err != nil {
+			return err
+		}
+	}
+
+	var memoryReservation int64
+	if opts.memoryReservation != "" {
+		memoryReservation, err = units.RAMInBytes(opts.memoryReservation)
+		if err != nil {
+			return err
+		}
+	}
+
+	var memorySwap int64
+	if opts.memorySwap != "" {
+		if opts.memorySwap == "-1" {
+			memorySwap = -1
+		} else {
+			memorySwap, err = units.RAMInBytes(opts.memorySwap)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cloneConfig := types.ContainerCloneConfig{
+		Name:   opts.name,
+		Pause:  opts.pause,
+		Env:    opts.env.GetAll(),
+		Labels: runconfigopts.ConvertKVStringsToMap(opts.labels.GetAll()),
+		Resources: containertypes.Resources{
+			CPUShares:         opts.cpuShares,
+			CpusetCpus:        opts.cpusetCpus,
+			CpusetMems:        opts.cpusetMems,
+			Memory:            memory,
+			MemoryReservation: memoryReservation,
+			MemorySwap:        memorySwap,
+		},
+	}
+
+	response, err := dockerCli.Client().ContainerClone(context.Background(), opts.container, cloneConfig)
+	if err != nil {
+		return err
+	}
+	for _, warning := range response.Warnings {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", warning)
+	}
+	fmt.Fprintln(dockerCli.Out(), response.ID)
+	return nil
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/cli"
+	"github.com/docker/docker/distribution"
 	"github.com/docker/docker/pkg/jsonmessage"
 	// FIXME migrate to docker/distribution/reference
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
+	"github.com/docker/docker/runconfig"
 	runconfigopts "github.com/docker/docker/runconfig/opts"
 	apiclient "github.com/docker/engine-api/client"
 	"github.com/docker/engine-api/types"
@@ -23,7 +26,9 @@ import (
 )
 
 type createOptions struct {
-	name string
+	name     string
+	platform string
+	template string
 }
 
 // NewCreateCommand creats a new cobra.Command for `docker create`
@@ -34,8 +39,16 @@ func NewCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create a new container",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.template != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.template != "" {
+				return runCreateFromTemplate(dockerCli, cmd.Flags(), &opts, args)
+			}
 			copts.Image = args[0]
 			if len(args) > 1 {
 				copts.Args = args[1:]
@@ -49,6 +62,8 @@ func NewCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.SetInterspersed(false)
 
 	flags.StringVar(&opts.name, "name", "", "Assign a name to the container")
+	flags.StringVar(&opts.template, "from-template", "", "Create the container from a configuration template ('-' for STDIN)")
+	flags.StringVar(&opts.platform, "platform", "", "Set the platform the container runs for, and of the image pulled from a manifest list (format: os[/arch[/variant]])")
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -65,7 +80,7 @@ func runCreate(dockerCli *client.DockerCli, flags *pflag.FlagSet, opts *createOp
 		reportError(dockerCli.Err(), "create", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
 	}
-	response, err := createContainer(context.Background(), dockerCli, config, hostConfig, networkingConfig, hostConfig.ContainerIDFile, opts.name)
+	response, err := createContainer(context.Background(), dockerCli, config, hostConfig, networkingConfig, hostConfig.ContainerIDFile, opts.name, opts.platform)
 	if err != nil {
 		return err
 	}
@@ -73,7 +88,59 @@ func runCreate(dockerCli *client.DockerCli, flags *pflag.FlagSet, opts *createOp
 	return nil
 }
 
-func pullImage(ctx context.Context, dockerCli *client.DockerCli, image string, out io.Writer) error {
+// templateFlags are the flags valid with --from-template, the other options of
+// the container being in the template.
+var templateFlags = map[string]bool{
+	"disable-content-trust": true,
+	"from-template":         true,
+	"help":                  true,
+	"name":                  true,
+	"platform":              true,
+}
+
+// runCreateFromTemplate creates a container from a configuration template
+// written by `docker inspect --format=config-template`, with the image and
+// the command of args, if any.
+func runCreateFromTemplate(dockerCli *client.DockerCli, flags *pflag.FlagSet, opts *createOptions, args []string) error {
+	var conflicting []string
+	flags.Visit(func(f *pflag.Flag) {
+		if !templateFlags[f.Name] {
+			conflicting = append(conflicting, "--"+f.Name)
+		}
+	})
+	if len(conflicting) > 0 {
+		return fmt.Errorf("Conflicting options: --from-template and %s", strings.Join(conflicting, ", "))
+	}
+
+	var in io.Reader = dockerCli.In()
+	if opts.template != "-" {
+		f, err := os.Open(opts.template)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	t, err := runconfig.DecodeConfigTemplate(in, dockerCli.Client().ClientVersion())
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		t.Config.Image = args[0]
+	}
+	if len(args) > 1 {
+		t.Config.Cmd = args[1:]
+	}
+
+	response, err := createContainer(context.Background(), dockerCli, t.Config, t.HostConfig, t.NetworkingConfig, "", opts.name, opts.platform)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s\n", response.ID)
+	return nil
+}
+
+func pullImage(ctx context.Context, dockerCli *client.DockerCli, image, platform string, out io.Writer) error {
 	ref, err := reference.ParseNamed(image)
 	if err != nil {
 		return err
@@ -93,6 +160,7 @@ func pullImage(ctx context.Context, dockerCli *client.DockerCli, image string, o
 
 	options := types.ImageCreateOptions{
 		RegistryAuth: encodedAuth,
+		Platform:     platform,
 	}
 
 	responseBody, err := dockerCli.Client().ImageCreate(ctx, image, options)
@@ -148,7 +216,7 @@ func newCIDFile(path string) (*cidFile, error) {
 	return &cidFile{path: path, file: f}, nil
 }
 
-func createContainer(ctx context.Context, dockerCli *client.DockerCli, config *container.Config, hostConfig *container.HostConfig, networkingConfig *networktypes.NetworkingConfig, cidfile, name string) (*types.ContainerCreateResponse, error) {
+func createContainer(ctx context.Context, dockerCli *client.DockerCli, config *container.Config, hostConfig *container.HostConfig, networkingConfig *networktypes.NetworkingConfig, cidfile, name, platform string) (*types.ContainerCreateResponse, error) {
 	stderr := dockerCli.Err()
 
 	var containerIDFile *cidFile
@@ -160,6 +228,13 @@ func createContainer(ctx context.Context, dockerCli *client.DockerCli, config *c
 		defer containerIDFile.Close()
 	}
 
+	// The architecture of the platform is the one the container runs for.
+	arch, err := distribution.PlatformArchitecture(platform)
+	if err != nil {
+		return nil, err
+	}
+	config.Architecture = arch
+
 	var trustedRef reference.Canonical
 	_, ref, err := reference.ParseIDOrReference(config.Image)
 	if err != nil {
@@ -187,7 +262,7 @@ func createContainer(ctx context.Context, dockerCli *client.DockerCli, config *c
 			fmt.Fprintf(stderr, "Unable to find image '%s' locally\n", ref.String())
 
 			// we don't want to write to stdout anything apart from container.ID
-			if err = pullImage(ctx, dockerCli, config.Image, stderr); err != nil {
+			if err = pullImage(ctx, dockerCli, config.Image, platform, stderr); err != nil {
 				return nil, err
 			}
 			if ref, ok := ref.(reference.NamedTagged); ok && trustedRef != nil {
@@ -8,12 +8,14 @@ import (
 
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type exportOptions struct {
 	container string
 	output    string
+	metadata  bool
 }
 
 // NewExportCommand creates a new `docker export` command
@@ -33,6 +35,7 @@ func NewExportCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.BoolVar(&opts.metadata, "metadata", false, "Include the runtime configuration of the container, for docker import")
 
 	return cmd
 }
@@ -44,7 +47,7 @@ func runExport(dockerCli *client.DockerCli, opts exportOptions) error {
 
 	clnt := dockerCli.Client()
 
-	responseBody, err := clnt.ContainerExport(context.Background(), opts.container)
+	responseBody, err := clnt.ContainerExport(context.Background(), opts.container, types.ContainerExportOptions{Metadata: opts.metadata})
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ type rmOptions struct {
 	rmVolumes bool
 	rmLink    bool
 	force     bool
+	parallel  int
 
 	containers []string
 }
@@ -38,19 +39,36 @@ func NewRmCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.BoolVarP(&opts.rmVolumes, "volumes", "v", false, "Remove the volumes associated with the container")
 	flags.BoolVarP(&opts.rmLink, "link", "l", false, "Remove the specified link")
 	flags.BoolVarP(&opts.force, "force", "f", false, "Force the removal of a running container (uses SIGKILL)")
+	flags.IntVar(&opts.parallel, "parallel", 0, "Remove the containers in a single request, this many at a time")
 	return cmd
 }
 
 func runRm(dockerCli *client.DockerCli, opts *rmOptions) error {
 	ctx := context.Background()
 
-	var errs []string
+	names := make([]string, 0, len(opts.containers))
 	for _, name := range opts.containers {
 		if name == "" {
 			return fmt.Errorf("Container name cannot be empty")
 		}
-		name = strings.Trim(name, "/")
+		names = append(names, strings.Trim(name, "/"))
+	}
 
+	if opts.parallel > 0 {
+		options := types.ContainerRemoveOptions{
+			RemoveVolumes: opts.rmVolumes,
+			RemoveLinks:   opts.rmLink,
+			Force:         opts.force,
+		}
+		results, err := dockerCli.Client().ContainersRemove(ctx, names, options, opts.parallel)
+		if err != nil {
+			return err
+		}
+		return printBatchResults(dockerCli, results)
+	}
+
+	var errs []string
+	for _, name := range names {
 		if err := removeContainer(dockerCli, ctx, name, opts.rmVolumes, opts.rmLink, opts.force); err != nil {
 			errs = append(errs, err.Error())
 		} else {
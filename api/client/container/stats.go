@@ -23,6 +23,7 @@ import (
 type statsOptions struct {
 	all      bool
 	noStream bool
+	since    string
 
 	containers []string
 }
@@ -44,12 +45,17 @@ func NewStatsCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.all, "all", "a", false, "Show all containers (default shows just running)")
 	flags.BoolVar(&opts.noStream, "no-stream", false, "Disable streaming stats and only pull the first result")
+	flags.StringVar(&opts.since, "since", "", "Show the history of the stats kept by the daemon since timestamp or relative (e.g. 1h)")
 	return cmd
 }
 
 // runStats displays a live stream of resource usage statistics for one or more containers.
 // This shows real-time information on CPU usage, memory usage, and network I/O.
 func runStats(dockerCli *client.DockerCli, opts *statsOptions) error {
+	if opts.since != "" {
+		return runStatsHistory(dockerCli, opts)
+	}
+
 	showAll := len(opts.containers) == 0
 	closeChan := make(chan error)
 
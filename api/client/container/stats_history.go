@@ -0,0 +1,63 @@
+package container
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+)
+
+// runStatsHistory displays the history of the resource usage statistics of
+// one or more containers, kept by the daemon.
+func runStatsHistory(dockerCli *client.DockerCli, opts *statsOptions) error {
+	if len(opts.containers) == 0 {
+		return fmt.Errorf("\"--since\" requires at least one container")
+	}
+	ctx := context.Background()
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprint(w, "CONTAINER\tTIME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS\n")
+	for _, container := range opts.containers {
+		samples, err := dockerCli.Client().ContainerStatsHistory(ctx, container, opts.since)
+		if err != nil {
+			return err
+		}
+		for i, s := range samples {
+			cpuPercent := "--"
+			if i > 0 {
+				cpuPercent = fmt.Sprintf("%.2f%%", calculateSampleCPUPercent(samples[i-1], s))
+			}
+			memPercent := 0.0
+			if s.MemoryLimit != 0 {
+				memPercent = float64(s.MemoryUsage) / float64(s.MemoryLimit) * 100.0
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s / %s\t%.2f%%\t%s / %s\t%s / %s\t%d\n",
+				container,
+				s.Read.Local().Format("2006-01-02 15:04:05"),
+				cpuPercent,
+				units.BytesSize(float64(s.MemoryUsage)), units.BytesSize(float64(s.MemoryLimit)),
+				memPercent,
+				units.HumanSize(float64(s.NetworkRx)), units.HumanSize(float64(s.NetworkTx)),
+				units.HumanSize(float64(s.BlockRead)), units.HumanSize(float64(s.BlockWrite)),
+				s.Pids)
+		}
+	}
+	return w.Flush()
+}
+
+// calculateSampleCPUPercent returns the CPU usage of a container between two
+// samples of its stats history.
+func calculateSampleCPUPercent(previous, s types.StatsSample) float64 {
+	var (
+		cpuDelta    = float64(s.CPUUsage) - float64(previous.CPUUsage)
+		systemDelta = float64(s.SystemCPUUsage) - float64(previous.SystemCPUUsage)
+	)
+	if systemDelta > 0.0 && cpuDelta > 0.0 {
+		return (cpuDelta / systemDelta) * float64(s.OnlineCPUs) * 100.0
+	}
+	return 0.0
+}
@@ -43,3 +43,14 @@ func TestCalculBlockIO(t *testing.T) {
 		t.Fatalf("blkWrite = %d, want 579", blkWrite)
 	}
 }
+
+func TestCalculateSampleCPUPercent(t *testing.T) {
+	previous := types.StatsSample{CPUUsage: 1000, SystemCPUUsage: 10000, OnlineCPUs: 2}
+	s := types.StatsSample{CPUUsage: 2000, SystemCPUUsage: 20000, OnlineCPUs: 2}
+	if percent := calculateSampleCPUPercent(previous, s); percent != 20.0 {
+		t.Fatalf("expected 20%% of CPU, got %.2f%%", percent)
+	}
+	if percent := calculateSampleCPUPercent(s, s); percent != 0.0 {
+		t.Fatalf("expected no CPU usage, got %.2f%%", percent)
+	}
+}
@@ -13,7 +13,8 @@ import (
 )
 
 type stopOptions struct {
-	time int
+	time     int
+	parallel int
 
 	containers []string
 }
@@ -35,15 +36,24 @@ func NewStopCommand(dockerCli *client.DockerCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.IntVarP(&opts.time, "time", "t", 10, "Seconds to wait for stop before killing it")
+	flags.IntVar(&opts.parallel, "parallel", 0, "Stop the containers in a single request, this many at a time")
 	return cmd
 }
 
 func runStop(dockerCli *client.DockerCli, opts *stopOptions) error {
 	ctx := context.Background()
+	timeout := time.Duration(opts.time) * time.Second
+
+	if opts.parallel > 0 {
+		results, err := dockerCli.Client().ContainersStop(ctx, opts.containers, &timeout, opts.parallel)
+		if err != nil {
+			return err
+		}
+		return printBatchResults(dockerCli, results)
+	}
 
 	var errs []string
 	for _, container := range opts.containers {
-		timeout := time.Duration(opts.time) * time.Second
 		if err := dockerCli.Client().ContainerStop(ctx, container, &timeout); err != nil {
 			errs = append(errs, err.Error())
 		} else {
@@ -1,10 +1,14 @@
 package container
 
 import (
+	"fmt"
+	"strings"
+
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
 	clientapi "github.com/docker/engine-api/client"
+	"github.com/docker/engine-api/types"
 )
 
 // getExitCode perform an inspect on the container. It returns
@@ -20,3 +24,20 @@ func getExitCode(dockerCli *client.DockerCli, ctx context.Context, containerID s
 	}
 	return c.State.Running, c.State.ExitCode, nil
 }
+
+// printBatchResults prints the containers a batch request succeeded on, and
+// returns the errors of the others.
+func printBatchResults(dockerCli *client.DockerCli, results []types.ContainerBatchResult) error {
+	var errs []string
+	for _, result := range results {
+		if result.Error != "" {
+			errs = append(errs, result.Error)
+		} else {
+			fmt.Fprintf(dockerCli.Out(), "%s\n", result.ID)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
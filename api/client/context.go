@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cliflags "github.com/docker/docker/cli/flags"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/cliconfig/configfile"
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+// DefaultContextName is the name of the context connecting to the daemon of
+// the -H flag, of DOCKER_HOST, or else to the default socket.
+const DefaultContextName = "default"
+
+// contextName returns the name of the context selected with the --context
+// flag, DOCKER_CONTEXT or `docker context use`, in that order. The -H flag and
+// DOCKER_HOST select the default context, unless --context or DOCKER_CONTEXT
+// is set.
+func contextName(clientFlags *cliflags.ClientFlags, configFile *configfile.ConfigFile) (string, error) {
+	if len(clientFlags.Common.Hosts) > 0 {
+		if clientFlags.FlagSet.IsSet("-context") {
+			return "", errors.New("Conflicting options: -H and --context")
+		}
+		return DefaultContextName, nil
+	}
+	if clientFlags.Context != "" {
+		return clientFlags.Context, nil
+	}
+	if os.Getenv("DOCKER_HOST") != "" || configFile.CurrentContext == "" {
+		return DefaultContextName, nil
+	}
+	return configFile.CurrentContext, nil
+}
+
+// lookupContext returns the context name of configFile, or nil for the
+// default context.
+func lookupContext(name string, configFile *configfile.ConfigFile) (*configfile.ContextConfig, error) {
+	if name == DefaultContextName {
+		return nil, nil
+	}
+	context, ok := configFile.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("Context %q does not exist, use `docker --context %s context use %s` to go back to the default context", name, DefaultContextName, DefaultContextName)
+	}
+	return &context, nil
+}
+
+// contextTLSOptions returns the TLS options of context. Like for the --tlscert
+// and --tlskey flags, the certificate and the key are only used if their file
+// exists.
+func contextTLSOptions(context *configfile.ContextConfig) *tlsconfig.Options {
+	certPath := context.CertPath
+	if certPath == "" {
+		certPath = cliconfig.ConfigDir()
+	}
+	options := &tlsconfig.Options{
+		CAFile:             filepath.Join(certPath, cliflags.DefaultCaFile),
+		CertFile:           filepath.Join(certPath, cliflags.DefaultCertFile),
+		KeyFile:            filepath.Join(certPath, cliflags.DefaultKeyFile),
+		InsecureSkipVerify: !context.TLSVerify,
+	}
+	if _, err := os.Stat(options.CertFile); os.IsNotExist(err) {
+		options.CertFile = ""
+	}
+	if _, err := os.Stat(options.KeyFile); os.IsNotExist(err) {
+		options.KeyFile = ""
+	}
+	return options
+}
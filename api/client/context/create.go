@@ -0,0 +1,87 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/cliconfig/configfile"
+	"github.com/docker/docker/opts"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	name        string
+	description string
+	host        string
+	apiVersion  string
+	tls         bool
+	tlsVerify   bool
+	certPath    string
+}
+
+func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] CONTEXT",
+		Short: "Create a context",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runCreate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.description, "description", "", "Description of the context")
+	flags.StringVarP(&opts.host, "host", "H", "", "Daemon socket to connect to")
+	flags.StringVar(&opts.apiVersion, "api-version", "", "API version to use with the daemon")
+	flags.BoolVar(&opts.tls, "tls", false, "Use TLS; implied by --tlsverify")
+	flags.BoolVar(&opts.tlsVerify, "tlsverify", false, "Use TLS and verify the remote")
+	flags.StringVar(&opts.certPath, "cert-path", "", "Directory of the ca.pem, cert.pem and key.pem files (default the client config directory)")
+
+	return cmd
+}
+
+func runCreate(dockerCli *client.DockerCli, createOpts createOptions) error {
+	if createOpts.name == client.DefaultContextName {
+		return fmt.Errorf("The %s context cannot be created", client.DefaultContextName)
+	}
+	if createOpts.host == "" {
+		return fmt.Errorf("A context requires a --host")
+	}
+	host, err := opts.ValidateHost(createOpts.host)
+	if err != nil {
+		return err
+	}
+
+	certPath := createOpts.certPath
+	if certPath != "" {
+		if certPath, err = filepath.Abs(certPath); err != nil {
+			return err
+		}
+	}
+
+	configFile := dockerCli.ConfigFile()
+	if _, exists := configFile.Contexts[createOpts.name]; exists {
+		return fmt.Errorf("Context %q already exists", createOpts.name)
+	}
+	if configFile.Contexts == nil {
+		configFile.Contexts = make(map[string]configfile.ContextConfig)
+	}
+	configFile.Contexts[createOpts.name] = configfile.ContextConfig{
+		Description: createOpts.description,
+		Host:        host,
+		APIVersion:  createOpts.apiVersion,
+		TLS:         createOpts.tls,
+		TLSVerify:   createOpts.tlsVerify,
+		CertPath:    certPath,
+	}
+	if err := configFile.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s\n", createOpts.name)
+	return nil
+}
@@ -0,0 +1,69 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet bool
+}
+
+func newListCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List contexts",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display context names")
+
+	return cmd
+}
+
+func runList(dockerCli *client.DockerCli, listOpts listOptions) error {
+	contexts := dockerCli.ConfigFile().Contexts
+
+	names := []string{client.DefaultContextName}
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	if listOpts.quiet {
+		for _, name := range names {
+			fmt.Fprintln(dockerCli.Out(), name)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "NAME\tDESCRIPTION\tHOST\n")
+	for _, name := range names {
+		description, host := contexts[name].Description, contexts[name].Host
+		if name == client.DefaultContextName {
+			description = "The daemon of -H, DOCKER_HOST or the default socket"
+			host, _ = opts.ParseHost(false, os.Getenv("DOCKER_HOST"))
+		}
+		if name == dockerCli.CurrentContext() {
+			name += " *"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, description, host)
+	}
+	w.Flush()
+	return nil
+}
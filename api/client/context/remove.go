@@ -0,0 +1,55 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCommand(dockerCli *client.DockerCli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm CONTEXT [CONTEXT...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more contexts",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(dockerCli, args)
+		},
+	}
+}
+
+func runRemove(dockerCli *client.DockerCli, names []string) error {
+	configFile := dockerCli.ConfigFile()
+	status := 0
+
+	var removed []string
+	for _, name := range names {
+		if _, exists := configFile.Contexts[name]; !exists {
+			fmt.Fprintf(dockerCli.Err(), "Context %q does not exist\n", name)
+			status = 1
+			continue
+		}
+		delete(configFile.Contexts, name)
+		// Removing the current context goes back to the default context.
+		if configFile.CurrentContext == name {
+			configFile.CurrentContext = ""
+		}
+		removed = append(removed, name)
+	}
+
+	if len(removed) > 0 {
+		if err := configFile.Save(); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			fmt.Fprintf(dockerCli.Out(), "%s\n", name)
+		}
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
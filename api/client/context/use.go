@@ -0,0 +1,37 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+func newUseCommand(dockerCli *client.DockerCli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use CONTEXT",
+		Short: "Set the context the client connects to by default",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUse(dockerCli, args[0])
+		},
+	}
+}
+
+func runUse(dockerCli *client.DockerCli, name string) error {
+	configFile := dockerCli.ConfigFile()
+	if name == client.DefaultContextName {
+		configFile.CurrentContext = ""
+	} else {
+		if _, exists := configFile.Contexts[name]; !exists {
+			return fmt.Errorf("Context %q does not exist", name)
+		}
+		configFile.CurrentContext = name
+	}
+	if err := configFile.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "%s\n", name)
+	return nil
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	cliflags "github.com/docker/docker/cli/flags"
+	"github.com/docker/docker/cliconfig/configfile"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+)
+
+func newTestClientFlags(t *testing.T, args ...string) *cliflags.ClientFlags {
+	clientFlags := &cliflags.ClientFlags{
+		FlagSet: flag.NewFlagSet("docker", flag.ContinueOnError),
+		Common:  &cliflags.CommonFlags{},
+	}
+	clientFlags.FlagSet.StringVar(&clientFlags.Context, []string{"-context"}, "", "")
+	clientFlags.FlagSet.Var(opts.NewNamedListOptsRef("hosts", &clientFlags.Common.Hosts, nil), []string{"H", "-host"}, "")
+	if err := clientFlags.FlagSet.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return clientFlags
+}
+
+func TestContextName(t *testing.T) {
+	defer os.Setenv("DOCKER_HOST", os.Getenv("DOCKER_HOST"))
+	os.Setenv("DOCKER_HOST", "")
+
+	configFile := &configfile.ConfigFile{
+		Contexts: map[string]configfile.ContextConfig{
+			"prod":    {Host: "tcp://prod:2376"},
+			"staging": {Host: "tcp://staging:2376"},
+		},
+	}
+
+	tests := []struct {
+		args           []string
+		currentContext string
+		dockerHost     string
+		expected       string
+	}{
+		{expected: DefaultContextName},
+		{currentContext: "prod", expected: "prod"},
+		{args: []string{"--context", "staging"}, currentContext: "prod", expected: "staging"},
+		{args: []string{"-H", "tcp://other:2375"}, currentContext: "prod", expected: DefaultContextName},
+		{currentContext: "prod", dockerHost: "tcp://other:2375", expected: DefaultContextName},
+		{args: []string{"--context", "staging"}, dockerHost: "tcp://other:2375", expected: "staging"},
+	}
+	for _, test := range tests {
+		os.Setenv("DOCKER_HOST", test.dockerHost)
+		configFile.CurrentContext = test.currentContext
+		name, err := contextName(newTestClientFlags(t, test.args...), configFile)
+		if err != nil {
+			t.Fatalf("%v: %v", test.args, err)
+		}
+		if name != test.expected {
+			t.Fatalf("%v: expected context %q, got %q", test.args, test.expected, name)
+		}
+	}
+
+	if _, err := contextName(newTestClientFlags(t, "--context", "prod", "-H", "tcp://other:2375"), configFile); err == nil {
+		t.Fatal("expected an error with both -H and --context")
+	}
+}
+
+func TestLookupContext(t *testing.T) {
+	configFile := &configfile.ConfigFile{
+		Contexts: map[string]configfile.ContextConfig{
+			"prod": {Host: "tcp://prod:2376", TLSVerify: true},
+		},
+	}
+
+	context, err := lookupContext(DefaultContextName, configFile)
+	if err != nil || context != nil {
+		t.Fatalf("expected no context for the default context, got %v, %v", context, err)
+	}
+	context, err = lookupContext("prod", configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if context.Host != "tcp://prod:2376" {
+		t.Fatalf("expected the host of the prod context, got %q", context.Host)
+	}
+	if options := contextTLSOptions(context); options.InsecureSkipVerify {
+		t.Fatal("expected the remote to be verified")
+	}
+	if _, err := lookupContext("missing", configFile); err == nil {
+		t.Fatal("expected an error for a missing context")
+	}
+}
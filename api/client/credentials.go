@@ -9,36 +9,59 @@ import (
 // GetCredentials loads the user credentials from a credentials store.
 // The store is determined by the config file settings.
 func GetCredentials(c *configfile.ConfigFile, serverAddress string) (types.AuthConfig, error) {
-	s := LoadCredentialsStore(c)
+	s := LoadCredentialsStore(c, serverAddress)
 	return s.Get(serverAddress)
 }
 
-// GetAllCredentials loads all credentials from a credentials store.
-// The store is determined by the config file settings.
+// GetAllCredentials loads all credentials from the credentials stores.
+// The stores are determined by the config file settings.
 func GetAllCredentials(c *configfile.ConfigFile) (map[string]types.AuthConfig, error) {
-	s := LoadCredentialsStore(c)
-	return s.GetAll()
+	s := LoadCredentialsStore(c, "")
+	auths, err := s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// The credentials of the registries with their own helper are not in
+	// the default store.
+	for serverAddress := range c.CredentialHelpers {
+		if auth, err := GetCredentials(c, serverAddress); err == nil {
+			auths[serverAddress] = auth
+		}
+	}
+	return auths, nil
 }
 
 // StoreCredentials saves the user credentials in a credentials store.
 // The store is determined by the config file settings.
 func StoreCredentials(c *configfile.ConfigFile, auth types.AuthConfig) error {
-	s := LoadCredentialsStore(c)
+	s := LoadCredentialsStore(c, auth.ServerAddress)
 	return s.Store(auth)
 }
 
 // EraseCredentials removes the user credentials from a credentials store.
 // The store is determined by the config file settings.
 func EraseCredentials(c *configfile.ConfigFile, serverAddress string) error {
-	s := LoadCredentialsStore(c)
+	s := LoadCredentialsStore(c, serverAddress)
 	return s.Erase(serverAddress)
 }
 
-// LoadCredentialsStore initializes a new credentials store based
-// in the settings provided in the configuration file.
-func LoadCredentialsStore(c *configfile.ConfigFile) credentials.Store {
-	if c.CredentialsStore != "" {
-		return credentials.NewNativeStore(c)
+// LoadCredentialsStore initializes a new credentials store for the
+// registry serverAddress based in the settings provided in the
+// configuration file.
+func LoadCredentialsStore(c *configfile.ConfigFile, serverAddress string) credentials.Store {
+	if helper := getConfiguredCredentialStore(c, serverAddress); helper != "" {
+		return credentials.NewNativeStore(c, helper)
 	}
 	return credentials.NewFileStore(c)
 }
+
+// getConfiguredCredentialStore returns the credentials helper configured for
+// the registry serverAddress, else the default credentials store. An empty
+// string is returned if the credentials are kept in the configuration file.
+func getConfiguredCredentialStore(c *configfile.ConfigFile, serverAddress string) string {
+	if helper, ok := c.CredentialHelpers[serverAddress]; ok && serverAddress != "" {
+		return helper
+	}
+	return c.CredentialsStore
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/docker/docker/cliconfig/configfile"
+)
+
+func TestGetConfiguredCredentialStore(t *testing.T) {
+	c := &configfile.ConfigFile{
+		CredentialsStore: "secretservice",
+		CredentialHelpers: map[string]string{
+			"registry.example.com": "gcloud",
+		},
+	}
+
+	tests := map[string]string{
+		"registry.example.com":        "gcloud",
+		"https://index.docker.io/v1/": "secretservice",
+		"other.example.com":           "secretservice",
+		"":                            "secretservice",
+	}
+	for serverAddress, expected := range tests {
+		if helper := getConfiguredCredentialStore(c, serverAddress); helper != expected {
+			t.Errorf("expected %q for %q, got %q", expected, serverAddress, helper)
+		}
+	}
+
+	c.CredentialsStore = ""
+	if helper := getConfiguredCredentialStore(c, "other.example.com"); helper != "" {
+		t.Errorf("expected the file store for other.example.com, got %q", helper)
+	}
+	if helper := getConfiguredCredentialStore(c, "registry.example.com"); helper != "gcloud" {
+		t.Errorf("expected gcloud for registry.example.com, got %q", helper)
+	}
+}
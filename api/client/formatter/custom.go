@@ -1,6 +1,7 @@
 package formatter
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/pkg/stringutils"
 	"github.com/docker/engine-api/types"
+	registrytypes "github.com/docker/engine-api/types/registry"
 	"github.com/docker/go-units"
 )
 
@@ -32,6 +34,21 @@ const (
 	tagHeader          = "TAG"
 	digestHeader       = "DIGEST"
 	mountsHeader       = "MOUNTS"
+	createdByHeader    = "CREATED BY"
+	tagsHeader         = "TAGS"
+	diskSizeHeader     = "DISK SIZE"
+	commentHeader      = "COMMENT"
+	diffIDHeader       = "DIFF ID"
+	digestsHeader      = "DIGESTS"
+	nameHeader         = "NAME"
+	descriptionHeader  = "DESCRIPTION"
+	starsHeader        = "STARS"
+	officialHeader     = "OFFICIAL"
+	automatedHeader    = "AUTOMATED"
+	volumeNameHeader   = "VOLUME NAME"
+	driverHeader       = "DRIVER"
+	mountpointHeader   = "MOUNTPOINT"
+	scopeHeader        = "SCOPE"
 )
 
 type containerContext struct {
@@ -210,6 +227,191 @@ func (c *imageContext) Size() string {
 	return units.HumanSize(float64(c.i.Size))
 }
 
+type historyContext struct {
+	baseSubContext
+	trunc bool
+	human bool
+	h     types.ImageHistory
+}
+
+func (c *historyContext) ID() string {
+	c.addHeader(imageHeader)
+	if c.trunc {
+		return stringid.TruncateID(c.h.ID)
+	}
+	return c.h.ID
+}
+
+// CreatedSince returns the time elapsed since the step, or its date in the
+// RFC 3339 format if the output is not human readable.
+func (c *historyContext) CreatedSince() string {
+	c.addHeader(createdSinceHeader)
+	createdAt := time.Unix(c.h.Created, 0)
+	if !c.human {
+		return createdAt.Format(time.RFC3339)
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(createdAt))
+}
+
+func (c *historyContext) CreatedAt() string {
+	c.addHeader(createdAtHeader)
+	return time.Unix(c.h.Created, 0).Format(time.RFC3339)
+}
+
+func (c *historyContext) CreatedBy() string {
+	c.addHeader(createdByHeader)
+	createdBy := strings.Replace(c.h.CreatedBy, "\t", " ", -1)
+	if c.trunc {
+		createdBy = stringutils.Truncate(createdBy, 45)
+	}
+	return createdBy
+}
+
+func (c *historyContext) Tags() string {
+	c.addHeader(tagsHeader)
+	return strings.Join(c.h.Tags, ",")
+}
+
+func (c *historyContext) Size() string {
+	c.addHeader(sizeHeader)
+	if !c.human {
+		return strconv.FormatInt(c.h.Size, 10)
+	}
+	return units.HumanSize(float64(c.h.Size))
+}
+
+func (c *historyContext) DiskSize() string {
+	c.addHeader(diskSizeHeader)
+	if !c.human {
+		return strconv.FormatInt(c.h.DiskSize, 10)
+	}
+	return units.HumanSize(float64(c.h.DiskSize))
+}
+
+func (c *historyContext) Comment() string {
+	c.addHeader(commentHeader)
+	return c.h.Comment
+}
+
+func (c *historyContext) DiffID() string {
+	c.addHeader(diffIDHeader)
+	if c.h.DiffID == "" {
+		return "<none>"
+	}
+	return c.h.DiffID
+}
+
+func (c *historyContext) Digests() string {
+	c.addHeader(digestsHeader)
+	if len(c.h.Digests) == 0 {
+		return "<none>"
+	}
+	return strings.Join(c.h.Digests, ",")
+}
+
+type searchContext struct {
+	baseSubContext
+	trunc bool
+	s     registrytypes.SearchResult
+}
+
+// MarshalJSON returns the search result, so that `{{json .}}` outputs it.
+func (c *searchContext) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.s)
+}
+
+func (c *searchContext) Name() string {
+	c.addHeader(nameHeader)
+	return c.s.Name
+}
+
+func (c *searchContext) Description() string {
+	c.addHeader(descriptionHeader)
+	desc := strings.Replace(c.s.Description, "\n", " ", -1)
+	desc = strings.Replace(desc, "\r", " ", -1)
+	if c.trunc && len(desc) > 45 {
+		desc = stringutils.Truncate(desc, 42) + "..."
+	}
+	return desc
+}
+
+func (c *searchContext) StarCount() string {
+	c.addHeader(starsHeader)
+	return strconv.Itoa(c.s.StarCount)
+}
+
+func (c *searchContext) IsOfficial() string {
+	c.addHeader(officialHeader)
+	if c.s.IsOfficial {
+		return "[OK]"
+	}
+	return ""
+}
+
+func (c *searchContext) IsAutomated() string {
+	c.addHeader(automatedHeader)
+	if c.s.IsAutomated {
+		return "[OK]"
+	}
+	return ""
+}
+
+type volumeContext struct {
+	baseSubContext
+	v *types.Volume
+}
+
+// MarshalJSON returns the volume, so that `{{json .}}` outputs it.
+func (c *volumeContext) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.v)
+}
+
+func (c *volumeContext) Name() string {
+	c.addHeader(volumeNameHeader)
+	return c.v.Name
+}
+
+func (c *volumeContext) Driver() string {
+	c.addHeader(driverHeader)
+	return c.v.Driver
+}
+
+func (c *volumeContext) Mountpoint() string {
+	c.addHeader(mountpointHeader)
+	return c.v.Mountpoint
+}
+
+func (c *volumeContext) Scope() string {
+	c.addHeader(scopeHeader)
+	return c.v.Scope
+}
+
+func (c *volumeContext) Labels() string {
+	c.addHeader(labelsHeader)
+	if c.v.Labels == nil {
+		return ""
+	}
+
+	var joinLabels []string
+	for k, v := range c.v.Labels {
+		joinLabels = append(joinLabels, fmt.Sprintf("%s=%s", k, v))
+	}
+	return strings.Join(joinLabels, ",")
+}
+
+func (c *volumeContext) Label(name string) string {
+	n := strings.Split(name, ".")
+	r := strings.NewReplacer("-", " ", "_", " ")
+	h := r.Replace(n[len(n)-1])
+
+	c.addHeader(h)
+
+	if c.v.Labels == nil {
+		return ""
+	}
+	return c.v.Labels[name]
+}
+
 type subContext interface {
 	fullHeader() string
 	addHeader(header string)
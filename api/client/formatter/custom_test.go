@@ -170,6 +170,80 @@ func TestImagesContext(t *testing.T) {
 	}
 }
 
+func TestHistoryContext(t *testing.T) {
+	imageID := stringid.GenerateRandomID()
+	unix := time.Now().Unix()
+
+	var ctx historyContext
+	cases := []struct {
+		historyCtx historyContext
+		expValue   string
+		expHeader  string
+		call       func() string
+	}{
+		{historyContext{
+			h:     types.ImageHistory{ID: imageID},
+			trunc: true,
+		}, stringid.TruncateID(imageID), imageHeader, ctx.ID},
+		{historyContext{
+			h:     types.ImageHistory{ID: imageID},
+			trunc: false,
+		}, imageID, imageHeader, ctx.ID},
+		{historyContext{
+			h:     types.ImageHistory{Created: unix},
+			human: false,
+		}, time.Unix(unix, 0).Format(time.RFC3339), createdSinceHeader, ctx.CreatedSince},
+		{historyContext{
+			h: types.ImageHistory{Created: unix},
+		}, time.Unix(unix, 0).Format(time.RFC3339), createdAtHeader, ctx.CreatedAt},
+		{historyContext{
+			h:     types.ImageHistory{CreatedBy: "/bin/sh -c apt-get update && apt-get install -y\tcurl"},
+			trunc: true,
+		}, "/bin/sh -c apt-get update && apt-get install ", createdByHeader, ctx.CreatedBy},
+		{historyContext{
+			h:     types.ImageHistory{CreatedBy: "/bin/sh -c apt-get update && apt-get install -y\tcurl"},
+			trunc: false,
+		}, "/bin/sh -c apt-get update && apt-get install -y curl", createdByHeader, ctx.CreatedBy},
+		{historyContext{
+			h:     types.ImageHistory{Size: 10},
+			human: true,
+		}, "10 B", sizeHeader, ctx.Size},
+		{historyContext{
+			h:     types.ImageHistory{Size: 10},
+			human: false,
+		}, "10", sizeHeader, ctx.Size},
+		{historyContext{
+			h:     types.ImageHistory{DiskSize: 4096},
+			human: true,
+		}, "4.096 kB", diskSizeHeader, ctx.DiskSize},
+		{historyContext{
+			h: types.ImageHistory{Comment: "a comment"},
+		}, "a comment", commentHeader, ctx.Comment},
+		{historyContext{
+			h: types.ImageHistory{DiffID: "sha256:d149ab53f8718e987c3a3024bb8aa0e2caadf6c0328f1d9d850b2a2a67f2819a"},
+		}, "sha256:d149ab53f8718e987c3a3024bb8aa0e2caadf6c0328f1d9d850b2a2a67f2819a", diffIDHeader, ctx.DiffID},
+		{historyContext{
+			h: types.ImageHistory{},
+		}, "<none>", diffIDHeader, ctx.DiffID},
+		{historyContext{
+			h: types.ImageHistory{Digests: []string{"sha256:cbbf2f9a", "sha256:8ac8bfaf"}},
+		}, "sha256:cbbf2f9a,sha256:8ac8bfaf", digestsHeader, ctx.Digests},
+	}
+
+	for _, c := range cases {
+		ctx = c.historyCtx
+		v := c.call()
+		if v != c.expValue {
+			t.Fatalf("Expected %s, was %s\n", c.expValue, v)
+		}
+
+		h := ctx.fullHeader()
+		if h != c.expHeader {
+			t.Fatalf("Expected %s, was %s\n", c.expHeader, h)
+		}
+	}
+}
+
 func compareMultipleValues(t *testing.T, value, expected string) {
 	// comma-separated values means probably a map input, which won't
 	// be guaranteed to have the same order as our expected value
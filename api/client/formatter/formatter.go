@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/utils/templates"
 	"github.com/docker/engine-api/types"
+	registrytypes "github.com/docker/engine-api/types/registry"
 )
 
 const (
@@ -21,6 +22,9 @@ const (
 	defaultImageTableFormat           = "table {{.Repository}}\t{{.Tag}}\t{{.ID}}\t{{.CreatedSince}} ago\t{{.Size}}"
 	defaultImageTableFormatWithDigest = "table {{.Repository}}\t{{.Tag}}\t{{.Digest}}\t{{.ID}}\t{{.CreatedSince}} ago\t{{.Size}}"
 	defaultQuietFormat                = "{{.ID}}"
+	defaultSearchTableFormat          = "table {{.Name}}\t{{.Description}}\t{{.StarCount}}\t{{.IsOfficial}}\t{{.IsAutomated}}"
+	defaultVolumeQuietFormat          = "{{.Name}}"
+	defaultVolumeTableFormat          = "table {{.Driver}}\t{{.Name}}"
 )
 
 // Context contains information required by the formatter to print the output as desired.
@@ -111,6 +115,33 @@ type ImageContext struct {
 	Images []types.Image
 }
 
+// HistoryContext contains image history specific information required by the formater, encapsulate a Context struct.
+type HistoryContext struct {
+	Context
+	// Human when set to true will display sizes and dates in human readable format.
+	Human bool
+	// Digest when set to true will display the digest of the layer created by each step.
+	Digest bool
+	// DiskSize when set to true will display the disk space used by the layer created by each step.
+	DiskSize bool
+	// History
+	History []types.ImageHistory
+}
+
+// SearchContext contains search specific information required by the formater, encapsulate a Context struct.
+type SearchContext struct {
+	Context
+	// Results
+	Results []registrytypes.SearchResult
+}
+
+// VolumeContext contains volume specific information required by the formater, encapsulate a Context struct.
+type VolumeContext struct {
+	Context
+	// Volumes
+	Volumes []*types.Volume
+}
+
 func (ctx ContainerContext) Write() {
 	switch ctx.Format {
 	case tableFormatKey:
@@ -305,3 +336,129 @@ virtual_size: {{.Size}}
 
 	ctx.postformat(tmpl, &imageContext{})
 }
+
+func (ctx HistoryContext) Write() {
+	switch ctx.Format {
+	case tableFormatKey:
+		if ctx.Quiet {
+			ctx.Format = defaultQuietFormat
+			break
+		}
+		ctx.Format = `table {{.ID}}`
+		if ctx.Digest {
+			ctx.Format += `\t{{.DiffID}}`
+		}
+		if ctx.Human {
+			ctx.Format += `\t{{.CreatedSince}} ago`
+		} else {
+			ctx.Format += `\t{{.CreatedSince}}`
+		}
+		ctx.Format += `\t{{.CreatedBy}}\t{{.Size}}`
+		if ctx.DiskSize {
+			ctx.Format += `\t{{.DiskSize}}`
+		}
+		ctx.Format += `\t{{.Comment}}`
+	case rawFormatKey:
+		if ctx.Quiet {
+			ctx.Format = `image_id: {{.ID}}`
+			break
+		}
+		ctx.Format = `image_id: {{.ID}}\ncreated_at: {{.CreatedAt}}\ncreated_by: {{.CreatedBy}}\nsize: {{.Size}}\n`
+		if ctx.DiskSize {
+			ctx.Format += `disk_size: {{.DiskSize}}\n`
+		}
+		if ctx.Digest {
+			ctx.Format += `diff_id: {{.DiffID}}\ndigests: {{.Digests}}\n`
+		}
+		ctx.Format += `comment: {{.Comment}}\n`
+	}
+
+	ctx.buffer = bytes.NewBufferString("")
+	ctx.preformat()
+
+	tmpl, err := ctx.parseFormat()
+	if err != nil {
+		return
+	}
+
+	for _, h := range ctx.History {
+		historyCtx := &historyContext{
+			trunc: ctx.Trunc,
+			human: ctx.Human,
+			h:     h,
+		}
+		err = ctx.contextFormat(tmpl, historyCtx)
+		if err != nil {
+			return
+		}
+	}
+
+	ctx.postformat(tmpl, &historyContext{})
+}
+
+func (ctx SearchContext) Write() {
+	switch ctx.Format {
+	case tableFormatKey:
+		ctx.Format = defaultSearchTableFormat
+	case rawFormatKey:
+		ctx.Format = `name: {{.Name}}\ndescription: {{.Description}}\nstar_count: {{.StarCount}}\nis_official: {{.IsOfficial}}\nis_automated: {{.IsAutomated}}\n`
+	}
+
+	ctx.buffer = bytes.NewBufferString("")
+	ctx.preformat()
+
+	tmpl, err := ctx.parseFormat()
+	if err != nil {
+		return
+	}
+
+	for _, result := range ctx.Results {
+		searchCtx := &searchContext{
+			trunc: ctx.Trunc,
+			s:     result,
+		}
+		err = ctx.contextFormat(tmpl, searchCtx)
+		if err != nil {
+			return
+		}
+	}
+
+	ctx.postformat(tmpl, &searchContext{})
+}
+
+func (ctx VolumeContext) Write() {
+	switch ctx.Format {
+	case tableFormatKey:
+		if ctx.Quiet {
+			ctx.Format = defaultVolumeQuietFormat
+		} else {
+			ctx.Format = defaultVolumeTableFormat
+		}
+	case rawFormatKey:
+		if ctx.Quiet {
+			ctx.Format = `name: {{.Name}}`
+		} else {
+			ctx.Format = `name: {{.Name}}\ndriver: {{.Driver}}\nmountpoint: {{.Mountpoint}}\nlabels: {{.Labels}}\n`
+		}
+	}
+
+	ctx.buffer = bytes.NewBufferString("")
+	ctx.preformat()
+
+	tmpl, err := ctx.parseFormat()
+	if err != nil {
+		return
+	}
+
+	for _, volume := range ctx.Volumes {
+		volumeCtx := &volumeContext{
+			v: volume,
+		}
+		err = ctx.contextFormat(tmpl, volumeCtx)
+		if err != nil {
+			return
+		}
+	}
+
+	ctx.postformat(tmpl, &volumeContext{v: &types.Volume{}})
+}
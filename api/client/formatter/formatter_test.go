@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/docker/engine-api/types"
+	registrytypes "github.com/docker/engine-api/types/registry"
 )
 
 func TestContainerContextWrite(t *testing.T) {
@@ -535,3 +536,270 @@ func TestImageContextWriteWithNoImage(t *testing.T) {
 		out.Reset()
 	}
 }
+
+func TestHistoryContextWrite(t *testing.T) {
+	unixTime := time.Now().AddDate(0, 0, -1).Unix()
+	expectedTime := time.Unix(unixTime, 0).Format(time.RFC3339)
+
+	contexts := []struct {
+		context  HistoryContext
+		expected string
+	}{
+		// Table Format
+		{
+			HistoryContext{
+				Context: Context{
+					Format: "table",
+				},
+				Human: true,
+			},
+			`IMAGE               CREATED             CREATED BY          SIZE                COMMENT
+imageID2            24 hours ago        /bin/sh -c #(nop)   0 B                 
+<missing>           24 hours ago        /bin/sh -c echo     1 kB                a comment
+`,
+		},
+		{
+			HistoryContext{
+				Context: Context{
+					Format: "table",
+				},
+				Human:    true,
+				Digest:   true,
+				DiskSize: true,
+			},
+			`IMAGE               DIFF ID             CREATED             CREATED BY          SIZE                DISK SIZE           COMMENT
+imageID2            <none>              24 hours ago        /bin/sh -c #(nop)   0 B                 0 B                 
+<missing>           sha256:d149ab53     24 hours ago        /bin/sh -c echo     1 kB                4.096 kB            a comment
+`,
+		},
+		{
+			HistoryContext{
+				Context: Context{
+					Format: "table",
+					Quiet:  true,
+				},
+			},
+			"imageID2\n<missing>\n",
+		},
+		// Raw Format
+		{
+			HistoryContext{
+				Context: Context{
+					Format: "raw",
+				},
+				Digest: true,
+			},
+			fmt.Sprintf(`image_id: imageID2
+created_at: %s
+created_by: /bin/sh -c #(nop)
+size: 0
+diff_id: <none>
+digests: <none>
+comment: 
+
+image_id: <missing>
+created_at: %s
+created_by: /bin/sh -c echo
+size: 1000
+diff_id: sha256:d149ab53
+digests: sha256:cbbf2f9a,sha256:8ac8bfaf
+comment: a comment
+
+`, expectedTime, expectedTime),
+		},
+		// Custom Format
+		{
+			HistoryContext{
+				Context: Context{
+					Format: "{{.ID}} {{.Tags}}",
+				},
+			},
+			"imageID2 image:tag1,image:tag2\n<missing> \n",
+		},
+	}
+
+	for _, context := range contexts {
+		history := []types.ImageHistory{
+			{ID: "imageID2", Created: unixTime, CreatedBy: "/bin/sh -c #(nop)", Tags: []string{"image:tag1", "image:tag2"}},
+			{ID: "<missing>", Created: unixTime, CreatedBy: "/bin/sh -c echo", Size: 1000, DiskSize: 4096, Comment: "a comment", DiffID: "sha256:d149ab53", Digests: []string{"sha256:cbbf2f9a", "sha256:8ac8bfaf"}},
+		}
+		out := bytes.NewBufferString("")
+		context.context.Output = out
+		context.context.History = history
+		context.context.Write()
+		actual := out.String()
+		if actual != context.expected {
+			t.Fatalf("Expected \n%s, got \n%s", context.expected, actual)
+		}
+	}
+}
+
+func TestSearchContextWrite(t *testing.T) {
+	contexts := []struct {
+		context  SearchContext
+		expected string
+	}{
+		// Table Format
+		{
+			SearchContext{
+				Context: Context{
+					Format: "table",
+					Trunc:  true,
+				},
+			},
+			`NAME                 DESCRIPTION                                     STARS               OFFICIAL            AUTOMATED
+busybox              Busybox base image.                             1000                [OK]                
+myregistry/busybox   An automated build of busybox, with a desc...   3                                       [OK]
+`,
+		},
+		// Raw Format
+		{
+			SearchContext{
+				Context: Context{
+					Format: "raw",
+				},
+			},
+			`name: busybox
+description: Busybox base image.
+star_count: 1000
+is_official: [OK]
+is_automated: 
+
+name: myregistry/busybox
+description: An automated build of busybox, with a description longer than the table
+star_count: 3
+is_official: 
+is_automated: [OK]
+
+`,
+		},
+		// Custom Format
+		{
+			SearchContext{
+				Context: Context{
+					Format: "{{json .}}",
+				},
+			},
+			`{"star_count":1000,"is_official":true,"name":"busybox","is_automated":false,"description":"Busybox base image."}
+{"star_count":3,"is_official":false,"name":"myregistry/busybox","is_automated":true,"description":"An automated build of busybox,\nwith a description longer than the table"}
+`,
+		},
+	}
+
+	for _, context := range contexts {
+		results := []registrytypes.SearchResult{
+			{Name: "busybox", Description: "Busybox base image.", StarCount: 1000, IsOfficial: true},
+			{Name: "myregistry/busybox", Description: "An automated build of busybox,\nwith a description longer than the table", StarCount: 3, IsAutomated: true},
+		}
+		out := bytes.NewBufferString("")
+		context.context.Output = out
+		context.context.Results = results
+		context.context.Write()
+		actual := out.String()
+		if actual != context.expected {
+			t.Fatalf("Expected \n%s, got \n%s", context.expected, actual)
+		}
+	}
+}
+
+func TestVolumeContextWrite(t *testing.T) {
+	contexts := []struct {
+		context  VolumeContext
+		expected string
+	}{
+		// Table Format
+		{
+			VolumeContext{
+				Context: Context{
+					Format: "table",
+				},
+			},
+			`DRIVER              VOLUME NAME
+local               data
+flocker             shared
+`,
+		},
+		{
+			VolumeContext{
+				Context: Context{
+					Format: "table",
+					Quiet:  true,
+				},
+			},
+			`data
+shared
+`,
+		},
+		{
+			VolumeContext{
+				Context: Context{
+					Format: "table {{.Name}}\t{{.Label \"com.example.tier\"}}",
+				},
+			},
+			`VOLUME NAME         TIER
+data                db
+shared              
+`,
+		},
+		// Raw Format
+		{
+			VolumeContext{
+				Context: Context{
+					Format: "raw",
+				},
+			},
+			`name: data
+driver: local
+mountpoint: /var/lib/docker/volumes/data/_data
+labels: com.example.tier=db
+
+name: shared
+driver: flocker
+mountpoint: /flocker/shared
+labels: 
+
+`,
+		},
+		// Custom Format
+		{
+			VolumeContext{
+				Context: Context{
+					Format: "{{truncate .Name 3}} {{upper .Driver}}",
+				},
+			},
+			`dat LOCAL
+sha FLOCKER
+`,
+		},
+	}
+
+	for _, context := range contexts {
+		volumes := []*types.Volume{
+			{Name: "data", Driver: "local", Mountpoint: "/var/lib/docker/volumes/data/_data", Labels: map[string]string{"com.example.tier": "db"}},
+			{Name: "shared", Driver: "flocker", Mountpoint: "/flocker/shared"},
+		}
+		out := bytes.NewBufferString("")
+		context.context.Output = out
+		context.context.Volumes = volumes
+		context.context.Write()
+		actual := out.String()
+		if actual != context.expected {
+			t.Fatalf("Expected \n%s, got \n%s", context.expected, actual)
+		}
+	}
+}
+
+func TestVolumeContextWriteWithNoVolumes(t *testing.T) {
+	out := bytes.NewBufferString("")
+	context := VolumeContext{
+		Context: Context{
+			Format: "table {{.Name}}\t{{.Mountpoint}}",
+			Output: out,
+		},
+	}
+	context.Write()
+	expected := "VOLUME NAME         MOUNTPOINT\n"
+	if actual := out.String(); actual != expected {
+		t.Fatalf("Expected \n%s, got \n%s", expected, actual)
+	}
+}
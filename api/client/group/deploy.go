@@ -0,0 +1,92 @@
+package group
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type deployOptions struct {
+	file string
+	name string
+}
+
+func newDeployCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts deployOptions
+
+	cmd := &cobra.Command{
+		Use:   "deploy [OPTIONS] FILE",
+		Short: "Create and start a group of containers from a bundle file",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.file = args[0]
+			return runDeploy(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.name, "name", "", "Group name (default: the name of the file without its extension)")
+
+	return cmd
+}
+
+func runDeploy(dockerCli *client.DockerCli, opts deployOptions) error {
+	f, err := os.Open(opts.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := loadBundle(f)
+	if err != nil {
+		return fmt.Errorf("Error reading %s: %v", opts.file, err)
+	}
+	req.Name = opts.name
+	if req.Name == "" {
+		base := filepath.Base(opts.file)
+		req.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	group, err := dockerCli.Client().GroupCreate(context.Background(), *req)
+	if err != nil {
+		return err
+	}
+	for _, n := range group.Networks {
+		fmt.Fprintf(dockerCli.Out(), "Created network %s\n", n)
+	}
+	for _, v := range group.Volumes {
+		fmt.Fprintf(dockerCli.Out(), "Created volume %s\n", v)
+	}
+	for _, c := range group.Containers {
+		fmt.Fprintf(dockerCli.Out(), "Started container %s\n", c)
+	}
+	return nil
+}
+
+// loadBundle reads the containers, networks and volumes of a group from a
+// bundle file.
+func loadBundle(r io.Reader) (*types.GroupCreateRequest, error) {
+	var req types.GroupCreateRequest
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		switch jsonErr := err.(type) {
+		case *json.SyntaxError:
+			return nil, fmt.Errorf("JSON syntax error at byte %v: %s", jsonErr.Offset, jsonErr.Error())
+		case *json.UnmarshalTypeError:
+			return nil, fmt.Errorf("Unexpected type at byte %v. Expected %s but received %s.", jsonErr.Offset, jsonErr.Type, jsonErr.Value)
+		}
+		return nil, err
+	}
+	if len(req.Containers) == 0 {
+		return nil, fmt.Errorf("the bundle has no container")
+	}
+	return &req, nil
+}
@@ -0,0 +1,34 @@
+package group
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadBundle(t *testing.T) {
+	req, err := loadBundle(strings.NewReader(`{
+		"Containers": {
+			"web": {"Image": "nginx", "Ports": [{"Protocol": "tcp", "Port": 80, "PublishedPort": 8080}], "Requires": ["db"]},
+			"db": {"Image": "postgres", "Volumes": ["data:/var/lib/postgresql/data"]}
+		},
+		"Volumes": ["data"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Containers) != 2 || req.Containers["web"].Ports[0].PublishedPort != 8080 || req.Containers["db"].Volumes[0] != "data:/var/lib/postgresql/data" {
+		t.Fatalf("unexpected bundle %+v", req)
+	}
+}
+
+func TestLoadBundleInvalid(t *testing.T) {
+	for content, expected := range map[string]string{
+		`{"Containers": {}}`:                    "the bundle has no container",
+		`{"Containers": {"web": {"Image": 1}}}`: "Unexpected type",
+		`{"Containers": `:                       "EOF",
+	} {
+		if _, err := loadBundle(strings.NewReader(content)); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected an error containing %q for %s, got %v", expected, content, err)
+		}
+	}
+}
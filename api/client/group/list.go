@@ -0,0 +1,58 @@
+package group
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet bool
+}
+
+func newListCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List groups",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display group names")
+
+	return cmd
+}
+
+func runList(dockerCli *client.DockerCli, opts listOptions) error {
+	groups, err := dockerCli.Client().GroupList(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if opts.quiet {
+		for _, g := range groups {
+			fmt.Fprintln(dockerCli.Out(), g.Name)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "NAME\tCONTAINERS\tNETWORKS\tVOLUMES\n")
+	for _, g := range groups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", g.Name, strings.Join(g.Containers, ","), strings.Join(g.Networks, ","), strings.Join(g.Volumes, ","))
+	}
+	w.Flush()
+	return nil
+}
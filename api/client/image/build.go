@@ -50,6 +50,14 @@ type buildOptions struct {
 	cpuSetMems     string
 	cgroupParent   string
 	isolation      string
+	target         string
+	platform       string
+	squash         bool
+	cacheFrom      []string
+	noOnBuild      bool
+	onBuildAllow   []string
+	secrets        secretOpt
+	ssh            sshOpt
 	quiet          bool
 	noCache        bool
 	rm             bool
@@ -92,12 +100,20 @@ func NewBuildCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.StringVar(&options.cpuSetMems, "cpuset-mems", "", "MEMs in which to allow execution (0-3, 0,1)")
 	flags.StringVar(&options.cgroupParent, "cgroup-parent", "", "Optional parent cgroup for the container")
 	flags.StringVar(&options.isolation, "isolation", "", "Container isolation technology")
+	flags.StringVar(&options.target, "target", "", "Set the target build stage to build")
+	flags.StringVar(&options.platform, "platform", "", "Set the platform of the base images pulled from manifest lists (format: os[/arch[/variant]])")
+	flags.StringSliceVar(&options.cacheFrom, "cache-from", []string{}, "Images to consider as cache sources")
+	flags.BoolVar(&options.noOnBuild, "no-onbuild", false, "Do not run the ONBUILD triggers of the base images")
+	flags.StringSliceVar(&options.onBuildAllow, "onbuild-allow", []string{}, "Only run the ONBUILD triggers of the given instructions")
+	flags.Var(&options.secrets, "secret", "Secret file to expose to RUN instructions (format: \"id=mysecret,src=/local/secret\")")
+	flags.Var(&options.ssh, "ssh", "SSH agent socket to expose to RUN instructions (format: default|<id>[=<socket>])")
 	flags.StringSliceVar(&options.labels, "label", []string{}, "Set metadata for an image")
 	flags.BoolVar(&options.noCache, "no-cache", false, "Do not use cache when building the image")
 	flags.BoolVar(&options.rm, "rm", true, "Remove intermediate containers after a successful build")
 	flags.BoolVar(&options.forceRm, "force-rm", false, "Always remove intermediate containers")
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the build output and print image ID on success")
 	flags.BoolVar(&options.pull, "pull", false, "Always attempt to pull a newer version of the image")
+	flags.BoolVar(&options.squash, "squash", false, "Squash newly built layers into a single new layer")
 
 	client.AddTrustedFlags(flags, true)
 
@@ -128,6 +144,14 @@ func runBuild(dockerCli *client.DockerCli, options buildOptions) error {
 		buildBuff = bytes.NewBuffer(nil)
 	}
 
+	var dockerfileCtx io.ReadCloser
+	if options.dockerfileName == builder.DockerfileFromStdin {
+		if specifiedContext == "-" {
+			return fmt.Errorf("invalid argument: can't use stdin for both build context and dockerfile")
+		}
+		dockerfileCtx = dockerCli.In()
+	}
+
 	switch {
 	case specifiedContext == "-":
 		buildCtx, relDockerfile, err = builder.GetContextFromReader(dockerCli.In(), options.dockerfileName)
@@ -185,7 +209,13 @@ func runBuild(dockerCli *client.DockerCli, options buildOptions) error {
 		var includes = []string{"."}
 		keepThem1, _ := fileutils.Matches(".dockerignore", excludes)
 		keepThem2, _ := fileutils.Matches(relDockerfile, excludes)
-		if keepThem1 || keepThem2 {
+		if dockerfileCtx != nil {
+			// The Dockerfile read from stdin is added to the context below,
+			// and to the .dockerignore, which must then be sent as well.
+			if keepThem1 {
+				includes = append(includes, ".dockerignore")
+			}
+		} else if keepThem1 || keepThem2 {
 			includes = append(includes, ".dockerignore", relDockerfile)
 		}
 
@@ -199,6 +229,14 @@ func runBuild(dockerCli *client.DockerCli, options buildOptions) error {
 		}
 	}
 
+	// The Dockerfile was read from stdin, add it to the context archive
+	if dockerfileCtx != nil {
+		buildCtx, relDockerfile, err = builder.AddDockerfileToBuildContext(dockerfileCtx, buildCtx)
+		if err != nil {
+			return err
+		}
+	}
+
 	ctx := context.Background()
 
 	var resolvedTags []*resolvedTag
@@ -265,6 +303,14 @@ func runBuild(dockerCli *client.DockerCli, options buildOptions) error {
 		BuildArgs:      runconfigopts.ConvertKVStringsToMap(options.buildArgs.GetAll()),
 		AuthConfigs:    dockerCli.RetrieveAuthConfigs(),
 		Labels:         runconfigopts.ConvertKVStringsToMap(options.labels),
+		Target:         options.target,
+		Platform:       options.platform,
+		Squash:         options.squash,
+		CacheFrom:      options.cacheFrom,
+		NoOnBuild:      options.noOnBuild,
+		OnBuildAllow:   options.onBuildAllow,
+		Secrets:        options.secrets.Value(),
+		SSHAgents:      options.ssh.Value(),
 	}
 
 	response, err := dockerCli.Client().ImageBuild(ctx, body, buildOptions)
@@ -1,28 +1,26 @@
 package image
 
 import (
-	"fmt"
-	"strconv"
 	"strings"
-	"text/tabwriter"
-	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/api/client/formatter"
 	"github.com/docker/docker/cli"
-	"github.com/docker/docker/pkg/stringid"
-	"github.com/docker/docker/pkg/stringutils"
-	"github.com/docker/go-units"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type historyOptions struct {
 	image string
 
-	human   bool
-	quiet   bool
-	noTrunc bool
+	human    bool
+	quiet    bool
+	noTrunc  bool
+	digests  bool
+	diskSize bool
+	format   string
 }
 
 // NewHistoryCommand create a new `docker history` command
@@ -44,6 +42,9 @@ func NewHistoryCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.BoolVarP(&opts.human, "human", "H", true, "Print sizes and dates in human readable format")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show numeric IDs")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.BoolVar(&opts.digests, "digests", false, "Show the digests of the layers")
+	flags.BoolVar(&opts.diskSize, "disk-size", false, "Show the disk space used by the layers")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print history using a Go template")
 
 	return cmd
 }
@@ -51,49 +52,34 @@ func NewHistoryCommand(dockerCli *client.DockerCli) *cobra.Command {
 func runHistory(dockerCli *client.DockerCli, opts historyOptions) error {
 	ctx := context.Background()
 
-	history, err := dockerCli.Client().ImageHistory(ctx, opts.image)
+	// The disk size is only computed by the daemon if it is displayed
+	options := types.ImageHistoryOptions{
+		DiskSize: opts.diskSize || strings.Contains(opts.format, ".DiskSize"),
+	}
+	history, err := dockerCli.Client().ImageHistory(ctx, opts.image, options)
 	if err != nil {
 		return err
 	}
 
-	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
-
-	if opts.quiet {
-		for _, entry := range history {
-			if opts.noTrunc {
-				fmt.Fprintf(w, "%s\n", entry.ID)
-			} else {
-				fmt.Fprintf(w, "%s\n", stringid.TruncateID(entry.ID))
-			}
-		}
-		w.Flush()
-		return nil
+	f := opts.format
+	if len(f) == 0 {
+		f = "table"
 	}
 
-	var imageID string
-	var createdBy string
-	var created string
-	var size string
-
-	fmt.Fprintln(w, "IMAGE\tCREATED\tCREATED BY\tSIZE\tCOMMENT")
-	for _, entry := range history {
-		imageID = entry.ID
-		createdBy = strings.Replace(entry.CreatedBy, "\t", " ", -1)
-		if opts.noTrunc == false {
-			createdBy = stringutils.Truncate(createdBy, 45)
-			imageID = stringid.TruncateID(entry.ID)
-		}
-
-		if opts.human {
-			created = units.HumanDuration(time.Now().UTC().Sub(time.Unix(entry.Created, 0))) + " ago"
-			size = units.HumanSize(float64(entry.Size))
-		} else {
-			created = time.Unix(entry.Created, 0).Format(time.RFC3339)
-			size = strconv.FormatInt(entry.Size, 10)
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", imageID, created, createdBy, size, entry.Comment)
+	historyCtx := formatter.HistoryContext{
+		Context: formatter.Context{
+			Output: dockerCli.Out(),
+			Format: f,
+			Quiet:  opts.quiet,
+			Trunc:  !opts.noTrunc,
+		},
+		Human:    opts.human,
+		Digest:   opts.digests,
+		DiskSize: opts.diskSize,
+		History:  history,
 	}
-	w.Flush()
+
+	historyCtx.Write()
+
 	return nil
 }
@@ -0,0 +1,136 @@
+package image
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// secretOpt is a Value type for parsing build secrets given as
+// `id=<id>,src=<path>`.
+type secretOpt struct {
+	values map[string][]byte
+}
+
+// Set a new secret value
+func (s *secretOpt) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+
+	var id, src string
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+		key, value := parts[0], parts[1]
+		switch strings.ToLower(key) {
+		case "id":
+			id = value
+		case "src", "source":
+			src = value
+		default:
+			return fmt.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	if id == "" {
+		return fmt.Errorf("secret id is required")
+	}
+	if strings.ContainsAny(id, "/\\") || id == "." || id == ".." {
+		return fmt.Errorf("invalid secret id %q", id)
+	}
+	if src == "" {
+		src = id
+	}
+	if _, exists := s.values[id]; exists {
+		return fmt.Errorf("duplicate secret id %q", id)
+	}
+
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read secret %s: %v", id, err)
+	}
+	if s.values == nil {
+		s.values = make(map[string][]byte)
+	}
+	s.values[id] = data
+	return nil
+}
+
+// Type returns the type of this option
+func (s *secretOpt) Type() string {
+	return "secret"
+}
+
+// String returns a string repr of this option
+func (s *secretOpt) String() string {
+	var ids []string
+	for id := range s.values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
+
+// Value returns the secrets content keyed by id
+func (s *secretOpt) Value() map[string][]byte {
+	return s.values
+}
+
+// sshOpt is a Value type for parsing SSH agent sockets to forward to a
+// build, given as `default`, `<id>` or `<id>=<socket path>`. When no path is
+// given the socket from the SSH_AUTH_SOCK environment variable is used.
+type sshOpt struct {
+	values map[string]string
+}
+
+// Set a new ssh agent value
+func (s *sshOpt) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	id := parts[0]
+	if id == "" || strings.ContainsAny(id, "/\\") {
+		return fmt.Errorf("invalid ssh agent id %q", id)
+	}
+
+	var path string
+	if len(parts) == 2 {
+		path = parts[1]
+	} else {
+		path = os.Getenv("SSH_AUTH_SOCK")
+		if path == "" {
+			return fmt.Errorf("invalid empty ssh agent socket, make sure SSH_AUTH_SOCK is set")
+		}
+	}
+	if s.values == nil {
+		s.values = make(map[string]string)
+	}
+	s.values[id] = path
+	return nil
+}
+
+// Type returns the type of this option
+func (s *sshOpt) Type() string {
+	return "ssh"
+}
+
+// String returns a string repr of this option
+func (s *sshOpt) String() string {
+	var ids []string
+	for id, path := range s.values {
+		ids = append(ids, id+"="+path)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ", ")
+}
+
+// Value returns the agent socket paths keyed by id
+func (s *sshOpt) Value() map[string]string {
+	return s.values
+}
@@ -0,0 +1,73 @@
+package image
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretOptSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build-secret-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(src, []byte("s3cr3t"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var opt secretOpt
+	if err := opt.Set("id=token,src=" + src); err != nil {
+		t.Fatal(err)
+	}
+	if string(opt.Value()["token"]) != "s3cr3t" {
+		t.Fatalf("unexpected secret value: %q", opt.Value()["token"])
+	}
+	if err := opt.Set("id=token,src=" + src); err == nil {
+		t.Fatal("expected an error for a duplicate secret id")
+	}
+}
+
+func TestSecretOptSetInvalid(t *testing.T) {
+	for _, value := range []string{
+		"src=/tmp/foo",
+		"id=../foo,src=/tmp/foo",
+		"id=foo,unknown=bar",
+		"id",
+		"id=foo,src=/this/path/does/not/exist",
+	} {
+		var opt secretOpt
+		if err := opt.Set(value); err == nil {
+			t.Fatalf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestSSHOptSet(t *testing.T) {
+	var opt sshOpt
+	if err := opt.Set("github=/tmp/agent.sock"); err != nil {
+		t.Fatal(err)
+	}
+	if opt.Value()["github"] != "/tmp/agent.sock" {
+		t.Fatalf("unexpected socket path: %q", opt.Value()["github"])
+	}
+
+	oldSock := os.Getenv("SSH_AUTH_SOCK")
+	defer os.Setenv("SSH_AUTH_SOCK", oldSock)
+
+	os.Setenv("SSH_AUTH_SOCK", "/run/user/agent.sock")
+	if err := opt.Set("default"); err != nil {
+		t.Fatal(err)
+	}
+	if opt.Value()["default"] != "/run/user/agent.sock" {
+		t.Fatalf("unexpected default socket path: %q", opt.Value()["default"])
+	}
+
+	os.Setenv("SSH_AUTH_SOCK", "")
+	if err := opt.Set("other"); err == nil {
+		t.Fatal("expected an error when SSH_AUTH_SOCK is not set")
+	}
+}
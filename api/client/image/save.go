@@ -8,12 +8,16 @@ import (
 
 	"github.com/docker/docker/api/client"
 	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
-	images []string
-	output string
+	images      []string
+	output      string
+	format      string
+	exclude     []string
+	compression string
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -33,6 +37,9 @@ func NewSaveCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVar(&opts.format, "format", "docker", "Layout of the archive, \"docker\" or \"oci\"")
+	flags.StringSliceVar(&opts.exclude, "exclude", []string{}, "Do not save the layers of an image, which must be present where the archive is loaded")
+	flags.StringVar(&opts.compression, "compression", "none", "Compression of the archive, \"none\" or \"gzip\"")
 
 	return cmd
 }
@@ -42,7 +49,11 @@ func runSave(dockerCli *client.DockerCli, opts saveOptions) error {
 		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
 	}
 
-	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images)
+	responseBody, err := dockerCli.Client().ImageSave(context.Background(), opts.images, types.ImageSaveOptions{
+		Format:      opts.format,
+		Exclude:     opts.exclude,
+		Compression: opts.compression,
+	})
 	if err != nil {
 		return err
 	}
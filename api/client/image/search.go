@@ -1,16 +1,13 @@
 package image
 
 import (
-	"fmt"
 	"sort"
-	"strings"
-	"text/tabwriter"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/api/client/formatter"
 	"github.com/docker/docker/cli"
-	"github.com/docker/docker/pkg/stringutils"
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/filters"
@@ -23,6 +20,7 @@ type searchOptions struct {
 	noTrunc bool
 	limit   int
 	filter  []string
+	format  string
 
 	// Deprecated
 	stars     uint
@@ -48,6 +46,7 @@ func NewSearchCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.StringSliceVarP(&opts.filter, "filter", "f", []string{}, "Filter output based on conditions provided")
 	flags.IntVar(&opts.limit, "limit", registry.DefaultSearchLimit, "Max number of search results")
+	flags.StringVar(&opts.format, "format", "", "Pretty-print search results using a Go template")
 
 	flags.BoolVar(&opts.automated, "automated", false, "Only show automated builds")
 	flags.UintVarP(&opts.stars, "stars", "s", 0, "Only displays with at least x stars")
@@ -97,33 +96,33 @@ func runSearch(dockerCli *client.DockerCli, opts searchOptions) error {
 		return err
 	}
 
-	results := searchResultsByStars(unorderedResults)
-	sort.Sort(results)
+	sort.Sort(searchResultsByStars(unorderedResults))
 
-	w := tabwriter.NewWriter(dockerCli.Out(), 10, 1, 3, ' ', 0)
-	fmt.Fprintf(w, "NAME\tDESCRIPTION\tSTARS\tOFFICIAL\tAUTOMATED\n")
-	for _, res := range results {
+	results := []registrytypes.SearchResult{}
+	for _, res := range unorderedResults {
 		// --automated and -s, --stars are deprecated since Docker 1.12
 		if (opts.automated && !res.IsAutomated) || (int(opts.stars) > res.StarCount) {
 			continue
 		}
-		desc := strings.Replace(res.Description, "\n", " ", -1)
-		desc = strings.Replace(desc, "\r", " ", -1)
-		if !opts.noTrunc && len(desc) > 45 {
-			desc = stringutils.Truncate(desc, 42) + "..."
-		}
-		fmt.Fprintf(w, "%s\t%s\t%d\t", res.Name, desc, res.StarCount)
-		if res.IsOfficial {
-			fmt.Fprint(w, "[OK]")
+		results = append(results, res)
+	}
 
-		}
-		fmt.Fprint(w, "\t")
-		if res.IsAutomated {
-			fmt.Fprint(w, "[OK]")
-		}
-		fmt.Fprint(w, "\n")
+	f := opts.format
+	if len(f) == 0 {
+		f = "table"
 	}
-	w.Flush()
+
+	searchCtx := formatter.SearchContext{
+		Context: formatter.Context{
+			Output: dockerCli.Out(),
+			Format: f,
+			Trunc:  !opts.noTrunc,
+		},
+		Results: results,
+	}
+
+	searchCtx.Write()
+
 	return nil
 }
 
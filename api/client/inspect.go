@@ -1,6 +1,7 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"golang.org/x/net/context"
@@ -8,9 +9,15 @@ import (
 	"github.com/docker/docker/api/client/inspect"
 	Cli "github.com/docker/docker/cli"
 	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/runconfig"
 	"github.com/docker/engine-api/client"
 )
 
+// configTemplateFormat is the value of --format to output the configuration
+// templates of containers, to create other containers with `docker create
+// --from-template`.
+const configTemplateFormat = "config-template"
+
 // CmdInspect displays low-level information on one or more containers, images or tasks.
 //
 // Usage: docker inspect [OPTIONS] CONTAINER|IMAGE|TASK [CONTAINER|IMAGE|TASK...]
@@ -29,6 +36,13 @@ func (cli *DockerCli) CmdInspect(args ...string) error {
 
 	ctx := context.Background()
 
+	if *tmplStr == configTemplateFormat {
+		if *inspectType != "" && *inspectType != "container" {
+			return fmt.Errorf("--format=%s is only valid for containers", configTemplateFormat)
+		}
+		return cli.inspectConfigTemplates(ctx, cmd.Args())
+	}
+
 	var elementSearcher inspect.GetRefFunc
 	switch *inspectType {
 	case "container":
@@ -53,6 +67,23 @@ func (cli *DockerCli) inspectContainers(ctx context.Context, getSize bool) inspe
 	}
 }
 
+// inspectConfigTemplates writes the configuration templates of containers,
+// one JSON object per container.
+func (cli *DockerCli) inspectConfigTemplates(ctx context.Context, refs []string) error {
+	for _, ref := range refs {
+		c, err := cli.client.ContainerInspect(ctx, ref)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(runconfig.NewConfigTemplate(c, cli.client.ClientVersion()), "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cli.out, "%s\n", b)
+	}
+	return nil
+}
+
 func (cli *DockerCli) inspectImages(ctx context.Context, getSize bool) inspect.GetRefFunc {
 	return func(ref string) (interface{}, []byte, error) {
 		return cli.client.ImageInspectWithRaw(ctx, ref, getSize)
@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type annotateOptions struct {
+	list       string
+	image      string
+	os         string
+	arch       string
+	variant    string
+	osFeatures []string
+}
+
+func newAnnotateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts annotateOptions
+
+	cmd := &cobra.Command{
+		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
+		Short: "Add platform information to a local manifest list entry",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.image = args[1]
+			return runAnnotate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.os, "os", "", "Set operating system")
+	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
+	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
+	return cmd
+}
+
+func runAnnotate(dockerCli *client.DockerCli, opts annotateOptions) error {
+	list, err := parseReference(opts.list)
+	if err != nil {
+		return err
+	}
+	image, err := parseReference(opts.image)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	m, err := s.get(list, image)
+	if err != nil {
+		return err
+	}
+
+	platform := &m.Descriptor.Platform
+	if opts.os != "" {
+		platform.OS = strings.ToLower(opts.os)
+	}
+	if opts.arch != "" {
+		platform.Architecture = strings.ToLower(opts.arch)
+	}
+	if opts.variant != "" {
+		platform.Variant = opts.variant
+	}
+	if len(opts.osFeatures) > 0 {
+		platform.OSFeatures = opts.osFeatures
+	}
+	return s.save(list, image, m)
+}
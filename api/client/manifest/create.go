@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	list      string
+	manifests []string
+	amend     bool
+	insecure  bool
+}
+
+func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] MANIFEST_LIST MANIFEST [MANIFEST...]",
+		Short: "Create a local manifest list for pushing to a registry",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			opts.manifests = args[1:]
+			return runCreate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runCreate(dockerCli *client.DockerCli, opts createOptions) error {
+	list, err := parseReference(opts.list)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	if s.exists(list) && !opts.amend {
+		return fmt.Errorf("refusing to amend an existing manifest list with no --amend flag")
+	}
+
+	ctx := context.Background()
+	for _, name := range opts.manifests {
+		ref, err := parseReference(name)
+		if err != nil {
+			return err
+		}
+		if ref.Hostname() != list.Hostname() {
+			return fmt.Errorf("%s is not on the same registry as %s", ref.String(), list.String())
+		}
+		m, err := fetchImageManifest(ctx, dockerCli, ref, opts.insecure)
+		if err != nil {
+			return err
+		}
+		if err := s.save(list, ref, m); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", list.String())
+	return nil
+}
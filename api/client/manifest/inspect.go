@@ -0,0 +1,108 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	list     string
+	image    string
+	insecure bool
+}
+
+func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] [MANIFEST_LIST] MANIFEST",
+		Short: "Display an image manifest, or manifest list",
+		Long: "Display a local manifest list or one of its entries. Manifests " +
+			"and manifest lists that are not in the local store are fetched " +
+			"from their registry.",
+		Args: cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 2 {
+				opts.list = args[0]
+				opts.image = args[1]
+			} else {
+				opts.image = args[0]
+			}
+			return runInspect(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
+	image, err := parseReference(opts.image)
+	if err != nil {
+		return err
+	}
+
+	s := newStore()
+	var payload []byte
+	switch {
+	case opts.list != "":
+		list, err := parseReference(opts.list)
+		if err != nil {
+			return err
+		}
+		m, err := s.get(list, image)
+		if err != nil {
+			return err
+		}
+		if payload, err = json.Marshal(m.Descriptor); err != nil {
+			return err
+		}
+	case s.exists(image):
+		manifests, err := s.getList(image)
+		if err != nil {
+			return err
+		}
+		list, err := buildManifestList(manifests)
+		if err != nil {
+			return err
+		}
+		if _, payload, err = list.Payload(); err != nil {
+			return err
+		}
+	default:
+		if payload, err = fetchPayload(context.Background(), dockerCli, image, opts.insecure); err != nil {
+			return err
+		}
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, payload, "", "    "); err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), out.String())
+	return nil
+}
+
+// fetchPayload returns the manifest, or manifest list, of ref as stored in
+// its registry.
+func fetchPayload(ctx context.Context, dockerCli *client.DockerCli, ref reference.Named, insecure bool) ([]byte, error) {
+	repo, err := getRepository(ctx, dockerCli, ref, insecure, "pull")
+	if err != nil {
+		return nil, err
+	}
+	m, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	_, payload, err := m.Payload()
+	return payload, err
+}
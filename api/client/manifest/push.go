@@ -0,0 +1,154 @@
+package manifest
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	distreference "github.com/docker/distribution/reference"
+	distclient "github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/spf13/cobra"
+)
+
+type pushOptions struct {
+	list     string
+	purge    bool
+	insecure bool
+}
+
+func newPushCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts pushOptions
+
+	cmd := &cobra.Command{
+		Use:   "push [OPTIONS] MANIFEST_LIST",
+		Short: "Push a manifest list to a repository",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.list = args[0]
+			return runPush(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.purge, "purge", "p", false, "Remove the local manifest list after push")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow push to an insecure registry")
+	return cmd
+}
+
+func runPush(dockerCli *client.DockerCli, opts pushOptions) error {
+	list, err := parseReference(opts.list)
+	if err != nil {
+		return err
+	}
+	tagged, ok := list.(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("a manifest list must be pushed to a tag, got %s", list.String())
+	}
+
+	s := newStore()
+	manifests, err := s.getList(list)
+	if err != nil {
+		return err
+	}
+	mfstList, err := buildManifestList(manifests)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	repo, err := getRepository(ctx, dockerCli, list, opts.insecure, "push", "pull")
+	if err != nil {
+		return err
+	}
+	for _, m := range manifests {
+		if err := copyManifest(ctx, repo, list, m); err != nil {
+			return err
+		}
+	}
+
+	svc, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	dgst, err := svc.Put(ctx, mfstList, distribution.WithTag(tagged.Tag()))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), dgst.String())
+
+	if opts.purge {
+		return s.remove(list)
+	}
+	return nil
+}
+
+// buildManifestList assembles the manifest list made of the given entries.
+func buildManifestList(manifests []imageManifest) (*manifestlist.DeserializedManifestList, error) {
+	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(manifests))
+	for _, m := range manifests {
+		if m.Descriptor.Platform.OS == "" || m.Descriptor.Platform.Architecture == "" {
+			return nil, fmt.Errorf("%s has no platform, set it with `docker manifest annotate`", m.Ref)
+		}
+		descriptors = append(descriptors, m.Descriptor)
+	}
+	return manifestlist.FromDescriptors(descriptors)
+}
+
+// copyManifest makes an image manifest of another repository of the registry
+// available in the repository of the manifest list, by mounting its blobs and
+// pushing the manifest itself. Nothing is done for the manifests of the
+// repository of the list.
+func copyManifest(ctx context.Context, repo distribution.Repository, list reference.Named, m imageManifest) error {
+	ref, err := reference.ParseNamed(m.Ref)
+	if err != nil {
+		return err
+	}
+	if ref.Name() == list.Name() {
+		return nil
+	}
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return err
+	}
+	source, err := distreference.WithName(repoInfo.RemoteName())
+	if err != nil {
+		return err
+	}
+
+	var manifest schema2.DeserializedManifest
+	if err := manifest.UnmarshalJSON(m.Payload); err != nil {
+		return err
+	}
+
+	bs := repo.Blobs(ctx)
+	for _, desc := range append([]distribution.Descriptor{manifest.Target()}, manifest.References()...) {
+		canonical, err := distreference.WithDigest(source, desc.Digest)
+		if err != nil {
+			return err
+		}
+		upload, err := bs.Create(ctx, distclient.WithMountFrom(canonical))
+		switch err.(type) {
+		case distribution.ErrBlobMounted:
+			continue
+		case nil:
+			// the registry started an upload instead of mounting the blob
+			upload.Cancel(ctx)
+			err = fmt.Errorf("blob was not mounted")
+		}
+		return fmt.Errorf("failed to mount %s from %s: %v", desc.Digest, ref.Name(), err)
+	}
+
+	svc, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = svc.Put(ctx, &manifest)
+	return err
+}
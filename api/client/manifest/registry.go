@@ -0,0 +1,140 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/api/client"
+	dockerdist "github.com/docker/docker/distribution"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+)
+
+// parseReference parses an image reference, adding the default tag to
+// references without a tag or digest.
+func parseReference(s string) (reference.Named, error) {
+	ref, err := reference.ParseNamed(s)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDefaultTag(ref), nil
+}
+
+// getRepository returns a client for the repository of ref on its registry.
+// insecure allows the registry to be reached over plain HTTP, or HTTPS
+// without certificate verification.
+func getRepository(ctx context.Context, dockerCli *client.DockerCli, ref reference.Named, insecure bool, actions ...string) (distribution.Repository, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	options := registry.ServiceOptions{V2Only: true}
+	if insecure {
+		options.InsecureRegistries = []string{repoInfo.Index.Name}
+	}
+	endpoints, err := registry.NewService(options).LookupPushEndpoints(repoInfo.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig := dockerCli.ResolveAuthConfig(ctx, repoInfo.Index)
+	lastErr := fmt.Errorf("no registry endpoint found for %s", ref.String())
+	for _, endpoint := range endpoints {
+		repo, confirmedV2, err := dockerdist.NewV2Repository(ctx, repoInfo, endpoint, nil, &authConfig, actions...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !confirmedV2 {
+			lastErr = fmt.Errorf("%s does not support the v2 registry API", endpoint.URL)
+			continue
+		}
+		return repo, nil
+	}
+	return nil, lastErr
+}
+
+// getManifest gets the manifest referenced by the tag or digest of ref.
+func getManifest(ctx context.Context, repo distribution.Repository, ref reference.Named) (distribution.Manifest, error) {
+	svc, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch r := ref.(type) {
+	case reference.Canonical:
+		return svc.Get(ctx, r.Digest())
+	case reference.NamedTagged:
+		return svc.Get(ctx, "", distribution.WithTag(r.Tag()))
+	}
+	return nil, fmt.Errorf("%s is not a tag or digest reference", ref.String())
+}
+
+// imageConfig holds the fields of an image configuration describing its
+// platform.
+type imageConfig struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+// fetchImageManifest gets the manifest of an image from its registry and
+// returns the entry of a manifest list describing it. Only schema 2
+// manifests can be part of a manifest list.
+func fetchImageManifest(ctx context.Context, dockerCli *client.DockerCli, ref reference.Named, insecure bool) (imageManifest, error) {
+	repo, err := getRepository(ctx, dockerCli, ref, insecure, "pull")
+	if err != nil {
+		return imageManifest{}, err
+	}
+	m, err := getManifest(ctx, repo, ref)
+	if err != nil {
+		return imageManifest{}, err
+	}
+
+	switch m.(type) {
+	case *schema2.DeserializedManifest:
+	case *manifestlist.DeserializedManifestList:
+		return imageManifest{}, fmt.Errorf("%s is a manifest list, it can't be added to another manifest list", ref.String())
+	default:
+		return imageManifest{}, fmt.Errorf("%s has an unsupported manifest format, only schema 2 manifests can be added to a manifest list", ref.String())
+	}
+
+	mediaType, payload, err := m.Payload()
+	if err != nil {
+		return imageManifest{}, err
+	}
+	blob, err := repo.Blobs(ctx).Get(ctx, m.(*schema2.DeserializedManifest).Target().Digest)
+	if err != nil {
+		return imageManifest{}, fmt.Errorf("failed to get the configuration of %s: %v", ref.String(), err)
+	}
+	var config imageConfig
+	if err := json.Unmarshal(blob, &config); err != nil {
+		return imageManifest{}, fmt.Errorf("invalid configuration for %s: %v", ref.String(), err)
+	}
+
+	dgst := digest.FromBytes(payload)
+	canonical, err := reference.WithDigest(ref, dgst)
+	if err != nil {
+		return imageManifest{}, err
+	}
+	return imageManifest{
+		Ref: canonical.String(),
+		Descriptor: manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{
+				MediaType: mediaType,
+				Size:      int64(len(payload)),
+				Digest:    dgst,
+			},
+			Platform: manifestlist.PlatformSpec{
+				Architecture: config.Architecture,
+				OS:           config.OS,
+			},
+		},
+		Payload: payload,
+	}, nil
+}
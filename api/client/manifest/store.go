@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/reference"
+)
+
+// imageManifest is an entry of a manifest list being assembled.
+type imageManifest struct {
+	// Ref is the canonical reference of the image manifest.
+	Ref string `json:"ref"`
+	// Descriptor is the entry of the manifest list, with the platform of
+	// the image.
+	Descriptor manifestlist.ManifestDescriptor `json:"descriptor"`
+	// Payload is the image manifest, needed to copy it to the repository of
+	// the manifest list when it is in another repository.
+	Payload []byte `json:"payload"`
+}
+
+// store keeps the manifest lists being assembled in the configuration
+// directory of the client, until they are pushed. Every list is a directory
+// holding a file per image manifest.
+type store struct {
+	root string
+}
+
+func newStore() *store {
+	return &store{root: filepath.Join(cliconfig.ConfigDir(), "manifests")}
+}
+
+func (s *store) listDir(list reference.Named) string {
+	return filepath.Join(s.root, url.QueryEscape(list.String()))
+}
+
+func (s *store) manifestFile(list, image reference.Named) string {
+	return filepath.Join(s.listDir(list), url.QueryEscape(image.String()))
+}
+
+// exists returns whether the manifest list is in the store.
+func (s *store) exists(list reference.Named) bool {
+	_, err := os.Stat(s.listDir(list))
+	return err == nil
+}
+
+// getList returns the entries of a manifest list, ordered by image reference.
+func (s *store) getList(list reference.Named) ([]imageManifest, error) {
+	files, err := ioutil.ReadDir(s.listDir(list))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("No such manifest list: %s", list.String())
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	manifests := make([]imageManifest, 0, len(names))
+	for _, name := range names {
+		m, err := readManifest(filepath.Join(s.listDir(list), name))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// get returns the entry of a manifest list for an image.
+func (s *store) get(list, image reference.Named) (imageManifest, error) {
+	m, err := readManifest(s.manifestFile(list, image))
+	if os.IsNotExist(err) {
+		return m, fmt.Errorf("No such manifest in %s: %s", list.String(), image.String())
+	}
+	return m, err
+}
+
+// save adds or updates the entry of a manifest list for an image.
+func (s *store) save(list, image reference.Named, m imageManifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.listDir(list), 0700); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(s.manifestFile(list, image), data, 0600)
+}
+
+// remove deletes a manifest list from the store.
+func (s *store) remove(list reference.Named) error {
+	return os.RemoveAll(s.listDir(list))
+}
+
+func readManifest(path string) (imageManifest, error) {
+	var m imageManifest
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("invalid manifest file %s: %v", path, err)
+	}
+	return m, nil
+}
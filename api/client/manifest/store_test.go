@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+)
+
+func TestStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "manifest-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	s := &store{root: root}
+
+	list, _ := parseReference("example.com/app")
+	amd64, _ := parseReference("example.com/app:amd64")
+	arm64, _ := parseReference("example.com/app:arm64")
+
+	if s.exists(list) {
+		t.Fatal("expected an empty store")
+	}
+	if _, err := s.getList(list); err == nil {
+		t.Fatal("expected an error for a missing manifest list")
+	}
+
+	for _, ref := range []string{"example.com/app:arm64", "example.com/app:amd64"} {
+		image, _ := parseReference(ref)
+		if err := s.save(list, image, imageManifest{Ref: ref}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := s.get(list, arm64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Descriptor.Platform.Architecture = "arm64"
+	if err := s.save(list, arm64, m); err != nil {
+		t.Fatal(err)
+	}
+
+	manifests, err := s.getList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 || manifests[0].Ref != amd64.String() || manifests[1].Descriptor.Platform.Architecture != "arm64" {
+		t.Fatalf("unexpected manifest list: %+v", manifests)
+	}
+
+	if err := s.remove(list); err != nil {
+		t.Fatal(err)
+	}
+	if s.exists(list) {
+		t.Fatal("expected the manifest list to be removed")
+	}
+}
+
+func TestBuildManifestList(t *testing.T) {
+	manifests := []imageManifest{
+		{Ref: "app@sha256:1", Descriptor: manifestlist.ManifestDescriptor{Platform: manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}}},
+		{Ref: "app@sha256:2", Descriptor: manifestlist.ManifestDescriptor{Platform: manifestlist.PlatformSpec{OS: "linux"}}},
+	}
+	if _, err := buildManifestList(manifests); err == nil {
+		t.Fatal("expected an error for an entry without architecture")
+	}
+
+	manifests[1].Descriptor.Platform.Architecture = "arm64"
+	list, err := buildManifestList(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Manifests) != 2 || list.Manifests[1].Platform.Architecture != "arm64" {
+		t.Fatalf("unexpected manifest list: %+v", list.Manifests)
+	}
+}
@@ -23,6 +23,7 @@ type createOptions struct {
 	labels     []string
 	internal   bool
 	ipv6       bool
+	dns        []string
 
 	ipamDriver  string
 	ipamSubnet  []string
@@ -55,6 +56,7 @@ func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
 	flags.StringSliceVar(&opts.labels, "label", []string{}, "Set metadata on a network")
 	flags.BoolVar(&opts.internal, "internal", false, "restricts external access to the network")
 	flags.BoolVar(&opts.ipv6, "ipv6", false, "enable IPv6 networking")
+	flags.StringSliceVar(&opts.dns, "dns-upstream", []string{}, "DNS server, in the [ZONE=]IP form, to forward the queries of the containers to")
 
 	flags.StringVar(&opts.ipamDriver, "ipam-driver", "default", "IP Address Management Driver")
 	flags.StringSliceVar(&opts.ipamSubnet, "subnet", []string{}, "subnet in CIDR format that represents a network segment")
@@ -88,6 +90,7 @@ func runCreate(dockerCli *client.DockerCli, opts createOptions) error {
 		Internal:       opts.internal,
 		EnableIPv6:     opts.ipv6,
 		Labels:         runconfigopts.ConvertKVStringsToMap(opts.labels),
+		DNSUpstreams:   opts.dns,
 	}
 
 	resp, err := client.NetworkCreate(context.Background(), opts.name, nc)
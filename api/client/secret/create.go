@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type createOptions struct {
+	name   string
+	file   string
+	labels []string
+}
+
+func newCreateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts createOptions
+
+	cmd := &cobra.Command{
+		Use:   "create NAME [FILE|-]",
+		Short: "Create a secret from a file or STDIN",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			if len(args) > 1 {
+				opts.file = args[1]
+			}
+			return runCreate(dockerCli, opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.labels, "label", []string{}, "Set metadata for a secret")
+
+	return cmd
+}
+
+func runCreate(dockerCli *client.DockerCli, opts createOptions) error {
+	client := dockerCli.Client()
+
+	var in io.Reader = dockerCli.In()
+	if opts.file != "" && opts.file != "-" {
+		f, err := os.Open(opts.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("error reading the content of the secret: %v", err)
+	}
+
+	secret, err := client.SecretCreate(context.Background(), types.SecretCreateRequest{
+		Name:   opts.name,
+		Labels: runconfigopts.ConvertKVStringsToMap(opts.labels),
+		Data:   data,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(dockerCli.Out(), "%s\n", secret.ID)
+	return nil
+}
@@ -0,0 +1,64 @@
+package secret
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet bool
+}
+
+func newListCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List secrets",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display secret IDs")
+
+	return cmd
+}
+
+func runList(dockerCli *client.DockerCli, opts listOptions) error {
+	secrets, err := dockerCli.Client().SecretList(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if opts.quiet {
+		for _, s := range secrets {
+			fmt.Fprintln(dockerCli.Out(), s.ID)
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "ID\tNAME\tSIZE\tCREATED\n")
+	for _, s := range secrets {
+		created := ""
+		if t, err := time.Parse(time.RFC3339Nano, s.CreatedAt); err == nil {
+			created = units.HumanDuration(time.Now().UTC().Sub(t)) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stringid.TruncateID(s.ID), s.Name, units.HumanSize(float64(s.Size)), created)
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,109 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+type diskUsageOptions struct {
+	verbose bool
+}
+
+func newDiskUsageCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts diskUsageOptions
+
+	cmd := &cobra.Command{
+		Use:   "df [OPTIONS]",
+		Short: "Show docker disk usage",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiskUsage(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show the disk usage of each volume")
+
+	return cmd
+}
+
+// byVolumeSize sorts the volumes by decreasing size, the volumes of unknown
+// size last.
+type byVolumeSize []*types.Volume
+
+func (r byVolumeSize) Len() int      { return len(r) }
+func (r byVolumeSize) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byVolumeSize) Less(i, j int) bool {
+	return r[i].UsageData.Size > r[j].UsageData.Size
+}
+
+func runDiskUsage(dockerCli *client.DockerCli, opts diskUsageOptions) error {
+	du, err := dockerCli.Client().DiskUsage(context.Background())
+	if err != nil {
+		return err
+	}
+
+	usedImages := make(map[string]bool)
+	var runningContainers int
+	var containersSize int64
+	for _, c := range du.Containers {
+		usedImages[c.ImageID] = true
+		if c.State == "running" {
+			runningContainers++
+		}
+		containersSize += c.SizeRw
+	}
+	var activeImages int
+	for _, i := range du.Images {
+		if usedImages[i.ID] {
+			activeImages++
+		}
+	}
+	var activeVolumes int
+	var volumesSize int64
+	for _, v := range du.Volumes {
+		if v.UsageData == nil {
+			v.UsageData = &types.VolumeUsageData{Size: -1}
+		}
+		if v.UsageData.RefCount > 0 {
+			activeVolumes++
+		}
+		if v.UsageData.Size > 0 {
+			volumesSize += v.UsageData.Size
+		}
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "TYPE\tTOTAL\tACTIVE\tSIZE\n")
+	fmt.Fprintf(w, "Images\t%d\t%d\t%s\n", len(du.Images), activeImages, units.HumanSize(float64(du.LayersSize)))
+	fmt.Fprintf(w, "Containers\t%d\t%d\t%s\n", len(du.Containers), runningContainers, units.HumanSize(float64(containersSize)))
+	fmt.Fprintf(w, "Volumes\t%d\t%d\t%s\n", len(du.Volumes), activeVolumes, units.HumanSize(float64(volumesSize)))
+	w.Flush()
+
+	if !opts.verbose {
+		return nil
+	}
+
+	sort.Stable(byVolumeSize(du.Volumes))
+	fmt.Fprintln(dockerCli.Out())
+	w = tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "VOLUME NAME\tDRIVER\tLINKS\tSIZE\n")
+	for _, v := range du.Volumes {
+		size := "N/A"
+		if v.UsageData.Size >= 0 {
+			size = units.HumanSize(float64(v.UsageData.Size))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", v.Name, v.Driver, v.UsageData.RefCount, size)
+	}
+	w.Flush()
+	return nil
+}
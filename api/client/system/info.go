@@ -60,6 +60,9 @@ func runInfo(dockerCli *client.DockerCli) error {
 	}
 	ioutils.FprintfIfNotEmpty(dockerCli.Out(), "Logging Driver: %s\n", info.LoggingDriver)
 	ioutils.FprintfIfNotEmpty(dockerCli.Out(), "Cgroup Driver: %s\n", info.CgroupDriver)
+	if len(info.SystemdCapabilities) != 0 {
+		fmt.Fprintf(dockerCli.Out(), "Systemd Capabilities: %s\n", strings.Join(info.SystemdCapabilities, " "))
+	}
 
 	fmt.Fprintf(dockerCli.Out(), "Plugins: \n")
 	fmt.Fprintf(dockerCli.Out(), " Volume:")
@@ -108,6 +111,19 @@ func runInfo(dockerCli *client.DockerCli) error {
 	ioutils.FprintfIfNotEmpty(dockerCli.Out(), "Architecture: %s\n", info.Architecture)
 	fmt.Fprintf(dockerCli.Out(), "CPUs: %d\n", info.NCPU)
 	fmt.Fprintf(dockerCli.Out(), "Total Memory: %s\n", units.BytesSize(float64(info.MemTotal)))
+	if len(info.NUMANodes) > 1 {
+		fmt.Fprintf(dockerCli.Out(), "NUMA Nodes: %d\n", len(info.NUMANodes))
+		for _, n := range info.NUMANodes {
+			fmt.Fprintf(dockerCli.Out(), " node%d: CPUs %s, Memory %s\n", n.ID, n.CPUs, units.BytesSize(float64(n.MemTotal)))
+		}
+	}
+	if l := info.CgroupLeaks; l != nil && (len(l.Leaks) > 0 || l.Removed > 0) {
+		fmt.Fprintf(dockerCli.Out(), "Cgroup Leaks: %d\n", len(l.Leaks))
+		for _, path := range l.Leaks {
+			fmt.Fprintf(dockerCli.Out(), " %s\n", path)
+		}
+		fmt.Fprintf(dockerCli.Out(), " Removed: %d\n", l.Removed)
+	}
 	ioutils.FprintfIfNotEmpty(dockerCli.Out(), "Name: %s\n", info.Name)
 	ioutils.FprintfIfNotEmpty(dockerCli.Out(), "ID: %s\n", info.ID)
 	fmt.Fprintf(dockerCli.Out(), "Docker Root Dir: %s\n", info.DockerRootDir)
@@ -169,6 +185,9 @@ func runInfo(dockerCli *client.DockerCli) error {
 			fmt.Fprintln(dockerCli.Err(), "WARNING: bridge-nf-call-ip6tables is disabled")
 		}
 	}
+	for _, warning := range info.Warnings {
+		fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", warning)
+	}
 
 	if info.Labels != nil {
 		fmt.Fprintln(dockerCli.Out(), "Labels:")
@@ -41,7 +41,9 @@ import (
 )
 
 var (
-	releasesRole = path.Join(data.CanonicalTargetsRole, "releases")
+	// ReleasesRole is the delegation role of the signers, whose tags are
+	// trusted with the tags of the targets role.
+	ReleasesRole = path.Join(data.CanonicalTargetsRole, "releases")
 	untrusted    bool
 )
 
@@ -81,6 +83,11 @@ func (cli *DockerCli) trustDirectory() string {
 	return filepath.Join(cliconfig.ConfigDir(), "trust")
 }
 
+// TrustKeyStore returns the store of the private keys of content trust.
+func (cli *DockerCli) TrustKeyStore() (trustmanager.KeyStore, error) {
+	return trustmanager.NewKeyFileStore(cli.trustDirectory(), cli.PassphraseRetriever())
+}
+
 // certificateDirectory returns the directory containing
 // TLS certificates for the given server. An error is
 // returned if there was an error parsing the server string.
@@ -123,10 +130,10 @@ func (scs simpleCredentialStore) RefreshToken(u *url.URL, service string) string
 func (scs simpleCredentialStore) SetRefreshToken(*url.URL, string, string) {
 }
 
-// getNotaryRepository returns a NotaryRepository which stores all the
+// NotaryRepository returns a NotaryRepository which stores all the
 // information needed to operate on a notary repository.
 // It creates an HTTP transport providing authentication support.
-func (cli *DockerCli) getNotaryRepository(repoInfo *registry.RepositoryInfo, authConfig types.AuthConfig, actions ...string) (*client.NotaryRepository, error) {
+func (cli *DockerCli) NotaryRepository(repoInfo *registry.RepositoryInfo, authConfig types.AuthConfig, actions ...string) (*client.NotaryRepository, error) {
 	server, err := trustServer(repoInfo.Index)
 	if err != nil {
 		return nil, err
@@ -194,7 +201,7 @@ func (cli *DockerCli) getNotaryRepository(repoInfo *registry.RepositoryInfo, aut
 	tr := transport.NewTransport(base, modifiers...)
 
 	return client.NewNotaryRepository(
-		cli.trustDirectory(), repoInfo.FullName(), server, tr, cli.getPassphraseRetriever(),
+		cli.trustDirectory(), repoInfo.FullName(), server, tr, cli.PassphraseRetriever(),
 		trustpinning.TrustPinConfig{})
 }
 
@@ -210,7 +217,9 @@ func convertTarget(t client.Target) (target, error) {
 	}, nil
 }
 
-func (cli *DockerCli) getPassphraseRetriever() passphrase.Retriever {
+// PassphraseRetriever returns the retriever of the passphrases of the keys of
+// content trust, from the environment or else prompted.
+func (cli *DockerCli) PassphraseRetriever() passphrase.Retriever {
 	aliasMap := map[string]string{
 		"root":     "root",
 		"snapshot": "repository",
@@ -247,20 +256,20 @@ func (cli *DockerCli) TrustedReference(ctx context.Context, ref reference.NamedT
 	// Resolve the Auth config relevant for this server
 	authConfig := cli.ResolveAuthConfig(ctx, repoInfo.Index)
 
-	notaryRepo, err := cli.getNotaryRepository(repoInfo, authConfig, "pull")
+	notaryRepo, err := cli.NotaryRepository(repoInfo, authConfig, "pull")
 	if err != nil {
 		fmt.Fprintf(cli.out, "Error establishing connection to trust repository: %s\n", err)
 		return nil, err
 	}
 
-	t, err := notaryRepo.GetTargetByName(ref.Tag(), releasesRole, data.CanonicalTargetsRole)
+	t, err := notaryRepo.GetTargetByName(ref.Tag(), ReleasesRole, data.CanonicalTargetsRole)
 	if err != nil {
 		return nil, err
 	}
 	// Only list tags in the top level targets role or the releases delegation role - ignore
 	// all other delegation roles
-	if t.Role != releasesRole && t.Role != data.CanonicalTargetsRole {
-		return nil, notaryError(repoInfo.FullName(), fmt.Errorf("No trust data for %s", ref.Tag()))
+	if t.Role != ReleasesRole && t.Role != data.CanonicalTargetsRole {
+		return nil, NotaryError(repoInfo.FullName(), fmt.Errorf("No trust data for %s", ref.Tag()))
 	}
 	r, err := convertTarget(t.Target)
 	if err != nil {
@@ -278,7 +287,9 @@ func (cli *DockerCli) TagTrusted(ctx context.Context, trustedRef reference.Canon
 	return cli.client.ImageTag(ctx, trustedRef.String(), ref.String())
 }
 
-func notaryError(repoName string, err error) error {
+// NotaryError returns the error of an operation on the notary repository of
+// repoName, with the context the users need to act on it.
+func NotaryError(repoName string, err error) error {
 	switch err.(type) {
 	case *json.SyntaxError:
 		logrus.Debugf("Notary syntax error: %s", err)
@@ -309,10 +320,10 @@ func notaryError(repoName string, err error) error {
 }
 
 // TrustedPull handles content trust pulling of an image
-func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.RepositoryInfo, ref registry.Reference, authConfig types.AuthConfig, requestPrivilege types.RequestPrivilegeFunc) error {
+func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.RepositoryInfo, ref registry.Reference, authConfig types.AuthConfig, requestPrivilege types.RequestPrivilegeFunc, platform string) error {
 	var refs []target
 
-	notaryRepo, err := cli.getNotaryRepository(repoInfo, authConfig, "pull")
+	notaryRepo, err := cli.NotaryRepository(repoInfo, authConfig, "pull")
 	if err != nil {
 		fmt.Fprintf(cli.out, "Error establishing connection to trust repository: %s\n", err)
 		return err
@@ -320,9 +331,9 @@ func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.Reposi
 
 	if ref.String() == "" {
 		// List all targets
-		targets, err := notaryRepo.ListTargets(releasesRole, data.CanonicalTargetsRole)
+		targets, err := notaryRepo.ListTargets(ReleasesRole, data.CanonicalTargetsRole)
 		if err != nil {
-			return notaryError(repoInfo.FullName(), err)
+			return NotaryError(repoInfo.FullName(), err)
 		}
 		for _, tgt := range targets {
 			t, err := convertTarget(tgt.Target)
@@ -332,23 +343,23 @@ func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.Reposi
 			}
 			// Only list tags in the top level targets role or the releases delegation role - ignore
 			// all other delegation roles
-			if tgt.Role != releasesRole && tgt.Role != data.CanonicalTargetsRole {
+			if tgt.Role != ReleasesRole && tgt.Role != data.CanonicalTargetsRole {
 				continue
 			}
 			refs = append(refs, t)
 		}
 		if len(refs) == 0 {
-			return notaryError(repoInfo.FullName(), fmt.Errorf("No trusted tags for %s", repoInfo.FullName()))
+			return NotaryError(repoInfo.FullName(), fmt.Errorf("No trusted tags for %s", repoInfo.FullName()))
 		}
 	} else {
-		t, err := notaryRepo.GetTargetByName(ref.String(), releasesRole, data.CanonicalTargetsRole)
+		t, err := notaryRepo.GetTargetByName(ref.String(), ReleasesRole, data.CanonicalTargetsRole)
 		if err != nil {
-			return notaryError(repoInfo.FullName(), err)
+			return NotaryError(repoInfo.FullName(), err)
 		}
 		// Only get the tag if it's in the top level targets role or the releases delegation role
 		// ignore it if it's in any other delegation roles
-		if t.Role != releasesRole && t.Role != data.CanonicalTargetsRole {
-			return notaryError(repoInfo.FullName(), fmt.Errorf("No trust data for %s", ref.String()))
+		if t.Role != ReleasesRole && t.Role != data.CanonicalTargetsRole {
+			return NotaryError(repoInfo.FullName(), fmt.Errorf("No trust data for %s", ref.String()))
 		}
 
 		logrus.Debugf("retrieving target for %s role\n", t.Role)
@@ -371,7 +382,7 @@ func (cli *DockerCli) TrustedPull(ctx context.Context, repoInfo *registry.Reposi
 		if err != nil {
 			return err
 		}
-		if err := cli.ImagePullPrivileged(ctx, authConfig, ref.String(), requestPrivilege, false); err != nil {
+		if err := cli.ImagePullPrivileged(ctx, authConfig, ref.String(), requestPrivilege, false, platform); err != nil {
 			return err
 		}
 
@@ -462,7 +473,7 @@ func (cli *DockerCli) TrustedPush(ctx context.Context, repoInfo *registry.Reposi
 
 	fmt.Fprintln(cli.out, "Signing and pushing trust metadata")
 
-	repo, err := cli.getNotaryRepository(repoInfo, authConfig, "push", "pull")
+	repo, err := cli.NotaryRepository(repoInfo, authConfig, "push", "pull")
 	if err != nil {
 		fmt.Fprintf(cli.out, "Error establishing connection to notary repository: %s\n", err)
 		return err
@@ -473,31 +484,15 @@ func (cli *DockerCli) TrustedPush(ctx context.Context, repoInfo *registry.Reposi
 
 	switch err.(type) {
 	case client.ErrRepoNotInitialized, client.ErrRepositoryNotExist:
-		keys := repo.CryptoService.ListKeys(data.CanonicalRootRole)
-		var rootKeyID string
-		// always select the first root key
-		if len(keys) > 0 {
-			sort.Strings(keys)
-			rootKeyID = keys[0]
-		} else {
-			rootPublicKey, err := repo.CryptoService.Create(data.CanonicalRootRole, "", data.ECDSAKey)
-			if err != nil {
-				return err
-			}
-			rootKeyID = rootPublicKey.ID()
-		}
-
-		// Initialize the notary repository with a remotely managed snapshot key
-		if err := repo.Initialize(rootKeyID, data.CanonicalSnapshotRole); err != nil {
-			return notaryError(repoInfo.FullName(), err)
+		if err := cli.InitializeNotaryRepository(repo, repoInfo.FullName()); err != nil {
+			return err
 		}
-		fmt.Fprintf(cli.out, "Finished initializing %q\n", repoInfo.FullName())
 		err = repo.AddTarget(target, data.CanonicalTargetsRole)
 	case nil:
 		// already initialized and we have successfully downloaded the latest metadata
 		err = cli.addTargetToAllSignableRoles(repo, target)
 	default:
-		return notaryError(repoInfo.FullName(), err)
+		return NotaryError(repoInfo.FullName(), err)
 	}
 
 	if err == nil {
@@ -506,13 +501,39 @@ func (cli *DockerCli) TrustedPush(ctx context.Context, repoInfo *registry.Reposi
 
 	if err != nil {
 		fmt.Fprintf(cli.out, "Failed to sign %q:%s - %s\n", repoInfo.FullName(), tag, err.Error())
-		return notaryError(repoInfo.FullName(), err)
+		return NotaryError(repoInfo.FullName(), err)
 	}
 
 	fmt.Fprintf(cli.out, "Successfully signed %q:%s\n", repoInfo.FullName(), tag)
 	return nil
 }
 
+// InitializeNotaryRepository initializes a notary repository without trust
+// data with the first root key, or a new one, and a snapshot key managed by
+// the notary server.
+func (cli *DockerCli) InitializeNotaryRepository(repo *client.NotaryRepository, repoName string) error {
+	keys := repo.CryptoService.ListKeys(data.CanonicalRootRole)
+	var rootKeyID string
+	// always select the first root key
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		rootKeyID = keys[0]
+	} else {
+		rootPublicKey, err := repo.CryptoService.Create(data.CanonicalRootRole, "", data.ECDSAKey)
+		if err != nil {
+			return err
+		}
+		rootKeyID = rootPublicKey.ID()
+	}
+
+	// Initialize the notary repository with a remotely managed snapshot key
+	if err := repo.Initialize(rootKeyID, data.CanonicalSnapshotRole); err != nil {
+		return NotaryError(repoName, err)
+	}
+	fmt.Fprintf(cli.out, "Finished initializing %q\n", repoName)
+	return nil
+}
+
 // Attempt to add the image target to all the top level delegation roles we can
 // (based on whether we have the signing key and whether the role's path allows
 // us to).
@@ -562,7 +583,7 @@ func (cli *DockerCli) addTargetToAllSignableRoles(repo *client.NotaryRepository,
 }
 
 // ImagePullPrivileged pulls the image and displays it to the output
-func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.AuthConfig, ref string, requestPrivilege types.RequestPrivilegeFunc, all bool) error {
+func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.AuthConfig, ref string, requestPrivilege types.RequestPrivilegeFunc, all bool, platform string) error {
 
 	encodedAuth, err := EncodeAuthToBase64(authConfig)
 	if err != nil {
@@ -572,6 +593,7 @@ func (cli *DockerCli) ImagePullPrivileged(ctx context.Context, authConfig types.
 		RegistryAuth:  encodedAuth,
 		PrivilegeFunc: requestPrivilege,
 		All:           all,
+		Platform:      platform,
 	}
 
 	responseBody, err := cli.client.ImagePull(ctx, ref, options)
@@ -0,0 +1,112 @@
+package trust
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	notaryclient "github.com/docker/notary/client"
+	"github.com/docker/notary/tuf/data"
+)
+
+var validSignerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// NewTrustCommand returns a cobra command for `trust` subcommands
+func NewTrustCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Manage the content trust of repositories",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newInspectCommand(dockerCli),
+		newKeyCommand(dockerCli),
+		newSignerCommand(dockerCli),
+	)
+	return cmd
+}
+
+func newKeyCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "Manage the signing keys",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newKeyGenerateCommand(dockerCli),
+		newKeyLoadCommand(dockerCli),
+	)
+	return cmd
+}
+
+func newSignerCommand(dockerCli *client.DockerCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "signer",
+		Short: "Manage the signers of repositories",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(dockerCli.Err(), "\n"+cmd.UsageString())
+		},
+	}
+	cmd.AddCommand(
+		newSignerAddCommand(dockerCli),
+		newSignerRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+// validateSignerName returns an error if name cannot be the name of a
+// signer, the name of its delegation role being targets/<name>.
+func validateSignerName(name string) error {
+	if !validSignerName.MatchString(name) {
+		return fmt.Errorf("invalid signer name %q: only lowercase letters, digits, - and _ are allowed", name)
+	}
+	if path.Join(data.CanonicalTargetsRole, name) == client.ReleasesRole {
+		return fmt.Errorf("invalid signer name %q: it is reserved", name)
+	}
+	return nil
+}
+
+// signerRole returns the delegation role of a signer.
+func signerRole(name string) string {
+	return path.Join(data.CanonicalTargetsRole, name)
+}
+
+// notaryRepository returns the notary repository of the repository of named.
+func notaryRepository(dockerCli *client.DockerCli, named reference.Named, actions ...string) (*notaryclient.NotaryRepository, *registry.RepositoryInfo, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(named)
+	if err != nil {
+		return nil, nil, err
+	}
+	authConfig := dockerCli.ResolveAuthConfig(context.Background(), repoInfo.Index)
+	repo, err := dockerCli.NotaryRepository(repoInfo, authConfig, actions...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, repoInfo, nil
+}
+
+// parseRepository parses the name of a repository, without a tag.
+func parseRepository(name string) (reference.Named, error) {
+	named, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	if !reference.IsNameOnly(named) {
+		return nil, fmt.Errorf("invalid repository %s: the tag or digest of an image is not expected", name)
+	}
+	return named, nil
+}
@@ -0,0 +1,176 @@
+package trust
+
+import (
+	"encoding/hex"
+	"path"
+	"sort"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/api/client/inspect"
+	"github.com/docker/docker/cli"
+	"github.com/docker/docker/reference"
+	"github.com/docker/notary/tuf/data"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	format string
+	names  []string
+}
+
+// signedTag is a tag of a repository, with the signers who signed it.
+type signedTag struct {
+	Name    string
+	Digest  string
+	Signers []string
+}
+
+// signer is a signer of a repository, or an administrative role, with the
+// IDs of its keys.
+type signer struct {
+	Name string
+	Keys []string
+}
+
+// repositoryTrust is the trust data of a repository.
+type repositoryTrust struct {
+	Name               string
+	SignedTags         []signedTag
+	Signers            []signer
+	AdministrativeKeys []signer
+}
+
+// repositoryAdmin is the signer of the tags signed with the key of the
+// targets role of a repository.
+const repositoryAdmin = "Repository"
+
+func newInspectCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] REPOSITORY[:TAG] [REPOSITORY[:TAG]...]",
+		Short: "Display the signed tags, the signers and the keys of one or more repositories",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runInspect(dockerCli, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.format, "format", "f", "", "Format the output using the given go template")
+
+	return cmd
+}
+
+func runInspect(dockerCli *client.DockerCli, opts inspectOptions) error {
+	getTrustFunc := func(name string) (interface{}, []byte, error) {
+		trust, err := inspectRepository(dockerCli, name)
+		return trust, nil, err
+	}
+	return inspect.Inspect(dockerCli.Out(), opts.names, opts.format, getTrustFunc)
+}
+
+func inspectRepository(dockerCli *client.DockerCli, name string) (*repositoryTrust, error) {
+	named, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	var tag string
+	if tagged, ok := named.(reference.NamedTagged); ok {
+		tag = tagged.Tag()
+	}
+	repo, repoInfo, err := notaryRepository(dockerCli, named, "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	trust := &repositoryTrust{
+		Name:               repoInfo.FullName(),
+		SignedTags:         []signedTag{},
+		Signers:            []signer{},
+		AdministrativeKeys: []signer{},
+	}
+	roles, err := repo.ListRoles()
+	if err != nil {
+		return nil, client.NotaryError(repoInfo.FullName(), err)
+	}
+	for _, r := range roles {
+		switch r.Name {
+		case data.CanonicalRootRole:
+			trust.AdministrativeKeys = append(trust.AdministrativeKeys, signer{Name: "Root", Keys: sortedKeys(r.KeyIDs)})
+		case data.CanonicalTargetsRole:
+			trust.AdministrativeKeys = append(trust.AdministrativeKeys, signer{Name: repositoryAdmin, Keys: sortedKeys(r.KeyIDs)})
+		}
+	}
+
+	// The IDs of the keys of the signers are the IDs of the keys in the key
+	// stores, not of their certificates.
+	delegations, err := repo.GetDelegationRoles()
+	if err != nil {
+		return nil, client.NotaryError(repoInfo.FullName(), err)
+	}
+	signingRoles := []string{data.CanonicalTargetsRole}
+	for _, r := range delegations {
+		if path.Dir(r.Name) != data.CanonicalTargetsRole {
+			continue
+		}
+		signingRoles = append(signingRoles, r.Name)
+		if r.Name != client.ReleasesRole {
+			trust.Signers = append(trust.Signers, signer{Name: path.Base(r.Name), Keys: sortedKeys(r.KeyIDs)})
+		}
+	}
+	sort.Sort(bySignerName(trust.Signers))
+
+	tags := make(map[string]*signedTag)
+	for _, role := range signingRoles {
+		targets, err := repo.ListTargets(role)
+		if err != nil {
+			return nil, client.NotaryError(repoInfo.FullName(), err)
+		}
+		for _, t := range targets {
+			if tag != "" && t.Name != tag {
+				continue
+			}
+			st, ok := tags[t.Name]
+			if !ok {
+				st = &signedTag{Name: t.Name, Signers: []string{}}
+				if h, ok := t.Hashes["sha256"]; ok {
+					st.Digest = "sha256:" + hex.EncodeToString(h)
+				}
+				tags[t.Name] = st
+			}
+			// The signers sign into their role and into the releases role.
+			switch role {
+			case client.ReleasesRole:
+			case data.CanonicalTargetsRole:
+				st.Signers = append(st.Signers, repositoryAdmin)
+			default:
+				st.Signers = append(st.Signers, path.Base(role))
+			}
+		}
+	}
+	for _, st := range tags {
+		sort.Strings(st.Signers)
+		trust.SignedTags = append(trust.SignedTags, *st)
+	}
+	sort.Sort(byTagName(trust.SignedTags))
+	return trust, nil
+}
+
+func sortedKeys(keyIDs []string) []string {
+	keys := append([]string{}, keyIDs...)
+	sort.Strings(keys)
+	return keys
+}
+
+type bySignerName []signer
+
+func (s bySignerName) Len() int           { return len(s) }
+func (s bySignerName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s bySignerName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type byTagName []signedTag
+
+func (s byTagName) Len() int           { return len(s) }
+func (s byTagName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTagName) Less(i, j int) bool { return s[i].Name < s[j].Name }
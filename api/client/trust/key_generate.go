@@ -0,0 +1,85 @@
+package trust
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/notary"
+	"github.com/docker/notary/cryptoservice"
+	"github.com/docker/notary/trustmanager"
+	"github.com/docker/notary/tuf/data"
+	"github.com/spf13/cobra"
+)
+
+type keyGenerateOptions struct {
+	name string
+	dir  string
+}
+
+func newKeyGenerateCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts keyGenerateOptions
+
+	cmd := &cobra.Command{
+		Use:   "generate [OPTIONS] NAME",
+		Short: "Generate the signing key of a signer",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runKeyGenerate(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.dir, "dir", "", "Directory to write the certificate of the key to, the current directory by default")
+
+	return cmd
+}
+
+func runKeyGenerate(dockerCli *client.DockerCli, opts keyGenerateOptions) error {
+	if err := validateSignerName(opts.name); err != nil {
+		return err
+	}
+	keyStore, err := dockerCli.TrustKeyStore()
+	if err != nil {
+		return err
+	}
+	cs := cryptoservice.NewCryptoService(keyStore)
+	pubKey, err := cs.Create(opts.name, "", data.ECDSAKey)
+	if err != nil {
+		return err
+	}
+	privKey, _, err := cs.GetPrivateKey(pubKey.ID())
+	if err != nil {
+		return err
+	}
+	certPath, err := writeCertificate(privKey, opts.name, opts.dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Generated the key %s of the signer %s, its certificate is in %s\n", pubKey.ID(), opts.name, certPath)
+	return nil
+}
+
+// writeCertificate writes the certificate of the key of a signer to the
+// file <name>.crt of dir, to add the signer to repositories with it.
+func writeCertificate(privKey data.PrivateKey, name, dir string) (string, error) {
+	start := time.Now()
+	cert, err := cryptoservice.GenerateCertificate(privKey, name, start, start.Add(notary.NotaryTargetsExpiry))
+	if err != nil {
+		return "", err
+	}
+	certPath := filepath.Join(dir, name+".crt")
+	f, err := os.OpenFile(certPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(trustmanager.CertToPEM(cert)); err != nil {
+		f.Close()
+		return "", err
+	}
+	return certPath, f.Close()
+}
@@ -0,0 +1,62 @@
+package trust
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/notary/trustmanager"
+	"github.com/docker/notary/tuf/utils"
+)
+
+func TestValidateSignerName(t *testing.T) {
+	for _, name := range []string{"alice", "ci-bot", "team_1"} {
+		if err := validateSignerName(name); err != nil {
+			t.Fatalf("expected %s to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Alice", "-alice", "a/b", "releases"} {
+		if err := validateSignerName(name); err == nil {
+			t.Fatalf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestWriteCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-trust-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	privKey, err := trustmanager.GenerateECDSAKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, err := writeCertificate(privKey, "alice", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certPath != filepath.Join(dir, "alice.crt") {
+		t.Fatalf("expected the certificate to be written to alice.crt, got %s", certPath)
+	}
+	pemBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := trustmanager.ParsePEMPublicKey(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The key signing a delegation is found by the canonical ID of the key
+	// of the certificate.
+	if keyID, err := utils.CanonicalKeyID(pubKey); err != nil || keyID != privKey.ID() {
+		t.Fatalf("expected the certificate to be of the key %s, got %s (%v)", privKey.ID(), keyID, err)
+	}
+
+	if _, err := writeCertificate(privKey, "alice", dir); err == nil {
+		t.Fatal("expected an existing certificate not to be overwritten")
+	}
+}
@@ -0,0 +1,75 @@
+package trust
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/notary/trustmanager"
+	"github.com/spf13/cobra"
+)
+
+type keyLoadOptions struct {
+	path string
+	name string
+	dir  string
+}
+
+func newKeyLoadCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts keyLoadOptions
+
+	cmd := &cobra.Command{
+		Use:   "load [OPTIONS] KEYFILE",
+		Short: "Load the private signing key of a signer from a PEM file",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.path = args[0]
+			return runKeyLoad(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.name, "name", "signer", "Name of the signer of the key")
+	flags.StringVar(&opts.dir, "dir", "", "Directory to write the certificate of the key to, the current directory by default")
+
+	return cmd
+}
+
+func runKeyLoad(dockerCli *client.DockerCli, opts keyLoadOptions) error {
+	if err := validateSignerName(opts.name); err != nil {
+		return err
+	}
+	pemBytes, err := ioutil.ReadFile(opts.path)
+	if err != nil {
+		return err
+	}
+	privKey, err := trustmanager.ParsePEMPrivateKey(pemBytes, "")
+	if err != nil {
+		block, _ := pem.Decode(pemBytes)
+		if block == nil || !x509.IsEncryptedPEMBlock(block) {
+			return fmt.Errorf("invalid private key %s: %v", opts.path, err)
+		}
+		privKey, _, err = trustmanager.GetPasswdDecryptBytes(dockerCli.PassphraseRetriever(), pemBytes, filepath.Base(opts.path), opts.name)
+		if err != nil {
+			return fmt.Errorf("cannot decrypt the private key %s: %v", opts.path, err)
+		}
+	}
+
+	keyStore, err := dockerCli.TrustKeyStore()
+	if err != nil {
+		return err
+	}
+	if err := keyStore.AddKey(trustmanager.KeyInfo{Role: opts.name, Gun: ""}, privKey); err != nil {
+		return err
+	}
+	certPath, err := writeCertificate(privKey, opts.name, opts.dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(dockerCli.Out(), "Loaded the key %s of the signer %s, its certificate is in %s\n", privKey.ID(), opts.name, certPath)
+	return nil
+}
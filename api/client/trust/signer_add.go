@@ -0,0 +1,111 @@
+package trust
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	notaryclient "github.com/docker/notary/client"
+	"github.com/docker/notary/trustmanager"
+	"github.com/docker/notary/tuf/data"
+	"github.com/spf13/cobra"
+)
+
+type signerAddOptions struct {
+	name         string
+	repositories []string
+	keys         []string
+}
+
+func newSignerAddCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts signerAddOptions
+
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] NAME REPOSITORY [REPOSITORY...]",
+		Short: "Add a signer to one or more repositories",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			opts.repositories = args[1:]
+			return runSignerAdd(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.keys, "key", nil, "Certificate of a key of the signer")
+
+	return cmd
+}
+
+func runSignerAdd(dockerCli *client.DockerCli, opts signerAddOptions) error {
+	if err := validateSignerName(opts.name); err != nil {
+		return err
+	}
+	if len(opts.keys) == 0 {
+		return errors.New("the certificate of a key of the signer is required, with --key")
+	}
+	var pubKeys []data.PublicKey
+	for _, path := range opts.keys {
+		pemBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		pubKey, err := trustmanager.ParsePEMPublicKey(pemBytes)
+		if err != nil {
+			return fmt.Errorf("invalid certificate %s: %v", path, err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+
+	status := 0
+	for _, name := range opts.repositories {
+		if err := addSigner(dockerCli, name, opts.name, pubKeys); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "Added the signer %s to %s\n", opts.name, name)
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// addSigner adds the keys of a signer to its delegation role and to the
+// releases role of a repository, initializing the trust data of the
+// repository if it has none yet. The tags pushed are then signed into both
+// roles with the keys.
+func addSigner(dockerCli *client.DockerCli, name, signer string, pubKeys []data.PublicKey) error {
+	named, err := parseRepository(name)
+	if err != nil {
+		return err
+	}
+	repo, repoInfo, err := notaryRepository(dockerCli, named, "push", "pull")
+	if err != nil {
+		return err
+	}
+
+	switch err := repo.Update(false); err.(type) {
+	case notaryclient.ErrRepoNotInitialized, notaryclient.ErrRepositoryNotExist:
+		if err := dockerCli.InitializeNotaryRepository(repo, repoInfo.FullName()); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+
+	for _, role := range []string{client.ReleasesRole, signerRole(signer)} {
+		if err := repo.AddDelegation(role, pubKeys, []string{""}); err != nil {
+			return client.NotaryError(repoInfo.FullName(), err)
+		}
+	}
+	if err := repo.Publish(); err != nil {
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package trust
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/notary/tuf/data"
+	"github.com/spf13/cobra"
+)
+
+type signerRemoveOptions struct {
+	name         string
+	repositories []string
+	force        bool
+}
+
+func newSignerRemoveCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts signerRemoveOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] NAME REPOSITORY [REPOSITORY...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove a signer from one or more repositories",
+		Args:    cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			opts.repositories = args[1:]
+			return runSignerRemove(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Remove the last signer of a repository, its signed tags not being trusted anymore")
+
+	return cmd
+}
+
+func runSignerRemove(dockerCli *client.DockerCli, opts signerRemoveOptions) error {
+	if err := validateSignerName(opts.name); err != nil {
+		return err
+	}
+
+	status := 0
+	for _, name := range opts.repositories {
+		if err := removeSigner(dockerCli, name, opts.name, opts.force); err != nil {
+			fmt.Fprintf(dockerCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(dockerCli.Out(), "Removed the signer %s from %s\n", opts.name, name)
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// removeSigner removes the delegation role of a signer from a repository,
+// and its keys from the releases role unless other signers have them. The
+// releases role is removed with the last signer, the tags being signed with
+// the key of the repository again.
+func removeSigner(dockerCli *client.DockerCli, name, signer string, force bool) error {
+	named, err := parseRepository(name)
+	if err != nil {
+		return err
+	}
+	repo, repoInfo, err := notaryRepository(dockerCli, named, "push", "pull")
+	if err != nil {
+		return err
+	}
+
+	roles, err := repo.ListRoles()
+	if err != nil {
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+	role := signerRole(signer)
+	var (
+		found      bool
+		keyIDs     []string
+		otherKeys  = make(map[string]bool)
+		lastSigner = true
+	)
+	for _, r := range roles {
+		switch {
+		case r.Name == role:
+			found = true
+			keyIDs = r.KeyIDs
+		case r.Name != client.ReleasesRole && path.Dir(r.Name) == data.CanonicalTargetsRole:
+			lastSigner = false
+			for _, keyID := range r.KeyIDs {
+				otherKeys[keyID] = true
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s is not a signer of %s", signer, repoInfo.FullName())
+	}
+	if lastSigner && !force {
+		return fmt.Errorf("%s is the last signer of %s, the tags it signed would not be trusted anymore: use --force to remove it", signer, repoInfo.FullName())
+	}
+
+	if err := repo.RemoveDelegationRole(role); err != nil {
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+	if lastSigner {
+		err = repo.RemoveDelegationRole(client.ReleasesRole)
+	} else {
+		var releasesKeys []string
+		for _, keyID := range keyIDs {
+			if !otherKeys[keyID] {
+				releasesKeys = append(releasesKeys, keyID)
+			}
+		}
+		if len(releasesKeys) > 0 {
+			err = repo.RemoveDelegationKeys(client.ReleasesRole, releasesKeys)
+		}
+	}
+	if err != nil {
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+	if err := repo.Publish(); err != nil {
+		return client.NotaryError(repoInfo.FullName(), err)
+	}
+	return nil
+}
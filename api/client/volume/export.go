@@ -0,0 +1,58 @@
+package volume
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	name   string
+	output string
+	pause  bool
+}
+
+func newExportCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS] VOLUME",
+		Short: "Export the content of a volume as a tar archive",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runExport(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.BoolVar(&opts.pause, "pause", false, "Pause the running containers using the volume during the export")
+
+	return cmd
+}
+
+func runExport(dockerCli *client.DockerCli, opts exportOptions) error {
+	if opts.output == "" && dockerCli.IsTerminalOut() {
+		return errors.New("Cowardly refusing to save to a terminal. Use the -o flag or redirect.")
+	}
+
+	responseBody, err := dockerCli.Client().VolumeExport(context.Background(), opts.name, types.VolumeExportOptions{Pause: opts.pause})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	if opts.output == "" {
+		_, err := io.Copy(dockerCli.Out(), responseBody)
+		return err
+	}
+
+	return client.CopyToFile(opts.output, responseBody)
+}
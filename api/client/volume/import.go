@@ -0,0 +1,53 @@
+package volume
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/cli"
+	"github.com/docker/engine-api/types"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	name  string
+	input string
+	pause bool
+}
+
+func newImportCommand(dockerCli *client.DockerCli) *cobra.Command {
+	var opts importOptions
+
+	cmd := &cobra.Command{
+		Use:   "import [OPTIONS] VOLUME",
+		Short: "Import the content of a tar archive into a volume",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runImport(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
+	flags.BoolVar(&opts.pause, "pause", false, "Pause the running containers using the volume during the import")
+
+	return cmd
+}
+
+func runImport(dockerCli *client.DockerCli, opts importOptions) error {
+	var input io.Reader = dockerCli.In()
+	if opts.input != "" {
+		file, err := os.Open(opts.input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	return dockerCli.Client().VolumeImport(context.Background(), opts.name, input, types.VolumeImportOptions{Pause: opts.pause})
+}
@@ -0,0 +1,106 @@
+// Package audit records the API calls changing the state of the daemon to an
+// audit log, in JSON.
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Event is the record of an API call in the audit log.
+type Event struct {
+	// Time is the time at which the call was completed.
+	Time time.Time `json:"time"`
+	// User is the identity of the client, from its TLS certificate.
+	User       string `json:"user,omitempty"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	// Parameters are the query parameters of the call.
+	Parameters url.Values `json:"parameters,omitempty"`
+	// Result is "success" or "failure".
+	Result string `json:"result"`
+	// StatusCode and Error are the status code and the message of the
+	// error of a call which failed.
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+const (
+	// ResultSuccess is the result of the calls which succeeded.
+	ResultSuccess = "success"
+	// ResultFailure is the result of the calls which failed.
+	ResultFailure = "failure"
+)
+
+// Logger writes the events to an audit log.
+type Logger interface {
+	Log(*Event) error
+	Close() error
+}
+
+// Creator creates a Logger from the options of its driver.
+type Creator func(opts map[string]string) (Logger, error)
+
+var drivers = make(map[string]Creator)
+
+// RegisterDriver registers the audit log driver name.
+func RegisterDriver(name string, c Creator) {
+	drivers[name] = c
+}
+
+// New returns a Logger of the driver name, configured with opts.
+func New(name string, opts map[string]string) (Logger, error) {
+	c, ok := drivers[name]
+	if !ok {
+		var names []string
+		for n := range drivers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("audit log driver %q is not supported, use one of %v", name, names)
+	}
+	return c(opts)
+}
+
+var versionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// Filter selects the API calls recorded in the audit log.
+type Filter struct {
+	patterns []string
+}
+
+// NewFilter returns a Filter selecting the calls changing the state of the
+// daemon whose path, without its API version, matches one of patterns. The
+// patterns use the syntax of path.Match, like "/containers/*/start". All the
+// calls changing the state of the daemon are selected if there is no pattern.
+func NewFilter(patterns []string) (*Filter, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, "/"); err != nil {
+			return nil, fmt.Errorf("invalid audit endpoint %q: %v", p, err)
+		}
+	}
+	return &Filter{patterns: patterns}, nil
+}
+
+// Audited returns whether a call is recorded in the audit log.
+func (f *Filter) Audited(method, p string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return false
+	}
+	if len(f.patterns) == 0 {
+		return true
+	}
+	p = versionPrefix.ReplaceAllString(p, "")
+	for _, pattern := range f.patterns {
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilterAudited(t *testing.T) {
+	all, err := NewFilter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected, err := NewFilter([]string{"/containers/*/start", "/images/create"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method   string
+		path     string
+		all      bool
+		selected bool
+	}{
+		{"GET", "/containers/json", false, false},
+		{"HEAD", "/containers/abc/archive", false, false},
+		{"POST", "/containers/abc/start", true, true},
+		{"POST", "/v1.25/containers/abc/start", true, true},
+		{"POST", "/containers/abc/stop", true, false},
+		{"DELETE", "/containers/abc", true, false},
+		{"POST", "/v1.24/images/create", true, true},
+		{"PUT", "/containers/abc/archive", true, false},
+	}
+	for _, c := range cases {
+		if audited := all.Audited(c.method, c.path); audited != c.all {
+			t.Fatalf("expected %s %s audited to be %v without patterns, got %v", c.method, c.path, c.all, audited)
+		}
+		if audited := selected.Audited(c.method, c.path); audited != c.selected {
+			t.Fatalf("expected %s %s audited to be %v, got %v", c.method, c.path, c.selected, audited)
+		}
+	}
+}
+
+func TestNewFilterInvalidPattern(t *testing.T) {
+	if _, err := NewFilter([]string{"/containers/[/start"}); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+}
+
+func TestNewUnknownDriver(t *testing.T) {
+	if _, err := New("unknown", nil); err == nil {
+		t.Fatal("expected an error for an unknown driver")
+	}
+}
+
+func TestFileLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := New("file", nil); err == nil {
+		t.Fatal("expected an error without path")
+	}
+	if _, err := New("file", map[string]string{"path": filepath.Join(dir, "audit.log"), "max-size": "1m"}); err == nil {
+		t.Fatal("expected an error for an unknown option")
+	}
+
+	p := filepath.Join(dir, "audit.log")
+	l, err := New("file", map[string]string{"path": p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []*Event{
+		{Time: time.Now().UTC(), User: "alice", Method: "POST", Path: "/containers/abc/start", Result: ResultSuccess},
+		{Time: time.Now().UTC(), Method: "DELETE", Path: "/images/busybox", Result: ResultFailure, StatusCode: 409, Error: "conflict"},
+	}
+	for _, e := range events {
+		if err := l.Log(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	var logged []Event
+	for s.Scan() {
+		var e Event
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		logged = append(logged, e)
+	}
+	if len(logged) != len(events) {
+		t.Fatalf("expected %d events, got %d", len(events), len(logged))
+	}
+	if logged[0].User != "alice" || logged[1].StatusCode != 409 || logged[1].Error != "conflict" {
+		t.Fatalf("unexpected events %+v", logged)
+	}
+}
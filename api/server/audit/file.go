@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+func init() {
+	RegisterDriver("file", newFileLogger)
+}
+
+// fileLogger appends the events to a file, one JSON object per line.
+type fileLogger struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func newFileLogger(opts map[string]string) (Logger, error) {
+	for k := range opts {
+		if k != "path" {
+			return nil, fmt.Errorf("unknown audit log opt '%s' for file audit log driver", k)
+		}
+	}
+	p := opts["path"]
+	if p == "" {
+		return nil, fmt.Errorf("the file audit log driver requires the path option")
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLogger{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (l *fileLogger) Log(e *Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(e)
+}
+
+func (l *fileLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/coreos/go-systemd/journal"
+)
+
+func init() {
+	RegisterDriver("journald", newJournaldLogger)
+}
+
+// journaldLogger sends the events to the journal. The user, the method, the
+// path and the result of the calls are also sent as fields of the journal
+// entries, to filter them with journalctl.
+type journaldLogger struct{}
+
+func newJournaldLogger(opts map[string]string) (Logger, error) {
+	for k := range opts {
+		return nil, fmt.Errorf("unknown audit log opt '%s' for journald audit log driver", k)
+	}
+	if !journal.Enabled() {
+		return nil, fmt.Errorf("journald is not enabled on this host")
+	}
+	return journaldLogger{}, nil
+}
+
+func (journaldLogger) Log(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	vars := map[string]string{
+		"SYSLOG_IDENTIFIER":   "docker-audit",
+		"DOCKER_AUDIT_METHOD": e.Method,
+		"DOCKER_AUDIT_PATH":   e.Path,
+		"DOCKER_AUDIT_RESULT": e.Result,
+	}
+	if e.User != "" {
+		vars["DOCKER_AUDIT_USER"] = e.User
+	}
+	if e.StatusCode != 0 {
+		vars["DOCKER_AUDIT_STATUS_CODE"] = strconv.Itoa(e.StatusCode)
+	}
+	return journal.Send(string(b), journal.PriInfo, vars)
+}
+
+func (journaldLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	syslog "github.com/RackSec/srslog"
+)
+
+func init() {
+	RegisterDriver("syslog", newSyslogLogger)
+}
+
+// syslogLogger sends the events to syslog, with the info priority of the
+// daemon facility.
+type syslogLogger struct {
+	w *syslog.Writer
+}
+
+func newSyslogLogger(opts map[string]string) (Logger, error) {
+	for k := range opts {
+		if k != "address" && k != "tag" {
+			return nil, fmt.Errorf("unknown audit log opt '%s' for syslog audit log driver", k)
+		}
+	}
+	tag := opts["tag"]
+	if tag == "" {
+		tag = "docker-audit"
+	}
+
+	// The local syslog is used if there is no address.
+	var network, address string
+	if opts["address"] != "" {
+		u, err := url.Parse(opts["address"])
+		if err != nil {
+			return nil, err
+		}
+		switch u.Scheme {
+		case "unix", "unixgram":
+			network, address = u.Scheme, u.Path
+		case "tcp", "udp":
+			network, address = u.Scheme, u.Host
+		default:
+			return nil, fmt.Errorf("unsupported syslog address %q, use unix, unixgram, tcp or udp", opts["address"])
+		}
+	}
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogLogger{w: w}, nil
+}
+
+func (l *syslogLogger) Log(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return l.w.Info(string(b))
+}
+
+func (l *syslogLogger) Close() error {
+	return l.w.Close()
+}
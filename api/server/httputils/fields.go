@@ -0,0 +1,85 @@
+package httputils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldsValue returns the list of fields of the form value k, which is a
+// comma-separated list like "Id,Names,Status".
+func FieldsValue(r *http.Request, k string) []string {
+	var fields []string
+	for _, f := range strings.Split(r.FormValue(k), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// WriteJSONFields writes v, a slice of structs or of pointers to structs,
+// as JSON, with only the given fields of each of its elements. The fields
+// are the names of the fields in JSON. All the fields are written if there
+// is none.
+func WriteJSONFields(w http.ResponseWriter, code int, v interface{}, fields []string) error {
+	if len(fields) == 0 {
+		return WriteJSON(w, code, v)
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for _, f := range fields {
+		if !known[f] {
+			return fmt.Errorf("invalid field '%s'", f)
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var elements []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &elements); err != nil {
+		return err
+	}
+	selected := make([]map[string]json.RawMessage, 0, len(elements))
+	for _, e := range elements {
+		s := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if value, ok := e[f]; ok {
+				s[f] = value
+			}
+		}
+		selected = append(selected, s)
+	}
+	return WriteJSON(w, code, selected)
+}
+
+// jsonFieldNames returns the names in JSON of the fields of the struct t, or
+// of the struct t points to.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
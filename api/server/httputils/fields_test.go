@@ -0,0 +1,60 @@
+package httputils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fieldsTestElement struct {
+	ID     string `json:"Id"`
+	Names  []string
+	Status string `json:",omitempty"`
+	Hidden string `json:"-"`
+}
+
+func TestFieldsValue(t *testing.T) {
+	r, _ := http.NewRequest("GET", "", nil)
+	r.Form = url.Values{"fields": {"Id, Names,,Status"}}
+	if fields := FieldsValue(r, "fields"); !reflect.DeepEqual(fields, []string{"Id", "Names", "Status"}) {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+	r.Form = url.Values{}
+	if fields := FieldsValue(r, "fields"); len(fields) != 0 {
+		t.Fatalf("expected no field, got %v", fields)
+	}
+}
+
+func TestWriteJSONFields(t *testing.T) {
+	elements := []*fieldsTestElement{
+		{ID: "1", Names: []string{"/a"}, Status: "Up", Hidden: "secret"},
+		{ID: "2", Names: []string{"/b"}},
+	}
+
+	cases := []struct {
+		fields   []string
+		expected string
+	}{
+		{nil, `[{"Id":"1","Names":["/a"],"Status":"Up"},{"Id":"2","Names":["/b"]}]`},
+		{[]string{"Id"}, `[{"Id":"1"},{"Id":"2"}]`},
+		{[]string{"Names", "Status"}, `[{"Names":["/a"],"Status":"Up"},{"Names":["/b"]}]`},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		if err := WriteJSONFields(w, http.StatusOK, elements, c.fields); err != nil {
+			t.Fatal(err)
+		}
+		if body := strings.TrimSpace(w.Body.String()); body != c.expected {
+			t.Fatalf("expected %s for fields %v, got %s", c.expected, c.fields, body)
+		}
+	}
+
+	for _, fields := range [][]string{{"Unknown"}, {"Hidden"}, {"ID"}} {
+		if err := WriteJSONFields(httptest.NewRecorder(), http.StatusOK, elements, fields); err == nil {
+			t.Fatalf("expected an error for fields %v", fields)
+		}
+	}
+}
@@ -18,6 +18,10 @@ const APIVersionKey = "api-version"
 // UAStringKey is used as key type for user-agent string in net/context struct
 const UAStringKey = "upstream-user-agent"
 
+// IdentityKey is used as key type for the identity of the client, from its
+// TLS certificate, in net/context struct
+const IdentityKey = "client-identity"
+
 // APIFunc is an adapter to allow the use of ordinary functions as Docker API endpoints.
 // Any function that has the appropriate signature can be registered as an API endpoint (e.g. getVersion).
 type APIFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error
@@ -104,3 +108,13 @@ func VersionFromContext(ctx context.Context) (ver string) {
 	}
 	return val.(string)
 }
+
+// IdentityFromContext returns the identity of the client from the context
+// using IdentityKey, or an empty string if the client has no TLS certificate.
+func IdentityFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	identity, _ := ctx.Value(IdentityKey).(string)
+	return identity
+}
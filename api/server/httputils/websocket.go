@@ -0,0 +1,37 @@
+package httputils
+
+import (
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// WebsocketPingInterval is the interval between the pings sent to the clients
+// of the WebSocket endpoints, keeping their connections open through the
+// proxies closing the idle connections.
+const WebsocketPingInterval = 30 * time.Second
+
+// pingCodec sends an empty ping frame. The client replies with a pong frame,
+// which the server discards.
+var pingCodec = websocket.Codec{
+	Marshal: func(v interface{}) ([]byte, byte, error) {
+		return nil, websocket.PingFrame, nil
+	},
+}
+
+// WebsocketKeepalive pings the client of conn every WebsocketPingInterval,
+// until stop is closed or a ping fails.
+func WebsocketKeepalive(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(WebsocketPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pingCodec.Send(conn, nil); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
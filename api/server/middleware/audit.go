@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/server/audit"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/envmask"
+	"golang.org/x/net/context"
+)
+
+// AuditMiddleware is a middleware that records the requests
+// changing the state of the daemon to an audit log.
+type AuditMiddleware struct {
+	logger audit.Logger
+	filter *audit.Filter
+	masker *envmask.Masker
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware recording
+// the requests selected by filter to logger, with the values of
+// the environment variables masked by masker.
+func NewAuditMiddleware(logger audit.Logger, filter *audit.Filter, masker *envmask.Masker) AuditMiddleware {
+	return AuditMiddleware{
+		logger: logger,
+		filter: filter,
+		masker: masker,
+	}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (a AuditMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if !a.filter.Audited(r.Method, r.URL.Path) {
+			return handler(ctx, w, r, vars)
+		}
+
+		err := handler(ctx, w, r, vars)
+
+		event := &audit.Event{
+			Time:       time.Now().UTC(),
+			User:       httputils.IdentityFromContext(ctx),
+			RemoteAddr: r.RemoteAddr,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Parameters: a.maskParameters(r.URL.Query()),
+			Result:     audit.ResultSuccess,
+		}
+		if err != nil {
+			event.Result = audit.ResultFailure
+			event.StatusCode = httputils.GetHTTPErrorStatusCode(err)
+			event.Error = a.masker.String(err.Error())
+		}
+		if logErr := a.logger.Log(event); logErr != nil {
+			logrus.Errorf("Error writing the audit log of %s %s: %v", r.Method, r.URL.Path, logErr)
+		}
+		return err
+	}
+}
+
+// maskParameters masks the values of the environment variables set by the
+// parameters of a call, such as the changes of a commit.
+func (a AuditMiddleware) maskParameters(params url.Values) url.Values {
+	if a.masker == nil {
+		return params
+	}
+	for k, values := range params {
+		for i, v := range values {
+			values[i] = a.masker.String(v)
+		}
+		params[k] = values
+	}
+	return params
+}
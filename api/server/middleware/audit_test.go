@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/api/server/audit"
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/pkg/envmask"
+	dockererrors "github.com/docker/docker/errors"
+	"golang.org/x/net/context"
+)
+
+type testAuditLogger struct {
+	events []*audit.Event
+}
+
+func (l *testAuditLogger) Log(e *audit.Event) error {
+	l.events = append(l.events, e)
+	return nil
+}
+
+func (l *testAuditLogger) Close() error {
+	return nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	logger := &testAuditLogger{}
+	filter, err := audit.NewFilter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewAuditMiddleware(logger, filter, nil)
+
+	var handlerErr error
+	h := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return handlerErr
+	})
+	ctx := context.WithValue(context.Background(), httputils.IdentityKey, "alice")
+
+	req, _ := http.NewRequest("GET", "/containers/json", nil)
+	if err := h(ctx, httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.events) != 0 {
+		t.Fatalf("expected GET requests not to be audited, got %d events", len(logger.events))
+	}
+
+	req, _ = http.NewRequest("POST", "/v1.25/containers/abc/kill?signal=HUP", nil)
+	if err := h(ctx, httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	handlerErr = dockererrors.NewErrorWithStatusCode(errors.New("container abc is not running"), http.StatusConflict)
+	if err := h(ctx, httptest.NewRecorder(), req, map[string]string{}); err != handlerErr {
+		t.Fatalf("expected the error of the handler, got %v", err)
+	}
+
+	if len(logger.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(logger.events))
+	}
+	e := logger.events[0]
+	if e.User != "alice" || e.Method != "POST" || e.Path != "/v1.25/containers/abc/kill" || e.Parameters.Get("signal") != "HUP" || e.Result != audit.ResultSuccess {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	e = logger.events[1]
+	if e.Result != audit.ResultFailure || e.StatusCode != http.StatusConflict || e.Error != "container abc is not running" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+}
+
+func TestAuditMiddlewareMasksSecrets(t *testing.T) {
+	logger := &testAuditLogger{}
+	filter, err := audit.NewFilter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masker, err := envmask.New([]string{"PASSWORD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewAuditMiddleware(logger, filter, masker)
+	h := m.WrapHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		return nil
+	})
+
+	req, _ := http.NewRequest("POST", "/commit?container=abc&changes=ENV+DB_PASSWORD%3Ds3cr3t&changes=ENV+USER%3Dadmin", nil)
+	if err := h(context.Background(), httptest.NewRecorder(), req, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(logger.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(logger.events))
+	}
+	changes := logger.events[0].Parameters["changes"]
+	if len(changes) != 2 || changes[0] != "ENV DB_PASSWORD="+envmask.Mask || changes[1] != "ENV USER=admin" {
+		t.Fatalf("expected the password to be masked, got %v", changes)
+	}
+}
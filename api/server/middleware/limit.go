@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"sync"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errors"
+	"golang.org/x/net/context"
+)
+
+// streamingRequests are the patterns of the paths, without the API version, of
+// the requests streaming for as long as the client wants. They are not
+// limited, as they would hold their slot forever.
+var streamingRequests = []string{
+	"/events",
+	"/events/ws",
+	"/containers/stats",
+	"/containers/*/attach",
+	"/containers/*/attach/ws",
+	"/containers/*/logs",
+	"/containers/*/stats",
+	"/containers/*/wait",
+	"/exec/*/start",
+}
+
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// LimitMiddleware is a middleware that limits the number of requests
+// handled concurrently by the daemon, and by each client. The requests over
+// the limit of the daemon wait in a queue for a request to complete. The
+// requests over the limit of their client, or which find the queue full, are
+// rejected with a 429 status code.
+type LimitMiddleware struct {
+	maxRequests          int
+	maxRequestsPerClient int
+	maxQueued            int
+
+	slots chan struct{}
+
+	mu      sync.Mutex
+	queued  int
+	clients map[string]int
+}
+
+// NewLimitMiddleware creates a new LimitMiddleware handling at most
+// maxRequests requests at a time, and maxRequestsPerClient requests at a time
+// for each client, with at most maxQueued requests waiting. A limit of 0 is
+// no limit.
+func NewLimitMiddleware(maxRequests, maxRequestsPerClient, maxQueued int) *LimitMiddleware {
+	m := &LimitMiddleware{
+		maxRequests:          maxRequests,
+		maxRequestsPerClient: maxRequestsPerClient,
+		maxQueued:            maxQueued,
+		clients:              make(map[string]int),
+	}
+	if maxRequests > 0 {
+		m.slots = make(chan struct{}, maxRequests)
+	}
+	return m
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m *LimitMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if isStreamingRequest(r.URL.Path) {
+			return handler(ctx, w, r, vars)
+		}
+
+		client := clientOf(ctx, r)
+		if err := m.acquireClient(client); err != nil {
+			w.Header().Set("Retry-After", "1")
+			return err
+		}
+		defer m.releaseClient(client)
+
+		if err := m.acquireSlot(); err != nil {
+			w.Header().Set("Retry-After", "1")
+			return err
+		}
+		defer m.releaseSlot()
+
+		return handler(ctx, w, r, vars)
+	}
+}
+
+func (m *LimitMiddleware) acquireClient(client string) error {
+	if m.maxRequestsPerClient <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.clients[client] >= m.maxRequestsPerClient {
+		return errors.NewErrorWithStatusCode(fmt.Errorf("too many concurrent requests: the limit of %d requests per client is reached", m.maxRequestsPerClient), http.StatusTooManyRequests)
+	}
+	m.clients[client]++
+	return nil
+}
+
+func (m *LimitMiddleware) releaseClient(client string) {
+	if m.maxRequestsPerClient <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.clients[client]--; m.clients[client] == 0 {
+		delete(m.clients, client)
+	}
+}
+
+// acquireSlot waits for a slot to handle a request, if there is room in the
+// queue.
+func (m *LimitMiddleware) acquireSlot() error {
+	if m.slots == nil {
+		return nil
+	}
+	select {
+	case m.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	m.mu.Lock()
+	if m.queued >= m.maxQueued {
+		m.mu.Unlock()
+		return errors.NewErrorWithStatusCode(fmt.Errorf("too many concurrent requests: the limit of %d requests is reached and %d requests are queued", m.maxRequests, m.maxQueued), http.StatusTooManyRequests)
+	}
+	m.queued++
+	m.mu.Unlock()
+
+	m.slots <- struct{}{}
+
+	m.mu.Lock()
+	m.queued--
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *LimitMiddleware) releaseSlot() {
+	if m.slots != nil {
+		<-m.slots
+	}
+}
+
+func isStreamingRequest(p string) bool {
+	p = apiVersionPrefix.ReplaceAllString(p, "")
+	for _, pattern := range streamingRequests {
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// clientOf returns the client of a request: the identity of its TLS
+// certificate, or else its IP address. All the clients of a unix socket are
+// the same client.
+func clientOf(ctx context.Context, r *http.Request) string {
+	if identity := httputils.IdentityFromContext(ctx); identity != "" {
+		return identity
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/server/httputils"
+	"golang.org/x/net/context"
+)
+
+func newBlockingHandler() (func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error, chan struct{}, chan struct{}) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	return handler, started, release
+}
+
+func serveLimited(h func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error, remoteAddr, path string) chan error {
+	errc := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest("POST", path, nil)
+		req.RemoteAddr = remoteAddr
+		errc <- h(context.Background(), httptest.NewRecorder(), req, map[string]string{})
+	}()
+	return errc
+}
+
+func expectTooManyRequests(t *testing.T, err error) {
+	if err == nil {
+		t.Fatal("expected the request to be rejected")
+	}
+	if code := httputils.GetHTTPErrorStatusCode(err); code != http.StatusTooManyRequests {
+		t.Fatalf("expected status code %d, got %d (%v)", http.StatusTooManyRequests, code, err)
+	}
+}
+
+func TestLimitMiddlewareQueue(t *testing.T) {
+	handler, started, release := newBlockingHandler()
+	h := NewLimitMiddleware(1, 0, 1).WrapHandler(handler)
+
+	first := serveLimited(h, "10.0.0.1:1000", "/v1.25/images/create")
+	<-started
+	queued := serveLimited(h, "10.0.0.2:1000", "/build")
+
+	// Wait for the second request to be queued.
+	time.Sleep(100 * time.Millisecond)
+	expectTooManyRequests(t, <-serveLimited(h, "10.0.0.3:1000", "/build"))
+
+	release <- struct{}{}
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	release <- struct{}{}
+	if err := <-queued; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimitMiddlewarePerClient(t *testing.T) {
+	handler, started, release := newBlockingHandler()
+	h := NewLimitMiddleware(0, 1, 0).WrapHandler(handler)
+
+	first := serveLimited(h, "10.0.0.1:1000", "/build")
+	<-started
+	expectTooManyRequests(t, <-serveLimited(h, "10.0.0.1:1001", "/build"))
+
+	other := serveLimited(h, "10.0.0.2:1000", "/build")
+	<-started
+	release <- struct{}{}
+	release <- struct{}{}
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-other; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimitMiddlewareStreamingRequests(t *testing.T) {
+	handler, started, release := newBlockingHandler()
+	h := NewLimitMiddleware(1, 1, 0).WrapHandler(handler)
+
+	events := serveLimited(h, "10.0.0.1:1000", "/v1.25/events")
+	<-started
+	attach := serveLimited(h, "10.0.0.1:1000", "/containers/abc/attach")
+	<-started
+	build := serveLimited(h, "10.0.0.1:1000", "/build")
+	<-started
+	for i := 0; i < 3; i++ {
+		release <- struct{}{}
+	}
+	for _, errc := range []chan error{events, attach, build} {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
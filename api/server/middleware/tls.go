@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/server/tlsauth"
+	"github.com/docker/docker/errors"
+	"golang.org/x/net/context"
+)
+
+// TLSMiddleware is a middleware that rejects the requests of the clients
+// whose certificate is revoked, and adds the identity of the clients to the
+// context of their requests.
+type TLSMiddleware struct {
+	store *tlsauth.Store
+}
+
+// NewTLSMiddleware creates a new TLSMiddleware verifying the client
+// certificates with store.
+func NewTLSMiddleware(store *tlsauth.Store) TLSMiddleware {
+	return TLSMiddleware{
+		store: store,
+	}
+}
+
+// WrapHandler returns a new handler function wrapping the previous one in the request chain.
+func (m TLSMiddleware) WrapHandler(handler func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error) func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+		if err := m.store.Verify(r.TLS); err != nil {
+			return errors.NewErrorWithStatusCode(err, http.StatusUnauthorized)
+		}
+		if identity := tlsauth.Identity(r.TLS); identity != "" {
+			ctx = context.WithValue(ctx, httputils.IdentityKey, identity)
+		}
+		return handler(ctx, w, r, vars)
+	}
+}
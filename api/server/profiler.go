@@ -3,6 +3,7 @@ package server
 import (
 	"expvar"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
 
@@ -25,6 +26,14 @@ func profilerSetup(mainRouter *mux.Router) {
 	r.HandleFunc("/pprof/threadcreate", pprof.Handler("threadcreate").ServeHTTP)
 }
 
+// ServeProfiler serves the profiler routes, and only them, on l until l is
+// closed.
+func ServeProfiler(l net.Listener) error {
+	m := mux.NewRouter()
+	profilerSetup(m)
+	return http.Serve(l, m)
+}
+
 // Replicated from expvar.go as not public.
 func expVars(w http.ResponseWriter, r *http.Request) {
 	first := true
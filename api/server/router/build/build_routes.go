@@ -42,6 +42,8 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 	options.SuppressOutput = httputils.BoolValue(r, "q")
 	options.NoCache = httputils.BoolValue(r, "nocache")
 	options.ForceRemove = httputils.BoolValue(r, "forcerm")
+	options.Squash = httputils.BoolValue(r, "squash")
+	options.NoOnBuild = httputils.BoolValue(r, "noonbuild")
 	options.MemorySwap = httputils.Int64ValueOrZero(r, "memswap")
 	options.Memory = httputils.Int64ValueOrZero(r, "memory")
 	options.CPUShares = httputils.Int64ValueOrZero(r, "cpushares")
@@ -51,6 +53,8 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 	options.CPUSetMems = r.FormValue("cpusetmems")
 	options.CgroupParent = r.FormValue("cgroupparent")
 	options.Tags = r.Form["t"]
+	options.Target = r.FormValue("target")
+	options.Platform = r.FormValue("platform")
 
 	if r.Form.Get("shmsize") != "" {
 		shmSize, err := strconv.ParseInt(r.Form.Get("shmsize"), 10, 64)
@@ -93,6 +97,33 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 		options.Labels = labels
 	}
 
+	var cacheFrom = []string{}
+	cacheFromJSON := r.FormValue("cachefrom")
+	if cacheFromJSON != "" {
+		if err := json.NewDecoder(strings.NewReader(cacheFromJSON)).Decode(&cacheFrom); err != nil {
+			return nil, err
+		}
+		options.CacheFrom = cacheFrom
+	}
+
+	var onBuildAllow = []string{}
+	onBuildAllowJSON := r.FormValue("onbuildallow")
+	if onBuildAllowJSON != "" {
+		if err := json.NewDecoder(strings.NewReader(onBuildAllowJSON)).Decode(&onBuildAllow); err != nil {
+			return nil, err
+		}
+		options.OnBuildAllow = onBuildAllow
+	}
+
+	var sshAgents = map[string]string{}
+	sshJSON := r.FormValue("ssh")
+	if sshJSON != "" {
+		if err := json.NewDecoder(strings.NewReader(sshJSON)).Decode(&sshAgents); err != nil {
+			return nil, err
+		}
+		options.SSHAgents = sshAgents
+	}
+
 	return options, nil
 }
 
@@ -108,6 +139,24 @@ func (s *syncWriter) Write(b []byte) (count int, err error) {
 	return
 }
 
+// stepOutput writes the structured output of a build: the progress of each
+// step as out-of-band data, and the output of the steps tagged with their
+// index.
+type stepOutput struct {
+	out io.Writer
+	sf  *streamformatter.StreamFormatter
+}
+
+func (s *stepOutput) Step(step types.BuildStep) {
+	s.out.Write(s.sf.FormatAux(strconv.Itoa(step.Index), step))
+}
+
+func (s *stepOutput) Output(index int) (io.Writer, io.Writer) {
+	id := strconv.Itoa(index)
+	return &streamformatter.StdoutFormatter{Writer: s.out, StreamFormatter: s.sf, ID: id},
+		&streamformatter.StderrFormatter{Writer: s.out, StreamFormatter: s.sf, ID: id}
+}
+
 func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	var (
 		authConfigs        = map[string]types.AuthConfig{}
@@ -124,7 +173,12 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	structured := strings.Contains(r.Header.Get("Accept"), types.MediaTypeBuildSteps)
+	if structured {
+		w.Header().Set("Content-Type", types.MediaTypeBuildSteps)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
 
 	output := ioutils.NewWriteFlusher(w)
 	defer output.Close()
@@ -135,7 +189,9 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		}
 		// Do not write the error in the http output if it's still empty.
 		// This prevents from writing a 200(OK) when there is an internal error.
-		if !output.Flushed() {
+		// Errors located in the Dockerfile are always streamed, so that the
+		// client gets their position.
+		if _, ok := err.(streamformatter.PositionalError); !ok && !output.Flushed() {
 			return err
 		}
 		_, err = w.Write(sf.FormatError(err))
@@ -151,6 +207,13 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 	}
 	buildOptions.AuthConfigs = authConfigs
 
+	if secretsEncoded := r.Header.Get("X-Build-Secrets"); secretsEncoded != "" {
+		secretsJSON := base64.NewDecoder(base64.URLEncoding, strings.NewReader(secretsEncoded))
+		if err := json.NewDecoder(secretsJSON).Decode(&buildOptions.Secrets); err != nil {
+			return errf(fmt.Errorf("invalid build secrets: %v", err))
+		}
+	}
+
 	remoteURL := r.FormValue("remote")
 
 	// Currently, only used if context is from a remote url.
@@ -177,6 +240,9 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		StderrFormatter:    stderr,
 		ProgressReaderFunc: createProgressReader,
 	}
+	if structured {
+		pg.StepOutput = &stepOutput{out: out, sf: sf}
+	}
 
 	imgID, err := br.backend.BuildFromContext(ctx, r.Body, remoteURL, buildOptions, pg)
 	if err != nil {
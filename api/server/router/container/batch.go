@@ -0,0 +1,112 @@
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/versions"
+	"golang.org/x/net/context"
+)
+
+// defaultBatchParallelism is the number of containers handled at a time by
+// the batch requests not giving their parallelism.
+const defaultBatchParallelism = 10
+
+func (s *containerRouter) postContainersBatchStart(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	ids, parallelism, err := parseBatchForm(r)
+	if err != nil {
+		return err
+	}
+	validateHostname := versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.24")
+	results := runBatch(ids, parallelism, func(id string) error {
+		return s.backend.ContainerStart(id, nil, validateHostname)
+	})
+	return httputils.WriteJSON(w, http.StatusOK, results)
+}
+
+func (s *containerRouter) postContainersBatchStop(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	ids, parallelism, err := parseBatchForm(r)
+	if err != nil {
+		return err
+	}
+	seconds, _ := strconv.Atoi(r.Form.Get("t"))
+	results := runBatch(ids, parallelism, func(id string) error {
+		return s.backend.ContainerStop(id, seconds)
+	})
+	return httputils.WriteJSON(w, http.StatusOK, results)
+}
+
+func (s *containerRouter) postContainersBatchRemove(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	ids, parallelism, err := parseBatchForm(r)
+	if err != nil {
+		return err
+	}
+	config := &types.ContainerRmConfig{
+		ForceRemove:  httputils.BoolValue(r, "force"),
+		RemoveVolume: httputils.BoolValue(r, "v"),
+		RemoveLink:   httputils.BoolValue(r, "link"),
+	}
+	results := runBatch(ids, parallelism, func(id string) error {
+		return s.backend.ContainerRm(id, config)
+	})
+	return httputils.WriteJSON(w, http.StatusOK, results)
+}
+
+// parseBatchForm returns the containers and the parallelism of a batch
+// request.
+func parseBatchForm(r *http.Request) ([]string, int, error) {
+	if err := httputils.ParseForm(r); err != nil {
+		return nil, 0, err
+	}
+
+	var ids []string
+	for _, id := range strings.Split(r.Form.Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, 0, validationError{fmt.Errorf("no container given in the ids parameter")}
+	}
+
+	parallelism := defaultBatchParallelism
+	if p := r.Form.Get("parallelism"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, 0, validationError{fmt.Errorf("invalid parallelism %q: it must be a positive integer", p)}
+		}
+		if n > 0 {
+			parallelism = n
+		}
+	}
+	return ids, parallelism, nil
+}
+
+// runBatch runs op on each of the containers ids, at most parallelism at a
+// time, and returns their results in the order of ids.
+func runBatch(ids []string, parallelism int, op func(id string) error) []types.ContainerBatchResult {
+	results := make([]types.ContainerBatchResult, len(ids))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].ID = id
+			if err := op(id); err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	return results
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
 	"github.com/docker/engine-api/types/filters"
+	timetypes "github.com/docker/engine-api/types/time"
 	"github.com/docker/engine-api/types/versions"
 	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
@@ -48,12 +49,20 @@ func (s *containerRouter) getContainersJSON(ctx context.Context, w http.Response
 		config.Limit = limit
 	}
 
+	if tmpOffset := r.Form.Get("offset"); tmpOffset != "" {
+		offset, err := strconv.Atoi(tmpOffset)
+		if err != nil {
+			return err
+		}
+		config.Offset = offset
+	}
+
 	containers, err := s.backend.Containers(config)
 	if err != nil {
 		return err
 	}
 
-	return httputils.WriteJSON(w, http.StatusOK, containers)
+	return httputils.WriteJSONFields(w, http.StatusOK, containers, httputils.FieldsValue(r, "fields"))
 }
 
 func (s *containerRouter) getContainersStats(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -75,6 +84,37 @@ func (s *containerRouter) getContainersStats(ctx context.Context, w http.Respons
 	return s.backend.ContainerStats(ctx, vars["name"], config)
 }
 
+func (s *containerRouter) getContainersStatsHistory(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var since time.Time
+	if v := r.Form.Get("since"); v != "" {
+		s, n, err := timetypes.ParseTimestamps(v, 0)
+		if err != nil {
+			return err
+		}
+		since = time.Unix(s, n)
+	}
+
+	samples, err := s.backend.ContainerStatsHistory(vars["name"], since)
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, samples)
+}
+
+func (s *containerRouter) getContainersStatsAll(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	config := &backend.ContainerStatsConfig{
+		Stream:    true,
+		OutStream: w,
+		Version:   string(httputils.VersionFromContext(ctx)),
+	}
+
+	return s.backend.ContainerStatsAll(ctx, config)
+}
+
 func (s *containerRouter) getContainersLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -121,7 +161,10 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 }
 
 func (s *containerRouter) getContainersExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
-	return s.backend.ContainerExport(vars["name"], w)
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	return s.backend.ContainerExport(vars["name"], httputils.BoolValue(r, "metadata"), w)
 }
 
 func (s *containerRouter) postContainersStart(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -258,14 +301,12 @@ func (s *containerRouter) postContainersUnpause(ctx context.Context, w http.Resp
 }
 
 func (s *containerRouter) postContainersWait(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
-	status, err := s.backend.ContainerWait(vars["name"], -1*time.Second)
+	status, err := s.backend.ContainerWaitStatus(vars["name"], -1*time.Second)
 	if err != nil {
 		return err
 	}
 
-	return httputils.WriteJSON(w, http.StatusOK, &types.ContainerWaitResponse{
-		StatusCode: status,
-	})
+	return httputils.WriteJSON(w, http.StatusOK, &status)
 }
 
 func (s *containerRouter) getContainersChanges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -277,6 +318,15 @@ func (s *containerRouter) getContainersChanges(ctx context.Context, w http.Respo
 	return httputils.WriteJSON(w, http.StatusOK, changes)
 }
 
+func (s *containerRouter) getContainersEnv(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	env, err := s.backend.ContainerEnv(vars["name"])
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, env)
+}
+
 func (s *containerRouter) getContainersTop(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -304,6 +354,27 @@ func (s *containerRouter) postContainerRename(ctx context.Context, w http.Respon
 	return nil
 }
 
+func (s *containerRouter) postContainerClone(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var cloneConfig types.ContainerCloneConfig
+	if err := json.NewDecoder(r.Body).Decode(&cloneConfig); err != nil {
+		return err
+	}
+
+	ccr, err := s.backend.ContainerClone(vars["name"], cloneConfig)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, ccr)
+}
+
 func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -490,6 +561,7 @@ func (s *containerRouter) wsContainersAttach(ctx context.Context, w http.Respons
 	setupStreams := func() (io.ReadCloser, io.Writer, io.Writer, error) {
 		wsChan := make(chan *websocket.Conn)
 		h := func(conn *websocket.Conn) {
+			go httputils.WebsocketKeepalive(conn, done)
 			wsChan <- conn
 			<-done
 		}
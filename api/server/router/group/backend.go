@@ -0,0 +1,14 @@
+package group
+
+import (
+	// TODO return types need to be refactored into pkg
+	"github.com/docker/engine-api/types"
+)
+
+// Backend is the methods that need to be implemented to provide
+// group specific functionality
+type Backend interface {
+	Groups() []*types.Group
+	GroupCreate(req types.GroupCreateRequest) (*types.Group, error)
+	GroupRm(name string) error
+}
@@ -0,0 +1,34 @@
+package group
+
+import "github.com/docker/docker/api/server/router"
+
+// groupRouter is a router to talk with the groups controller
+type groupRouter struct {
+	backend Backend
+	routes  []router.Route
+}
+
+// NewRouter initializes a new group router
+func NewRouter(b Backend) router.Router {
+	r := &groupRouter{
+		backend: b,
+	}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routes to the groups controller
+func (r *groupRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *groupRouter) initRoutes() {
+	r.routes = []router.Route{
+		// GET
+		router.NewGetRoute("/groups", r.getGroupsList),
+		// POST
+		router.NewPostRoute("/groups/create", r.postGroupsCreate),
+		// DELETE
+		router.NewDeleteRoute("/groups/{name:.*}", r.deleteGroups),
+	}
+}
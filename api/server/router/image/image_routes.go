@@ -105,7 +105,7 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 			}
 		}
 
-		err = s.backend.PullImage(ctx, image, tag, metaHeaders, authConfig, output)
+		err = s.backend.PullImage(ctx, image, tag, r.Form.Get("platform"), metaHeaders, authConfig, output)
 	} else { //import
 		src := r.Form.Get("fromSrc")
 		// 'err' MUST NOT be defined within this block, we need any error
@@ -185,7 +185,7 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	if err := s.backend.ExportImage(names, r.Form["exclude"], r.Form.Get("format"), r.Form.Get("compression"), output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -250,18 +250,41 @@ func (s *imageRouter) getImagesJSON(ctx context.Context, w http.ResponseWriter,
 		return err
 	}
 
+	limit, err := httputils.Int64ValueOrDefault(r, "limit", 0)
+	if err != nil {
+		return err
+	}
+	offset, err := httputils.Int64ValueOrDefault(r, "offset", 0)
+	if err != nil {
+		return err
+	}
+
 	// FIXME: The filter parameter could just be a match filter
 	images, err := s.backend.Images(r.Form.Get("filters"), r.Form.Get("filter"), httputils.BoolValue(r, "all"))
 	if err != nil {
 		return err
 	}
 
-	return httputils.WriteJSON(w, http.StatusOK, images)
+	// The images are sorted from the most recent to the oldest.
+	if offset > 0 {
+		if offset > int64(len(images)) {
+			offset = int64(len(images))
+		}
+		images = images[offset:]
+	}
+	if limit > 0 && limit < int64(len(images)) {
+		images = images[:limit]
+	}
+
+	return httputils.WriteJSONFields(w, http.StatusOK, images, httputils.FieldsValue(r, "fields"))
 }
 
 func (s *imageRouter) getImagesHistory(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
 	name := vars["name"]
-	history, err := s.backend.ImageHistory(name)
+	history, err := s.backend.ImageHistory(name, httputils.BoolValue(r, "disksize"))
 	if err != nil {
 		return err
 	}
@@ -183,6 +183,7 @@ func (n *networkRouter) buildNetworkResource(nw libnetwork.Network) *types.Netwo
 	buildIpamResources(r, info)
 	r.Internal = info.Internal()
 	r.Labels = info.Labels()
+	r.DNSUpstreams = info.DNSUpstreams()
 
 	epl := nw.Endpoints()
 	for _, e := range epl {
@@ -281,5 +282,16 @@ func buildEndpointResource(e libnetwork.Endpoint) types.EndpointResource {
 			er.IPv6Address = ipv6.String()
 		}
 	}
+	if gw := ei.Gateway(); len(gw) > 0 {
+		er.Gateway = gw.String()
+	}
+	if gw6 := ei.GatewayIPv6(); len(gw6) > 0 {
+		er.IPv6Gateway = gw6.String()
+	}
+	if a, ok := e.(interface {
+		MyAliases() []string
+	}); ok {
+		er.Aliases = a.MyAliases()
+	}
 	return er
 }
@@ -0,0 +1,14 @@
+package secret
+
+import (
+	// TODO return types need to be refactored into pkg
+	"github.com/docker/engine-api/types"
+)
+
+// Backend is the methods that need to be implemented to provide
+// secret specific functionality
+type Backend interface {
+	Secrets() []types.Secret
+	SecretCreate(req types.SecretCreateRequest) (*types.Secret, error)
+	SecretRm(nameOrID string) error
+}
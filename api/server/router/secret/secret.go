@@ -0,0 +1,34 @@
+package secret
+
+import "github.com/docker/docker/api/server/router"
+
+// secretRouter is a router to talk with the secrets controller
+type secretRouter struct {
+	backend Backend
+	routes  []router.Route
+}
+
+// NewRouter initializes a new secret router
+func NewRouter(b Backend) router.Router {
+	r := &secretRouter{
+		backend: b,
+	}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routes to the secrets controller
+func (r *secretRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *secretRouter) initRoutes() {
+	r.routes = []router.Route{
+		// GET
+		router.NewGetRoute("/secrets", r.getSecretsList),
+		// POST
+		router.NewPostRoute("/secrets/create", r.postSecretsCreate),
+		// DELETE
+		router.NewDeleteRoute("/secrets/{name:.*}", r.deleteSecrets),
+	}
+}
@@ -14,6 +14,10 @@ import (
 type Backend interface {
 	SystemInfo() (*types.Info, error)
 	SystemVersion() types.Version
+	SystemDiagnostics() (*types.Diagnostics, error)
+	SystemDiskUsage() (*types.DiskUsage, error)
+	LogLevels() types.LogLevels
+	SetLogLevels(levels types.LogLevels) error
 	SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{})
 	UnsubscribeFromEvents(chan interface{})
 	AuthenticateToRegistry(ctx context.Context, authConfig *types.AuthConfig) (string, string, error)
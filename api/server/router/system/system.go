@@ -24,8 +24,13 @@ func NewRouter(b Backend, c *cluster.Cluster) router.Router {
 		router.NewOptionsRoute("/{anyroute:.*}", optionsHandler),
 		router.NewGetRoute("/_ping", pingHandler),
 		router.Cancellable(router.NewGetRoute("/events", r.getEvents)),
+		router.Cancellable(router.NewGetRoute("/events/ws", r.wsGetEvents)),
 		router.NewGetRoute("/info", r.getInfo),
 		router.NewGetRoute("/version", r.getVersion),
+		router.NewGetRoute("/debug", r.getDebug),
+		router.NewGetRoute("/system/df", r.getDiskUsage),
+		router.NewGetRoute("/log-levels", r.getLogLevels),
+		router.NewPostRoute("/log-levels", r.postLogLevels),
 		router.NewPostRoute("/auth", r.postAuth),
 	}
 
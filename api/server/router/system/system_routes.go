@@ -3,7 +3,10 @@ package system
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -16,6 +19,7 @@ import (
 	"github.com/docker/engine-api/types/filters"
 	timetypes "github.com/docker/engine-api/types/time"
 	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
 )
 
 func optionsHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
@@ -47,40 +51,90 @@ func (s *systemRouter) getVersion(ctx context.Context, w http.ResponseWriter, r
 	return httputils.WriteJSON(w, http.StatusOK, info)
 }
 
-func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
-	if err := httputils.ParseForm(r); err != nil {
+func (s *systemRouter) getDebug(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	diagnostics, err := s.backend.SystemDiagnostics()
+	if err != nil {
 		return err
 	}
 
-	since, err := eventTime(r.Form.Get("since"))
+	return httputils.WriteJSON(w, http.StatusOK, diagnostics)
+}
+
+func (s *systemRouter) getDiskUsage(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	du, err := s.backend.SystemDiskUsage()
 	if err != nil {
 		return err
 	}
-	until, err := eventTime(r.Form.Get("until"))
-	if err != nil {
+
+	return httputils.WriteJSON(w, http.StatusOK, du)
+}
+
+func (s *systemRouter) getLogLevels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	return httputils.WriteJSON(w, http.StatusOK, s.backend.LogLevels())
+}
+
+func (s *systemRouter) postLogLevels(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.CheckForJSON(r); err != nil {
 		return err
 	}
 
-	var (
-		timeout        <-chan time.Time
-		onlyPastEvents bool
-	)
-	if !until.IsZero() {
-		if until.Before(since) {
-			return errors.NewBadRequestError(fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", r.Form.Get("since"), r.Form.Get("until")))
-		}
+	var levels types.LogLevels
+	if err := json.NewDecoder(r.Body).Decode(&levels); err != nil {
+		return errors.NewBadRequestError(err)
+	}
+	if err := s.backend.SetLogLevels(levels); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
 
-		now := time.Now()
+// eventsRequest holds the parameters of a request for the events.
+type eventsRequest struct {
+	since, until time.Time
+	filters      filters.Args
+	// cursor is the time in nanoseconds of the last event received by the
+	// client before it reconnected. The events up to the cursor are not sent
+	// again.
+	cursor int64
+}
 
-		onlyPastEvents = until.Before(now)
+func parseEventsRequest(r *http.Request) (*eventsRequest, error) {
+	since, err := eventTime(r.Form.Get("since"))
+	if err != nil {
+		return nil, err
+	}
+	until, err := eventTime(r.Form.Get("until"))
+	if err != nil {
+		return nil, err
+	}
+	if !until.IsZero() && until.Before(since) {
+		return nil, errors.NewBadRequestError(fmt.Errorf("`since` time (%s) cannot be after `until` time (%s)", r.Form.Get("since"), r.Form.Get("until")))
+	}
 
-		if !onlyPastEvents {
-			dur := until.Sub(now)
-			timeout = time.NewTimer(dur).C
+	var cursor int64
+	if c := r.Form.Get("cursor"); c != "" {
+		cursor, err = strconv.ParseInt(c, 10, 64)
+		if err != nil || cursor < 0 {
+			return nil, errors.NewBadRequestError(fmt.Errorf("invalid cursor %q: it must be the timeNano of an event", c))
+		}
+		if t := time.Unix(0, cursor); t.After(since) {
+			since = t
 		}
 	}
 
 	ef, err := filters.FromParam(r.Form.Get("filters"))
+	if err != nil {
+		return nil, err
+	}
+	return &eventsRequest{since: since, until: until, filters: ef, cursor: cursor}, nil
+}
+
+func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	req, err := parseEventsRequest(r)
 	if err != nil {
 		return err
 	}
@@ -91,12 +145,69 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 	output.Flush()
 
 	enc := json.NewEncoder(output)
+	return s.streamEvents(ctx, req, func(ev events.Message) error {
+		return enc.Encode(ev)
+	}, nil)
+}
 
-	buffered, l := s.backend.SubscribeToEvents(since, until, ef)
+func (s *systemRouter) wsGetEvents(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	req, err := parseEventsRequest(r)
+	if err != nil {
+		return err
+	}
+
+	h := func(conn *websocket.Conn) {
+		// The client sends nothing but the control frames, read to handle
+		// its pongs and to notice when it closes the connection.
+		closed := make(chan struct{})
+		go func() {
+			io.Copy(ioutil.Discard, conn)
+			close(closed)
+		}()
+		go httputils.WebsocketKeepalive(conn, closed)
+
+		if err := s.streamEvents(ctx, req, func(ev events.Message) error {
+			return websocket.JSON.Send(conn, ev)
+		}, closed); err != nil {
+			logrus.Debugf("Error sending the events over websocket: %v", err)
+		}
+		conn.Close()
+	}
+
+	srv := websocket.Server{Handler: h, Handshake: nil}
+	srv.ServeHTTP(w, r)
+	return nil
+}
+
+// streamEvents sends the events requested with send, until the end of the
+// request, the cancellation of ctx or the closing of stop.
+func (s *systemRouter) streamEvents(ctx context.Context, req *eventsRequest, send func(events.Message) error, stop <-chan struct{}) error {
+	var (
+		timeout        <-chan time.Time
+		onlyPastEvents bool
+	)
+	if !req.until.IsZero() {
+		now := time.Now()
+
+		onlyPastEvents = req.until.Before(now)
+
+		if !onlyPastEvents {
+			dur := req.until.Sub(now)
+			timeout = time.NewTimer(dur).C
+		}
+	}
+
+	buffered, l := s.backend.SubscribeToEvents(req.since, req.until, req.filters)
 	defer s.backend.UnsubscribeFromEvents(l)
 
 	for _, ev := range buffered {
-		if err := enc.Encode(ev); err != nil {
+		if ev.TimeNano <= req.cursor {
+			continue
+		}
+		if err := send(ev); err != nil {
 			return err
 		}
 	}
@@ -113,11 +224,16 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 				logrus.Warnf("unexpected event message: %q", ev)
 				continue
 			}
-			if err := enc.Encode(jev); err != nil {
+			if jev.TimeNano <= req.cursor {
+				continue
+			}
+			if err := send(jev); err != nil {
 				return err
 			}
 		case <-timeout:
 			return nil
+		case <-stop:
+			return nil
 		case <-ctx.Done():
 			logrus.Debug("Client context cancelled, stop sending events")
 			return nil
@@ -1,6 +1,8 @@
 package volume
 
 import (
+	"io"
+
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/engine-api/types"
 )
@@ -12,4 +14,6 @@ type Backend interface {
 	VolumeInspect(name string) (*types.Volume, error)
 	VolumeCreate(name, driverName string, opts, labels map[string]string) (*types.Volume, error)
 	VolumeRm(name string) error
+	VolumeExport(name string, pause bool) (io.ReadCloser, error)
+	VolumeImport(name string, content io.Reader, pause bool) error
 }
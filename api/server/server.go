@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/api/server/middleware"
 	"github.com/docker/docker/api/server/router"
 	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/loglevel"
 	"github.com/gorilla/mux"
 	"golang.org/x/net/context"
 )
@@ -137,7 +138,7 @@ func (s *Server) makeHTTPHandler(handler httputils.APIFunc) http.HandlerFunc {
 		}
 
 		if err := handlerFunc(ctx, w, r, vars); err != nil {
-			logrus.Errorf("Handler for %s %s returned error: %v", r.Method, r.URL.Path, err)
+			loglevel.WithSubsystem(loglevel.API).Errorf("Handler for %s %s returned error: %v", r.Method, r.URL.Path, err)
 			httputils.MakeErrorHandler(err)(w, r)
 		}
 	}
@@ -163,12 +164,12 @@ func (s *Server) InitRouter(enableProfiler bool, routers ...router.Router) {
 func (s *Server) createMux() *mux.Router {
 	m := mux.NewRouter()
 
-	logrus.Debug("Registering routers")
+	loglevel.WithSubsystem(loglevel.API).Debug("Registering routers")
 	for _, apiRouter := range s.routers {
 		for _, r := range apiRouter.Routes() {
 			f := s.makeHTTPHandler(r.Handler())
 
-			logrus.Debugf("Registering %s, %s", r.Method(), r.Path())
+			loglevel.WithSubsystem(loglevel.API).Debugf("Registering %s, %s", r.Method(), r.Path())
 			m.Path(versionMatcher + r.Path()).Methods(r.Method()).Handler(f)
 			m.Path(r.Path()).Methods(r.Method()).Handler(f)
 		}
@@ -187,7 +188,7 @@ func (s *Server) createMux() *mux.Router {
 // the API execution.
 func (s *Server) Wait(waitChan chan error) {
 	if err := s.serveAPI(); err != nil {
-		logrus.Errorf("ServeAPI error: %v", err)
+		loglevel.WithSubsystem(loglevel.API).Errorf("ServeAPI error: %v", err)
 		waitChan <- err
 		return
 	}
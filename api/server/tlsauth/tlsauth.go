@@ -0,0 +1,200 @@
+// Package tlsauth manages the TLS configuration of the API server: the
+// certificate of the server, which can be reloaded without restarting the
+// daemon, and the verification of the client certificates against a
+// certificate revocation list.
+package tlsauth
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/go-connections/tlsconfig"
+)
+
+// Options are the files of the TLS configuration of the API server.
+type Options struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	// CRLFile is the certificate revocation list the client certificates
+	// are verified against, if any. It must be signed by a CA of CAFile.
+	CRLFile    string
+	ClientAuth tls.ClientAuthType
+}
+
+// Store holds the certificate of the API server and the certificates revoked
+// by the certificate revocation list. Reload reads them again from their
+// files, so that they can be rotated without restarting the daemon.
+type Store struct {
+	options Options
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	crl  *revocationList
+}
+
+// revocationList is a certificate revocation list and the CA which signed it.
+type revocationList struct {
+	issuer     []byte // raw subject of the CA
+	nextUpdate time.Time
+	revoked    map[string]struct{}
+}
+
+// New returns a Store loading its certificates from the files of options.
+func New(options Options) (*Store, error) {
+	if options.CRLFile != "" && options.ClientAuth < tls.VerifyClientCertIfGiven {
+		return nil, fmt.Errorf("a certificate revocation list requires the verification of the client certificates")
+	}
+	s := &Store{options: options}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads the certificate of the server and the certificate revocation
+// list again. The previous ones are kept if one of them cannot be loaded.
+func (s *Store) Reload() error {
+	cert, err := tls.LoadX509KeyPair(s.options.CertFile, s.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("Error reading X509 key pair (cert: %q, key: %q): %v. Make sure the key is not encrypted.", s.options.CertFile, s.options.KeyFile, err)
+	}
+	var crl *revocationList
+	if s.options.CRLFile != "" {
+		if crl, err = loadRevocationList(s.options.CRLFile, s.options.CAFile); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = &cert
+	s.crl = crl
+	s.mu.Unlock()
+	return nil
+}
+
+// ServerConfig returns the TLS configuration of the API server. The
+// certificate it presents is the last one loaded by the Store.
+func (s *Store) ServerConfig() (*tls.Config, error) {
+	tlsConfig, err := tlsconfig.Server(tlsconfig.Options{
+		CAFile:     s.options.CAFile,
+		CertFile:   s.options.CertFile,
+		KeyFile:    s.options.KeyFile,
+		ClientAuth: s.options.ClientAuth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.Certificates = nil
+	tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.cert, nil
+	}
+	return tlsConfig, nil
+}
+
+// Verify returns an error if the client certificate of a connection is
+// revoked by the certificate revocation list.
+func (s *Store) Verify(state *tls.ConnectionState) error {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	s.mu.RLock()
+	crl := s.crl
+	s.mu.RUnlock()
+	if crl == nil {
+		return nil
+	}
+
+	cert := state.PeerCertificates[0]
+	if !bytes.Equal(cert.RawIssuer, crl.issuer) {
+		return nil
+	}
+	if _, revoked := crl.revoked[cert.SerialNumber.String()]; revoked {
+		return fmt.Errorf("client certificate %s of %q is revoked", cert.SerialNumber, cert.Subject.CommonName)
+	}
+	if time.Now().After(crl.nextUpdate) {
+		logrus.Warnf("Certificate revocation list %s is out of date since %s", s.options.CRLFile, crl.nextUpdate)
+	}
+	return nil
+}
+
+// Identity returns the identity of the client of a connection, which is the
+// common name of its certificate, or an empty string if it has none.
+func Identity(state *tls.ConnectionState) string {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
+
+// loadRevocationList reads the certificate revocation list crlFile, in PEM or
+// DER format, and verifies that it is signed by a CA of caFile.
+func loadRevocationList(crlFile, caFile string) (*revocationList, error) {
+	data, err := ioutil.ReadFile(crlFile)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read certificate revocation list %q: %v", crlFile, err)
+	}
+	crl, err := x509.ParseCRL(data)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse certificate revocation list %q: %v", crlFile, err)
+	}
+
+	cas, err := loadCertificates(caFile)
+	if err != nil {
+		return nil, err
+	}
+	var issuer *x509.Certificate
+	for _, ca := range cas {
+		if ca.CheckCRLSignature(crl) == nil {
+			issuer = ca
+			break
+		}
+	}
+	if issuer == nil {
+		return nil, fmt.Errorf("certificate revocation list %q is not signed by a CA of %q", crlFile, caFile)
+	}
+
+	l := &revocationList{
+		issuer:     issuer.RawSubject,
+		nextUpdate: crl.TBSCertList.NextUpdate,
+		revoked:    make(map[string]struct{}),
+	}
+	for _, c := range crl.TBSCertList.RevokedCertificates {
+		l.revoked[c.SerialNumber.String()] = struct{}{}
+	}
+	logrus.Debugf("Loaded %d revoked certificates from %s", len(l.revoked), crlFile)
+	return l, nil
+}
+
+func loadCertificates(file string) ([]*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read CA certificate %q: %v", file, err)
+	}
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse CA certificate %q: %v", file, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,229 @@
+package tlsauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a certificate signed by the CA, and its key, in PEM format.
+func (ca *testCA) issue(t *testing.T, serial int64, name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func (ca *testCA) crl(t *testing.T, serials ...int64) []byte {
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+	der, err := ca.cert.CreateCRL(rand.Reader, ca.key, revoked, time.Now(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func setupStoreFiles(t *testing.T, ca *testCA) (string, Options) {
+	dir, err := ioutil.TempDir("", "tlsauth-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, key := ca.issue(t, 2, "server")
+	return dir, Options{
+		CAFile:     writeFile(t, dir, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})),
+		CertFile:   writeFile(t, dir, "cert.pem", cert),
+		KeyFile:    writeFile(t, dir, "key.pem", key),
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}
+}
+
+func connectionState(t *testing.T, cert []byte) *tls.ConnectionState {
+	block, _ := pem.Decode(cert)
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.ConnectionState{PeerCertificates: []*x509.Certificate{c}}
+}
+
+func TestStoreReloadServerCertificate(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir, options := setupStoreFiles(t, ca)
+	defer os.RemoveAll(dir)
+
+	s, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig, err := s.ServerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := tlsConfig.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, key := ca.issue(t, 3, "server")
+	writeFile(t, dir, "cert.pem", cert)
+	writeFile(t, dir, "key.pem", key)
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := tlsConfig.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after.Certificate[0]) == string(before.Certificate[0]) {
+		t.Fatal("expected the reloaded certificate to be presented")
+	}
+
+	// A broken certificate does not replace the loaded one.
+	writeFile(t, dir, "cert.pem", []byte("invalid"))
+	if err := s.Reload(); err == nil {
+		t.Fatal("expected an error reloading an invalid certificate")
+	}
+	current, _ := tlsConfig.GetCertificate(nil)
+	if current != after {
+		t.Fatal("expected the previous certificate to be kept")
+	}
+}
+
+func TestStoreVerifyRevokedCertificate(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir, options := setupStoreFiles(t, ca)
+	defer os.RemoveAll(dir)
+
+	options.CRLFile = writeFile(t, dir, "crl.pem", ca.crl(t, 10))
+	s, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, _ := ca.issue(t, 10, "alice")
+	valid, _ := ca.issue(t, 11, "bob")
+	if err := s.Verify(connectionState(t, revoked)); err == nil || !strings.Contains(err.Error(), "revoked") {
+		t.Fatalf("expected the certificate to be revoked, got %v", err)
+	}
+	if err := s.Verify(connectionState(t, valid)); err != nil {
+		t.Fatal(err)
+	}
+	// The serial numbers revoked by a CA do not revoke those of another CA.
+	other, _ := newTestCA(t, "other").issue(t, 10, "carol")
+	if err := s.Verify(connectionState(t, other)); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, dir, "crl.pem", ca.crl(t, 11))
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(connectionState(t, revoked)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(connectionState(t, valid)); err == nil {
+		t.Fatal("expected the reloaded certificate revocation list to revoke the certificate")
+	}
+}
+
+func TestStoreRevocationListNotSignedByCA(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir, options := setupStoreFiles(t, ca)
+	defer os.RemoveAll(dir)
+
+	options.CRLFile = writeFile(t, dir, "crl.pem", newTestCA(t, "other").crl(t, 10))
+	if _, err := New(options); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("expected the certificate revocation list to be refused, got %v", err)
+	}
+}
+
+func TestStoreRevocationListRequiresClientAuth(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dir, options := setupStoreFiles(t, ca)
+	defer os.RemoveAll(dir)
+
+	options.CRLFile = writeFile(t, dir, "crl.pem", ca.crl(t))
+	options.ClientAuth = tls.NoClientCert
+	if _, err := New(options); err == nil {
+		t.Fatal("expected an error without the verification of the client certificates")
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	cert, _ := newTestCA(t, "ca").issue(t, 2, "alice")
+	if identity := Identity(connectionState(t, cert)); identity != "alice" {
+		t.Fatalf("expected identity alice, got %q", identity)
+	}
+	if identity := Identity(nil); identity != "" {
+		t.Fatalf("expected no identity, got %q", identity)
+	}
+}
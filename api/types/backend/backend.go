@@ -82,4 +82,16 @@ type ProgressWriter struct {
 	StdoutFormatter    *streamformatter.StdoutFormatter
 	StderrFormatter    *streamformatter.StderrFormatter
 	ProgressReaderFunc func(io.ReadCloser) io.ReadCloser
+	// StepOutput receives the structured output of a build, if it was
+	// requested.
+	StepOutput BuildStepOutput
+}
+
+// BuildStepOutput receives the structured output of a build: the progress
+// of its steps, and their output.
+type BuildStepOutput interface {
+	// Step reports the progress of a step.
+	Step(step types.BuildStep)
+	// Output returns the writers of the output of the step index.
+	Output(index int) (stdout, stderr io.Writer)
 }
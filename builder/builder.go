@@ -112,7 +112,7 @@ type Backend interface {
 	// TagImage tags an image with newTag
 	TagImageWithReference(image.ID, reference.Named) error
 	// PullOnBuild tells Docker to pull image referenced by `name`.
-	PullOnBuild(ctx context.Context, name string, authConfigs map[string]types.AuthConfig, output io.Writer) (Image, error)
+	PullOnBuild(ctx context.Context, name string, authConfigs map[string]types.AuthConfig, platform string, output io.Writer) (Image, error)
 	// ContainerAttachRaw attaches to container.
 	ContainerAttachRaw(cID string, stdin io.ReadCloser, stdout, stderr io.Writer, stream bool) error
 	// ContainerCreate creates a new Docker container and returns potential warnings
@@ -137,7 +137,15 @@ type Backend interface {
 	// with Context.Walk
 	//ContainerCopy(name string, res string) (io.ReadCloser, error)
 	// TODO: use copyBackend api
-	CopyOnBuild(containerID string, destPath string, src FileInfo, decompress bool) error
+	// chown, if not empty, is the user and group given with `--chown=<user>[:<group>]`,
+	// resolved in the container, that the copied files belong to.
+	CopyOnBuild(containerID string, destPath string, src FileInfo, decompress bool, chown string) error
+	// ContainerArchivePath creates an archive of the filesystem resource at the
+	// specified path in the container identified by the given name.
+	ContainerArchivePath(name string, path string) (io.ReadCloser, *types.ContainerPathStat, error)
+	// SquashImage creates a new image merging the layers added by `id` on
+	// top of `parent` into a single layer.
+	SquashImage(id string, parent string) (string, error)
 }
 
 // Image represents a Docker image used by the builder.
@@ -146,6 +154,19 @@ type Image interface {
 	RunConfig() *container.Config
 }
 
+// LayeredImage is an Image which tells the digest of its top layer.
+type LayeredImage interface {
+	Image
+	TopLayer() string
+}
+
+// ImageCacheBuilder represents a generator for stateful image cache.
+type ImageCacheBuilder interface {
+	// MakeImageCache creates a stateful image cache. The images referenced
+	// by `cacheFrom` are used as additional cache sources.
+	MakeImageCache(cacheFrom []string) ImageCache
+}
+
 // ImageCache abstracts an image cache store.
 // (parent image, child runconfig) -> child image
 type ImageCache interface {
@@ -1,7 +1,9 @@
 package builder
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/fileutils"
@@ -18,8 +21,13 @@ import (
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/docker/docker/pkg/stringid"
 )
 
+// DockerfileFromStdin is the Dockerfile name used to read the Dockerfile
+// from STDIN instead of from the build context (`docker build -f - PATH`).
+const DockerfileFromStdin = "-"
+
 // ValidateContextDirectory checks if all the contents of the directory
 // can be read and returns an error if some files can't be read
 // symlinks which point to non-existing files don't trigger an error
@@ -83,6 +91,10 @@ func GetContextFromReader(r io.ReadCloser, dockerfileName string) (out io.ReadCl
 		return ioutils.NewReadCloserWrapper(buf, func() error { return r.Close() }), dockerfileName, nil
 	}
 
+	if dockerfileName == DockerfileFromStdin {
+		return nil, "", fmt.Errorf("build context is not an archive")
+	}
+
 	// Input should be read as a Dockerfile.
 	tmpDir, err := ioutil.TempDir("", "docker-build-context-")
 	if err != nil {
@@ -160,7 +172,7 @@ func GetContextFromLocalDir(localDir, dockerfileName string) (absContextDir, rel
 	// When using a local context directory, when the Dockerfile is specified
 	// with the `-f/--file` option then it is considered relative to the
 	// current directory and not the context directory.
-	if dockerfileName != "" {
+	if dockerfileName != "" && dockerfileName != DockerfileFromStdin {
 		if dockerfileName, err = filepath.Abs(dockerfileName); err != nil {
 			return "", "", fmt.Errorf("unable to get absolute path to Dockerfile: %v", err)
 		}
@@ -200,6 +212,11 @@ func getDockerfileRelPath(givenContextDir, givenDockerfile string) (absContextDi
 		return "", "", fmt.Errorf("context must be a directory: %s", absContextDir)
 	}
 
+	// The Dockerfile is read from STDIN and added to the context later on.
+	if givenDockerfile == DockerfileFromStdin {
+		return absContextDir, givenDockerfile, nil
+	}
+
 	absDockerfile := givenDockerfile
 	if absDockerfile == "" {
 		// No -f/--file was specified so use the default relative to the
@@ -253,6 +270,105 @@ func getDockerfileRelPath(givenContextDir, givenDockerfile string) (absContextDi
 	return absContextDir, relDockerfile, nil
 }
 
+// AddDockerfileToBuildContext adds the Dockerfile read from dockerfileCtx to
+// the tar archive of the build context, under a random name so that it can't
+// collide with the files of the context. The random name is appended to the
+// .dockerignore file of the context (which is created if needed) so that the
+// daemon removes it from the context once the Dockerfile has been parsed.
+// Returns the new tar archive and the name of the Dockerfile inside it.
+func AddDockerfileToBuildContext(dockerfileCtx io.ReadCloser, buildCtx io.ReadCloser) (io.ReadCloser, string, error) {
+	file, err := ioutil.ReadAll(dockerfileCtx)
+	dockerfileCtx.Close()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(file) == 0 {
+		return nil, "", fmt.Errorf("the Dockerfile read from STDIN cannot be empty")
+	}
+
+	now := time.Now()
+	randomName := ".dockerfile." + stringid.GenerateRandomID()[:20]
+	newHeader := func(name string, size int) *tar.Header {
+		return &tar.Header{
+			Name:       name,
+			Mode:       0600,
+			Size:       int64(size),
+			ModTime:    now,
+			AccessTime: now,
+			ChangeTime: now,
+			Typeflag:   tar.TypeReg,
+		}
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		tarReader := tar.NewReader(buildCtx)
+		tarWriter := tar.NewWriter(pipeWriter)
+
+		defer buildCtx.Close()
+
+		writeFile := func(hdr *tar.Header, content []byte) error {
+			if err := tarWriter.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err := tarWriter.Write(content)
+			return err
+		}
+
+		foundIgnore := false
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+
+			if filepath.Clean(hdr.Name) != ".dockerignore" {
+				if err := tarWriter.WriteHeader(hdr); err != nil {
+					pipeWriter.CloseWithError(err)
+					return
+				}
+				if _, err := io.Copy(tarWriter, tarReader); err != nil {
+					pipeWriter.CloseWithError(err)
+					return
+				}
+				continue
+			}
+
+			foundIgnore = true
+			content := new(bytes.Buffer)
+			if _, err := io.Copy(content, tarReader); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+			content.WriteString("\n" + randomName + "\n")
+			hdr.Size = int64(content.Len())
+			if err := writeFile(hdr, content.Bytes()); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+
+		if !foundIgnore {
+			content := []byte(".dockerignore\n" + randomName + "\n")
+			if err := writeFile(newHeader(".dockerignore", len(content)), content); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+		if err := writeFile(newHeader(randomName, len(file)), file); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		pipeWriter.CloseWithError(tarWriter.Close())
+	}()
+
+	return pipeReader, randomName, nil
+}
+
 // isUNC returns true if the path is UNC (one starting \\). It always returns
 // false on Linux.
 func isUNC(path string) bool {
@@ -305,3 +305,105 @@ func TestValidateContextDirectoryWithOneFile(t *testing.T) {
 func TestValidateContextDirectoryWithOneFileExcludes(t *testing.T) {
 	testValidateContextDirectory(t, prepareOneFile, []string{DefaultDockerfileName})
 }
+
+func readTarEntries(t *testing.T, r io.Reader) map[string]string {
+	entries := make(map[string]string)
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error when reading tar archive: %s", err)
+		}
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("Error when reading tar archive: %s", err)
+		}
+		entries[header.Name] = string(content)
+	}
+	return entries
+}
+
+func TestAddDockerfileToBuildContext(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+
+	createTestTempFile(t, contextDir, "foo", "bar", 0777)
+	createTestTempFile(t, contextDir, ".dockerignore", "*.log", 0777)
+
+	tarStream, err := archive.Tar(contextDir, archive.Uncompressed)
+	if err != nil {
+		t.Fatalf("Error when creating tar: %s", err)
+	}
+
+	dockerfile := ioutil.NopCloser(strings.NewReader(dockerfileContents))
+	tarArchive, relDockerfile, err := AddDockerfileToBuildContext(dockerfile, tarStream)
+	if err != nil {
+		t.Fatalf("Error when executing AddDockerfileToBuildContext: %s", err)
+	}
+	if !strings.HasPrefix(relDockerfile, ".dockerfile.") {
+		t.Fatalf("Unexpected Dockerfile name: %s", relDockerfile)
+	}
+
+	entries := readTarEntries(t, tarArchive)
+	if entries[relDockerfile] != dockerfileContents {
+		t.Fatalf("Dockerfile contents should be %q, got %q", dockerfileContents, entries[relDockerfile])
+	}
+	if entries["foo"] != "bar" {
+		t.Fatalf("Context file should be kept, got %v", entries)
+	}
+	if expected := "*.log\n" + relDockerfile + "\n"; entries[".dockerignore"] != expected {
+		t.Fatalf(".dockerignore should be %q, got %q", expected, entries[".dockerignore"])
+	}
+}
+
+func TestAddDockerfileToBuildContextNoDockerignore(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+
+	createTestTempFile(t, contextDir, "foo", "bar", 0777)
+
+	tarStream, err := archive.Tar(contextDir, archive.Uncompressed)
+	if err != nil {
+		t.Fatalf("Error when creating tar: %s", err)
+	}
+
+	dockerfile := ioutil.NopCloser(strings.NewReader(dockerfileContents))
+	tarArchive, relDockerfile, err := AddDockerfileToBuildContext(dockerfile, tarStream)
+	if err != nil {
+		t.Fatalf("Error when executing AddDockerfileToBuildContext: %s", err)
+	}
+
+	entries := readTarEntries(t, tarArchive)
+	if expected := ".dockerignore\n" + relDockerfile + "\n"; entries[".dockerignore"] != expected {
+		t.Fatalf(".dockerignore should be %q, got %q", expected, entries[".dockerignore"])
+	}
+	if entries[relDockerfile] != dockerfileContents {
+		t.Fatalf("Dockerfile contents should be %q, got %q", dockerfileContents, entries[relDockerfile])
+	}
+}
+
+func TestGetContextFromLocalDirDockerfileFromStdin(t *testing.T) {
+	contextDir, cleanup := createTestTempDir(t, "", "builder-context-test")
+	defer cleanup()
+
+	absContextDir, relDockerfile, err := GetContextFromLocalDir(contextDir, DockerfileFromStdin)
+	if err != nil {
+		t.Fatalf("Error when getting context from local dir: %s", err)
+	}
+	if absContextDir == "" {
+		t.Fatal("Absolute context dir should not be empty")
+	}
+	if relDockerfile != DockerfileFromStdin {
+		t.Fatalf("Relative path should be %s, got: %s", DockerfileFromStdin, relDockerfile)
+	}
+}
+
+func TestGetContextFromReaderStringDockerfileFromStdin(t *testing.T) {
+	_, _, err := GetContextFromReader(ioutil.NopCloser(strings.NewReader(dockerfileContents)), DockerfileFromStdin)
+	if err == nil {
+		t.Fatal("Expected error when the context is not an archive and the Dockerfile is read from stdin")
+	}
+}
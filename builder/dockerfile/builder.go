@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/docker/docker/distribution"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
@@ -55,22 +56,32 @@ type Builder struct {
 	Stderr io.Writer
 	Output io.Writer
 
-	docker    builder.Backend
-	context   builder.Context
-	clientCtx context.Context
-	cancel    context.CancelFunc
+	docker     builder.Backend
+	context    builder.Context
+	clientCtx  context.Context
+	cancel     context.CancelFunc
+	imageCache builder.ImageCache
 
 	dockerfile       *parser.Node
 	runConfig        *container.Config // runconfig for cmd, run, entrypoint etc.
 	flags            *BFlags
 	tmpContainers    map[string]struct{}
 	image            string // imageID
+	architecture     string // architecture the containers run for, from the platform of the build
 	noBaseImage      bool
 	maintainer       string
 	cmdSet           bool
 	disableCommit    bool
 	cacheBusted      bool
 	allowedBuildArgs map[string]bool // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run'.
+	stages           []*buildStage
+	stageContexts    map[string]builder.Context // image ID -> root filesystem, for COPY --from
+	secretsDir       string                     // host directory holding the build secrets
+	workers          chan struct{}              // worker pool bounding the stages built concurrently
+
+	stepOutput               backend.BuildStepOutput // receives the structured output of the build, if requested
+	step                     *types.BuildStep        // the step being dispatched, with a structured output
+	buildStdout, buildStderr io.Writer               // the output of the build, while a step has its own
 
 	// TODO: remove once docker.Commit can receive a tag
 	id string
@@ -79,11 +90,18 @@ type Builder struct {
 // BuildManager implements builder.Backend and is shared across all Builder objects.
 type BuildManager struct {
 	backend builder.Backend
+	workers chan struct{}
 }
 
-// NewBuildManager creates a BuildManager.
-func NewBuildManager(b builder.Backend) (bm *BuildManager) {
-	return &BuildManager{backend: b}
+// NewBuildManager creates a BuildManager. maxConcurrentStages is the
+// maximum number of build stages that may be built at a time by all the
+// builds; stages are built one after the other if it is lower than 2.
+func NewBuildManager(b builder.Backend, maxConcurrentStages int) (bm *BuildManager) {
+	bm = &BuildManager{backend: b}
+	if maxConcurrentStages > 1 {
+		bm.workers = make(chan struct{}, maxConcurrentStages)
+	}
+	return bm
 }
 
 // BuildFromContext builds a new image from a given context.
@@ -104,6 +122,8 @@ func (bm *BuildManager) BuildFromContext(ctx context.Context, src io.ReadCloser,
 	if err != nil {
 		return "", err
 	}
+	b.workers = bm.workers
+	b.stepOutput = pg.StepOutput
 	return b.build(pg.StdoutFormatter, pg.StderrFormatter, pg.Output)
 }
 
@@ -130,6 +150,13 @@ func NewBuilder(clientCtx context.Context, config *types.ImageBuildOptions, back
 		tmpContainers:    map[string]struct{}{},
 		id:               stringid.GenerateNonCryptoID(),
 		allowedBuildArgs: make(map[string]bool),
+		stageContexts:    make(map[string]builder.Context),
+	}
+	if b.architecture, err = distribution.PlatformArchitecture(config.Platform); err != nil {
+		return nil, err
+	}
+	if icb, ok := backend.(builder.ImageCacheBuilder); ok {
+		b.imageCache = icb.MakeImageCache(config.CacheFrom)
 	}
 	if dockerfile != nil {
 		b.dockerfile, err = parser.Parse(dockerfile)
@@ -188,14 +215,14 @@ func sanitizeRepoAndTags(names []string) ([]reference.Named, error) {
 //
 // This will (barring errors):
 //
-// * read the dockerfile from context
-// * parse the dockerfile if not already parsed
-// * walk the AST and execute it by dispatching to handlers. If Remove
-//   or ForceRemove is set, additional cleanup around containers happens after
-//   processing.
-// * Tag image, if applicable.
-// * Print a happy message and return the image ID.
-//
+//   - read the dockerfile from context
+//   - parse the dockerfile if not already parsed
+//   - drop the stages following the target stage, if one was requested
+//   - walk the AST and execute it by dispatching to handlers. If Remove
+//     or ForceRemove is set, additional cleanup around containers happens after
+//     processing.
+//   - Tag image, if applicable.
+//   - Print a happy message and return the image ID.
 func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (string, error) {
 	b.Stdout = stdout
 	b.Stderr = stderr
@@ -213,6 +240,18 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", err
 	}
 
+	if err := validateOnBuildAllow(b.options.OnBuildAllow); err != nil {
+		return "", err
+	}
+
+	if b.options.Target != "" {
+		if err := trimToTarget(b.dockerfile, b.options.Target); err != nil {
+			return "", err
+		}
+	}
+	defer b.closeStageContexts()
+	defer b.cleanupSecrets()
+
 	if len(b.options.Labels) > 0 {
 		line := "LABEL "
 		for k, v := range b.options.Labels {
@@ -225,28 +264,14 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		b.dockerfile.Children = append(b.dockerfile.Children, node)
 	}
 
-	var shortImgID string
-	for i, n := range b.dockerfile.Children {
-		select {
-		case <-b.clientCtx.Done():
-			logrus.Debug("Builder: build cancelled!")
-			fmt.Fprintf(b.Stdout, "Build cancelled")
-			return "", fmt.Errorf("Build cancelled")
-		default:
-			// Not cancelled yet, keep going...
-		}
-		if err := b.dispatch(i, n); err != nil {
-			if b.options.ForceRemove {
-				b.clearTmp()
-			}
-			return "", err
-		}
-
-		shortImgID = stringid.TruncateID(b.image)
-		fmt.Fprintf(b.Stdout, " ---> %s\n", shortImgID)
-		if b.options.Remove {
-			b.clearTmp()
-		}
+	// Independent stages are built concurrently when the daemon allows it
+	if stages := splitStages(b.dockerfile); len(stages) > 1 && cap(b.workers) > 1 {
+		err = b.dispatchStages(stages)
+	} else {
+		err = b.dispatchNodes(0, b.dockerfile.Children)
+	}
+	if err != nil {
+		return "", err
 	}
 
 	// check if there are any leftover build-args that were passed but not
@@ -265,6 +290,17 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", fmt.Errorf("No image was generated. Is your Dockerfile empty?")
 	}
 
+	if b.options.Squash {
+		var fromID string
+		if n := len(b.stages); n > 0 {
+			fromID = b.stages[n-1].base
+		}
+		b.image, err = b.docker.SquashImage(b.image, fromID)
+		if err != nil {
+			return "", fmt.Errorf("error squashing image: %v", err)
+		}
+	}
+
 	imageID := image.ID(b.image)
 	for _, rt := range repoAndTags {
 		if err := b.docker.TagImageWithReference(imageID, rt); err != nil {
@@ -272,7 +308,7 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		}
 	}
 
-	fmt.Fprintf(b.Stdout, "Successfully built %s\n", shortImgID)
+	fmt.Fprintf(b.Stdout, "Successfully built %s\n", stringid.TruncateID(b.image))
 	return b.image, nil
 }
 
@@ -148,42 +148,67 @@ func add(b *Builder, args []string, attributes map[string]bool, original string)
 		return errAtLeastOneArgument("ADD")
 	}
 
+	flChown := b.flags.AddString("chown", "")
+
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, true, true, "ADD")
+	return b.runContextCommand(args, true, true, "ADD", b.context, flChown.Value)
 }
 
 // COPY foo /path
 //
-// Same as 'ADD' but without the tar and remote url handling.
+// Same as 'ADD' but without the tar and remote url handling. With
+// `--from=<stage>` the files are copied from the image produced by a previous
+// build stage (or from any other image) instead of the build context.
 //
 func dispatchCopy(b *Builder, args []string, attributes map[string]bool, original string) error {
 	if len(args) < 2 {
 		return errAtLeastOneArgument("COPY")
 	}
 
+	flFrom := b.flags.AddString("from", "")
+	flChown := b.flags.AddString("chown", "")
+
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, false, false, "COPY")
+	source := b.context
+	if flFrom.Value != "" {
+		var err error
+		if source, err = b.stageContext(flFrom.Value); err != nil {
+			return err
+		}
+	}
+
+	return b.runContextCommand(args, false, false, "COPY", source, flChown.Value)
 }
 
-// FROM imagename
+// FROM imagename [AS name]
 //
-// This sets the image the dockerfile will build on top of.
+// This sets the image the dockerfile will build on top of. Every FROM starts
+// a new build stage, which can optionally be named for use with COPY --from.
 //
 func from(b *Builder, args []string, attributes map[string]bool, original string) error {
-	if len(args) != 1 {
-		return errExactlyOneArgument("FROM")
+	var stageName string
+	switch {
+	case len(args) == 1:
+	case len(args) == 3 && strings.EqualFold(args[1], "as"):
+		stageName = strings.ToLower(args[2])
+	default:
+		return fmt.Errorf("FROM requires either one argument, or three: FROM <source> [AS <name>]")
 	}
 
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
+	if err := b.startStage(stageName); err != nil {
+		return err
+	}
+
 	name := args[0]
 
 	var (
@@ -205,11 +230,13 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 			// TODO: shouldn't we error out if error is different from "not found" ?
 		}
 		if image == nil {
-			image, err = b.docker.PullOnBuild(b.clientCtx, name, b.options.AuthConfigs, b.Output)
+			image, err = b.docker.PullOnBuild(b.clientCtx, name, b.options.AuthConfigs, b.options.Platform, b.Output)
 			if err != nil {
 				return err
 			}
 		}
+		// the layers of the base image are kept when squashing
+		b.stages[len(b.stages)-1].base = image.ImageID()
 	}
 
 	return b.processImageFrom(image)
@@ -366,8 +393,10 @@ func run(b *Builder, args []string, attributes map[string]bool, original string)
 
 	// set Cmd manually, this is special case only for Dockerfiles
 	b.runConfig.Cmd = config.Cmd
-	// set build-time environment for 'run'.
+	// set build-time environment for 'run'. The forwarded SSH agent is
+	// added after the cache lookup so that it doesn't affect cache hits.
 	b.runConfig.Env = append(b.runConfig.Env, cmdBuildEnv...)
+	b.runConfig.Env = append(b.runConfig.Env, b.sshEnv()...)
 	// set config as already being escaped, this prevents double escaping on windows
 	b.runConfig.ArgsEscaped = true
 
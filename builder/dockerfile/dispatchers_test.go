@@ -17,7 +17,6 @@ type commandWithFunction struct {
 func TestCommandsExactlyOneArgument(t *testing.T) {
 	commands := []commandWithFunction{
 		{"MAINTAINER", func(args []string) error { return maintainer(nil, args, nil, "") }},
-		{"FROM", func(args []string) error { return from(nil, args, nil, "") }},
 		{"WORKDIR", func(args []string) error { return workdir(nil, args, nil, "") }},
 		{"USER", func(args []string) error { return user(nil, args, nil, "") }}}
 
@@ -170,6 +169,76 @@ func TestFrom(t *testing.T) {
 	}
 }
 
+func TestFromArguments(t *testing.T) {
+	invalid := [][]string{
+		{},
+		{"busybox", "AS"},
+		{"busybox", "FOR", "build"},
+		{"busybox", "AS", "build", "extra"},
+	}
+
+	for _, args := range invalid {
+		err := from(nil, args, nil, "")
+		if err == nil {
+			t.Fatalf("Error should be present for FROM %v", args)
+		}
+
+		expectedError := "FROM requires either one argument, or three: FROM <source> [AS <name>]"
+		if err.Error() != expectedError {
+			t.Fatalf("Wrong error message for FROM %v. Got: %s. Should be: %s", args, err.Error(), expectedError)
+		}
+	}
+}
+
+func TestFromStages(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not support FROM scratch")
+	}
+
+	b := &Builder{flags: &BFlags{}, runConfig: &container.Config{}, disableCommit: true}
+
+	if err := from(b, []string{"scratch", "AS", "Build"}, nil, ""); err != nil {
+		t.Fatalf("Error when executing from: %s", err.Error())
+	}
+	b.image = "builtimage"
+	b.runConfig.Env = []string{"FOO=bar"}
+
+	if err := from(b, []string{"scratch"}, nil, ""); err != nil {
+		t.Fatalf("Error when executing from: %s", err.Error())
+	}
+
+	if len(b.stages) != 2 {
+		t.Fatalf("Expected 2 build stages, got %d", len(b.stages))
+	}
+	if b.stages[0].name != "build" || b.stages[0].image != "builtimage" {
+		t.Fatalf("Unexpected first stage: %+v", *b.stages[0])
+	}
+	for _, env := range b.runConfig.Env {
+		if env == "FOO=bar" {
+			t.Fatalf("Runconfig should be reset for a new stage, got env %v", b.runConfig.Env)
+		}
+	}
+
+	for _, ref := range []string{"build", "BUILD", "0"} {
+		img, err := b.stageImage(ref)
+		if err != nil {
+			t.Fatalf("Error resolving stage %q: %s", ref, err.Error())
+		}
+		if img != "builtimage" {
+			t.Fatalf("Stage %q should resolve to builtimage, got %s", ref, img)
+		}
+	}
+
+	if _, err := b.stageImage("1"); err == nil {
+		t.Fatalf("Referencing the current stage should fail")
+	}
+
+	err := from(b, []string{"scratch", "as", "build"}, nil, "")
+	if err == nil || !strings.Contains(err.Error(), "duplicate name") {
+		t.Fatalf("Expected a duplicate stage name error, got: %v", err)
+	}
+}
+
 func TestOnbuildIllegalTriggers(t *testing.T) {
 	triggers := []struct{ command, expectedError string }{
 		{"ONBUILD", "Chaining ONBUILD via `ONBUILD ONBUILD` isn't allowed"},
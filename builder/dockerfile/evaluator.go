@@ -107,7 +107,7 @@ func (b *Builder) dispatch(stepN int, ast *parser.Node) error {
 	original := ast.Original
 	flags := ast.Flags
 	strList := []string{}
-	msg := fmt.Sprintf("Step %d : %s", stepN+1, upperCasedCmd)
+	msg := upperCasedCmd
 
 	if len(ast.Flags) > 0 {
 		msg += " " + strings.Join(ast.Flags, " ")
@@ -189,7 +189,9 @@ func (b *Builder) dispatch(stepN int, ast *parser.Node) error {
 	}
 
 	msg += " " + strings.Join(msgList, " ")
-	fmt.Fprintln(b.Stdout, msg)
+	if !b.startStep(stepN, msg) {
+		fmt.Fprintf(b.Stdout, "Step %d : %s\n", stepN+1, msg)
+	}
 
 	// XXX yes, we skip any cmds that are not valid; the parser should have
 	// picked these out already.
@@ -199,5 +201,5 @@ func (b *Builder) dispatch(stepN int, ast *parser.Node) error {
 		return f(b, strList, attrs, original)
 	}
 
-	return fmt.Errorf("Unknown instruction: %s", upperCasedCmd)
+	return parser.UnknownInstructionError(ast)
 }
@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -22,6 +23,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/builder"
+	"github.com/docker/docker/builder/dockerfile/command"
 	"github.com/docker/docker/builder/dockerfile/parser"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/httputils"
@@ -92,10 +94,13 @@ type copyInfo struct {
 	decompress bool
 }
 
-func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
-	if b.context == nil {
+func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string, source builder.Context, chown string) error {
+	if source == nil {
 		return fmt.Errorf("No context given. Impossible to use %s", cmdName)
 	}
+	if chown != "" && runtime.GOOS == "windows" {
+		return fmt.Errorf("The --chown flag of %s is not supported on Windows", cmdName)
+	}
 
 	if len(args) < 2 {
 		return fmt.Errorf("Invalid %s format - at least two arguments required", cmdName)
@@ -129,7 +134,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			continue
 		}
 		// not a URL
-		subInfos, err := b.calcCopyInfo(cmdName, orig, allowLocalDecompression, true)
+		subInfos, err := b.calcCopyInfo(source, cmdName, orig, allowLocalDecompression, true)
 		if err != nil {
 			return err
 		}
@@ -171,8 +176,15 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		origPaths = strings.Join(origs, " ")
 	}
 
+	// the ownership is part of the cache key, but only when set so that
+	// the cache of existing images keeps matching
+	cmdFlags := ""
+	if chown != "" {
+		cmdFlags = "--chown=" + chown + " "
+	}
+
 	cmd := b.runConfig.Cmd
-	b.runConfig.Cmd = strslice.StrSlice(append(getShell(b.runConfig), fmt.Sprintf("#(nop) %s %s in %s ", cmdName, srcHash, dest)))
+	b.runConfig.Cmd = strslice.StrSlice(append(getShell(b.runConfig), fmt.Sprintf("#(nop) %s %s%s in %s ", cmdName, cmdFlags, srcHash, dest)))
 	defer func(cmd strslice.StrSlice) { b.runConfig.Cmd = cmd }(cmd)
 
 	if hit, err := b.probeCache(); err != nil {
@@ -181,13 +193,15 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		return nil
 	}
 
-	container, err := b.docker.ContainerCreate(types.ContainerCreateConfig{Config: b.runConfig}, true)
+	config := *b.runConfig
+	config.Architecture = b.architecture
+	container, err := b.docker.ContainerCreate(types.ContainerCreateConfig{Config: &config}, true)
 	if err != nil {
 		return err
 	}
 	b.tmpContainers[container.ID] = struct{}{}
 
-	comment := fmt.Sprintf("%s %s in %s", cmdName, origPaths, dest)
+	comment := fmt.Sprintf("%s %s%s in %s", cmdName, cmdFlags, origPaths, dest)
 
 	// Twiddle the destination when its a relative path - meaning, make it
 	// relative to the WORKINGDIR
@@ -196,7 +210,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 	}
 
 	for _, info := range infos {
-		if err := b.docker.CopyOnBuild(container.ID, dest, info.FileInfo, info.decompress); err != nil {
+		if err := b.docker.CopyOnBuild(container.ID, dest, info.FileInfo, info.decompress, chown); err != nil {
 			return err
 		}
 	}
@@ -293,7 +307,7 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	return &builder.HashedFileInfo{FileInfo: builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}, FileHash: hash}, nil
 }
 
-func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
+func (b *Builder) calcCopyInfo(source builder.Context, cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
 
 	// Work in daemon-specific OS filepath semantics
 	origPath = filepath.FromSlash(origPath)
@@ -306,7 +320,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	// Deal with wildcards
 	if allowWildcards && containsWildcards(origPath) {
 		var copyInfos []copyInfo
-		if err := b.context.Walk("", func(path string, info builder.FileInfo, err error) error {
+		if err := source.Walk("", func(path string, info builder.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
@@ -320,7 +334,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 			// Note we set allowWildcards to false in case the name has
 			// a * in it
-			subInfos, err := b.calcCopyInfo(cmdName, path, allowLocalDecompression, false)
+			subInfos, err := b.calcCopyInfo(source, cmdName, path, allowLocalDecompression, false)
 			if err != nil {
 				return err
 			}
@@ -334,7 +348,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 
 	// Must be a dir or a file
 
-	statPath, fi, err := b.context.Stat(origPath)
+	statPath, fi, err := source.Stat(origPath)
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +367,7 @@ func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression
 	}
 	// Must be a dir
 	var subfiles []string
-	err = b.context.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
+	err = source.Walk(statPath, func(path string, info builder.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -418,7 +432,11 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 		if nTriggers > 1 {
 			word = "triggers"
 		}
-		fmt.Fprintf(b.Stderr, "# Executing %d build %s...\n", nTriggers, word)
+		action := "Executing"
+		if b.options.NoOnBuild {
+			action = "Skipping"
+		}
+		fmt.Fprintf(b.Stderr, "# %s %d build %s...\n", action, nTriggers, word)
 	}
 
 	// Copy the ONBUILD triggers, and remove them from the config, since the config will be comitted.
@@ -433,6 +451,11 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 		}
 
 		for i, n := range ast.Children {
+			if !b.isOnBuildAllowed(n.Value) {
+				fmt.Fprintf(b.Stderr, "# Skipping build trigger: %s\n", step)
+				continue
+			}
+
 			switch strings.ToUpper(n.Value) {
 			case "ONBUILD":
 				return fmt.Errorf("Chaining ONBUILD via `ONBUILD ONBUILD` isn't allowed")
@@ -440,6 +463,7 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 				return fmt.Errorf("%s isn't allowed as an ONBUILD trigger", n.Value)
 			}
 
+			fmt.Fprintf(b.Stderr, "# Executing build trigger: %s\n", step)
 			if err := b.dispatch(i, n); err != nil {
 				return err
 			}
@@ -449,15 +473,17 @@ func (b *Builder) processImageFrom(img builder.Image) error {
 	return nil
 }
 
-// probeCache checks if `b.docker` implements builder.ImageCache and image-caching
+// probeCache checks if an image cache is available and image-caching
 // is enabled (`b.UseCache`).
-// If so attempts to look up the current `b.image` and `b.runConfig` pair with `b.docker`.
+// If so attempts to look up the current `b.image` and `b.runConfig` pair in
+// `b.imageCache`, which includes the images passed with `--cache-from`.
 // If an image is found, probeCache returns `(true, nil)`.
 // If no image is found, it returns `(false, nil)`.
 // If there is any error, it returns `(false, err)`.
 func (b *Builder) probeCache() (bool, error) {
-	c, ok := b.docker.(builder.ImageCache)
-	if !ok || b.options.NoCache || b.cacheBusted {
+	c := b.imageCache
+	if c == nil || b.options.NoCache || b.cacheBusted {
+		b.setStepCache(false)
 		return false, nil
 	}
 	cache, err := c.GetCachedImageOnBuild(b.image, b.runConfig)
@@ -467,10 +493,13 @@ func (b *Builder) probeCache() (bool, error) {
 	if len(cache) == 0 {
 		logrus.Debugf("[BUILDER] Cache miss: %s", b.runConfig.Cmd)
 		b.cacheBusted = true
+		b.setStepCache(false)
 		return false, nil
 	}
 
-	fmt.Fprintf(b.Stdout, " ---> Using cache\n")
+	if !b.setStepCache(true) {
+		fmt.Fprintf(b.Stdout, " ---> Using cache\n")
+	}
 	logrus.Debugf("[BUILDER] Use cached version: %s", b.runConfig.Cmd)
 	b.image = string(cache)
 
@@ -495,18 +524,25 @@ func (b *Builder) create() (string, error) {
 		Ulimits:      b.options.Ulimits,
 	}
 
+	binds, err := b.secretBinds()
+	if err != nil {
+		return "", err
+	}
+
 	// TODO: why not embed a hostconfig in builder?
 	hostConfig := &container.HostConfig{
+		Binds:     binds,
 		Isolation: b.options.Isolation,
 		ShmSize:   b.options.ShmSize,
 		Resources: resources,
 	}
 
 	config := *b.runConfig
+	config.Architecture = b.architecture
 
 	// Create the container
 	c, err := b.docker.ContainerCreate(types.ContainerCreateConfig{
-		Config:     b.runConfig,
+		Config:     &config,
 		HostConfig: hostConfig,
 	}, true)
 	if err != nil {
@@ -656,6 +692,34 @@ func (b *Builder) parseDockerfile() error {
 	return nil
 }
 
+// isOnBuildAllowed returns true if the ONBUILD triggers of the base image
+// running the instruction `cmd` are allowed to run for this build.
+func (b *Builder) isOnBuildAllowed(cmd string) bool {
+	if b.options.NoOnBuild {
+		return false
+	}
+	if len(b.options.OnBuildAllow) == 0 {
+		return true
+	}
+	for _, allowed := range b.options.OnBuildAllow {
+		if strings.EqualFold(allowed, cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOnBuildAllow checks that the instructions allowed as ONBUILD
+// triggers are valid instructions.
+func validateOnBuildAllow(instructions []string) error {
+	for _, i := range instructions {
+		if _, ok := command.Commands[strings.ToLower(i)]; !ok {
+			return fmt.Errorf("invalid instruction %q: unknown instruction for ONBUILD triggers", i)
+		}
+	}
+	return nil
+}
+
 // determine if build arg is part of built-in args or user
 // defined args in Dockerfile at any point in time.
 func (b *Builder) isBuildArgAllowed(arg string) bool {
@@ -2,12 +2,15 @@ package dockerfile
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
 )
 
 func TestEmptyDockerfile(t *testing.T) {
@@ -122,3 +125,75 @@ func TestDockerfileOutsideTheBuildContext(t *testing.T) {
 		t.Fatalf("Wrong error message. Should be \"%s\". Got \"%s\"", expectedError, err.Error())
 	}
 }
+
+func TestCreateAppliesBuildResources(t *testing.T) {
+	var hostConfig *container.HostConfig
+	backend := &mockBackend{
+		containerCreateFunc: func(config types.ContainerCreateConfig) (types.ContainerCreateResponse, error) {
+			hostConfig = config.HostConfig
+			return types.ContainerCreateResponse{ID: "abc"}, nil
+		},
+	}
+
+	options := &types.ImageBuildOptions{
+		Memory:       64 * 1024 * 1024,
+		MemorySwap:   128 * 1024 * 1024,
+		CPUShares:    512,
+		CPUSetCPUs:   "0-1",
+		CPUSetMems:   "0",
+		CgroupParent: "/ci-builds",
+	}
+	b, err := NewBuilder(context.Background(), options, backend, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Stdout = ioutil.Discard
+	b.noBaseImage = true
+
+	if _, err := b.create(); err != nil {
+		t.Fatalf("Error creating build container: %v", err)
+	}
+
+	if hostConfig == nil {
+		t.Fatal("Build container was created without a host config")
+	}
+	r := hostConfig.Resources
+	if r.Memory != options.Memory || r.MemorySwap != options.MemorySwap {
+		t.Fatalf("Memory limits not applied: got memory=%d memswap=%d", r.Memory, r.MemorySwap)
+	}
+	if r.CPUShares != options.CPUShares || r.CpusetCpus != options.CPUSetCPUs || r.CpusetMems != options.CPUSetMems {
+		t.Fatalf("CPU limits not applied: got shares=%d cpus=%q mems=%q", r.CPUShares, r.CpusetCpus, r.CpusetMems)
+	}
+	if r.CgroupParent != options.CgroupParent {
+		t.Fatalf("Cgroup parent not applied: got %q", r.CgroupParent)
+	}
+}
+
+func TestIsOnBuildAllowed(t *testing.T) {
+	b := &Builder{options: &types.ImageBuildOptions{}}
+	if !b.isOnBuildAllowed("run") {
+		t.Fatal("Expected ONBUILD triggers to be allowed by default")
+	}
+
+	b.options.OnBuildAllow = []string{"COPY", "env"}
+	if !b.isOnBuildAllowed("copy") || !b.isOnBuildAllowed("env") {
+		t.Fatal("Expected allowed instructions to match case-insensitively")
+	}
+	if b.isOnBuildAllowed("run") {
+		t.Fatal("Expected RUN trigger to be filtered out")
+	}
+
+	b.options.NoOnBuild = true
+	if b.isOnBuildAllowed("copy") {
+		t.Fatal("Expected no ONBUILD trigger to be allowed with NoOnBuild")
+	}
+}
+
+func TestValidateOnBuildAllow(t *testing.T) {
+	if err := validateOnBuildAllow([]string{"RUN", "copy"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := validateOnBuildAllow([]string{"RUN", "FOO"}); err == nil {
+		t.Fatal("Expected error for unknown instruction")
+	}
+}
@@ -0,0 +1,78 @@
+package dockerfile
+
+import (
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/builder"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
+)
+
+// mockBackend implements the builder.Backend interface for unit testing
+type mockBackend struct {
+	containerCreateFunc func(config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
+}
+
+func (m *mockBackend) GetImageOnBuild(name string) (builder.Image, error) {
+	return nil, nil
+}
+
+func (m *mockBackend) TagImageWithReference(image.ID, reference.Named) error {
+	return nil
+}
+
+func (m *mockBackend) PullOnBuild(ctx context.Context, name string, authConfigs map[string]types.AuthConfig, platform string, output io.Writer) (builder.Image, error) {
+	return nil, nil
+}
+
+func (m *mockBackend) ContainerAttachRaw(cID string, stdin io.ReadCloser, stdout, stderr io.Writer, stream bool) error {
+	return nil
+}
+
+func (m *mockBackend) ContainerCreate(config types.ContainerCreateConfig, validateHostname bool) (types.ContainerCreateResponse, error) {
+	if m.containerCreateFunc != nil {
+		return m.containerCreateFunc(config)
+	}
+	return types.ContainerCreateResponse{}, nil
+}
+
+func (m *mockBackend) ContainerRm(name string, config *types.ContainerRmConfig) error {
+	return nil
+}
+
+func (m *mockBackend) Commit(string, *backend.ContainerCommitConfig) (string, error) {
+	return "", nil
+}
+
+func (m *mockBackend) ContainerKill(containerID string, sig uint64) error {
+	return nil
+}
+
+func (m *mockBackend) ContainerStart(containerID string, hostConfig *container.HostConfig, validateHostname bool) error {
+	return nil
+}
+
+func (m *mockBackend) ContainerWait(containerID string, timeout time.Duration) (int, error) {
+	return 0, nil
+}
+
+func (m *mockBackend) ContainerUpdateCmdOnBuild(containerID string, cmd []string) error {
+	return nil
+}
+
+func (m *mockBackend) CopyOnBuild(containerID string, destPath string, src builder.FileInfo, decompress bool, chown string) error {
+	return nil
+}
+
+func (m *mockBackend) ContainerArchivePath(name string, path string) (io.ReadCloser, *types.ContainerPathStat, error) {
+	return nil, nil, nil
+}
+
+func (m *mockBackend) SquashImage(id string, parent string) (string, error) {
+	return id, nil
+}
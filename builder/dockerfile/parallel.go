@@ -0,0 +1,263 @@
+package dockerfile
+
+// Concurrent execution of build stages. The stages of a multi-stage
+// Dockerfile only depend on each other through `COPY --from`, so the stages
+// that don't copy from each other can be built at the same time. The number
+// of stages built concurrently is bounded by a worker pool shared by all the
+// builds of the daemon.
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/builder"
+	"github.com/docker/docker/builder/dockerfile/command"
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/engine-api/types/container"
+	"golang.org/x/net/context"
+)
+
+// stageNodes holds the instructions of a single build stage.
+type stageNodes struct {
+	name  string         // name of the stage, may be empty
+	first int            // index of the first instruction in the Dockerfile
+	nodes []*parser.Node // instructions of the stage, starting with FROM
+}
+
+// splitStages groups the instructions of the Dockerfile by build stage.
+// Instructions preceding the first FROM are kept in the first stage so that
+// dispatching them reports the usual error.
+func splitStages(ast *parser.Node) []*stageNodes {
+	var stages []*stageNodes
+	for i, n := range ast.Children {
+		if n.Value == command.From || len(stages) == 0 {
+			s := &stageNodes{first: i}
+			if n.Value == command.From {
+				s.name = stageName(n)
+			}
+			stages = append(stages, s)
+		}
+		s := stages[len(stages)-1]
+		s.nodes = append(s.nodes, n)
+	}
+	return stages
+}
+
+// stageDependencies returns, for each stage, the indices of the previous
+// stages it copies files from. References that don't match a previous stage
+// are images and don't create a dependency.
+func stageDependencies(stages []*stageNodes) [][]int {
+	deps := make([][]int, len(stages))
+	for i, s := range stages {
+		seen := make(map[int]bool)
+		for _, n := range s.nodes {
+			if n.Value != command.Copy {
+				continue
+			}
+			for _, f := range n.Flags {
+				if !strings.HasPrefix(f, "--from=") {
+					continue
+				}
+				if d := stageIndex(stages[:i], strings.TrimPrefix(f, "--from=")); d >= 0 && !seen[d] {
+					seen[d] = true
+					deps[i] = append(deps[i], d)
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// stageIndex resolves a `--from` reference to the index of one of the given
+// stages, or returns -1.
+func stageIndex(stages []*stageNodes, ref string) int {
+	if i, err := strconv.Atoi(ref); err == nil {
+		if i >= 0 && i < len(stages) {
+			return i
+		}
+		return -1
+	}
+	for i, s := range stages {
+		if s.name != "" && s.name == strings.ToLower(ref) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dispatchNodes dispatches the given instructions in order. `first` is the
+// index of the first instruction in the Dockerfile, used for the step number.
+func (b *Builder) dispatchNodes(first int, nodes []*parser.Node) error {
+	for i, n := range nodes {
+		select {
+		case <-b.clientCtx.Done():
+			logrus.Debug("Builder: build cancelled!")
+			fmt.Fprintf(b.Stdout, "Build cancelled")
+			return fmt.Errorf("Build cancelled")
+		default:
+			// Not cancelled yet, keep going...
+		}
+		if err := b.dispatch(first+i, n); err != nil {
+			if b.options.ForceRemove {
+				b.clearTmp()
+			}
+			b.endStep(err)
+			return err
+		}
+
+		if b.step == nil {
+			fmt.Fprintf(b.Stdout, " ---> %s\n", stringid.TruncateID(b.image))
+		}
+		if b.options.Remove {
+			b.clearTmp()
+		}
+		b.endStep(nil)
+	}
+	return nil
+}
+
+// dispatchStages builds the given stages, running the stages that don't
+// depend on each other concurrently. Each stage is built by its own Builder
+// sharing the options, context and output of b; once all the stages are
+// built, b holds the result of the last one.
+func (b *Builder) dispatchStages(stages []*stageNodes) error {
+	deps := stageDependencies(stages)
+
+	ctx, cancel := context.WithCancel(b.clientCtx)
+	defer cancel()
+
+	var (
+		outputLock sync.Mutex
+		stdout     = &lockedWriter{mu: &outputLock, w: b.Stdout}
+		stderr     = &lockedWriter{mu: &outputLock, w: b.Stderr}
+		output     = &lockedWriter{mu: &outputLock, w: b.Output}
+	)
+
+	// create the secrets directory up front so that it is shared by all stages
+	if _, err := b.secretBinds(); err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		done     = make([]chan struct{}, len(stages))
+		builders = make([]*Builder, len(stages))
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for i := range stages {
+		done[i] = make(chan struct{})
+	}
+
+	for i := range stages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, d := range deps[i] {
+				select {
+				case <-done[d]:
+				case <-ctx.Done():
+					return
+				}
+				if builders[d] == nil {
+					// the dependency failed, the error is reported by its own goroutine
+					return
+				}
+			}
+
+			select {
+			case b.workers <- struct{}{}:
+				defer func() { <-b.workers }()
+			case <-ctx.Done():
+				return
+			}
+
+			images := make(map[int]string)
+			for _, d := range deps[i] {
+				images[d] = builders[d].image
+			}
+			sb := b.stageBuilder(ctx, stages[:i], images)
+			sb.Stdout, sb.Stderr, sb.Output = stdout, stderr, output
+			defer sb.closeStageContexts()
+
+			if err := sb.dispatchNodes(stages[i].first, stages[i].nodes); err != nil {
+				fail(err)
+				return
+			}
+			builders[i] = sb
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := b.clientCtx.Err(); err != nil {
+		return fmt.Errorf("Build cancelled")
+	}
+
+	for _, sb := range builders {
+		for arg := range sb.allowedBuildArgs {
+			b.allowedBuildArgs[arg] = true
+		}
+	}
+	last := builders[len(builders)-1]
+	b.stages = last.stages
+	b.image = last.image
+	b.runConfig = last.runConfig
+	return nil
+}
+
+// stageBuilder returns a Builder for a stage following the given previous
+// stages. `images` holds the images built by the stages it depends on, the
+// other previous stages may still be running.
+func (b *Builder) stageBuilder(ctx context.Context, previous []*stageNodes, images map[int]string) *Builder {
+	sb := &Builder{
+		options:          b.options,
+		docker:           b.docker,
+		context:          b.context,
+		clientCtx:        ctx,
+		cancel:           b.cancel,
+		dockerfile:       b.dockerfile,
+		runConfig:        new(container.Config),
+		tmpContainers:    map[string]struct{}{},
+		allowedBuildArgs: make(map[string]bool),
+		stageContexts:    make(map[string]builder.Context),
+		secretsDir:       b.secretsDir,
+		stepOutput:       b.stepOutput,
+		id:               b.id,
+	}
+	// every stage narrows the cache sources on its own chain of images
+	if icb, ok := b.docker.(builder.ImageCacheBuilder); ok {
+		sb.imageCache = icb.MakeImageCache(b.options.CacheFrom)
+	}
+	for i, s := range previous {
+		sb.stages = append(sb.stages, &buildStage{name: s.name, image: images[i]})
+	}
+	return sb
+}
+
+// lockedWriter serializes the writes of concurrent stages to a shared output.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
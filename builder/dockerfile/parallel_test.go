@@ -0,0 +1,55 @@
+package dockerfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+)
+
+func TestSplitStages(t *testing.T) {
+	dockerfile := `FROM busybox AS build
+RUN echo foo > /foo
+FROM busybox
+COPY --from=build /foo /foo
+LABEL foo=bar
+`
+	ast, err := parser.Parse(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stages := splitStages(ast)
+	if len(stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(stages))
+	}
+	if stages[0].name != "build" || stages[0].first != 0 || len(stages[0].nodes) != 2 {
+		t.Fatalf("Unexpected first stage: %+v", stages[0])
+	}
+	if stages[1].name != "" || stages[1].first != 2 || len(stages[1].nodes) != 3 {
+		t.Fatalf("Unexpected second stage: %+v", stages[1])
+	}
+}
+
+func TestStageDependencies(t *testing.T) {
+	dockerfile := `FROM busybox AS a
+RUN true
+FROM busybox AS b
+COPY --from=busybox /bin/sh /sh
+FROM busybox
+COPY --from=A /foo /foo
+COPY --from=1 /bar /bar
+COPY --from=a /baz /baz
+`
+	ast, err := parser.Parse(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps := stageDependencies(splitStages(ast))
+	expected := [][]int{nil, nil, {0, 1}}
+	if !reflect.DeepEqual(deps, expected) {
+		t.Fatalf("Expected dependencies %v, got %v", expected, deps)
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerfile/command"
+)
+
+// ParseError is an error located at a line of the Dockerfile. The line and
+// column are 1-based, the column is 0 when unknown.
+type ParseError struct {
+	Line       int
+	Column     int
+	Msg        string
+	Suggestion string // optional hint on how to fix the error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("Dockerfile parse error line %d: %s", e.Line, e.Msg)
+	if e.Suggestion != "" {
+		msg += " (" + e.Suggestion + ")"
+	}
+	return msg
+}
+
+// Position returns the line and column of the error.
+func (e *ParseError) Position() (int, int) {
+	return e.Line, e.Column
+}
+
+// Hint returns the suggestion on how to fix the error, if any.
+func (e *ParseError) Hint() string {
+	return e.Suggestion
+}
+
+// newParseError locates err at the given line and column, unless it already
+// is a ParseError.
+func newParseError(line, column int, err error) error {
+	if _, ok := err.(*ParseError); ok {
+		return err
+	}
+	return &ParseError{Line: line, Column: column, Msg: err.Error()}
+}
+
+// UnknownInstructionError returns the error for a node whose instruction
+// isn't supported, with a suggestion when it looks like a misspelled one.
+func UnknownInstructionError(node *Node) error {
+	err := &ParseError{
+		Line:   node.StartLine,
+		Column: 1,
+		Msg:    "Unknown instruction: " + strings.ToUpper(node.Value),
+	}
+	if cmd := suggestCommand(node.Value); cmd != "" {
+		err.Suggestion = "did you mean " + strings.ToUpper(cmd) + "?"
+	}
+	return err
+}
+
+// suggestCommand returns the Dockerfile command closest to cmd, or an empty
+// string if none is close enough.
+func suggestCommand(cmd string) string {
+	cmd = strings.ToLower(cmd)
+	var commands []string
+	for c := range command.Commands {
+		commands = append(commands, c)
+	}
+	// iterate in a fixed order so that ties resolve the same way every time
+	sort.Strings(commands)
+
+	best, bestDistance := "", 3
+	for _, c := range commands {
+		if d := editDistance(cmd, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
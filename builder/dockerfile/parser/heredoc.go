@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerfile/command"
+)
+
+// heredoc is a here-document redirection (`<<WORD` or `<<-WORD`) found in the
+// shell form of a RUN instruction.
+type heredoc struct {
+	delimiter string // the word ending the document, without quotes
+	stripTabs bool   // `<<-` strips the leading tabs of the document lines
+}
+
+// findHeredocs returns the here-documents started by the given shell command,
+// in order. Redirections inside quotes and here-strings (`<<<`) are ignored,
+// and so is anything that is not followed by a valid delimiter word, such as
+// the `<<` shift operator of arithmetic expansions.
+func findHeredocs(cmd string) []heredoc {
+	var (
+		heredocs []heredoc
+		quote    rune
+		escaped  bool
+		runes    = []rune(cmd)
+	)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case escaped:
+			escaped = false
+			continue
+		case c == '\\' && quote != '\'':
+			escaped = true
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '\'' || c == '"':
+			quote = c
+			continue
+		}
+		if c != '<' || i+1 >= len(runes) || runes[i+1] != '<' {
+			continue
+		}
+		if i+2 < len(runes) && runes[i+2] == '<' {
+			// here-string, skip all three characters
+			i += 2
+			continue
+		}
+		j := i + 2
+		h := heredoc{}
+		if j < len(runes) && runes[j] == '-' {
+			h.stripTabs = true
+			j++
+		}
+		for j < len(runes) && (runes[j] == ' ' || runes[j] == '\t') {
+			j++
+		}
+		word, n := heredocDelimiter(runes[j:])
+		if word == "" {
+			i++
+			continue
+		}
+		h.delimiter = word
+		heredocs = append(heredocs, h)
+		i = j + n - 1
+	}
+	return heredocs
+}
+
+// heredocDelimiter parses the delimiter word at the start of s, optionally
+// enclosed in single or double quotes. It returns the unquoted word and the
+// number of runes consumed.
+func heredocDelimiter(s []rune) (string, int) {
+	if len(s) == 0 {
+		return "", 0
+	}
+	if s[0] == '\'' || s[0] == '"' {
+		for i := 1; i < len(s); i++ {
+			if s[i] == s[0] {
+				if i == 1 {
+					return "", 0
+				}
+				return string(s[1:i]), i + 1
+			}
+		}
+		return "", 0
+	}
+	n := 0
+	for n < len(s) && isWordRune(s[n], n == 0) {
+		n++
+	}
+	return string(s[:n]), n
+}
+
+func isWordRune(c rune, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// readHeredocs reads the documents of the heredocs of a RUN node from the
+// lines following the instruction, and adds them to the command. The command
+// and its documents are passed as is to the shell, except for a RUN whose
+// whole command is a single redirection, such as
+//
+//	RUN <<EOF
+//	apt-get update
+//	apt-get install -y curl
+//	EOF
+//
+// in which case the document is run as the script. It returns the number of
+// lines read.
+func readHeredocs(node *Node, scanner *bufio.Scanner) (int, error) {
+	if node.Value != command.Run || node.Attributes["json"] || node.Next == nil {
+		return 0, nil
+	}
+	heredocs := findHeredocs(node.Next.Value)
+	if len(heredocs) == 0 {
+		return 0, nil
+	}
+
+	read := 0
+	script := []string{node.Next.Value}
+	var single []string
+	for _, h := range heredocs {
+		terminated := false
+		for scanner.Scan() {
+			read++
+			line := scanner.Text()
+			script = append(script, line)
+			if h.stripTabs {
+				line = strings.TrimLeft(line, "\t")
+			}
+			if line == h.delimiter {
+				terminated = true
+				break
+			}
+			single = append(single, line)
+		}
+		if !terminated {
+			return read, fmt.Errorf("unterminated heredoc, expected %q to end the document", h.delimiter)
+		}
+	}
+
+	if len(heredocs) == 1 && isSingleHeredoc(node.Next.Value) {
+		node.Next.Value = strings.Join(single, "\n")
+	} else {
+		node.Next.Value = strings.Join(script, "\n")
+	}
+	return read, nil
+}
+
+// isSingleHeredoc returns whether the shell command only consists of a single
+// heredoc redirection.
+func isSingleHeredoc(cmd string) bool {
+	cmd = strings.TrimSpace(cmd)
+	if !strings.HasPrefix(cmd, "<<") {
+		return false
+	}
+	cmd = strings.TrimPrefix(strings.TrimPrefix(cmd, "<<"), "-")
+	word, n := heredocDelimiter([]rune(strings.TrimLeft(cmd, " \t")))
+	return word != "" && n == len([]rune(strings.TrimLeft(cmd, " \t")))
+}
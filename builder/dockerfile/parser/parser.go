@@ -74,7 +74,7 @@ func init() {
 		command.Entrypoint:  parseMaybeJSON,
 		command.Env:         parseEnv,
 		command.Expose:      parseStringsWhitespaceDelimited,
-		command.From:        parseString,
+		command.From:        parseStringsWhitespaceDelimited,
 		command.Healthcheck: parseHealthConfig,
 		command.Label:       parseLabel,
 		command.Maintainer:  parseString,
@@ -90,6 +90,12 @@ func init() {
 
 // ParseLine parse a line and return the remainder.
 func ParseLine(line string) (string, *Node, error) {
+	return parseLine(line, true)
+}
+
+// parseLine parses a line and returns the remainder. An unclosed JSON array
+// only continues on the next line if continueJSON is set.
+func parseLine(line string, continueJSON bool) (string, *Node, error) {
 
 	// Handle the parser directive '# escape=<char>. Parser directives must precede
 	// any builder instruction or other comments, and cannot be repeated.
@@ -122,6 +128,12 @@ func ParseLine(line string) (string, *Node, error) {
 		return line, nil, nil
 	}
 
+	// The JSON form of an instruction can be split over several lines
+	// without escaping the newlines.
+	if continueJSON && continuesJSONArray(line) {
+		return line, nil, nil
+	}
+
 	cmd, flags, args, err := splitCommand(line)
 	if err != nil {
 		return "", nil, err
@@ -144,7 +156,8 @@ func ParseLine(line string) (string, *Node, error) {
 }
 
 // Parse is the main parse routine.
-// It handles an io.ReadWriteCloser and returns the root of the AST.
+// It handles an io.ReadWriteCloser and returns the root of the AST. Errors
+// are returned as *ParseError, locating the faulty instruction.
 func Parse(rwc io.Reader) (*Node, error) {
 	directiveEscapeSeen = false
 	lookingForDirectives = true
@@ -162,10 +175,11 @@ func Parse(rwc io.Reader) (*Node, error) {
 			scannedBytes = bytes.TrimPrefix(scannedBytes, utf8bom)
 		}
 		scannedLine := strings.TrimLeftFunc(string(scannedBytes), unicode.IsSpace)
+		column := len(scannedBytes) - len(scannedLine) + 1
 		currentLine++
 		line, child, err := ParseLine(scannedLine)
 		if err != nil {
-			return nil, err
+			return nil, newParseError(currentLine, column, err)
 		}
 		startLine := currentLine
 
@@ -180,7 +194,7 @@ func Parse(rwc io.Reader) (*Node, error) {
 
 				line, child, err = ParseLine(line + newline)
 				if err != nil {
-					return nil, err
+					return nil, newParseError(startLine, column, err)
 				}
 
 				if child != nil {
@@ -188,14 +202,25 @@ func Parse(rwc io.Reader) (*Node, error) {
 				}
 			}
 			if child == nil && line != "" {
-				_, child, err = ParseLine(line)
+				if continuesJSONArray(line) && currentLine > startLine {
+					return nil, &ParseError{Line: startLine, Column: column, Msg: "unterminated JSON array, expected ]"}
+				}
+				// a single line array that isn't closed is parsed as
+				// the shell form, as it has always been
+				_, child, err = parseLine(line, false)
 				if err != nil {
-					return nil, err
+					return nil, newParseError(startLine, column, err)
 				}
 			}
 		}
 
 		if child != nil {
+			n, err := readHeredocs(child, scanner)
+			currentLine += n
+			if err != nil {
+				return nil, newParseError(startLine, column, err)
+			}
+
 			// Update the line information for the current child.
 			child.StartLine = startLine
 			child.EndLine = currentLine
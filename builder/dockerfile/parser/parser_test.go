@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -164,3 +165,91 @@ func TestLineInformation(t *testing.T) {
 		}
 	}
 }
+
+func TestParseErrorLocation(t *testing.T) {
+	tests := []struct {
+		dockerfile string
+		line       int
+		column     int
+		msg        string
+	}{
+		{"FROM busybox\nENV foo\n", 2, 1, "ENV must have two arguments"},
+		{"FROM busybox\n\n  LABEL \\\n  foo\n", 3, 3, "LABEL must have two arguments"},
+		{"FROM busybox\nRUN cat <<A\nfoo\n", 2, 1, `unterminated heredoc, expected "A"`},
+		{"FROM busybox\nCMD [\n  \"foo\"\n", 2, 1, "unterminated JSON array"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.dockerfile))
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Fatalf("expected a *ParseError for %q, got %v", test.dockerfile, err)
+		}
+		if line, column := perr.Position(); line != test.line || column != test.column {
+			t.Fatalf("wrong position for %q: expected %d:%d, got %d:%d", test.dockerfile, test.line, test.column, line, column)
+		}
+		if !strings.Contains(perr.Error(), test.msg) {
+			t.Fatalf("expected error for %q to contain %q, got %q", test.dockerfile, test.msg, perr.Error())
+		}
+	}
+}
+
+func TestUnknownInstructionError(t *testing.T) {
+	tests := map[string]string{
+		"COPPY":      "did you mean COPY?",
+		"entrypiont": "did you mean ENTRYPOINT?",
+		"XYZ":        "",
+		"HEALTHCHEK": "did you mean HEALTHCHECK?",
+	}
+
+	for cmd, suggestion := range tests {
+		err := UnknownInstructionError(&Node{Value: strings.ToLower(cmd), StartLine: 4}).(*ParseError)
+		if err.Line != 4 {
+			t.Fatalf("expected the error for %s on line 4, got %d", cmd, err.Line)
+		}
+		if err.Hint() != suggestion {
+			t.Fatalf("expected suggestion %q for %s, got %q", suggestion, cmd, err.Hint())
+		}
+	}
+}
+
+func TestHeredocLineInformation(t *testing.T) {
+	ast, err := Parse(strings.NewReader("FROM busybox\nRUN <<EOF\necho hello\nEOF\nCMD [\n  \"sh\"\n]\nUSER root\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]int{{1, 1}, {2, 4}, {5, 7}, {8, 8}}
+	if len(ast.Children) != len(expected) {
+		t.Fatalf("expected %d instructions, got %d", len(expected), len(ast.Children))
+	}
+	for i, child := range ast.Children {
+		if child.StartLine != expected[i][0] || child.EndLine != expected[i][1] {
+			t.Fatalf("wrong line information for child %d: expected(%d-%d), actual(%d-%d)",
+				i, expected[i][0], expected[i][1], child.StartLine, child.EndLine)
+		}
+	}
+}
+
+func TestFindHeredocs(t *testing.T) {
+	tests := map[string][]heredoc{
+		"cat <<EOF":                      {{delimiter: "EOF"}},
+		"cat <<-'END' > /file":           {{delimiter: "END", stripTabs: true}},
+		`cat << "a b" <<B`:               {{delimiter: "a b"}, {delimiter: "B"}},
+		"cat <<<word":                    nil,
+		"echo '<<EOF' \"<<EOF\" \\<<EOF": nil,
+		"echo $((1<<2))":                 nil,
+	}
+
+	for cmd, expected := range tests {
+		heredocs := findHeredocs(cmd)
+		if len(heredocs) != len(expected) {
+			t.Fatalf("expected %v heredocs for %q, got %v", expected, cmd, heredocs)
+		}
+		for i, h := range heredocs {
+			if h != expected[i] {
+				t.Fatalf("expected %v heredocs for %q, got %v", expected, cmd, heredocs)
+			}
+		}
+	}
+}
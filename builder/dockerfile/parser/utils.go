@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/docker/docker/builder/dockerfile/command"
 )
 
 // Dump dumps the AST defined by `node` as a list of sexps.
@@ -73,6 +75,46 @@ func splitCommand(line string) (string, []string, string, error) {
 	return cmd, flags, strings.TrimSpace(args), nil
 }
 
+// continuesJSONArray returns whether the line is the start of the JSON form of
+// an instruction that continues on the next line, that is an array whose
+// closing bracket is missing.
+func continuesJSONArray(line string) bool {
+	cmd, _, args, err := splitCommand(line)
+	if err != nil {
+		return false
+	}
+	switch cmd {
+	case command.Onbuild:
+		return continuesJSONArray(args)
+	case command.Healthcheck:
+		fields := tokenWhitespace.Split(args, 2)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "cmd") {
+			return false
+		}
+		args = fields[1]
+	}
+	if !strings.HasPrefix(args, "[") {
+		return false
+	}
+
+	depth, inString, escaped := 0, false, false
+	for _, c := range args {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && c == '[':
+			depth++
+		case !inString && c == ']':
+			depth--
+		}
+	}
+	return !inString && depth > 0
+}
+
 // covers comments and empty lines. Lines should be trimmed before passing to
 // this function.
 func stripComments(line string) string {
@@ -0,0 +1,117 @@
+package dockerfile
+
+// Build-time secrets and SSH agent forwarding. Secrets passed with
+// `docker build --secret` are written to a private directory (backed by a
+// tmpfs where supported) that is bind-mounted read-only into the containers
+// running RUN instructions. Because bind mounts are not part of the
+// container's writable layer, the secrets never end up in a committed image.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+const (
+	// secretsMountPath is the directory in which secrets are exposed to RUN instructions
+	secretsMountPath = "/run/secrets"
+	// sshMountPath is the directory in which SSH agent sockets are exposed to RUN instructions
+	sshMountPath = "/run/ssh"
+	// defaultSSHAgentID is the id of the agent that SSH_AUTH_SOCK points to in RUN instructions
+	defaultSSHAgentID = "default"
+)
+
+// secretBinds returns the bind mounts that expose the build secrets and SSH
+// agent sockets to a build container. The secrets directory is created on
+// first use and removed by cleanupSecrets.
+func (b *Builder) secretBinds() ([]string, error) {
+	var binds []string
+
+	if len(b.options.Secrets) > 0 {
+		if b.secretsDir == "" {
+			dir, err := b.writeSecrets()
+			if err != nil {
+				return nil, err
+			}
+			b.secretsDir = dir
+		}
+		binds = append(binds, fmt.Sprintf("%s:%s:ro", b.secretsDir, secretsMountPath))
+	}
+
+	var ids []string
+	for id := range b.options.SSHAgents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		sock := b.options.SSHAgents[id]
+		if fi, err := os.Stat(sock); err != nil {
+			return nil, fmt.Errorf("invalid ssh agent %s: %v", id, err)
+		} else if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("invalid ssh agent %s: %s is not a socket", id, sock)
+		}
+		binds = append(binds, fmt.Sprintf("%s:%s", sock, sshSocketPath(id)))
+	}
+	return binds, nil
+}
+
+// sshEnv returns the environment pointing SSH clients in RUN instructions
+// to the forwarded default agent, if any.
+func (b *Builder) sshEnv() []string {
+	if _, ok := b.options.SSHAgents[defaultSSHAgentID]; !ok {
+		return nil
+	}
+	return []string{"SSH_AUTH_SOCK=" + sshSocketPath(defaultSSHAgentID)}
+}
+
+func sshSocketPath(id string) string {
+	return path.Join(sshMountPath, id+".sock")
+}
+
+func (b *Builder) writeSecrets() (dir string, err error) {
+	dir, err = ioutils.TempDir("", "docker-build-secrets")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	if err = mountSecretsFS(dir); err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			unmountSecretsFS(dir)
+		}
+	}()
+
+	for id, data := range b.options.Secrets {
+		if err = ioutil.WriteFile(filepath.Join(dir, id), data, 0400); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// cleanupSecrets removes the secrets directory created for the build.
+func (b *Builder) cleanupSecrets() {
+	if b.secretsDir == "" {
+		return
+	}
+	if err := unmountSecretsFS(b.secretsDir); err != nil {
+		logrus.Warnf("[BUILDER] failed to unmount build secrets: %v", err)
+	}
+	if err := os.RemoveAll(b.secretsDir); err != nil {
+		logrus.Warnf("[BUILDER] failed to remove build secrets: %v", err)
+	}
+	b.secretsDir = ""
+}
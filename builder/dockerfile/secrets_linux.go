@@ -0,0 +1,13 @@
+package dockerfile
+
+import "github.com/docker/docker/pkg/mount"
+
+// mountSecretsFS mounts a tmpfs on dir so that build secrets are never
+// written to disk.
+func mountSecretsFS(dir string) error {
+	return mount.Mount("tmpfs", dir, "tmpfs", "nodev,nosuid,noexec,mode=0755,size=4m")
+}
+
+func unmountSecretsFS(dir string) error {
+	return mount.Unmount(dir)
+}
@@ -0,0 +1,54 @@
+package dockerfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestSSHEnv(t *testing.T) {
+	b := &Builder{options: &types.ImageBuildOptions{}}
+	if env := b.sshEnv(); len(env) != 0 {
+		t.Fatalf("Expected no environment without ssh agents, got %v", env)
+	}
+
+	b.options.SSHAgents = map[string]string{"other": "/tmp/agent.sock"}
+	if env := b.sshEnv(); len(env) != 0 {
+		t.Fatalf("Expected no environment without default ssh agent, got %v", env)
+	}
+
+	b.options.SSHAgents["default"] = "/tmp/agent.sock"
+	env := b.sshEnv()
+	if len(env) != 1 || env[0] != "SSH_AUTH_SOCK=/run/ssh/default.sock" {
+		t.Fatalf("Unexpected ssh environment: %v", env)
+	}
+}
+
+func TestSecretBindsInvalidSSHAgent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "builder-secrets-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "agent.sock")
+	if err := ioutil.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &Builder{options: &types.ImageBuildOptions{
+		SSHAgents: map[string]string{"default": file},
+	}}
+	if _, err := b.secretBinds(); err == nil || !strings.Contains(err.Error(), "is not a socket") {
+		t.Fatalf("Expected not a socket error, got %v", err)
+	}
+
+	b.options.SSHAgents["default"] = filepath.Join(dir, "missing.sock")
+	if _, err := b.secretBinds(); err == nil {
+		t.Fatal("Expected error for missing ssh agent socket")
+	}
+}
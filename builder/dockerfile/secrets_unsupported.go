@@ -0,0 +1,11 @@
+// +build !linux
+
+package dockerfile
+
+func mountSecretsFS(dir string) error {
+	return nil
+}
+
+func unmountSecretsFS(dir string) error {
+	return nil
+}
@@ -0,0 +1,200 @@
+package dockerfile
+
+// Support for multi-stage Dockerfiles. Every FROM instruction starts a new
+// build stage; the image produced by a stage can be referenced by later
+// stages through `COPY --from=<name|index>`.
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/builder"
+	"github.com/docker/docker/builder/dockerfile/command"
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/strslice"
+)
+
+// buildStage is a single FROM-delimited section of a Dockerfile.
+type buildStage struct {
+	name  string // lowercased name given with `FROM image AS name`, may be empty
+	base  string // ID of the image the stage is based on, empty for scratch
+	image string // ID of the image this stage produced, set once the stage is done
+}
+
+// startStage finishes the current stage (if any) and begins a new one,
+// resetting the per-stage builder state.
+func (b *Builder) startStage(name string) error {
+	if name != "" {
+		if _, err := strconv.Atoi(name); err == nil {
+			return fmt.Errorf("invalid name for build stage: %q, name can't be a number", name)
+		}
+		for _, s := range b.stages {
+			if s.name == name {
+				return fmt.Errorf("duplicate name for build stage: %q", name)
+			}
+		}
+	}
+	if n := len(b.stages); n > 0 {
+		b.stages[n-1].image = b.image
+	}
+	b.stages = append(b.stages, &buildStage{name: name})
+
+	b.image = ""
+	b.noBaseImage = false
+	b.maintainer = ""
+	b.cmdSet = false
+	b.cacheBusted = false
+	b.runConfig = new(container.Config)
+	return nil
+}
+
+// stageImage resolves a `--from` reference to an image ID. The reference is
+// either the name or the index of a previous stage, or an image name.
+func (b *Builder) stageImage(ref string) (string, error) {
+	// the last stage is the one currently being built and can't be used
+	previous := b.stages
+	if len(previous) > 0 {
+		previous = previous[:len(previous)-1]
+	}
+	if i, err := strconv.Atoi(ref); err == nil {
+		if i < 0 || i >= len(previous) {
+			return "", fmt.Errorf("invalid from flag value %d: refers to a stage that has not been built yet", i)
+		}
+		return previous[i].image, nil
+	}
+	for _, s := range previous {
+		if s.name == strings.ToLower(ref) {
+			return s.image, nil
+		}
+	}
+
+	img, err := b.docker.GetImageOnBuild(ref)
+	if err != nil || img == nil {
+		if img, err = b.docker.PullOnBuild(b.clientCtx, ref, b.options.AuthConfigs, b.options.Platform, b.Output); err != nil {
+			return "", err
+		}
+	}
+	return img.ImageID(), nil
+}
+
+// stageContext returns a build context holding the root filesystem of the
+// image referenced by `ref`. Contexts are kept until the end of the build
+// so that several COPY --from instructions can share them.
+func (b *Builder) stageContext(ref string) (builder.Context, error) {
+	imageID, err := b.stageImage(ref)
+	if err != nil {
+		return nil, err
+	}
+	if imageID == "" {
+		return nil, fmt.Errorf("stage %q did not produce an image", ref)
+	}
+	if ctx, ok := b.stageContexts[imageID]; ok {
+		return ctx, nil
+	}
+
+	c, err := b.docker.ContainerCreate(types.ContainerCreateConfig{
+		Config: &container.Config{
+			Image:        imageID,
+			Cmd:          strslice.StrSlice(append(getShell(b.runConfig), "#(nop) ", "COPY --from="+ref)),
+			Architecture: b.architecture,
+		},
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer b.removeContainer(c.ID)
+
+	rc, _, err := b.docker.ContainerArchivePath(c.ID, "/")
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	ctx, err := builder.MakeTarSumContext(relativeTarNames(rc))
+	if err != nil {
+		return nil, err
+	}
+	b.stageContexts[imageID] = ctx
+	return ctx, nil
+}
+
+// closeStageContexts removes the temporary contexts created for COPY --from.
+func (b *Builder) closeStageContexts() {
+	for id, ctx := range b.stageContexts {
+		ctx.Close()
+		delete(b.stageContexts, id)
+	}
+}
+
+// relativeTarNames rewrites an archive of a root filesystem, whose entries
+// are rooted at "/", so that its entries are relative. Build contexts look
+// files up by their relative path.
+func relativeTarNames(r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(r)
+		tw := tar.NewWriter(pw)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			hdr.Name = strings.TrimLeft(hdr.Name, "/")
+			if hdr.Name == "" {
+				continue
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	return pr
+}
+
+// stageName returns the lowercased name given to the stage started by the
+// FROM node `n`, or an empty string.
+func stageName(n *parser.Node) string {
+	var args []string
+	for next := n.Next; next != nil; next = next.Next {
+		args = append(args, next.Value)
+	}
+	if len(args) == 3 && strings.EqualFold(args[1], "as") {
+		return strings.ToLower(args[2])
+	}
+	return ""
+}
+
+// trimToTarget removes all instructions following the stage named `target`.
+func trimToTarget(ast *parser.Node, target string) error {
+	target = strings.ToLower(target)
+	found := false
+	for i, n := range ast.Children {
+		if n.Value != command.From {
+			continue
+		}
+		if found {
+			ast.Children = ast.Children[:i]
+			return nil
+		}
+		found = stageName(n) == target
+	}
+	if !found {
+		return fmt.Errorf("failed to reach build target %s in Dockerfile", target)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package dockerfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+)
+
+const multiStageDockerfile = `FROM busybox AS build
+RUN echo build
+FROM busybox as Test
+RUN echo test
+FROM busybox
+RUN echo final
+`
+
+func TestTrimToTarget(t *testing.T) {
+	cases := []struct {
+		target   string
+		expected int
+	}{
+		{"build", 2},
+		{"test", 4},
+		{"TEST", 4},
+	}
+
+	for _, c := range cases {
+		ast, err := parser.Parse(strings.NewReader(multiStageDockerfile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := trimToTarget(ast, c.target); err != nil {
+			t.Fatalf("Error trimming to target %s: %v", c.target, err)
+		}
+		if len(ast.Children) != c.expected {
+			t.Fatalf("Target %s: expected %d instructions, got %d", c.target, c.expected, len(ast.Children))
+		}
+	}
+}
+
+func TestTrimToUnknownTarget(t *testing.T) {
+	ast, err := parser.Parse(strings.NewReader(multiStageDockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = trimToTarget(ast, "nosuchstage")
+	if err == nil || !strings.Contains(err.Error(), "failed to reach build target") {
+		t.Fatalf("Expected an error for an unknown target, got: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package dockerfile
+
+// Structured output of a build. When a client requests it, each step of the
+// build reports its progress when it starts and when it ends, and its output
+// is tagged with its index, so that the output of the stages built
+// concurrently can be told apart.
+
+import (
+	"time"
+
+	"github.com/docker/docker/builder"
+	"github.com/docker/engine-api/types"
+)
+
+// startStep reports the start of the step index, and sends the output of the
+// builder to the output of the step. It returns false if the structured
+// output was not requested, or if a step is already being dispatched, like
+// the ONBUILD triggers of the base image of a FROM step.
+func (b *Builder) startStep(index int, instruction string) bool {
+	if b.stepOutput == nil || b.step != nil {
+		return false
+	}
+	b.step = &types.BuildStep{
+		Index:       index + 1,
+		Instruction: instruction,
+		Status:      "start",
+		Start:       time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	b.stepOutput.Step(*b.step)
+	b.buildStdout, b.buildStderr = b.Stdout, b.Stderr
+	b.Stdout, b.Stderr = b.stepOutput.Output(b.step.Index)
+	return true
+}
+
+// setStepCache records whether the image of the step being dispatched was
+// found in the cache. A step running ONBUILD triggers is a cache hit only if
+// all of them are. It returns false if there is no such step.
+func (b *Builder) setStepCache(hit bool) bool {
+	if b.step == nil {
+		return false
+	}
+	if !hit {
+		b.step.Cache = "miss"
+	} else if b.step.Cache == "" {
+		b.step.Cache = "hit"
+	}
+	return true
+}
+
+// endStep reports the end of the step being dispatched, if any, with the
+// error err it failed with, and sends the output of the builder back to the
+// output of the build.
+func (b *Builder) endStep(err error) {
+	if b.step == nil {
+		return
+	}
+	step := *b.step
+	b.step = nil
+	if err != nil {
+		step.Status = "error"
+		step.Error = err.Error()
+	} else {
+		step.Status = "done"
+		step.ImageID = b.image
+		step.Layer = b.topLayer(b.image)
+	}
+	step.End = time.Now().UTC().Format(time.RFC3339Nano)
+	b.stepOutput.Step(step)
+	b.Stdout, b.Stderr = b.buildStdout, b.buildStderr
+}
+
+// topLayer returns the digest of the top layer of the image id, or an empty
+// string if the backend does not tell it.
+func (b *Builder) topLayer(id string) string {
+	if id == "" {
+		return ""
+	}
+	img, err := b.docker.GetImageOnBuild(id)
+	if err != nil {
+		return ""
+	}
+	if li, ok := img.(builder.LayeredImage); ok {
+		return li.TopLayer()
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package dockerfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+type recordedStepOutput struct {
+	steps  []types.BuildStep
+	output map[int]*bytes.Buffer
+}
+
+func (r *recordedStepOutput) Step(step types.BuildStep) {
+	r.steps = append(r.steps, step)
+}
+
+func (r *recordedStepOutput) Output(index int) (io.Writer, io.Writer) {
+	if r.output[index] == nil {
+		r.output[index] = new(bytes.Buffer)
+	}
+	return r.output[index], r.output[index]
+}
+
+func TestBuildSteps(t *testing.T) {
+	var stdout bytes.Buffer
+	out := &recordedStepOutput{output: make(map[int]*bytes.Buffer)}
+	b := &Builder{docker: &mockBackend{}, Stdout: &stdout, Stderr: &stdout, stepOutput: out}
+
+	if !b.startStep(0, "RUN make") {
+		t.Fatal("expected the step to start")
+	}
+	if b.startStep(1, "RUN nested") {
+		t.Fatal("expected no nested step")
+	}
+	b.Stdout.Write([]byte("building\n"))
+	b.setStepCache(true)
+	b.setStepCache(false)
+	b.image = "sha256:1234"
+	b.endStep(nil)
+
+	b.startStep(1, "RUN false")
+	b.endStep(errors.New("failed"))
+
+	b.Stdout.Write([]byte("Successfully built\n"))
+
+	if len(out.steps) != 4 {
+		t.Fatalf("expected 4 step reports, got %v", out.steps)
+	}
+	if s := out.steps[0]; s.Index != 1 || s.Instruction != "RUN make" || s.Status != "start" || s.Start == "" {
+		t.Fatalf("unexpected start of step 1: %+v", s)
+	}
+	if s := out.steps[1]; s.Status != "done" || s.Cache != "miss" || s.ImageID != "sha256:1234" || s.End == "" {
+		t.Fatalf("unexpected end of step 1: %+v", s)
+	}
+	if s := out.steps[3]; s.Index != 2 || s.Status != "error" || s.Error != "failed" {
+		t.Fatalf("unexpected end of step 2: %+v", s)
+	}
+	if out.output[1].String() != "building\n" {
+		t.Fatalf("expected the output of step 1 to be tagged, got %q", out.output[1].String())
+	}
+	if stdout.String() != "Successfully built\n" {
+		t.Fatalf("expected the output after the steps in the output of the build, got %q", stdout.String())
+	}
+
+	b = &Builder{Stdout: &stdout}
+	if b.startStep(0, "RUN make") || b.setStepCache(true) {
+		t.Fatal("expected no step without a structured output")
+	}
+}
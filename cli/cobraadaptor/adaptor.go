@@ -2,16 +2,22 @@ package cobraadaptor
 
 import (
 	"github.com/docker/docker/api/client"
+	"github.com/docker/docker/api/client/completion"
 	"github.com/docker/docker/api/client/container"
+	"github.com/docker/docker/api/client/context"
+	"github.com/docker/docker/api/client/group"
 	"github.com/docker/docker/api/client/image"
+	"github.com/docker/docker/api/client/manifest"
 	"github.com/docker/docker/api/client/network"
 	"github.com/docker/docker/api/client/node"
 	"github.com/docker/docker/api/client/plugin"
 	"github.com/docker/docker/api/client/registry"
+	"github.com/docker/docker/api/client/secret"
 	"github.com/docker/docker/api/client/service"
 	"github.com/docker/docker/api/client/stack"
 	"github.com/docker/docker/api/client/swarm"
 	"github.com/docker/docker/api/client/system"
+	"github.com/docker/docker/api/client/trust"
 	"github.com/docker/docker/api/client/volume"
 	"github.com/docker/docker/cli"
 	cliflags "github.com/docker/docker/cli/flags"
@@ -47,6 +53,7 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		stack.NewTopLevelDeployCommand(dockerCli),
 		swarm.NewSwarmCommand(dockerCli),
 		container.NewAttachCommand(dockerCli),
+		container.NewCloneCommand(dockerCli),
 		container.NewCommitCommand(dockerCli),
 		container.NewCopyCommand(dockerCli),
 		container.NewCreateCommand(dockerCli),
@@ -68,6 +75,8 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		container.NewUnpauseCommand(dockerCli),
 		container.NewUpdateCommand(dockerCli),
 		container.NewWaitCommand(dockerCli),
+		context.NewContextCommand(dockerCli),
+		group.NewGroupCommand(dockerCli),
 		image.NewBuildCommand(dockerCli),
 		image.NewHistoryCommand(dockerCli),
 		image.NewImagesCommand(dockerCli),
@@ -79,13 +88,18 @@ func NewCobraAdaptor(clientFlags *cliflags.ClientFlags) CobraAdaptor {
 		image.NewSearchCommand(dockerCli),
 		image.NewImportCommand(dockerCli),
 		image.NewTagCommand(dockerCli),
+		manifest.NewManifestCommand(dockerCli),
 		network.NewNetworkCommand(dockerCli),
 		system.NewEventsCommand(dockerCli),
 		registry.NewLoginCommand(dockerCli),
 		registry.NewLogoutCommand(dockerCli),
+		secret.NewSecretCommand(dockerCli),
+		trust.NewTrustCommand(dockerCli),
+		system.NewSystemCommand(dockerCli),
 		system.NewVersionCommand(dockerCli),
 		volume.NewVolumeCommand(dockerCli),
 		system.NewInfoCommand(dockerCli),
+		completion.NewCompletionCommand(dockerCli),
 	)
 	plugin.NewPluginCommand(rootCmd, dockerCli)
 
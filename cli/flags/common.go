@@ -8,6 +8,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/cliconfig"
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/pkg/loglevel"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/go-connections/tlsconfig"
 )
@@ -116,8 +117,8 @@ func SetDaemonLogLevel(logLevel string) {
 			fmt.Fprintf(os.Stderr, "Unable to parse logging level: %s\n", logLevel)
 			os.Exit(1)
 		}
-		logrus.SetLevel(lvl)
+		loglevel.SetLevel(lvl)
 	} else {
-		logrus.SetLevel(logrus.InfoLevel)
+		loglevel.SetLevel(logrus.InfoLevel)
 	}
 }
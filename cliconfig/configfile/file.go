@@ -22,13 +22,31 @@ const (
 
 // ConfigFile ~/.docker/config.json file info
 type ConfigFile struct {
-	AuthConfigs      map[string]types.AuthConfig `json:"auths"`
-	HTTPHeaders      map[string]string           `json:"HttpHeaders,omitempty"`
-	PsFormat         string                      `json:"psFormat,omitempty"`
-	ImagesFormat     string                      `json:"imagesFormat,omitempty"`
-	DetachKeys       string                      `json:"detachKeys,omitempty"`
-	CredentialsStore string                      `json:"credsStore,omitempty"`
-	Filename         string                      `json:"-"` // Note: for internal use only
+	AuthConfigs       map[string]types.AuthConfig `json:"auths"`
+	HTTPHeaders       map[string]string           `json:"HttpHeaders,omitempty"`
+	PsFormat          string                      `json:"psFormat,omitempty"`
+	ImagesFormat      string                      `json:"imagesFormat,omitempty"`
+	DetachKeys        string                      `json:"detachKeys,omitempty"`
+	CredentialsStore  string                      `json:"credsStore,omitempty"`
+	CredentialHelpers map[string]string           `json:"credHelpers,omitempty"`
+	Contexts          map[string]ContextConfig    `json:"contexts,omitempty"`
+	CurrentContext    string                      `json:"currentContext,omitempty"`
+	Filename          string                      `json:"-"` // Note: for internal use only
+}
+
+// ContextConfig is a named daemon endpoint, which the client connects to
+// when it is selected with `docker --context` or `docker context use`.
+type ContextConfig struct {
+	Description string `json:"description,omitempty"`
+	Host        string `json:"host"`
+	// APIVersion is the API version used with the daemon, instead of the
+	// version of the client.
+	APIVersion string `json:"apiVersion,omitempty"`
+	TLS        bool   `json:"tls,omitempty"`
+	TLSVerify  bool   `json:"tlsVerify,omitempty"`
+	// CertPath is the directory of the ca.pem, cert.pem and key.pem files
+	// used for TLS.
+	CertPath string `json:"certPath,omitempty"`
 }
 
 // LegacyLoadFromReader reads the non-nested configuration data given and sets up the
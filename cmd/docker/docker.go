@@ -98,6 +98,7 @@ func initClientFlags(commonFlags *cliflags.CommonFlags) *cliflags.ClientFlags {
 	clientFlags := &cliflags.ClientFlags{FlagSet: new(flag.FlagSet), Common: commonFlags}
 	client := clientFlags.FlagSet
 	client.StringVar(&clientFlags.ConfigDir, []string{"-config"}, cliconfig.ConfigDir(), "Location of client config files")
+	client.StringVar(&clientFlags.Context, []string{"-context"}, os.Getenv("DOCKER_CONTEXT"), "Name of the context to connect to")
 
 	clientFlags.PostParse = func() {
 		clientFlags.Common.PostParse()
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,15 +15,19 @@ import (
 	"github.com/docker/distribution/uuid"
 	"github.com/docker/docker/api"
 	apiserver "github.com/docker/docker/api/server"
+	"github.com/docker/docker/api/server/audit"
 	"github.com/docker/docker/api/server/middleware"
 	"github.com/docker/docker/api/server/router"
 	"github.com/docker/docker/api/server/router/build"
 	"github.com/docker/docker/api/server/router/container"
+	"github.com/docker/docker/api/server/router/group"
 	"github.com/docker/docker/api/server/router/image"
 	"github.com/docker/docker/api/server/router/network"
+	secretrouter "github.com/docker/docker/api/server/router/secret"
 	swarmrouter "github.com/docker/docker/api/server/router/swarm"
 	systemrouter "github.com/docker/docker/api/server/router/system"
 	"github.com/docker/docker/api/server/router/volume"
+	"github.com/docker/docker/api/server/tlsauth"
 	"github.com/docker/docker/builder/dockerfile"
 	cliflags "github.com/docker/docker/cli/flags"
 	"github.com/docker/docker/cliconfig"
@@ -33,8 +38,10 @@ import (
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/authorization"
+	"github.com/docker/docker/pkg/envmask"
 	"github.com/docker/docker/pkg/jsonlog"
 	"github.com/docker/docker/pkg/listeners"
+	"github.com/docker/docker/pkg/loglevel"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/pidfile"
 	"github.com/docker/docker/pkg/signal"
@@ -42,7 +49,6 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/utils"
-	"github.com/docker/go-connections/tlsconfig"
 )
 
 const (
@@ -55,8 +61,14 @@ type DaemonCli struct {
 	commonFlags *cliflags.CommonFlags
 	configFile  *string
 
-	api *apiserver.Server
-	d   *daemon.Daemon
+	api         *apiserver.Server
+	d           *daemon.Daemon
+	tlsStore    *tlsauth.Store
+	auditLogger audit.Logger
+	auditFilter *audit.Filter
+	auditMasker *envmask.Masker
+
+	debugListener net.Listener
 }
 
 func presentInHelp(usage string) string { return usage }
@@ -68,6 +80,7 @@ func NewDaemonCli() *DaemonCli {
 	daemonConfig := new(daemon.Config)
 	daemonConfig.LogConfig.Config = make(map[string]string)
 	daemonConfig.ClusterOpts = make(map[string]string)
+	daemonConfig.AuditLogOpts = make(map[string]string)
 
 	if runtime.GOOS != "linux" {
 		daemonConfig.V2Only = true
@@ -152,10 +165,14 @@ func (cli *DaemonCli) start() (err error) {
 		logrus.Warn("Running experimental build")
 	}
 
-	logrus.SetFormatter(&logrus.TextFormatter{
+	var formatter logrus.Formatter = &logrus.TextFormatter{
 		TimestampFormat: jsonlog.RFC3339NanoFixed,
 		DisableColors:   cli.Config.RawLogs,
-	})
+	}
+	if cli.Config.LogFormat == "json" {
+		formatter = &logrus.JSONFormatter{TimestampFormat: jsonlog.RFC3339NanoFixed}
+	}
+	logrus.SetFormatter(loglevel.NewFormatter(formatter))
 
 	if err := setDefaultUmask(); err != nil {
 		return fmt.Errorf("Failed to set umask: %v", err)
@@ -188,21 +205,48 @@ func (cli *DaemonCli) start() (err error) {
 	}
 
 	if cli.Config.TLS {
-		tlsOptions := tlsconfig.Options{
+		tlsOptions := tlsauth.Options{
 			CAFile:   cli.Config.CommonTLSOptions.CAFile,
 			CertFile: cli.Config.CommonTLSOptions.CertFile,
 			KeyFile:  cli.Config.CommonTLSOptions.KeyFile,
+			CRLFile:  cli.Config.TLSCRLFile,
 		}
 
 		if cli.Config.TLSVerify {
 			// server requires and verifies client's certificate
 			tlsOptions.ClientAuth = tls.RequireAndVerifyClientCert
 		}
-		tlsConfig, err := tlsconfig.Server(tlsOptions)
+		tlsStore, err := tlsauth.New(tlsOptions)
+		if err != nil {
+			return err
+		}
+		tlsConfig, err := tlsStore.ServerConfig()
 		if err != nil {
 			return err
 		}
 		serverConfig.TLSConfig = tlsConfig
+		cli.tlsStore = tlsStore
+	} else if cli.Config.TLSCRLFile != "" {
+		return fmt.Errorf("--tlscrl requires --tlsverify")
+	}
+
+	if cli.Config.AuditLogDriver != "" && cli.Config.AuditLogDriver != "none" {
+		auditFilter, err := audit.NewFilter(cli.Config.AuditEndpoints)
+		if err != nil {
+			return err
+		}
+		auditMasker, err := envmask.New(cli.Config.SecretEnvPatterns)
+		if err != nil {
+			return err
+		}
+		auditLogger, err := audit.New(cli.Config.AuditLogDriver, cli.Config.AuditLogOpts)
+		if err != nil {
+			return fmt.Errorf("Failed to initialize the audit log: %v", err)
+		}
+		defer auditLogger.Close()
+		cli.auditLogger = auditLogger
+		cli.auditFilter = auditFilter
+		cli.auditMasker = auditMasker
 	}
 
 	if len(cli.Config.Hosts) == 0 {
@@ -246,6 +290,10 @@ func (cli *DaemonCli) start() (err error) {
 		api.Accept(protoAddrParts[1], ls...)
 	}
 
+	if err := cli.serveDebugSocket(); err != nil {
+		return err
+	}
+
 	if err := migrateKey(); err != nil {
 		return err
 	}
@@ -291,7 +339,7 @@ func (cli *DaemonCli) start() (err error) {
 	}).Info("Docker daemon")
 
 	cli.initMiddlewares(api, serverConfig)
-	initRouter(api, d, c)
+	initRouter(api, d, c, cli.Config)
 
 	cli.d = d
 	cli.setupConfigReloadTrap()
@@ -309,7 +357,7 @@ func (cli *DaemonCli) start() (err error) {
 	// Wait for serve API to complete
 	errAPI := <-serveAPIWait
 	c.Cleanup()
-	shutdownDaemon(d, 15)
+	shutdownDaemon(d, time.Duration(d.ShutdownTimeout()))
 	containerdRemote.Cleanup()
 	if errAPI != nil {
 		return fmt.Errorf("Shutting down due to ServeAPI error: %v", errAPI)
@@ -324,6 +372,12 @@ func (cli *DaemonCli) reloadConfig() {
 			logrus.Errorf("Error reconfiguring the daemon: %v", err)
 			return
 		}
+		if config.IsValueSet("log-level") {
+			cliflags.SetDaemonLogLevel(config.LogLevel)
+		}
+		if config.IsValueSet("log-levels") {
+			setSubsystemLogLevels(config)
+		}
 		if config.IsValueSet("debug") {
 			debugEnabled := utils.IsDebugEnabled()
 			switch {
@@ -341,10 +395,19 @@ func (cli *DaemonCli) reloadConfig() {
 	if err := daemon.ReloadConfiguration(*cli.configFile, flag.CommandLine, reload); err != nil {
 		logrus.Error(err)
 	}
+
+	if cli.tlsStore != nil {
+		if err := cli.tlsStore.Reload(); err != nil {
+			logrus.Errorf("Error reloading the TLS certificates: %v", err)
+		}
+	}
 }
 
 func (cli *DaemonCli) stop() {
 	cli.api.Close()
+	if cli.debugListener != nil {
+		cli.debugListener.Close()
+	}
 }
 
 // shutdownDaemon just wraps daemon.Shutdown() to handle a timeout in case
@@ -404,11 +467,19 @@ func loadDaemonCliConfig(config *daemon.Config, flags *flag.FlagSet, commonConfi
 
 	// ensure that the log level is the one set after merging configurations
 	cliflags.SetDaemonLogLevel(config.LogLevel)
+	setSubsystemLogLevels(config)
 
 	return config, nil
 }
 
-func initRouter(s *apiserver.Server, d *daemon.Daemon, c *cluster.Cluster) {
+// setSubsystemLogLevels sets the log levels of the subsystems of the daemon,
+// validated with the configuration.
+func setSubsystemLogLevels(config *daemon.Config) {
+	levels, _ := loglevel.ParseSubsystemLevels(config.LogLevels)
+	loglevel.SetSubsystemLevels(levels)
+}
+
+func initRouter(s *apiserver.Server, d *daemon.Daemon, c *cluster.Cluster, config *daemon.Config) {
 	decoder := runconfig.ContainerDecoder{}
 
 	routers := []router.Router{
@@ -416,7 +487,9 @@ func initRouter(s *apiserver.Server, d *daemon.Daemon, c *cluster.Cluster) {
 		image.NewRouter(d, decoder),
 		systemrouter.NewRouter(d, c),
 		volume.NewRouter(d),
-		build.NewRouter(dockerfile.NewBuildManager(d)),
+		secretrouter.NewRouter(d),
+		group.NewRouter(d),
+		build.NewRouter(dockerfile.NewBuildManager(d, *config.MaxConcurrentBuildStages)),
 		swarmrouter.NewRouter(c),
 	}
 	if d.NetworkControllerEnabled() {
@@ -446,4 +519,23 @@ func (cli *DaemonCli) initMiddlewares(s *apiserver.Server, cfg *apiserver.Config
 		handleAuthorization := authorization.NewMiddleware(authZPlugins)
 		s.UseMiddleware(handleAuthorization)
 	}
+
+	if cli.Config.APIMaxConcurrentRequests > 0 || cli.Config.APIMaxConcurrentRequestsPerClient > 0 {
+		l := middleware.NewLimitMiddleware(cli.Config.APIMaxConcurrentRequests, cli.Config.APIMaxConcurrentRequestsPerClient, cli.Config.APIMaxQueuedRequests)
+		s.UseMiddleware(l)
+	}
+
+	// The requests denied by the authorization plugins or over the limits
+	// are audited too.
+	if cli.auditLogger != nil {
+		a := middleware.NewAuditMiddleware(cli.auditLogger, cli.auditFilter, cli.auditMasker)
+		s.UseMiddleware(a)
+	}
+
+	// The client certificates are verified before any other middleware
+	// handles the requests.
+	if cli.tlsStore != nil {
+		t := middleware.NewTLSMiddleware(cli.tlsStore)
+		s.UseMiddleware(t)
+	}
 }
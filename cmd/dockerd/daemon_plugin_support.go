@@ -10,5 +10,14 @@ import (
 )
 
 func pluginInit(config *daemon.Config, remote libcontainerd.Remote, rs registry.Service) error {
-	return plugin.Init(config.Root, config.ExecRoot, remote, rs, config.LiveRestore)
+	// The plugins run under the cgroup parent of the containers.
+	systemd := daemon.UsingSystemd(config)
+	cgroupParent := config.CgroupParent
+	if cgroupParent == "" {
+		cgroupParent = "/docker"
+		if systemd {
+			cgroupParent = "system.slice"
+		}
+	}
+	return plugin.Init(config.Root, config.ExecRoot, remote, rs, config.LiveRestore, cgroupParent, systemd)
 }
@@ -69,6 +69,11 @@ func allocateDaemonPort(addr string) error {
 func notifyShutdown(err error) {
 }
 
+// serveDebugSocket does nothing: there is no debug socket on Solaris.
+func (cli *DaemonCli) serveDebugSocket() error {
+	return nil
+}
+
 func wrapListeners(proto string, ls []net.Listener) []net.Listener {
 	return ls
 }
@@ -9,12 +9,16 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/Sirupsen/logrus"
+	apiserver "github.com/docker/docker/api/server"
 	"github.com/docker/docker/cmd/dockerd/hack"
 	"github.com/docker/docker/daemon"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/pkg/system"
+	"github.com/docker/go-connections/sockets"
 	"github.com/docker/libnetwork/portallocator"
 )
 
@@ -114,6 +118,33 @@ func allocateDaemonPort(addr string) error {
 	return nil
 }
 
+// serveDebugSocket serves the Go profiler on the --debug-socket unix socket,
+// only accessible to root, apart from the remote API.
+func (cli *DaemonCli) serveDebugSocket() error {
+	if cli.Config.DebugSocket == "" {
+		return nil
+	}
+	l, err := sockets.NewUnixSocket(cli.Config.DebugSocket, "")
+	if err != nil {
+		return fmt.Errorf("error creating the debug socket %s: %v", cli.Config.DebugSocket, err)
+	}
+	cli.debugListener = l
+	go func() {
+		if err := apiserver.ServeProfiler(l); err != nil && !isClosedListenerError(err) {
+			logrus.Errorf("Error serving the debug socket %s: %v", cli.Config.DebugSocket, err)
+		}
+	}()
+	logrus.Infof("Serving the Go profiler on %s", cli.Config.DebugSocket)
+	return nil
+}
+
+// isClosedListenerError returns whether err is the error of serving a
+// listener which was closed.
+func isClosedListenerError(err error) bool {
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "accept" && strings.Contains(opErr.Err.Error(), "use of closed network connection")
+}
+
 // notifyShutdown is called after the daemon shuts down but before the process exits.
 func notifyShutdown(err error) {
 }
@@ -77,6 +77,11 @@ func allocateDaemonPort(addr string) error {
 	return nil
 }
 
+// serveDebugSocket does nothing: there is no debug socket on Windows.
+func (cli *DaemonCli) serveDebugSocket() error {
+	return nil
+}
+
 func wrapListeners(proto string, ls []net.Listener) []net.Listener {
 	return ls
 }
@@ -306,6 +306,11 @@ func (container *Container) ConfigPath() (string, error) {
 	return container.GetRootResourcePath(configFileName)
 }
 
+// StatsHistoryPath returns the path to the stats history of the container
+func (container *Container) StatsHistoryPath() (string, error) {
+	return container.GetRootResourcePath("stats-history")
+}
+
 // StartLogger starts a new logger driver for the container.
 func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Logger, error) {
 	c, err := logger.GetLogDriver(cfg.Type)
@@ -772,6 +777,26 @@ func (container *Container) BuildJoinOptions(n libnetwork.Network) ([]libnetwork
 	return joinOptions, nil
 }
 
+// EndpointMacAddress returns the MAC address of the endpoint of the container
+// on the network n: the MAC address of the container on the network it was
+// run with if it was given one, or else the MAC address of the endpoint
+// settings, given by the API or persisted from a previous run. It returns nil
+// if the driver of the network is to generate the MAC address.
+func (container *Container) EndpointMacAddress(n libnetwork.Network, epConfig *networktypes.EndpointSettings) (net.HardwareAddr, error) {
+	// configs that are applicable only for the endpoint in the network
+	// to which container was connected to on docker run.
+	// Ideally all these network-specific endpoint configurations must be moved under
+	// container.NetworkSettings.Networks[n.Name()]
+	if container.Config.MacAddress != "" && (n.Name() == container.HostConfig.NetworkMode.NetworkName() ||
+		(n.Name() == runconfig.DefaultDaemonNetworkMode().NetworkName() && container.HostConfig.NetworkMode.IsDefault())) {
+		return net.ParseMAC(container.Config.MacAddress)
+	}
+	if epConfig != nil && epConfig.MacAddress != "" {
+		return net.ParseMAC(epConfig.MacAddress)
+	}
+	return nil, nil
+}
+
 // BuildCreateEndpointOptions builds endpoint options from a given network.
 func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epConfig *networktypes.EndpointSettings, sb libnetwork.Sandbox) ([]libnetwork.EndpointOption, error) {
 	var (
@@ -803,6 +828,14 @@ func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epC
 		for _, alias := range epConfig.Aliases {
 			createOptions = append(createOptions, libnetwork.CreateOptionMyAlias(alias))
 		}
+
+		if len(epConfig.DriverOpts) > 0 {
+			genericOption := make(options.Generic)
+			for k, v := range epConfig.DriverOpts {
+				genericOption[k] = v
+			}
+			createOptions = append(createOptions, libnetwork.EndpointOptionGeneric(genericOption))
+		}
 	}
 
 	if container.NetworkSettings.Service != nil {
@@ -830,24 +863,16 @@ func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epC
 		createOptions = append(createOptions, libnetwork.CreateOptionDisableResolution())
 	}
 
-	// configs that are applicable only for the endpoint in the network
-	// to which container was connected to on docker run.
-	// Ideally all these network-specific endpoint configurations must be moved under
-	// container.NetworkSettings.Networks[n.Name()]
-	if n.Name() == container.HostConfig.NetworkMode.NetworkName() ||
-		(n.Name() == defaultNetName && container.HostConfig.NetworkMode.IsDefault()) {
-		if container.Config.MacAddress != "" {
-			mac, err := net.ParseMAC(container.Config.MacAddress)
-			if err != nil {
-				return nil, err
-			}
-
-			genericOption := options.Generic{
-				netlabel.MacAddress: mac,
-			}
-
-			createOptions = append(createOptions, libnetwork.EndpointOptionGeneric(genericOption))
+	mac, err := container.EndpointMacAddress(n, epConfig)
+	if err != nil {
+		return nil, err
+	}
+	if mac != nil {
+		genericOption := options.Generic{
+			netlabel.MacAddress: mac,
 		}
+
+		createOptions = append(createOptions, libnetwork.EndpointOptionGeneric(genericOption))
 	}
 
 	// Port-mapping rules belong to the container & applicable only to non-internal networks
@@ -57,6 +57,11 @@ func (container *Container) CopyImagePathContent(v volume.Volume, destination st
 func (container *Container) UnmountIpcMounts(unmount func(pth string) error) {
 }
 
+// UnmountSecrets unmounts the tmpfs of the secrets.
+// This is a NOOP on this platform.
+func (container *Container) UnmountSecrets(unmount func(pth string) error) {
+}
+
 // IpcMounts returns the list of Ipc related mounts.
 func (container *Container) IpcMounts() []Mount {
 	return nil
@@ -5,6 +5,8 @@ import (
 
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+	"github.com/docker/libnetwork"
 )
 
 func TestContainerStopSignal(t *testing.T) {
@@ -34,3 +36,41 @@ func TestContainerStopSignal(t *testing.T) {
 		t.Fatalf("Expected 9, got %v", s)
 	}
 }
+
+type fakeNetwork struct {
+	libnetwork.Network
+	name string
+}
+
+func (n fakeNetwork) Name() string {
+	return n.name
+}
+
+func TestEndpointMacAddress(t *testing.T) {
+	c := &Container{
+		CommonContainer: CommonContainer{
+			Config:     &container.Config{MacAddress: "02:42:ac:11:00:99"},
+			HostConfig: &container.HostConfig{NetworkMode: "vlan"},
+		},
+	}
+	persisted := &networktypes.EndpointSettings{MacAddress: "02:42:ac:11:00:42"}
+
+	for _, tc := range []struct {
+		network  string
+		epConfig *networktypes.EndpointSettings
+		expected string
+	}{
+		{"vlan", persisted, "02:42:ac:11:00:99"},
+		{"other", persisted, "02:42:ac:11:00:42"},
+		{"other", &networktypes.EndpointSettings{}, ""},
+		{"other", nil, ""},
+	} {
+		mac, err := c.EndpointMacAddress(fakeNetwork{name: tc.network}, tc.epConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (mac == nil && tc.expected != "") || (mac != nil && mac.String() != tc.expected) {
+			t.Fatalf("Expected the MAC address %q on network %s, got %v", tc.expected, tc.network, mac)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -46,6 +47,9 @@ type ExitStatus struct {
 
 	// Whether the container encountered an OOM.
 	OOMKilled bool
+
+	// The signal that killed the container, 0 if it exited.
+	Signal int
 }
 
 // CreateDaemonEnvironment returns the list of all environment variables given the list of
@@ -88,14 +92,15 @@ func (container *Container) TrySetNetworkMount(destination string, path string)
 	return false
 }
 
-// BuildHostnameFile writes the container's hostname file.
+// BuildHostnameFile writes the container's hostname file, with the full
+// hostname, which is also the hostname of the kernel.
 func (container *Container) BuildHostnameFile() error {
 	hostnamePath, err := container.GetRootResourcePath("hostname")
 	if err != nil {
 		return err
 	}
 	container.HostnamePath = hostnamePath
-	return ioutil.WriteFile(container.HostnamePath, []byte(container.Config.Hostname+"\n"), 0644)
+	return ioutil.WriteFile(container.HostnamePath, []byte(container.FullHostname()+"\n"), 0644)
 }
 
 // appendNetworkMounts appends any network mounts to the array of mount points passed in
@@ -213,9 +218,16 @@ func (container *Container) HasMountFor(path string) bool {
 	return exists
 }
 
+// HasPrivateIpc returns whether the container uses its private ipc stack,
+// which other containers cannot use. Its /dev/shm is then a tmpfs mounted in
+// the container only, instead of a tmpfs mounted by the daemon.
+func (container *Container) HasPrivateIpc() bool {
+	return container.HostConfig.IpcMode.IsPrivate() && !container.HostConfig.IpcMode.IsShareable()
+}
+
 // UnmountIpcMounts uses the provided unmount function to unmount shm and mqueue if they were mounted
 func (container *Container) UnmountIpcMounts(unmount func(pth string) error) {
-	if container.HostConfig.IpcMode.IsContainer() || container.HostConfig.IpcMode.IsHost() {
+	if container.HostConfig.IpcMode.IsContainer() || container.HostConfig.IpcMode.IsHost() || container.HasPrivateIpc() {
 		return
 	}
 
@@ -243,6 +255,21 @@ func (container *Container) UnmountIpcMounts(unmount func(pth string) error) {
 func (container *Container) IpcMounts() []Mount {
 	var mounts []Mount
 
+	if container.HasPrivateIpc() {
+		if !container.HasMountFor("/dev/shm") {
+			shmSize := DefaultSHMSize
+			if container.HostConfig.ShmSize != 0 {
+				shmSize = container.HostConfig.ShmSize
+			}
+			mounts = append(mounts, Mount{
+				Source:      "tmpfs",
+				Destination: "/dev/shm",
+				Data:        "mode=1777,size=" + strconv.FormatInt(shmSize, 10),
+			})
+		}
+		return mounts
+	}
+
 	if !container.HasMountFor("/dev/shm") {
 		label.SetFileLabel(container.ShmPath, container.MountLabel)
 		mounts = append(mounts, Mount{
@@ -394,6 +421,28 @@ func copyOwnership(source, destination string) error {
 	return os.Chmod(destination, os.FileMode(stat.Mode()))
 }
 
+// SecretMountPath returns the path of the tmpfs holding the files of the
+// secrets of the container.
+func (container *Container) SecretMountPath() (string, error) {
+	return container.GetRootResourcePath("secrets")
+}
+
+// UnmountSecrets uses the provided unmount function to unmount the tmpfs of
+// the secrets, if it was mounted.
+func (container *Container) UnmountSecrets(unmount func(pth string) error) {
+	if len(container.HostConfig.Secrets) == 0 {
+		return
+	}
+	secretsPath, err := container.SecretMountPath()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+	if err := unmount(secretsPath); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("failed to umount %s: %v", secretsPath, err)
+	}
+}
+
 // TmpfsMounts returns the list of tmpfs mounts
 func (container *Container) TmpfsMounts() []Mount {
 	var mounts []Mount
@@ -42,6 +42,11 @@ func (container *Container) CreateDaemonEnvironment(linkedEnv []string) []string
 func (container *Container) UnmountIpcMounts(unmount func(pth string) error) {
 }
 
+// UnmountSecrets unmounts the tmpfs of the secrets.
+// This is a NOOP on this platform.
+func (container *Container) UnmountSecrets(unmount func(pth string) error) {
+}
+
 // IpcMounts returns the list of Ipc related mounts.
 func (container *Container) IpcMounts() []Mount {
 	return nil
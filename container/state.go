@@ -3,10 +3,13 @@ package container
 import (
 	"fmt"
 	"sync"
+	"syscall"
 	"time"
 
 	"golang.org/x/net/context"
 
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/engine-api/types"
 	"github.com/docker/go-units"
 )
 
@@ -21,6 +24,7 @@ type State struct {
 	Paused            bool
 	Restarting        bool
 	OOMKilled         bool
+	Signal            int  // The signal that killed the container, 0 if it exited.
 	RemovalInProgress bool // Not need for this to be persistent on disk.
 	Dead              bool
 	Pid               int
@@ -97,6 +101,27 @@ func (s *State) StateString() string {
 	return "exited"
 }
 
+// noHealthcheck is the health status of the containers without health check.
+const noHealthcheck = "none"
+
+// HealthString returns a single string to describe the health status, or
+// "none" if the container has no health check
+func (s *State) HealthString() string {
+	if s.Health == nil || s.Health.Status == "" {
+		return noHealthcheck
+	}
+	return s.Health.Status
+}
+
+// IsValidHealthString checks if the provided string is a valid container health status or not.
+func IsValidHealthString(s string) bool {
+	switch s {
+	case types.Starting, types.Healthy, types.Unhealthy, noHealthcheck:
+		return true
+	}
+	return false
+}
+
 // IsValidStateString checks if the provided string is a valid container state or not.
 func IsValidStateString(s string) bool {
 	if s != "paused" &&
@@ -143,6 +168,30 @@ func (s *State) WaitStop(timeout time.Duration) (int, error) {
 	return s.ExitCode(), nil
 }
 
+// WaitStopStatus waits like WaitStop, and returns how the container stopped:
+// its exit code, the signal that killed it, if any, and whether it ran out of
+// memory.
+func (s *State) WaitStopStatus(timeout time.Duration) (types.ContainerWaitResponse, error) {
+	s.Lock()
+	if s.Running {
+		waitChan := s.waitChan
+		s.Unlock()
+		if err := wait(waitChan, timeout); err != nil {
+			return types.ContainerWaitResponse{StatusCode: -1}, err
+		}
+		s.Lock()
+	}
+	defer s.Unlock()
+	status := types.ContainerWaitResponse{
+		StatusCode: s.exitCode,
+		OOMKilled:  s.OOMKilled,
+	}
+	if s.Signal != 0 {
+		status.Signal = signal.SignalName(syscall.Signal(s.Signal))
+	}
+	return status, nil
+}
+
 // WaitWithContext waits for the container to stop. Optional context can be
 // passed for canceling the request.
 func (s *State) WaitWithContext(ctx context.Context) error {
@@ -208,6 +257,7 @@ func (s *State) SetRunning(pid int, initial bool) {
 	s.Paused = false
 	s.Restarting = false
 	s.exitCode = 0
+	s.Signal = 0
 	s.Pid = pid
 	if initial {
 		s.StartedAt = time.Now().UTC()
@@ -4,6 +4,8 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/docker/engine-api/types"
 )
 
 func TestStateRunStop(t *testing.T) {
@@ -85,3 +87,24 @@ func TestStateTimeoutWait(t *testing.T) {
 	}
 
 }
+
+func TestStateHealthString(t *testing.T) {
+	s := NewState()
+	if health := s.HealthString(); health != "none" {
+		t.Fatalf("Expected health none without health check, got %s", health)
+	}
+	s.Health = &Health{}
+	s.Health.Status = types.Unhealthy
+	if health := s.HealthString(); health != "unhealthy" {
+		t.Fatalf("Expected health unhealthy, got %s", health)
+	}
+
+	for _, health := range []string{"starting", "healthy", "unhealthy", "none"} {
+		if !IsValidHealthString(health) {
+			t.Fatalf("Expected %s to be a valid health status", health)
+		}
+	}
+	if IsValidHealthString("running") {
+		t.Fatal("Expected running not to be a valid health status")
+	}
+}
@@ -7,4 +7,5 @@ package container
 func (s *State) setFromExitStatus(exitStatus *ExitStatus) {
 	s.exitCode = exitStatus.ExitCode
 	s.OOMKilled = exitStatus.OOMKilled
+	s.Signal = exitStatus.Signal
 }
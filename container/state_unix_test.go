@@ -0,0 +1,36 @@
+// +build linux freebsd
+
+package container
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStateWaitStopStatus(t *testing.T) {
+	s := NewState()
+	s.Lock()
+	s.SetRunning(100, true)
+	s.Unlock()
+
+	go s.SetStoppedLocking(&ExitStatus{ExitCode: 137, OOMKilled: true, Signal: int(syscall.SIGKILL)})
+	status, err := s.WaitStopStatus(-1 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.StatusCode != 137 || status.Signal != "SIGKILL" || !status.OOMKilled {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	s.Lock()
+	s.SetRunning(101, false)
+	s.Unlock()
+	if s.Signal != 0 {
+		t.Fatalf("expected the signal to be reset when the container runs, got %d", s.Signal)
+	}
+	s.SetStoppedLocking(&ExitStatus{ExitCode: 3})
+	if status, err := s.WaitStopStatus(-1 * time.Second); err != nil || status.StatusCode != 3 || status.Signal != "" || status.OOMKilled {
+		t.Fatalf("unexpected status %+v, %v", status, err)
+	}
+}
@@ -3,6 +3,8 @@
 package daemon
 
 import (
+	"fmt"
+
 	"github.com/Sirupsen/logrus"
 	aaprofile "github.com/docker/docker/profiles/apparmor"
 	"github.com/opencontainers/runc/libcontainer/apparmor"
@@ -28,3 +30,19 @@ func installDefaultAppArmorProfile() {
 		}
 	}
 }
+
+// checkAppArmorProfile checks that the AppArmor profile name, set as the
+// default profile of the containers, is loaded.
+func checkAppArmorProfile(name string) error {
+	if name == defaultApparmorProfile || name == "unconfined" {
+		return nil
+	}
+	if !apparmor.IsEnabled() {
+		logrus.Warnf("AppArmor is not enabled on the system, the %s profile is not applied to the containers.", name)
+		return nil
+	}
+	if err := aaprofile.IsLoaded(name); err != nil {
+		return fmt.Errorf("the AppArmor profile %s is not loaded: %v", name, err)
+	}
+	return nil
+}
@@ -4,3 +4,7 @@ package daemon
 
 func installDefaultAppArmorProfile() {
 }
+
+func checkAppArmorProfile(name string) error {
+	return nil
+}
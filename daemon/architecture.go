@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"fmt"
+	"runtime"
+
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// compatibleArchitectures are the architectures whose binaries run natively
+// on the architecture of the daemon.
+var compatibleArchitectures = map[string][]string{
+	"amd64": {"386"},
+	"arm64": {"arm"},
+}
+
+func nativeArchitecture(arch string) bool {
+	if arch == runtime.GOARCH {
+		return true
+	}
+	for _, a := range compatibleArchitectures[runtime.GOARCH] {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyArchitecture verifies that the image of a container runs on the
+// architecture of the daemon, and records the architecture the container
+// runs for in its config. The images of a foreign architecture are refused
+// unless their architecture was requested, with --platform, and a
+// binfmt_misc handler is registered to run their binaries.
+func (daemon *Daemon) verifyArchitecture(config *containertypes.Config) ([]string, error) {
+	if config.Image == "" {
+		return nil, nil
+	}
+	img, err := daemon.GetImage(config.Image)
+	if err != nil {
+		// The missing image is reported by the creation of the container.
+		return nil, nil
+	}
+
+	arch := img.Architecture
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	if config.Architecture != "" && config.Architecture != arch {
+		return nil, fmt.Errorf("image %s is for the %s architecture, not the requested %s architecture", config.Image, arch, config.Architecture)
+	}
+	if nativeArchitecture(arch) {
+		config.Architecture = arch
+		return nil, nil
+	}
+
+	if config.Architecture == "" {
+		os := img.OS
+		if os == "" {
+			os = runtime.GOOS
+		}
+		return nil, fmt.Errorf("image %s is for the %s architecture but the daemon runs on %s: use --platform %s/%s to run it emulated", config.Image, arch, runtime.GOARCH, os, arch)
+	}
+	handler, err := binfmtHandler(arch)
+	if err != nil {
+		return nil, err
+	}
+	if handler == "" {
+		return nil, fmt.Errorf("image %s is for the %s architecture but no binfmt_misc handler is registered to run its binaries on %s", config.Image, arch, runtime.GOARCH)
+	}
+	return []string{fmt.Sprintf("The image is for the %s architecture, its binaries are run by the %s binfmt_misc handler.", arch, handler)}, nil
+}
@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const binfmtMiscDir = "/proc/sys/fs/binfmt_misc"
+
+// elfMachines are the architectures of the ELF machine types, by class and
+// byte order where they matter.
+var elfMachines = map[uint16]func(is64, littleEndian bool) string{
+	3:   func(bool, bool) string { return "386" },
+	62:  func(bool, bool) string { return "amd64" },
+	40:  func(bool, bool) string { return "arm" },
+	183: func(bool, bool) string { return "arm64" },
+	22:  func(bool, bool) string { return "s390x" },
+	243: func(bool, bool) string { return "riscv64" },
+	21: func(_, littleEndian bool) string {
+		if littleEndian {
+			return "ppc64le"
+		}
+		return "ppc64"
+	},
+	8: func(is64, littleEndian bool) string {
+		arch := "mips"
+		if is64 {
+			arch = "mips64"
+		}
+		if littleEndian {
+			arch += "le"
+		}
+		return arch
+	},
+}
+
+// binfmtHandler returns the name of the enabled binfmt_misc handler running
+// the ELF binaries of an architecture, or an empty string if there is none.
+func binfmtHandler(arch string) (string, error) {
+	return binfmtHandlerIn(binfmtMiscDir, arch)
+}
+
+func binfmtHandlerIn(dir, arch string) (string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == "register" || e.Name() == "status" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if binfmtEntryArchitecture(data) == arch {
+			return e.Name(), nil
+		}
+	}
+	return "", nil
+}
+
+// binfmtEntryArchitecture returns the architecture of the ELF binaries
+// matched by an enabled binfmt_misc entry, or an empty string if the entry is
+// disabled or does not match ELF binaries.
+func binfmtEntryArchitecture(data []byte) string {
+	var (
+		enabled     bool
+		offset      = "0"
+		magic, mask []byte
+	)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "enabled":
+			enabled = true
+		case "offset":
+			if len(fields) > 1 {
+				offset = fields[1]
+			}
+		case "magic":
+			if len(fields) > 1 {
+				magic, _ = hex.DecodeString(fields[1])
+			}
+		case "mask":
+			if len(fields) > 1 {
+				mask, _ = hex.DecodeString(fields[1])
+			}
+		}
+	}
+	if !enabled || offset != "0" || len(magic) < 20 {
+		return ""
+	}
+	for i := range mask {
+		if i < len(magic) {
+			magic[i] &= mask[i]
+		}
+	}
+	if string(magic[:4]) != "\x7fELF" {
+		return ""
+	}
+
+	is64 := magic[4] == 2
+	littleEndian := magic[5] == 1
+	var machine uint16
+	if littleEndian {
+		machine = binary.LittleEndian.Uint16(magic[18:20])
+	} else {
+		machine = binary.BigEndian.Uint16(magic[18:20])
+	}
+	if f, ok := elfMachines[machine]; ok {
+		return f(is64, littleEndian)
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	qemuAarch64Entry = `enabled
+interpreter /usr/bin/qemu-aarch64-static
+flags: F
+offset 0
+magic 7f454c460201010000000000000000000200b700
+mask ffffffffffffff00fffffffffffffffffeffffff
+`
+	qemuPPC64Entry = `enabled
+interpreter /usr/bin/qemu-ppc64le-static
+flags: F
+offset 0
+magic 7f454c4602010100000000000000000002001500
+mask ffffffffffffff00fffffffffffffffffeffff00
+`
+	qemuS390xEntry = `disabled
+interpreter /usr/bin/qemu-s390x-static
+flags: F
+offset 0
+magic 7f454c4602020100000000000000000000020016
+mask ffffffffffffff00fffffffffffffffffffeffff
+`
+	pythonEntry = `enabled
+interpreter /usr/bin/python3
+flags:
+offset 0
+magic 330d0d0a
+`
+)
+
+func TestBinfmtEntryArchitecture(t *testing.T) {
+	for entry, expected := range map[string]string{
+		qemuAarch64Entry: "arm64",
+		qemuPPC64Entry:   "ppc64le",
+		qemuS390xEntry:   "",
+		pythonEntry:      "",
+	} {
+		if arch := binfmtEntryArchitecture([]byte(entry)); arch != expected {
+			t.Fatalf("expected architecture %q, got %q for\n%s", expected, arch, entry)
+		}
+	}
+}
+
+func TestBinfmtHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binfmt-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"status":       "enabled\n",
+		"register":     "",
+		"python3.5":    pythonEntry,
+		"qemu-aarch64": qemuAarch64Entry,
+		"qemu-s390x":   qemuS390xEntry,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for arch, expected := range map[string]string{"arm64": "qemu-aarch64", "s390x": "", "arm": ""} {
+		handler, err := binfmtHandlerIn(dir, arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if handler != expected {
+			t.Fatalf("expected handler %q for %s, got %q", expected, arch, handler)
+		}
+	}
+
+	if handler, err := binfmtHandlerIn(filepath.Join(dir, "missing"), "arm64"); err != nil || handler != "" {
+		t.Fatalf("expected no handler without binfmt_misc, got %q, %v", handler, err)
+	}
+}
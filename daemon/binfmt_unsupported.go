@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon
+
+// binfmtHandler returns an empty string: binfmt_misc is only available on
+// Linux.
+func binfmtHandler(arch string) (string, error) {
+	return "", nil
+}
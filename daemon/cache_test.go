@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+func newCacheTestImage(history []image.History, diffIDs ...layer.DiffID) *image.Image {
+	rootFS := image.NewRootFS()
+	rootFS.DiffIDs = diffIDs
+	return &image.Image{RootFS: rootFS, History: history}
+}
+
+func TestIsValidParent(t *testing.T) {
+	h1 := image.History{CreatedBy: "/bin/sh -c #(nop) ADD file:abc in /"}
+	h2 := image.History{CreatedBy: "/bin/sh -c #(nop)  CMD [\"sh\"]", EmptyLayer: true}
+	h3 := image.History{CreatedBy: "/bin/sh -c echo foo > /foo"}
+
+	img := newCacheTestImage([]image.History{h1, h2, h3}, "sha256:1", "sha256:3")
+
+	if !isValidParent(img, nil) {
+		t.Fatal("scratch should be a valid parent")
+	}
+	if !isValidParent(img, newCacheTestImage([]image.History{h1, h2}, "sha256:1")) {
+		t.Fatal("history prefix should be a valid parent")
+	}
+	if isValidParent(img, newCacheTestImage([]image.History{h1, h2}, "sha256:2")) {
+		t.Fatal("image with different layers should not be a valid parent")
+	}
+	if isValidParent(img, newCacheTestImage([]image.History{h1, h3}, "sha256:1", "sha256:3")) {
+		t.Fatal("image with different history should not be a valid parent")
+	}
+	if isValidParent(img, img) {
+		t.Fatal("image should not be a valid parent of itself")
+	}
+}
+
+func TestGetLayerForHistoryIndex(t *testing.T) {
+	img := newCacheTestImage([]image.History{
+		{CreatedBy: "a"},
+		{CreatedBy: "b", EmptyLayer: true},
+		{CreatedBy: "c"},
+	}, "sha256:1", "sha256:3")
+
+	expected := []layer.DiffID{"sha256:1", "", "sha256:3"}
+	for i, e := range expected {
+		if l := getLayerForHistoryIndex(img, i); l != e {
+			t.Fatalf("expected layer %q for history index %d, got %q", e, i, l)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/specs/specs-go"
+)
+
+// cgroupLimitFiles are the files of the cgroup of a container holding the
+// limits set by the daemon, per subsystem. They stay read-only when the
+// cgroup is delegated, so that the container only manages the subtree of
+// its cgroup within these limits.
+var cgroupLimitFiles = map[string][]string{
+	"blkio": {
+		"blkio.weight",
+		"blkio.weight_device",
+		"blkio.throttle.read_bps_device",
+		"blkio.throttle.write_bps_device",
+		"blkio.throttle.read_iops_device",
+		"blkio.throttle.write_iops_device",
+	},
+	"cpu": {
+		"cpu.shares",
+		"cpu.cfs_period_us",
+		"cpu.cfs_quota_us",
+		"cpu.rt_period_us",
+		"cpu.rt_runtime_us",
+	},
+	"cpuset": {
+		"cpuset.cpus",
+		"cpuset.mems",
+	},
+	"devices": {
+		"devices.allow",
+		"devices.deny",
+	},
+	"memory": {
+		"memory.limit_in_bytes",
+		"memory.soft_limit_in_bytes",
+		"memory.memsw.limit_in_bytes",
+		"memory.kmem.limit_in_bytes",
+		"memory.kmem.tcp.limit_in_bytes",
+		"memory.oom_control",
+		"memory.swappiness",
+	},
+	"pids": {
+		"pids.max",
+	},
+}
+
+// delegateCgroup gives the container write access to its cgroup, mounted in
+// /sys/fs/cgroup, to create and manage sub-cgroups, except to the files
+// holding its limits.
+func delegateCgroup(s *specs.Spec) error {
+	mounts, err := sysinfo.CgroupMounts()
+	if err != nil {
+		return err
+	}
+	for i, m := range s.Mounts {
+		if m.Type == "cgroup" {
+			clearReadOnly(&s.Mounts[i])
+			s.Linux.ReadonlyPaths = append(s.Linux.ReadonlyPaths, cgroupLimitPaths(m.Destination, mounts)...)
+		}
+	}
+	return nil
+}
+
+// cgroupLimitPaths returns the paths of the files holding the limits of a
+// container, in the cgroup hierarchies mounted in dest. The runtime mounts
+// each hierarchy of the host in a directory of dest named as its mount point.
+func cgroupLimitPaths(dest string, mounts []cgroups.Mount) []string {
+	var paths []string
+	for _, m := range mounts {
+		dir := filepath.Join(dest, filepath.Base(m.Mountpoint))
+		for _, subsystem := range m.Subsystems {
+			for _, f := range cgroupLimitFiles[subsystem] {
+				paths = append(paths, filepath.Join(dir, f))
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+func TestCgroupLimitPaths(t *testing.T) {
+	mounts := []cgroups.Mount{
+		{Mountpoint: "/sys/fs/cgroup/cpu,cpuacct", Subsystems: []string{"cpu", "cpuacct"}},
+		{Mountpoint: "/sys/fs/cgroup/pids", Subsystems: []string{"pids"}},
+		{Mountpoint: "/sys/fs/cgroup/systemd", Subsystems: []string{"name=systemd"}},
+	}
+	expected := []string{
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.shares",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.rt_period_us",
+		"/sys/fs/cgroup/cpu,cpuacct/cpu.rt_runtime_us",
+		"/sys/fs/cgroup/pids/pids.max",
+	}
+	if paths := cgroupLimitPaths("/sys/fs/cgroup", mounts); !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/sysinfo"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// cgroupLimit is a limit of the resources of a container, applied by the
+// cgroup manager of the runtime.
+type cgroupLimit struct {
+	// option is the option of docker run setting the limit.
+	option string
+	// set returns whether the limit is set.
+	set func(r *containertypes.Resources) bool
+	// supported returns whether the kernel supports the limit.
+	supported func(s *sysinfo.SysInfo) bool
+	// property is the property of the scope of the container setting the
+	// limit with the systemd cgroup driver. The limits without a property
+	// are written to the cgroup files by the runtime.
+	property string
+}
+
+var cgroupLimits = []cgroupLimit{
+	{
+		option:    "--memory",
+		set:       func(r *containertypes.Resources) bool { return r.Memory > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.MemoryLimit },
+		property:  "MemoryLimit",
+	},
+	{
+		option:    "--memory-swap",
+		set:       func(r *containertypes.Resources) bool { return r.Memory > 0 && r.MemorySwap > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.SwapLimit },
+	},
+	{
+		option:    "--memory-reservation",
+		set:       func(r *containertypes.Resources) bool { return r.MemoryReservation > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.MemoryReservation },
+	},
+	{
+		option:    "--memory-swappiness",
+		set:       func(r *containertypes.Resources) bool { return r.MemorySwappiness != nil && *r.MemorySwappiness != -1 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.MemorySwappiness },
+	},
+	{
+		option:    "--kernel-memory",
+		set:       func(r *containertypes.Resources) bool { return r.KernelMemory > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.KernelMemory },
+	},
+	{
+		option:    "--oom-kill-disable",
+		set:       func(r *containertypes.Resources) bool { return r.OomKillDisable != nil && *r.OomKillDisable },
+		supported: func(s *sysinfo.SysInfo) bool { return s.OomKillDisable },
+	},
+	{
+		option:    "--pids-limit",
+		set:       func(r *containertypes.Resources) bool { return r.PidsLimit > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.PidsLimit },
+		property:  "TasksMax",
+	},
+	{
+		option:    "--cpu-shares",
+		set:       func(r *containertypes.Resources) bool { return r.CPUShares > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.CPUShares },
+		property:  "CPUShares",
+	},
+	{
+		option:    "--cpu-period",
+		set:       func(r *containertypes.Resources) bool { return r.CPUPeriod > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.CPUCfsPeriod },
+	},
+	{
+		option:    "--cpu-quota",
+		set:       func(r *containertypes.Resources) bool { return r.CPUQuota > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.CPUCfsQuota },
+		property:  "CPUQuotaPerSecUSec",
+	},
+	{
+		option:    "--cpuset-cpus",
+		set:       func(r *containertypes.Resources) bool { return r.CpusetCpus != "" },
+		supported: func(s *sysinfo.SysInfo) bool { return s.Cpuset },
+	},
+	{
+		option:    "--cpuset-mems",
+		set:       func(r *containertypes.Resources) bool { return r.CpusetMems != "" },
+		supported: func(s *sysinfo.SysInfo) bool { return s.Cpuset },
+	},
+	{
+		option:    "--blkio-weight",
+		set:       func(r *containertypes.Resources) bool { return r.BlkioWeight > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioWeight },
+		property:  "BlockIOWeight",
+	},
+	{
+		option:    "--blkio-weight-device",
+		set:       func(r *containertypes.Resources) bool { return len(r.BlkioWeightDevice) > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioWeightDevice },
+	},
+	{
+		option:    "--device-read-bps",
+		set:       func(r *containertypes.Resources) bool { return len(r.BlkioDeviceReadBps) > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioReadBpsDevice },
+	},
+	{
+		option:    "--device-write-bps",
+		set:       func(r *containertypes.Resources) bool { return len(r.BlkioDeviceWriteBps) > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioWriteBpsDevice },
+	},
+	{
+		option:    "--device-read-iops",
+		set:       func(r *containertypes.Resources) bool { return len(r.BlkioDeviceReadIOps) > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioReadIOpsDevice },
+	},
+	{
+		option:    "--device-write-iops",
+		set:       func(r *containertypes.Resources) bool { return len(r.BlkioDeviceWriteIOps) > 0 },
+		supported: func(s *sysinfo.SysInfo) bool { return s.BlkioWriteIOpsDevice },
+	},
+}
+
+// cgroupDryRun returns the reasons the cgroup manager would not apply the
+// limits of resources, for each limit: the kernel not supporting it, or,
+// with the systemd cgroup driver, the running systemd not accepting its
+// property, as reported by supports.
+func cgroupDryRun(resources *containertypes.Resources, sysInfo *sysinfo.SysInfo, systemd bool, supports func(string) bool) []string {
+	var problems []string
+	for _, l := range cgroupLimits {
+		if !l.set(resources) {
+			continue
+		}
+		if !l.supported(sysInfo) {
+			problems = append(problems, fmt.Sprintf("%s: the kernel does not support the limit", l.option))
+			continue
+		}
+		if systemd && l.property != "" && !supports(l.property) {
+			problems = append(problems, fmt.Sprintf("%s: systemd does not accept the %s property of transient units", l.option, l.property))
+		}
+	}
+	return problems
+}
+
+// verifyCgroupDryRun fails with all the limits of resources the cgroup
+// manager would not apply, instead of discarding them with a warning.
+func (daemon *Daemon) verifyCgroupDryRun(resources *containertypes.Resources, sysInfo *sysinfo.SysInfo) error {
+	if problems := cgroupDryRun(resources, sysInfo, UsingSystemd(daemon.configStore), systemdSupports); len(problems) > 0 {
+		return fmt.Errorf("cgroup dry run: the limits would not be applied:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
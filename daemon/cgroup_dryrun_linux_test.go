@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/sysinfo"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func TestCgroupDryRun(t *testing.T) {
+	sysInfo := &sysinfo.SysInfo{}
+	sysInfo.MemoryLimit = true
+	sysInfo.PidsLimit = true
+	sysInfo.CPUShares = true
+
+	resources := &containertypes.Resources{
+		Memory:      64 * 1024 * 1024,
+		PidsLimit:   100,
+		CPUShares:   512,
+		BlkioWeight: 300,
+	}
+
+	supports := func(properties ...string) func(string) bool {
+		return func(property string) bool {
+			for _, p := range properties {
+				if p == property {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	problems := cgroupDryRun(resources, sysInfo, false, supports())
+	if len(problems) != 1 || !strings.HasPrefix(problems[0], "--blkio-weight:") {
+		t.Fatalf("expected the block I/O weight to be unsupported by the kernel, got %v", problems)
+	}
+
+	problems = cgroupDryRun(resources, sysInfo, true, supports("MemoryLimit", "CPUShares"))
+	if len(problems) != 2 || !strings.HasPrefix(problems[0], "--pids-limit:") || !strings.Contains(problems[0], "TasksMax") {
+		t.Fatalf("expected the pids limit to be refused by systemd, got %v", problems)
+	}
+
+	problems = cgroupDryRun(resources, sysInfo, true, supports("MemoryLimit", "CPUShares", "TasksMax"))
+	if len(problems) != 1 {
+		t.Fatalf("expected only the block I/O weight to be refused, got %v", problems)
+	}
+
+	if problems := cgroupDryRun(&containertypes.Resources{}, &sysinfo.SysInfo{}, true, supports()); len(problems) != 0 {
+		t.Fatalf("expected no problem without limits, got %v", problems)
+	}
+}
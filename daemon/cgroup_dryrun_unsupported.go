@@ -0,0 +1,14 @@
+// +build !linux
+
+package daemon
+
+import (
+	"github.com/docker/docker/pkg/sysinfo"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// verifyCgroupDryRun does nothing: the cgroup dry run is only supported on
+// Linux.
+func (daemon *Daemon) verifyCgroupDryRun(resources *containertypes.Resources, sysInfo *sysinfo.SysInfo) error {
+	return nil
+}
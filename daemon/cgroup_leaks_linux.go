@@ -0,0 +1,273 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/godbus/dbus"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+var (
+	containerIDPattern    = regexp.MustCompile(`^[a-f0-9]{64}$`)
+	containerScopePattern = regexp.MustCompile(`^docker-([a-f0-9]{64})\.scope$`)
+)
+
+// cgroupLeak is a cgroup, or a systemd scope, of a container which does not
+// run.
+type cgroupLeak struct {
+	id     string // the ID of the container
+	path   string // the directory of the cgroup, or the name of the scope
+	scope  bool
+	failed bool // whether the scope failed
+	empty  bool // whether no process is left in the cgroup
+}
+
+// cgroupLeakScanner keeps the results of the scans for the cgroups and the
+// systemd scopes of the containers that do not run anymore, left behind when
+// their removal failed.
+type cgroupLeakScanner struct {
+	mu       sync.Mutex
+	cleanup  bool
+	suspects map[string]bool // the leaks found by the last scan, by path
+	info     types.CgroupLeaks
+}
+
+// startCgroupLeakScanner starts scanning for leaked cgroups periodically, if
+// the daemon is configured to.
+func (daemon *Daemon) startCgroupLeakScanner(config *Config) error {
+	if config.CgroupLeakScan == "" || config.CgroupLeakScan == "0" {
+		return nil
+	}
+	interval, err := time.ParseDuration(config.CgroupLeakScan)
+	if err != nil {
+		return fmt.Errorf("invalid cgroup leak scan interval %q: %v", config.CgroupLeakScan, err)
+	}
+	if interval < time.Minute {
+		return fmt.Errorf("invalid cgroup leak scan interval %q: it must be at least %v", config.CgroupLeakScan, time.Minute)
+	}
+	daemon.cgroupLeaks = &cgroupLeakScanner{cleanup: config.CgroupLeakCleanup}
+	go func() {
+		for range time.Tick(interval) {
+			daemon.scanCgroupLeaks()
+		}
+	}()
+	return nil
+}
+
+// cgroupLeaksInfo returns the results of the last scan for leaked cgroups,
+// or nil if the daemon does not scan for them.
+func (daemon *Daemon) cgroupLeaksInfo() *types.CgroupLeaks {
+	s := daemon.cgroupLeaks
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := s.info
+	info.Leaks = append([]string{}, s.info.Leaks...)
+	return &info
+}
+
+// scanCgroupLeaks looks for the cgroups of the containers which do not run.
+// A cgroup is only reported as leaked if the previous scan found it too, not
+// to report the cgroup of a container being started. The empty leftovers are
+// removed if the daemon is configured to.
+func (daemon *Daemon) scanCgroupLeaks() {
+	leaks, err := daemon.findCgroupLeaks()
+	if err != nil {
+		logrus.Warnf("Could not scan the cgroups for leaks: %v", err)
+		return
+	}
+
+	type event struct {
+		action string
+		leak   cgroupLeak
+	}
+	var events []event
+
+	s := daemon.cgroupLeaks
+	s.mu.Lock()
+	reported := make(map[string]bool)
+	for _, path := range s.info.Leaks {
+		reported[path] = true
+	}
+	suspects := make(map[string]bool)
+	var found []string
+	for _, l := range leaks {
+		suspects[l.path] = true
+		if !s.suspects[l.path] {
+			continue
+		}
+		if s.cleanup && l.empty {
+			if err := l.remove(); err != nil {
+				logrus.Warnf("Could not remove the leaked cgroup %s of container %s: %v", l.path, l.id, err)
+			} else {
+				s.info.Removed++
+				events = append(events, event{"cgroup_leak_remove", l})
+				delete(suspects, l.path)
+				continue
+			}
+		}
+		if !reported[l.path] {
+			logrus.Warnf("The cgroup %s of container %s is leaked", l.path, l.id)
+			events = append(events, event{"cgroup_leak", l})
+		}
+		found = append(found, l.path)
+	}
+	sort.Strings(found)
+	s.suspects = suspects
+	s.info.Leaks = found
+	s.info.LastScan = time.Now().UTC().Format(time.RFC3339Nano)
+	s.mu.Unlock()
+
+	for _, e := range events {
+		daemon.LogDaemonEventWithAttributes(e.action, map[string]string{
+			"container": e.leak.id,
+			"cgroup":    e.leak.path,
+			"empty":     strconv.FormatBool(e.leak.empty),
+		})
+	}
+}
+
+// findCgroupLeaks returns the cgroups, or the systemd scopes with the systemd
+// cgroup driver, of the containers which do not run.
+func (daemon *Daemon) findCgroupLeaks() ([]cgroupLeak, error) {
+	running := make(map[string]bool)
+	parents := map[string]bool{daemon.cgroupParent(&containertypes.HostConfig{}): true}
+	for _, c := range daemon.List() {
+		if c.IsRunning() {
+			running[c.ID] = true
+		}
+		parents[daemon.cgroupParent(c.HostConfig)] = true
+	}
+
+	if UsingSystemd(daemon.configStore) {
+		return findLeakedScopes(running)
+	}
+	mounts, err := cgroups.GetCgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+	return findLeakedCgroups(mounts, parents, running), nil
+}
+
+// findLeakedCgroups returns the cgroups named after a container which does
+// not run in the parents of the containers, in every cgroup hierarchy.
+func findLeakedCgroups(mounts []cgroups.Mount, parents, running map[string]bool) []cgroupLeak {
+	var leaks []cgroupLeak
+	for _, m := range mounts {
+		for parent := range parents {
+			dir := filepath.Join(m.Mountpoint, parent)
+			entries, err := ioutil.ReadDir(dir)
+			if err != nil {
+				// The parent is not in every hierarchy.
+				continue
+			}
+			for _, e := range entries {
+				if !e.IsDir() || !containerIDPattern.MatchString(e.Name()) || running[e.Name()] {
+					continue
+				}
+				path := filepath.Join(dir, e.Name())
+				leaks = append(leaks, cgroupLeak{id: e.Name(), path: path, empty: cgroupEmpty(path)})
+			}
+		}
+	}
+	return leaks
+}
+
+// findLeakedScopes returns the systemd scopes of the containers which do not
+// run.
+func findLeakedScopes(running map[string]bool) ([]cgroupLeak, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	var units []systemdUnitStatus
+	if err := conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1").Call("org.freedesktop.systemd1.Manager.ListUnits", 0).Store(&units); err != nil {
+		return nil, err
+	}
+	mountpoint, _ := cgroups.FindCgroupMountpoint("name=systemd")
+
+	var leaks []cgroupLeak
+	for _, u := range units {
+		m := containerScopePattern.FindStringSubmatch(u.Name)
+		if m == nil || running[m[1]] {
+			continue
+		}
+		failed := u.ActiveState == "failed"
+		l := cgroupLeak{id: m[1], path: u.Name, scope: true, failed: failed, empty: failed}
+		if !l.empty && mountpoint != "" {
+			if v, err := conn.Object("org.freedesktop.systemd1", u.Path).GetProperty("org.freedesktop.systemd1.Scope.ControlGroup"); err == nil {
+				if cgroup, ok := v.Value().(string); ok && cgroup != "" {
+					l.empty = cgroupEmpty(filepath.Join(mountpoint, cgroup))
+				}
+			}
+		}
+		leaks = append(leaks, l)
+	}
+	return leaks, nil
+}
+
+// cgroupEmpty returns whether no process is left in a cgroup and its
+// children.
+func cgroupEmpty(dir string) bool {
+	empty := true
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if procs, err := ioutil.ReadFile(filepath.Join(path, "cgroup.procs")); err == nil && strings.TrimSpace(string(procs)) != "" {
+			empty = false
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return empty
+}
+
+// remove removes a leaked cgroup, with its children, or stops a leaked scope.
+func (l cgroupLeak) remove() error {
+	if l.scope {
+		conn, err := dbus.SystemBus()
+		if err != nil {
+			return err
+		}
+		manager := conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1")
+		if l.failed {
+			return manager.Call("org.freedesktop.systemd1.Manager.ResetFailedUnit", 0, l.path).Err
+		}
+		return manager.Call("org.freedesktop.systemd1.Manager.StopUnit", 0, l.path, "replace").Err
+	}
+
+	var dirs []string
+	if err := filepath.Walk(l.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// The children are removed before their parents.
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Remove(dirs[i]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
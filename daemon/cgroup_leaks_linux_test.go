@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+func TestFindLeakedCgroups(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroup-leaks-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	running := strings.Repeat("a", 64)
+	leaked := strings.Repeat("b", 64)
+	busy := strings.Repeat("c", 64)
+	for _, dir := range []string{
+		"memory/docker/" + running,
+		"memory/docker/" + leaked + "/nested",
+		"memory/docker/" + busy,
+		"memory/docker/not-a-container",
+		"cpu/custom/" + leaked,
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "memory/docker", busy, "cgroup.procs"), []byte("42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := []cgroups.Mount{
+		{Mountpoint: filepath.Join(root, "memory"), Subsystems: []string{"memory"}},
+		{Mountpoint: filepath.Join(root, "cpu"), Subsystems: []string{"cpu", "cpuacct"}},
+	}
+	leaks := findLeakedCgroups(mounts, map[string]bool{"/docker": true, "/custom": true}, map[string]bool{running: true})
+	found := make(map[string]cgroupLeak)
+	for _, l := range leaks {
+		found[l.path] = l
+	}
+	if len(found) != 3 {
+		t.Fatalf("expected 3 leaked cgroups, got %v", leaks)
+	}
+	if l := found[filepath.Join(root, "memory/docker", leaked)]; l.id != leaked || !l.empty {
+		t.Fatalf("expected an empty leaked cgroup, got %+v", l)
+	}
+	if l := found[filepath.Join(root, "memory/docker", busy)]; l.id != busy || l.empty {
+		t.Fatalf("expected a leaked cgroup with processes, got %+v", l)
+	}
+	if _, ok := found[filepath.Join(root, "cpu/custom", leaked)]; !ok {
+		t.Fatal("expected the leaked cgroup in the custom parent to be found")
+	}
+
+	if err := found[filepath.Join(root, "memory/docker", leaked)].remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "memory/docker", leaked)); !os.IsNotExist(err) {
+		t.Fatalf("expected the leaked cgroup to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+// +build !linux
+
+package daemon
+
+import "github.com/docker/engine-api/types"
+
+// cgroupLeakScanner is empty: there are no cgroups on this platform.
+type cgroupLeakScanner struct{}
+
+// startCgroupLeakScanner does nothing: there are no cgroups on this
+// platform.
+func (daemon *Daemon) startCgroupLeakScanner(config *Config) error {
+	return nil
+}
+
+// cgroupLeaksInfo returns nil: there are no cgroups on this platform.
+func (daemon *Daemon) cgroupLeaksInfo() *types.CgroupLeaks {
+	return nil
+}
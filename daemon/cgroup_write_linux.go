@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+var (
+	// cgroupWriteAttempts is the number of attempts of a write to a cgroup
+	// file failing with a transient error.
+	cgroupWriteAttempts = 5
+	// cgroupWriteBackoff is the delay before the second attempt, doubled
+	// before each of the next ones.
+	cgroupWriteBackoff = 10 * time.Millisecond
+)
+
+// cgroupWriteError is the error of a write to a cgroup file.
+type cgroupWriteError struct {
+	subsystem string
+	path      string
+	value     string
+	attempts  int
+	err       error
+}
+
+func (e *cgroupWriteError) Error() string {
+	attempts := ""
+	if e.attempts > 1 {
+		attempts = fmt.Sprintf(" after %d attempts", e.attempts)
+	}
+	return fmt.Sprintf("cannot write %q to %s of the %s cgroup%s: %v", e.value, e.path, e.subsystem, attempts, e.err)
+}
+
+// transientCgroupWriteError returns whether a write to a cgroup file failing
+// with err may succeed later: the kernel refuses with EBUSY a write racing
+// with another change of the cgroup, and with EINVAL a write to a cgroup
+// whose parent is still being configured, like a cpuset without CPUs yet.
+func transientCgroupWriteError(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+	switch err {
+	case syscall.EBUSY, syscall.EINVAL, syscall.EAGAIN, syscall.EINTR:
+		return true
+	}
+	return false
+}
+
+// writeCgroupFile writes value to the file of the cgroup dir in the
+// hierarchy of subsystem, retrying with a backoff the writes failing with a
+// transient error.
+func writeCgroupFile(subsystem, dir, file, value string) error {
+	path := filepath.Join(dir, file)
+	backoff := cgroupWriteBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = ioutil.WriteFile(path, []byte(value), 0644); err == nil {
+			return nil
+		}
+		if !transientCgroupWriteError(err) || attempt >= cgroupWriteAttempts {
+			if pe, ok := err.(*os.PathError); ok {
+				err = pe.Err
+			}
+			return &cgroupWriteError{subsystem: subsystem, path: path, value: value, attempts: attempt, err: err}
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestTransientCgroupWriteError(t *testing.T) {
+	for err, expected := range map[error]bool{
+		syscall.EBUSY: true,
+		&os.PathError{Op: "open", Err: syscall.EINVAL}: true,
+		syscall.ENOENT: false,
+		&os.PathError{Op: "open", Err: syscall.EPERM}: false,
+	} {
+		if actual := transientCgroupWriteError(err); actual != expected {
+			t.Fatalf("expected %v to be transient: %v, got %v", err, expected, actual)
+		}
+	}
+}
+
+func TestWriteCgroupFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-cgroup-write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := writeCgroupFile("cpuset", dir, "cpuset.cpus", "0-3"); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := ioutil.ReadFile(filepath.Join(dir, "cpuset.cpus")); err != nil || string(content) != "0-3" {
+		t.Fatalf("expected 0-3, got %q (%v)", content, err)
+	}
+
+	// A missing cgroup is not transient, the write is not retried.
+	missing := filepath.Join(dir, "missing")
+	err = writeCgroupFile("memory", missing, "memory.limit_in_bytes", "1024")
+	if err == nil {
+		t.Fatal("expected an error writing to a missing cgroup")
+	}
+	for _, s := range []string{"memory cgroup", filepath.Join(missing, "memory.limit_in_bytes"), `"1024"`} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("expected the error to contain %s, got %v", s, err)
+		}
+	}
+	if we, ok := err.(*cgroupWriteError); !ok || we.attempts != 1 || we.err != syscall.ENOENT {
+		t.Fatalf("expected a single attempt failing with ENOENT, got %#v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/opencontainers/runc/libcontainer/user"
+)
+
+// getChownIdentity resolves the `--chown=<user>[:<group>]` flag of the ADD
+// and COPY build instructions to the host UID and GID the copied files must
+// belong to. Names are looked up in the /etc/passwd and /etc/group files of
+// the container. When the group is omitted, it is resolved from the user
+// value, so `--chown=10` is the same as `--chown=10:10`.
+func (daemon *Daemon) getChownIdentity(c *container.Container, chown string) (int, int, error) {
+	parts := strings.Split(chown, ":")
+	if len(parts) > 2 || parts[0] == "" {
+		return 0, 0, fmt.Errorf("invalid chown string format: %s", chown)
+	}
+	userStr, groupStr := parts[0], parts[0]
+	if len(parts) == 2 {
+		groupStr = parts[1]
+	}
+
+	var passwd, group io.Reader
+	if f, err := readUserFile(c, "/etc/passwd"); err == nil {
+		defer f.Close()
+		passwd = f
+	}
+	if f, err := readUserFile(c, "/etc/group"); err == nil {
+		defer f.Close()
+		group = f
+	}
+
+	execUser, err := user.GetExecUser(userStr+":"+groupStr, nil, passwd, group)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to resolve --chown=%s: %v", chown, err)
+	}
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uid, err := idtools.ToHost(execUser.Uid, uidMaps)
+	if err != nil {
+		return 0, 0, err
+	}
+	gid, err := idtools.ToHost(execUser.Gid, gidMaps)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uid, gid, nil
+}
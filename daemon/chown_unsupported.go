@@ -0,0 +1,13 @@
+// +build !linux
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+)
+
+func (daemon *Daemon) getChownIdentity(c *container.Container, chown string) (int, int, error) {
+	return 0, 0, fmt.Errorf("--chown is not supported on this platform")
+}
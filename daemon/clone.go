@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/utils"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+)
+
+// ContainerClone creates a container with the configuration of another,
+// with some overrides, and a copy of its writable layer. The volumes are not
+// copied: the clone shares the named volumes and the bind mounts of the
+// source, and gets new anonymous volumes.
+func (daemon *Daemon) ContainerClone(name string, c types.ContainerCloneConfig) (types.ContainerCreateResponse, error) {
+	if runtime.GOOS == "windows" {
+		return types.ContainerCreateResponse{}, errors.New("cloning a container is not supported on Windows")
+	}
+	src, err := daemon.GetContainer(name)
+	if err != nil {
+		return types.ContainerCreateResponse{}, err
+	}
+
+	params, networks, err := cloneCreateConfig(src, c)
+	if err != nil {
+		return types.ContainerCreateResponse{}, err
+	}
+	resp, err := daemon.containerCreate(params, false, true)
+	if err != nil {
+		return resp, err
+	}
+	clone, err := daemon.GetContainer(resp.ID)
+	if err == nil {
+		err = daemon.cloneContainer(src, clone, networks, c.Pause)
+	}
+	if err != nil {
+		if err := daemon.ContainerRm(resp.ID, &types.ContainerRmConfig{ForceRemove: true, RemoveVolume: true}); err != nil {
+			logrus.Errorf("Error removing the clone %s of container %s: %v", resp.ID, src.ID, err)
+		}
+		return types.ContainerCreateResponse{}, fmt.Errorf("error cloning container %s: %v", src.ID, err)
+	}
+
+	daemon.LogContainerEventWithAttributes(src, "clone", map[string]string{"clone": clone.Name[1:]})
+	return resp, nil
+}
+
+// cloneCreateConfig returns the configuration of a clone of a container,
+// and the settings of the networks it should be connected to after it is
+// created.
+func cloneCreateConfig(src *container.Container, c types.ContainerCloneConfig) (types.ContainerCreateConfig, map[string]*networktypes.EndpointSettings, error) {
+	src.Lock()
+	defer src.Unlock()
+
+	var (
+		config     containertypes.Config
+		hostConfig containertypes.HostConfig
+		networks   = make(map[string]*networktypes.EndpointSettings)
+	)
+	if err := deepCopy(&config, src.Config); err != nil {
+		return types.ContainerCreateConfig{}, nil, err
+	}
+	if err := deepCopy(&hostConfig, src.HostConfig); err != nil {
+		return types.ContainerCreateConfig{}, nil, err
+	}
+
+	// The hostname and the MAC address of the clone are its own.
+	if config.Hostname == stringid.TruncateID(src.ID) {
+		config.Hostname = ""
+	}
+	config.MacAddress = ""
+	config.Env = utils.ReplaceOrAppendEnvValues(config.Env, c.Env)
+	if len(c.Labels) > 0 && config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	for k, v := range c.Labels {
+		config.Labels[k] = v
+	}
+	mergeResources(&hostConfig.Resources, c.Resources)
+
+	params := types.ContainerCreateConfig{
+		Name:       c.Name,
+		Config:     &config,
+		HostConfig: &hostConfig,
+	}
+	for n, settings := range src.NetworkSettings.Networks {
+		if !containertypes.NetworkMode(n).IsUserDefined() {
+			continue
+		}
+		// The addresses of the source are not reused, nor its short ID.
+		endpoint := &networktypes.EndpointSettings{Links: settings.Links}
+		for _, alias := range settings.Aliases {
+			if alias != stringid.TruncateID(src.ID) {
+				endpoint.Aliases = append(endpoint.Aliases, alias)
+			}
+		}
+		if n == hostConfig.NetworkMode.NetworkName() {
+			params.NetworkingConfig = &networktypes.NetworkingConfig{
+				EndpointsConfig: map[string]*networktypes.EndpointSettings{n: endpoint},
+			}
+			continue
+		}
+		networks[n] = endpoint
+	}
+	return params, networks, nil
+}
+
+// cloneContainer connects a clone to the networks of its source, and copies
+// the writable layer of the source into the clone, pausing the source during
+// the copy if it is running and pause is true.
+func (daemon *Daemon) cloneContainer(src, clone *container.Container, networks map[string]*networktypes.EndpointSettings, pause bool) error {
+	for n, endpoint := range networks {
+		if err := daemon.ConnectToNetwork(clone, n, endpoint); err != nil {
+			return err
+		}
+	}
+
+	if pause && src.IsRunning() && !src.IsPaused() {
+		if err := daemon.containerPause(src); err != nil {
+			return err
+		}
+		defer daemon.containerUnpause(src)
+	}
+
+	changes, err := src.RWLayer.TarStream()
+	if err != nil {
+		return err
+	}
+	defer changes.Close()
+
+	if err := daemon.Mount(clone); err != nil {
+		return err
+	}
+	defer daemon.Unmount(clone)
+
+	// The changes are applied as they are stored by the graphdriver of the
+	// source, their owners already remapped.
+	_, err = chrootarchive.ApplyUncompressedLayer(clone.BaseFS, changes, &archive.TarOptions{})
+	return err
+}
+
+// mergeResources sets the non-zero fields of overrides in resources.
+func mergeResources(resources *containertypes.Resources, overrides containertypes.Resources) {
+	dst := reflect.ValueOf(resources).Elem()
+	src := reflect.ValueOf(overrides)
+	for i := 0; i < src.NumField(); i++ {
+		f := src.Field(i)
+		if !reflect.DeepEqual(f.Interface(), reflect.Zero(f.Type()).Interface()) {
+			dst.Field(i).Set(f)
+		}
+	}
+}
+
+// deepCopy copies src into dst, through JSON.
+func deepCopy(dst, src interface{}) error {
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/network"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+)
+
+func TestCloneCreateConfig(t *testing.T) {
+	src := container.NewBaseContainer("8f0c8c6c8b6a4f9e7e0c0e1d2d3d4d5d6d7d8d9dadbdcdddedfdeeeff00112233", "")
+	src.Config = &containertypes.Config{
+		Hostname:   "8f0c8c6c8b6a",
+		MacAddress: "02:42:ac:11:00:02",
+		Env:        []string{"PATH=/usr/bin", "LOG_LEVEL=info"},
+		Labels:     map[string]string{"app": "db"},
+	}
+	src.HostConfig = &containertypes.HostConfig{
+		NetworkMode: "backend",
+		Resources:   containertypes.Resources{Memory: 1 << 30, CPUShares: 512},
+	}
+	src.NetworkSettings = &network.Settings{
+		Networks: map[string]*networktypes.EndpointSettings{
+			"backend":  {IPAddress: "10.0.0.2", Aliases: []string{"db", "8f0c8c6c8b6a"}},
+			"frontend": {IPAddress: "10.0.1.2"},
+		},
+	}
+
+	params, networks, err := cloneCreateConfig(src, types.ContainerCloneConfig{
+		Name:      "db-test",
+		Env:       []string{"LOG_LEVEL=debug"},
+		Labels:    map[string]string{"test": "1"},
+		Resources: containertypes.Resources{Memory: 1 << 29},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params.Config.Hostname != "" || params.Config.MacAddress != "" {
+		t.Fatalf("expected the clone not to reuse the hostname and MAC address of the source, got %q, %q", params.Config.Hostname, params.Config.MacAddress)
+	}
+	if len(params.Config.Env) != 2 || params.Config.Env[1] != "LOG_LEVEL=debug" {
+		t.Fatalf("unexpected environment %v", params.Config.Env)
+	}
+	if params.Config.Labels["app"] != "db" || params.Config.Labels["test"] != "1" {
+		t.Fatalf("unexpected labels %v", params.Config.Labels)
+	}
+	if src.Config.Env[1] != "LOG_LEVEL=info" || len(src.Config.Labels) != 1 {
+		t.Fatalf("expected the configuration of the source to be unchanged, got %+v", src.Config)
+	}
+	if params.HostConfig.Memory != 1<<29 || params.HostConfig.CPUShares != 512 {
+		t.Fatalf("unexpected resources %+v", params.HostConfig.Resources)
+	}
+
+	endpoint := params.NetworkingConfig.EndpointsConfig["backend"]
+	if endpoint == nil || endpoint.IPAddress != "" || len(endpoint.Aliases) != 1 || endpoint.Aliases[0] != "db" {
+		t.Fatalf("unexpected endpoint on the primary network %+v", endpoint)
+	}
+	if len(networks) != 1 || networks["frontend"] == nil || networks["frontend"].IPAddress != "" {
+		t.Fatalf("unexpected networks to connect %v", networks)
+	}
+}
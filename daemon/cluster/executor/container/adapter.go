@@ -60,7 +60,7 @@ func (c *containerAdapter) pullImage(ctx context.Context) error {
 	pr, pw := io.Pipe()
 	metaHeaders := map[string][]string{}
 	go func() {
-		err := c.backend.PullImage(ctx, c.container.image(), "", metaHeaders, authConfig, pw)
+		err := c.backend.PullImage(ctx, c.container.image(), "", "", metaHeaders, authConfig, pw)
 		pw.CloseWithError(err)
 	}()
 
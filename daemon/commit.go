@@ -193,11 +193,18 @@ func (daemon *Daemon) Commit(name string, c *backend.ContainerCommitConfig) (str
 
 	history = append(history, h)
 
+	// The image of a container run emulated is for the architecture the
+	// container runs for.
+	arch := container.Config.Architecture
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+
 	config, err := json.Marshal(&image.Image{
 		V1Image: image.V1Image{
 			DockerVersion:   dockerversion.Version,
 			Config:          newConfig,
-			Architecture:    runtime.GOARCH,
+			Architecture:    arch,
 			OS:              runtime.GOOS,
 			Container:       container.ID,
 			ContainerConfig: *container.Config,
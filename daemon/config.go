@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"strings"
 	"sync"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/distribution"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/discovery"
+	"github.com/docker/docker/pkg/loglevel"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/registry"
+	containertypes "github.com/docker/engine-api/types/container"
 	"github.com/imdario/mergo"
 )
 
@@ -26,6 +30,13 @@ const (
 	// maximum number of uploads that
 	// may take place at a time for each push.
 	defaultMaxConcurrentUploads = 5
+	// defaultMaxConcurrentBuildStages is the default value for
+	// maximum number of build stages that
+	// may be built at a time by all the builds.
+	defaultMaxConcurrentBuildStages = 3
+	// defaultShutdownTimeout is the default time, in seconds, given to
+	// the containers to stop when the daemon shuts down.
+	defaultShutdownTimeout = 10
 	// stockRuntimeName is the reserved name/alias used to represent the
 	// OCI runtime being shipped with the docker daemon package.
 	stockRuntimeName = "runc"
@@ -41,11 +52,21 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
+	"audit-log-opts":     true,
 	"cluster-store-opts": true,
 	"log-opts":           true,
+	"registries":         true,
 	"runtimes":           true,
 }
 
+// fileOnlyOptions contains configuration keys
+// that can only be set in the configuration file,
+// and have no matching flag.
+var fileOnlyOptions = map[string]bool{
+	"registries": true,
+	"log-levels": true,
+}
+
 // LogConfig represents the default log configuration.
 // It includes json tags to deserialize configuration from a file
 // using the same names that the flags in the command line use.
@@ -110,6 +131,69 @@ type CommonConfig struct {
 	// reachable by other hosts.
 	ClusterAdvertise string `json:"cluster-advertise,omitempty"`
 
+	// ContentTrust restricts the pulls to the images signed with content
+	// trust.
+	ContentTrust bool `json:"content-trust,omitempty"`
+
+	// ContentTrustServer is the URL of the trust server used to verify the
+	// signatures of the pulled images.
+	ContentTrustServer string `json:"content-trust-server,omitempty"`
+
+	// TLSCRLFile is the certificate revocation list the client
+	// certificates are verified against.
+	TLSCRLFile string `json:"tlscrl,omitempty"`
+
+	// AuditLogDriver is the driver of the audit log of the API calls
+	// changing the state of the daemon. There is no audit log if it is
+	// empty.
+	AuditLogDriver string            `json:"audit-log-driver,omitempty"`
+	AuditLogOpts   map[string]string `json:"audit-log-opts,omitempty"`
+
+	// APIMaxConcurrentRequests is the maximum number of API requests
+	// handled at a time, and APIMaxConcurrentRequestsPerClient the maximum
+	// for each client. There is no limit if they are 0.
+	APIMaxConcurrentRequests          int `json:"api-max-concurrent-requests,omitempty"`
+	APIMaxConcurrentRequestsPerClient int `json:"api-max-concurrent-requests-per-client,omitempty"`
+
+	// APIMaxQueuedRequests is the maximum number of API requests waiting
+	// for one of the APIMaxConcurrentRequests requests to complete.
+	APIMaxQueuedRequests int `json:"api-max-queued-requests,omitempty"`
+
+	// AuditEndpoints are the patterns of the paths of the API calls
+	// recorded in the audit log. All the calls changing the state of the
+	// daemon are recorded if it is empty.
+	AuditEndpoints []string `json:"audit-endpoints,omitempty"`
+
+	// SecretEnvPatterns are the regular expressions of the names of the
+	// environment variables whose values are masked in the inspect output
+	// and in the audit log.
+	SecretEnvPatterns []string `json:"secret-env-patterns,omitempty"`
+
+	// HooksDir is the directory of the executables run at the lifecycle
+	// transitions of the containers, and HookPlugins the plugins called
+	// after them.
+	HooksDir    string   `json:"hooks-dir,omitempty"`
+	HookPlugins []string `json:"hook-plugins,omitempty"`
+
+	// ShutdownTimeout is the time, in seconds, given to the containers to
+	// stop when the daemon shuts down, before they are killed.
+	ShutdownTimeout int `json:"shutdown-timeout,omitempty"`
+
+	// EventsJournalSize is the maximum size of the journal persisting the
+	// events across the restarts of the daemon, and EventsJournalAge the
+	// maximum age of its events. The events are not persisted without a
+	// size.
+	EventsJournalSize string `json:"events-journal-size,omitempty"`
+	EventsJournalAge  string `json:"events-journal-age,omitempty"`
+
+	// LayerCacheListen is the address the daemon serves its layers on to
+	// the other daemons, LayerCachePeers the URLs of the other daemons it
+	// gets the layers of the pulled images from before the registries, and
+	// LayerCacheToken the token shared by the daemons.
+	LayerCacheListen string   `json:"layer-cache-listen,omitempty"`
+	LayerCachePeers  []string `json:"layer-cache-peers,omitempty"`
+	LayerCacheToken  string   `json:"layer-cache-token,omitempty"`
+
 	// MaxConcurrentDownloads is the maximum number of downloads that
 	// may take place at a time for each pull.
 	MaxConcurrentDownloads *int `json:"max-concurrent-downloads,omitempty"`
@@ -118,6 +202,27 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// MaxConcurrentBuildStages is the maximum number of independent build
+	// stages that may be built at a time by all the builds.
+	MaxConcurrentBuildStages *int `json:"max-concurrent-build-stages,omitempty"`
+
+	// PushCompression is the compression of the layers pushed, gzip or
+	// zstd, and PushCompressionLevel its level, 0 for the default level of
+	// the compression.
+	PushCompression      string `json:"push-compression,omitempty"`
+	PushCompressionLevel int    `json:"push-compression-level,omitempty"`
+
+	// DefaultNetwork is the network the containers run without a network
+	// are connected to, the built-in default network if empty.
+	DefaultNetwork string `json:"default-network,omitempty"`
+
+	// LogFormat is the format of the logs of the daemon, text or json.
+	LogFormat string `json:"log-format,omitempty"`
+
+	// LogLevels are the levels of the logs of the subsystems of the daemon
+	// having their own level, like {"graphdriver": "debug"}.
+	LogLevels map[string]string `json:"log-levels,omitempty"`
+
 	Debug     bool     `json:"debug,omitempty"`
 	Hosts     []string `json:"hosts,omitempty"`
 	LogLevel  string   `json:"log-level,omitempty"`
@@ -140,7 +245,7 @@ type CommonConfig struct {
 // Subsequent calls to `flag.Parse` will populate config with values parsed
 // from the command-line.
 func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string) string) {
-	var maxConcurrentDownloads, maxConcurrentUploads int
+	var maxConcurrentDownloads, maxConcurrentUploads, maxConcurrentBuildStages int
 
 	config.ServiceOptions.InstallCliFlags(cmd, usageFn)
 
@@ -153,6 +258,7 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.GraphDriver, []string{"s", "-storage-driver"}, "", usageFn("Storage driver to use"))
 	cmd.IntVar(&config.Mtu, []string{"#mtu", "-mtu"}, 0, usageFn("Set the containers network MTU"))
 	cmd.BoolVar(&config.RawLogs, []string{"-raw-logs"}, false, usageFn("Full timestamps without ANSI coloring"))
+	cmd.StringVar(&config.LogFormat, []string{"-log-format"}, "text", usageFn("Format of the logs of the daemon (text or json)"))
 	// FIXME: why the inconsistency between "hosts" and "sockets"?
 	cmd.Var(opts.NewListOptsRef(&config.DNS, opts.ValidateIPAddress), []string{"#dns", "-dns"}, usageFn("DNS server to use"))
 	cmd.Var(opts.NewNamedListOptsRef("dns-opts", &config.DNSOptions, nil), []string{"-dns-opt"}, usageFn("DNS options to use"))
@@ -164,11 +270,39 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
 	cmd.StringVar(&config.CorsHeaders, []string{"-api-cors-header"}, "", usageFn("Set CORS headers in the remote API"))
+	cmd.IntVar(&config.APIMaxConcurrentRequests, []string{"-api-max-concurrent-requests"}, 0, usageFn("Set the max API requests handled concurrently"))
+	cmd.IntVar(&config.APIMaxConcurrentRequestsPerClient, []string{"-api-max-concurrent-requests-per-client"}, 0, usageFn("Set the max API requests handled concurrently for each client"))
+	cmd.IntVar(&config.APIMaxQueuedRequests, []string{"-api-max-queued-requests"}, 0, usageFn("Set the max API requests waiting for a concurrent request to complete"))
+	cmd.BoolVar(&config.ContentTrust, []string{"-content-trust"}, false, usageFn("Only pull images signed with content trust"))
+	cmd.StringVar(&config.ContentTrustServer, []string{"-content-trust-server"}, "", usageFn("Set the trust server used to verify the pulled images"))
+	cmd.StringVar(&config.TLSCRLFile, []string{"-tlscrl"}, "", usageFn("Path to the certificate revocation list of the client certificates"))
+	cmd.StringVar(&config.AuditLogDriver, []string{"-audit-log-driver"}, "", usageFn("Driver of the audit log of the API calls (file, journald or syslog)"))
+	cmd.Var(opts.NewNamedMapOpts("audit-log-opts", config.AuditLogOpts, nil), []string{"-audit-log-opt"}, usageFn("Set audit log driver options"))
+	cmd.Var(opts.NewNamedListOptsRef("audit-endpoints", &config.AuditEndpoints, nil), []string{"-audit-endpoint"}, usageFn("Only audit the API calls matching this path pattern"))
+	cmd.Var(opts.NewNamedListOptsRef("secret-env-patterns", &config.SecretEnvPatterns, nil), []string{"-secret-env-pattern"}, usageFn("Mask the values of the environment variables matching this name pattern"))
+	cmd.StringVar(&config.HooksDir, []string{"-hooks-dir"}, "", usageFn("Directory of the hooks run at the lifecycle transitions of the containers"))
+	cmd.Var(opts.NewNamedListOptsRef("hook-plugins", &config.HookPlugins, nil), []string{"-hook-plugin"}, usageFn("Call this plugin at the lifecycle transitions of the containers"))
+	cmd.IntVar(&config.ShutdownTimeout, []string{"-shutdown-timeout"}, defaultShutdownTimeout, usageFn("Set the time, in seconds, to stop the containers on shutdown before killing them"))
+	cmd.StringVar(&config.EventsJournalSize, []string{"-events-journal-size"}, "", usageFn("Persist the events across restarts in a journal of this maximum size"))
+	cmd.StringVar(&config.EventsJournalAge, []string{"-events-journal-age"}, "", usageFn("Drop the events older than this duration from the events journal"))
+	cmd.StringVar(&config.LayerCacheListen, []string{"-layer-cache-listen"}, "", usageFn("Serve the layers of the daemon to other daemons on this address"))
+	cmd.Var(opts.NewNamedListOptsRef("layer-cache-peers", &config.LayerCachePeers, nil), []string{"-layer-cache-peer"}, usageFn("Get the layers of the pulled images from the daemon at this URL before the registry"))
+	cmd.StringVar(&config.LayerCacheToken, []string{"-layer-cache-token"}, "", usageFn("Token shared by the daemons serving their layers to each other"))
+	cmd.StringVar(&config.DefaultNetwork, []string{"-default-network"}, "", usageFn("Connect the containers run without a network to this network"))
 	cmd.IntVar(&maxConcurrentDownloads, []string{"-max-concurrent-downloads"}, defaultMaxConcurrentDownloads, usageFn("Set the max concurrent downloads for each pull"))
 	cmd.IntVar(&maxConcurrentUploads, []string{"-max-concurrent-uploads"}, defaultMaxConcurrentUploads, usageFn("Set the max concurrent uploads for each push"))
+	cmd.IntVar(&maxConcurrentBuildStages, []string{"-max-concurrent-build-stages"}, defaultMaxConcurrentBuildStages, usageFn("Set the max build stages built concurrently"))
+	cmd.StringVar(&config.PushCompression, []string{"-push-compression"}, distribution.CompressionGzip, usageFn("Compression of the layers pushed (gzip or zstd)"))
+	cmd.IntVar(&config.PushCompressionLevel, []string{"-push-compression-level"}, 0, usageFn("Compression level of the layers pushed, 0 for the default level"))
 
 	config.MaxConcurrentDownloads = &maxConcurrentDownloads
 	config.MaxConcurrentUploads = &maxConcurrentUploads
+	config.MaxConcurrentBuildStages = &maxConcurrentBuildStages
+}
+
+// pushCompression returns the compression of the layers pushed.
+func (config *Config) pushCompression() distribution.LayerCompression {
+	return distribution.LayerCompression{Codec: config.PushCompression, Level: config.PushCompressionLevel}
 }
 
 // IsValueSet returns true if a configuration value
@@ -331,6 +465,9 @@ func findConfigurationConflicts(config map[string]interface{}, flags *flag.FlagS
 	// 1. Search keys from the file that we don't recognize as flags.
 	unknownKeys := make(map[string]interface{})
 	for key, value := range config {
+		if fileOnlyOptions[key] {
+			continue
+		}
 		flagName := "-" + key
 		if flag := flags.Lookup(flagName); flag == nil {
 			unknownKeys[key] = value
@@ -393,7 +530,8 @@ func findConfigurationConflicts(config map[string]interface{}, flags *flag.FlagS
 
 // ValidateConfiguration validates some specific configs.
 // such as config.DNS, config.Labels, config.DNSSearch,
-// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads.
+// as well as config.MaxConcurrentDownloads, config.MaxConcurrentUploads
+// and config.MaxConcurrentBuildStages.
 func ValidateConfiguration(config *Config) error {
 	// validate DNS
 	for _, dns := range config.DNS {
@@ -416,6 +554,44 @@ func ValidateConfiguration(config *Config) error {
 		}
 	}
 
+	// validate Mirrors
+	for _, mirror := range config.Mirrors {
+		if _, err := registry.ValidateMirror(mirror); err != nil {
+			return err
+		}
+	}
+
+	// validate Registries
+	if err := registry.ValidateRegistries(config.Registries); err != nil {
+		return err
+	}
+
+	// validate LogLevel
+	if config.LogLevel != "" {
+		if _, err := logrus.ParseLevel(config.LogLevel); err != nil {
+			return fmt.Errorf("invalid log level: %s", config.LogLevel)
+		}
+	}
+
+	// validate LogFormat
+	switch config.LogFormat {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("invalid log format: %s, use text or json", config.LogFormat)
+	}
+
+	// validate LogLevels
+	if _, err := loglevel.ParseSubsystemLevels(config.LogLevels); err != nil {
+		return err
+	}
+
+	// validate ContentTrustServer
+	if config.ContentTrustServer != "" {
+		if u, err := url.Parse(config.ContentTrustServer); err != nil || u.Scheme != "https" {
+			return fmt.Errorf("invalid content trust server: valid https URL required, got %s", config.ContentTrustServer)
+		}
+	}
+
 	// validate MaxConcurrentDownloads
 	if config.IsValueSet("max-concurrent-downloads") && config.MaxConcurrentDownloads != nil && *config.MaxConcurrentDownloads < 0 {
 		return fmt.Errorf("invalid max concurrent downloads: %d", *config.MaxConcurrentDownloads)
@@ -426,6 +602,35 @@ func ValidateConfiguration(config *Config) error {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
 
+	if config.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid shutdown timeout: %d", config.ShutdownTimeout)
+	}
+
+	if err := config.pushCompression().Validate(); err != nil {
+		return err
+	}
+
+	// validate DefaultNetwork
+	if n := containertypes.NetworkMode(config.DefaultNetwork); n.IsDefault() || n.IsContainer() {
+		return fmt.Errorf("invalid default network: %s", config.DefaultNetwork)
+	}
+
+	// validate the limits of the API requests
+	if config.APIMaxConcurrentRequests < 0 {
+		return fmt.Errorf("invalid max concurrent API requests: %d", config.APIMaxConcurrentRequests)
+	}
+	if config.APIMaxConcurrentRequestsPerClient < 0 {
+		return fmt.Errorf("invalid max concurrent API requests per client: %d", config.APIMaxConcurrentRequestsPerClient)
+	}
+	if config.APIMaxQueuedRequests < 0 {
+		return fmt.Errorf("invalid max queued API requests: %d", config.APIMaxQueuedRequests)
+	}
+
+	// validate MaxConcurrentBuildStages
+	if config.IsValueSet("max-concurrent-build-stages") && config.MaxConcurrentBuildStages != nil && *config.MaxConcurrentBuildStages < 0 {
+		return fmt.Errorf("invalid max concurrent build stages: %d", *config.MaxConcurrentBuildStages)
+	}
+
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
 		if _, ok := runtimes[stockRuntimeName]; ok {
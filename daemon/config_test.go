@@ -171,6 +171,31 @@ func TestDaemonConfigurationMergeConflictsWithInnerStructs(t *testing.T) {
 	}
 }
 
+func TestDaemonConfigurationRegistries(t *testing.T) {
+	f, err := ioutil.TempFile("", "docker-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := f.Name()
+	f.Write([]byte(`{"registries": {"docker.io/library": {"mirrors": ["https://mirror.example.com"]}, "registry.example.com": {"insecure": true}}}`))
+	f.Close()
+
+	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
+	flags.Bool([]string{"-debug"}, false, "")
+
+	cc, err := MergeDaemonConfigurations(&Config{}, flags, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mirrors := cc.Registries["docker.io/library"].Mirrors; len(mirrors) != 1 || mirrors[0] != "https://mirror.example.com" {
+		t.Fatalf("expected the mirror of docker.io/library, got %v", mirrors)
+	}
+	if !cc.Registries["registry.example.com"].Insecure {
+		t.Fatalf("expected registry.example.com to be insecure, got %v", cc.Registries)
+	}
+}
+
 func TestFindConfigurationConflictsWithUnknownKeys(t *testing.T) {
 	config := map[string]interface{}{"tls-verify": "true"}
 	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
@@ -275,4 +300,48 @@ func TestValidateConfiguration(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+
+	c7 := &Config{
+		CommonConfig: CommonConfig{
+			ContentTrustServer: "http://notary.example.com",
+		},
+	}
+
+	err = ValidateConfiguration(c7)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	c8 := &Config{
+		CommonConfig: CommonConfig{
+			ContentTrustServer: "https://notary.example.com",
+		},
+	}
+
+	err = ValidateConfiguration(c8)
+	if err != nil {
+		t.Fatalf("expected no error, got error %v", err)
+	}
+
+	c9 := &Config{
+		CommonConfig: CommonConfig{
+			DefaultNetwork: "container:web",
+		},
+	}
+
+	err = ValidateConfiguration(c9)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	c10 := &Config{
+		CommonConfig: CommonConfig{
+			DefaultNetwork: "apps",
+		},
+	}
+
+	err = ValidateConfiguration(c10)
+	if err != nil {
+		t.Fatalf("expected no error, got error %v", err)
+	}
 }
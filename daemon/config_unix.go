@@ -35,6 +35,38 @@ type Config struct {
 	Runtimes             map[string]types.Runtime `json:"runtimes,omitempty"`
 	DefaultRuntime       string                   `json:"default-runtime,omitempty"`
 	OOMScoreAdjust       int                      `json:"oom-score-adjust,omitempty"`
+
+	// SeccompProfile, DefaultCapabilities and DefaultAppArmorProfile
+	// replace the built-in security defaults of the containers.
+	SeccompProfile         string   `json:"seccomp-profile,omitempty"`
+	DefaultCapabilities    []string `json:"default-capabilities,omitempty"`
+	DefaultAppArmorProfile string   `json:"default-apparmor-profile,omitempty"`
+
+	// DefaultDeviceCgroupRules are added to the built-in rules of the
+	// devices cgroup of the containers, like "c 189:* rmw".
+	DefaultDeviceCgroupRules []string `json:"default-device-cgroup-rules,omitempty"`
+
+	// DebugSocket is the path of a unix socket serving the Go profiler and
+	// the runtime trace of the daemon, whether debug is enabled or not.
+	DebugSocket string `json:"debug-socket,omitempty"`
+
+	// StatsHistory is how long the daemon keeps the history of the resource
+	// usage of each container, like "1h". There is no history if it is
+	// empty.
+	StatsHistory string `json:"stats-history,omitempty"`
+
+	// CgroupDryRun makes the creation and the update of a container fail
+	// with all the limits its cgroup manager would not apply, instead of
+	// discarding them with a warning.
+	CgroupDryRun bool `json:"cgroup-dry-run,omitempty"`
+
+	// CgroupLeakScan is the interval between the scans for the cgroups and
+	// the systemd scopes of the containers which do not run anymore, like
+	// "10m". There is no scan if it is empty or "0".
+	CgroupLeakScan string `json:"cgroup-leak-scan,omitempty"`
+
+	// CgroupLeakCleanup makes the scans remove the empty leaked cgroups.
+	CgroupLeakCleanup bool `json:"cgroup-leak-cleanup,omitempty"`
 }
 
 // bridgeConfig stores all the bridge driver specific
@@ -54,6 +86,7 @@ type bridgeConfig struct {
 	DefaultGatewayIPv4          net.IP `json:"default-gateway,omitempty"`
 	DefaultGatewayIPv6          net.IP `json:"default-gateway-v6,omitempty"`
 	InterContainerCommunication bool   `json:"icc,omitempty"`
+	ICCRules                    string `json:"icc-rules,omitempty"`
 }
 
 // InstallFlags adds command-line options to the top-level flag parser for
@@ -81,6 +114,7 @@ func (config *Config) InstallFlags(cmd *flag.FlagSet, usageFn func(string) strin
 	cmd.Var(opts.NewIPOpt(&config.bridgeConfig.DefaultGatewayIPv4, ""), []string{"-default-gateway"}, usageFn("Container default gateway IPv4 address"))
 	cmd.Var(opts.NewIPOpt(&config.bridgeConfig.DefaultGatewayIPv6, ""), []string{"-default-gateway-v6"}, usageFn("Container default gateway IPv6 address"))
 	cmd.BoolVar(&config.bridgeConfig.InterContainerCommunication, []string{"#icc", "-icc"}, true, usageFn("Enable inter-container communication"))
+	cmd.StringVar(&config.bridgeConfig.ICCRules, []string{"-icc-rules"}, "", usageFn("Inter-container communication rules of the default bridge network"))
 	cmd.Var(opts.NewIPOpt(&config.bridgeConfig.DefaultIP, "0.0.0.0"), []string{"#ip", "-ip"}, usageFn("Default IP when binding container ports"))
 	cmd.BoolVar(&config.bridgeConfig.EnableUserlandProxy, []string{"-userland-proxy"}, true, usageFn("Use userland proxy for loopback traffic"))
 	cmd.BoolVar(&config.EnableCors, []string{"#api-enable-cors", "#-api-enable-cors"}, false, usageFn("Enable CORS headers in the remote API, this is deprecated by --api-cors-header"))
@@ -92,6 +126,15 @@ func (config *Config) InstallFlags(cmd *flag.FlagSet, usageFn func(string) strin
 	cmd.Var(runconfigopts.NewNamedRuntimeOpt("runtimes", &config.Runtimes, stockRuntimeName), []string{"-add-runtime"}, usageFn("Register an additional OCI compatible runtime"))
 	cmd.StringVar(&config.DefaultRuntime, []string{"-default-runtime"}, stockRuntimeName, usageFn("Default OCI runtime to be used"))
 	cmd.IntVar(&config.OOMScoreAdjust, []string{"-oom-score-adjust"}, -500, usageFn("Set the oom_score_adj for the daemon"))
+	cmd.StringVar(&config.SeccompProfile, []string{"-seccomp-profile"}, "", usageFn("Path to the default seccomp profile of the containers"))
+	cmd.Var(opts.NewNamedListOptsRef("default-capabilities", &config.DefaultCapabilities, nil), []string{"-default-capability"}, usageFn("Default capabilities of the containers, replacing the built-in set"))
+	cmd.Var(opts.NewNamedListOptsRef("default-device-cgroup-rules", &config.DefaultDeviceCgroupRules, runconfigopts.ValidateDeviceCgroupRule), []string{"-default-device-cgroup-rule"}, usageFn("Default rule added to the cgroup allowed devices list of the containers"))
+	cmd.StringVar(&config.DefaultAppArmorProfile, []string{"-default-apparmor-profile"}, "", usageFn("Default AppArmor profile of the containers"))
+	cmd.StringVar(&config.DebugSocket, []string{"-debug-socket"}, "", usageFn("Path of a unix socket serving the Go profiler endpoints"))
+	cmd.StringVar(&config.StatsHistory, []string{"-stats-history"}, "", usageFn("Keep the history of the resource usage of the containers for this duration"))
+	cmd.BoolVar(&config.CgroupDryRun, []string{"-cgroup-dry-run"}, false, usageFn("Validate the resource limits of the containers against the kernel and systemd before creating them"))
+	cmd.StringVar(&config.CgroupLeakScan, []string{"-cgroup-leak-scan"}, "10m", usageFn("Interval between the scans for the leaked cgroups of the containers, 0 to disable them"))
+	cmd.BoolVar(&config.CgroupLeakCleanup, []string{"-cgroup-leak-cleanup"}, false, usageFn("Remove the empty leaked cgroups of the containers"))
 
 	config.attachExperimentalFlags(cmd, usageFn)
 }
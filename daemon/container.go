@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"fmt"
+	"net"
 	"path/filepath"
 	"regexp"
 	"time"
@@ -221,6 +222,12 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 			}
 		}
 
+		if config.MacAddress != "" {
+			if _, err := net.ParseMAC(config.MacAddress); err != nil {
+				return nil, fmt.Errorf("invalid MAC address %s: %v", config.MacAddress, err)
+			}
+		}
+
 		// Validate if the given hostname is RFC 1123 (https://tools.ietf.org/html/rfc1123) compliant.
 		if validateHostname && len(config.Hostname) > 0 {
 			// RFC1123 specifies that 63 bytes is the maximium length
@@ -251,6 +258,10 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 		}
 	}
 
+	if err := daemon.verifySecrets(hostConfig); err != nil {
+		return nil, err
+	}
+
 	// Now do platform-specific verification
 	return verifyPlatformContainerSettings(daemon, hostConfig, config, update)
 }
@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/hooks"
+)
+
+// runContainerHooks runs the hooks of a locked container at a stage, with its
+// inspect data on their standard input.
+func (daemon *Daemon) runContainerHooks(stage hooks.Stage, container *container.Container) error {
+	inspect, err := daemon.containerInspectCurrent(container, false)
+	if err != nil {
+		return err
+	}
+	return daemon.hooks.Run(stage, inspect)
+}
+
+// runPostStopHooks runs the post-stop hooks of a locked container which
+// exited, in the background so that they do not hold its lock.
+func (daemon *Daemon) runPostStopHooks(container *container.Container) {
+	inspect, err := daemon.containerInspectCurrent(container, false)
+	if err != nil {
+		logrus.Errorf("Error running the post-stop hooks of container %s: %v", container.ID, err)
+		return
+	}
+	go daemon.hooks.Run(hooks.PostStop, inspect)
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net"
@@ -8,12 +9,13 @@ import (
 	"path"
 	"strings"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/network"
 	derr "github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/loglevel"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/runconfig"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/go-connections/nat"
@@ -118,6 +120,11 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container) ([]lib
 	for _, extraHost := range container.HostConfig.ExtraHosts {
 		// allow IPv6 addresses in extra hosts; only split on first ":"
 		parts := strings.SplitN(extraHost, ":", 2)
+		if parts[1] == runconfigopts.HostGateway {
+			if parts[1], err = daemon.hostGatewayIP(); err != nil {
+				return nil, fmt.Errorf("cannot resolve the extra host %s: %v", extraHost, err)
+			}
+		}
 		sboxOptions = append(sboxOptions, libnetwork.OptionExtraHost(parts[0], parts[1]))
 	}
 
@@ -216,7 +223,7 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container) ([]lib
 		}
 
 		_, alias = path.Split(alias)
-		logrus.Debugf("Update /etc/hosts of %s for alias %s with ip %s", parent.ID, alias, bridgeSettings.IPAddress)
+		loglevel.WithSubsystem(loglevel.Network).Debugf("Update /etc/hosts of %s for alias %s with ip %s", parent.ID, alias, bridgeSettings.IPAddress)
 		sboxOptions = append(sboxOptions, libnetwork.OptionParentUpdate(
 			parent.ID,
 			alias,
@@ -350,6 +357,9 @@ func (daemon *Daemon) updateContainerNetworkSettings(container *container.Contai
 	if mode.IsUserDefined() {
 		n, err = daemon.FindNetwork(networkName)
 		if err != nil {
+			if isNoSuchNetworkError(err) {
+				return derr.NewRequestNotFoundError(fmt.Errorf("%v: create it with `docker network create`, or connect the container to another network with --network", err))
+			}
 			return err
 		}
 		if !container.Managed && n.Info().Dynamic() {
@@ -392,7 +402,7 @@ func (daemon *Daemon) allocateNetwork(container *container.Container) error {
 
 	// Cleanup any stale sandbox left over due to ungraceful daemon shutdown
 	if err := controller.SandboxDestroy(container.ID); err != nil {
-		logrus.Errorf("failed to cleanup up stale network sandbox for container %s", container.ID)
+		loglevel.WithSubsystem(loglevel.Network).Errorf("failed to cleanup up stale network sandbox for container %s", container.ID)
 	}
 
 	updateSettings := false
@@ -487,6 +497,40 @@ func validateNetworkingConfig(n libnetwork.Network, epConfig *networktypes.Endpo
 	return nil
 }
 
+// persistentMacAddressDrivers are the network drivers the MAC addresses of the
+// endpoints of the containers are kept for across restarts, like the DHCP
+// reservations of the hosts on a macvlan network need.
+var persistentMacAddressDrivers = map[string]bool{
+	"macvlan": true,
+}
+
+// verifyEndpointMacAddress checks that the MAC address of the endpoint of a
+// container on the network n is not used by another endpoint. A MAC address
+// other than the one the container was run with, like one persisted from a
+// previous run, is dropped instead, for the driver to generate another one.
+func (daemon *Daemon) verifyEndpointMacAddress(container *container.Container, n libnetwork.Network, endpointConfig *networktypes.EndpointSettings) error {
+	mac, err := container.EndpointMacAddress(n, endpointConfig)
+	if err != nil || mac == nil {
+		return err
+	}
+	endpointName := strings.TrimPrefix(container.Name, "/")
+	for _, ep := range n.Endpoints() {
+		if ep.Name() == endpointName || ep.Info() == nil || ep.Info().Iface() == nil {
+			continue
+		}
+		if !bytes.Equal(ep.Info().Iface().MacAddress(), mac) {
+			continue
+		}
+		if fixed, err := net.ParseMAC(container.Config.MacAddress); err != nil || !bytes.Equal(fixed, mac) {
+			loglevel.WithSubsystem(loglevel.Network).Warnf("The MAC address %s of container %s on network %s is used by %s, generating another one", mac, container.ID, n.Name(), ep.Name())
+			endpointConfig.MacAddress = ""
+			return nil
+		}
+		return fmt.Errorf("MAC address %s is already in use on network %s by %s", mac, n.Name(), ep.Name())
+	}
+	return nil
+}
+
 // cleanOperationalData resets the operational data from the passed endpoint settings
 func cleanOperationalData(es *networktypes.EndpointSettings) {
 	es.EndpointID = ""
@@ -562,6 +606,10 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 
 	controller := daemon.netController
 
+	if err := daemon.verifyEndpointMacAddress(container, n, endpointConfig); err != nil {
+		return err
+	}
+
 	sb := daemon.getNetworkSandbox(container)
 	createOptions, err := container.BuildCreateEndpointOptions(n, endpointConfig, sb)
 	if err != nil {
@@ -576,7 +624,7 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 	defer func() {
 		if err != nil {
 			if e := ep.Delete(false); e != nil {
-				logrus.Warnf("Could not rollback container connection to network %s", idOrName)
+				loglevel.WithSubsystem(loglevel.Network).Warnf("Could not rollback container connection to network %s", idOrName)
 			}
 		}
 	}()
@@ -614,10 +662,39 @@ func (daemon *Daemon) connectToNetwork(container *container.Container, idOrName
 
 	container.NetworkSettings.Ports = getPortMapInfo(sb)
 
-	daemon.LogNetworkEventWithAttributes(n, "connect", map[string]string{"container": container.ID})
+	daemon.updateICCRules(n)
+	daemon.updateDSCPMarks(container, n)
+	daemon.LogNetworkEventWithAttributes(n, "connect", endpointEventAttributes(container, container.NetworkSettings.Networks[n.Name()]))
 	return nil
 }
 
+// endpointEventAttributes returns the attributes of the connect and
+// disconnect events of a container, with the details of its endpoint on the
+// network if it has one.
+func endpointEventAttributes(container *container.Container, epSettings *networktypes.EndpointSettings) map[string]string {
+	attributes := map[string]string{
+		"container": container.ID,
+	}
+	if epSettings == nil {
+		return attributes
+	}
+	details := map[string]string{
+		"endpointID":  epSettings.EndpointID,
+		"ipv4Address": epSettings.IPAddress,
+		"ipv6Address": epSettings.GlobalIPv6Address,
+		"gateway":     epSettings.Gateway,
+		"ipv6Gateway": epSettings.IPv6Gateway,
+		"macAddress":  epSettings.MacAddress,
+		"aliases":     strings.Join(epSettings.Aliases, ","),
+	}
+	for k, v := range details {
+		if v != "" {
+			attributes[k] = v
+		}
+	}
+	return attributes
+}
+
 // ForceEndpointDelete deletes an endpoing from a network forcefully
 func (daemon *Daemon) ForceEndpointDelete(name string, n libnetwork.Network) error {
 	ep, err := n.EndpointByName(name)
@@ -693,7 +770,9 @@ func (daemon *Daemon) initializeNetworking(container *container.Container) error
 		return nil
 	}
 
-	if container.HostConfig.NetworkMode.IsHost() {
+	// the hostname of a container using the host's UTS namespace is the
+	// hostname of the host, in /etc/hostname and /etc/hosts too
+	if container.HostConfig.NetworkMode.IsHost() || container.HostConfig.UTSMode.IsHost() {
 		container.Config.Hostname, err = os.Hostname()
 		if err != nil {
 			return err
@@ -742,27 +821,33 @@ func (daemon *Daemon) releaseNetwork(container *container.Container) {
 	}
 
 	var networks []libnetwork.Network
+	var attributes []map[string]string
 	for n, epSettings := range settings {
-		if nw, err := daemon.FindNetwork(n); err == nil {
+		mac := epSettings.MacAddress
+		nw, err := daemon.FindNetwork(n)
+		if err == nil {
 			networks = append(networks, nw)
+			attributes = append(attributes, endpointEventAttributes(container, epSettings))
 		}
 		cleanOperationalData(epSettings)
+		if err == nil && persistentMacAddressDrivers[nw.Type()] {
+			epSettings.MacAddress = mac
+		}
 	}
 
 	sb, err := daemon.netController.SandboxByID(sid)
 	if err != nil {
-		logrus.Warnf("error locating sandbox id %s: %v", sid, err)
+		loglevel.WithSubsystem(loglevel.Network).Warnf("error locating sandbox id %s: %v", sid, err)
 		return
 	}
 
+	daemon.removeDSCPMarks(container, sb)
 	if err := sb.Delete(); err != nil {
-		logrus.Errorf("Error deleting sandbox id %s for container %s: %v", sid, container.ID, err)
+		loglevel.WithSubsystem(loglevel.Network).Errorf("Error deleting sandbox id %s for container %s: %v", sid, container.ID, err)
 	}
 
-	for _, nw := range networks {
-		attributes := map[string]string{
-			"container": container.ID,
-		}
-		daemon.LogNetworkEventWithAttributes(nw, "disconnect", attributes)
+	for i, nw := range networks {
+		daemon.updateICCRules(nw)
+		daemon.LogNetworkEventWithAttributes(nw, "disconnect", attributes[i])
 	}
 }
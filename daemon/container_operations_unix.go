@@ -4,6 +4,7 @@ package daemon
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -19,6 +20,7 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/runconfig"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
@@ -128,6 +130,7 @@ func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n li
 	if container.HostConfig.NetworkMode.IsHost() && containertypes.NetworkMode(n.Type()).IsHost() {
 		return runconfig.ErrConflictHostNetwork
 	}
+	attributes := endpointEventAttributes(container, container.NetworkSettings.Networks[n.Name()])
 	if !container.Running {
 		if container.RemovalInProgress || container.Dead {
 			return errRemovalContainer(container.ID)
@@ -147,8 +150,9 @@ func (daemon *Daemon) DisconnectFromNetwork(container *container.Container, n li
 		return fmt.Errorf("Error saving container to disk: %v", err)
 	}
 
-	attributes := map[string]string{
-		"container": container.ID,
+	daemon.updateICCRules(n)
+	if container.Running {
+		daemon.updateDSCPMarks(container, n)
 	}
 	daemon.LogNetworkEventWithAttributes(n, "disconnect", attributes)
 	return nil
@@ -163,6 +167,9 @@ func (daemon *Daemon) getIpcContainer(container *container.Container) (*containe
 	if !c.IsRunning() {
 		return nil, fmt.Errorf("cannot join IPC of a non running container: %s", containerID)
 	}
+	if c.HasPrivateIpc() {
+		return nil, fmt.Errorf("cannot join IPC of a container with a private IPC namespace: %s", containerID)
+	}
 	if c.IsRestarting() {
 		return nil, errContainerIsRestarting(container.ID)
 	}
@@ -203,6 +210,9 @@ func (daemon *Daemon) setupIpcDirs(c *container.Container) error {
 			return fmt.Errorf("/dev/shm is not mounted, but must be for --ipc=host")
 		}
 		c.ShmPath = "/dev/shm"
+	} else if c.HasPrivateIpc() {
+		// the shm tmpfs is mounted in the container only
+		c.ShmPath = ""
 	} else {
 		rootUID, rootGID := daemon.GetRemappedUIDGID()
 		if !c.HasMountFor("/dev/shm") {
@@ -321,6 +331,39 @@ func specDeviceCgroup(d *configs.Device) specs.DeviceCgroup {
 	}
 }
 
+// parseDeviceCgroupRule parses a rule of the devices cgroup, like
+// "c 189:* rmw", allowing the access to the devices of a type, a for all
+// the types, b or c, and of a major and a minor number, * for any.
+func parseDeviceCgroupRule(rule string) (specs.DeviceCgroup, error) {
+	invalid := fmt.Errorf("invalid device cgroup rule %q, use the format \"<type> <major>:<minor> <access>\", like \"c 189:* rmw\"", rule)
+	fields := strings.Split(rule, " ")
+	if len(fields) != 3 {
+		return specs.DeviceCgroup{}, invalid
+	}
+	t, numbers, access := fields[0], strings.Split(fields[1], ":"), fields[2]
+	if (t != "a" && t != "b" && t != "c") || len(numbers) != 2 || !runconfigopts.ValidDeviceMode(access) {
+		return specs.DeviceCgroup{}, invalid
+	}
+	var ids [2]*int64
+	for i, n := range numbers {
+		if n == "*" {
+			continue
+		}
+		id, err := strconv.ParseInt(n, 10, 64)
+		if err != nil || id < 0 {
+			return specs.DeviceCgroup{}, invalid
+		}
+		ids[i] = &id
+	}
+	return specs.DeviceCgroup{
+		Allow:  true,
+		Type:   &t,
+		Major:  ids[0],
+		Minor:  ids[1],
+		Access: &access,
+	}, nil
+}
+
 func getDevicesFromPath(deviceMapping containertypes.DeviceMapping) (devs []specs.Device, devPermissions []specs.DeviceCgroup, err error) {
 	resolvedPathOnHost := deviceMapping.PathOnHost
 
@@ -383,3 +426,63 @@ func isLinkable(child *container.Container) bool {
 func errRemovalContainer(containerID string) error {
 	return fmt.Errorf("Container %s is marked for removal and cannot be connected or disconnected to the network", containerID)
 }
+
+// setupSecrets writes the secrets of the container in a tmpfs, so that they
+// never reach its layers, and returns the mounts of their files.
+func (daemon *Daemon) setupSecrets(c *container.Container) ([]container.Mount, error) {
+	if len(c.HostConfig.Secrets) == 0 {
+		return nil, nil
+	}
+	secretsPath, err := c.SecretMountPath()
+	if err != nil {
+		return nil, err
+	}
+	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	if err := idtools.MkdirAllAs(secretsPath, 0700, rootUID, rootGID); err != nil {
+		return nil, err
+	}
+	if mounted, err := mount.Mounted(secretsPath); err != nil {
+		return nil, err
+	} else if !mounted {
+		tmpfsOptions := fmt.Sprintf("mode=0700,uid=%d,gid=%d", rootUID, rootGID)
+		if err := syscall.Mount("tmpfs", secretsPath, "tmpfs", uintptr(syscall.MS_NOEXEC|syscall.MS_NOSUID|syscall.MS_NODEV), label.FormatMountLabel(tmpfsOptions, c.GetMountLabel())); err != nil {
+			return nil, fmt.Errorf("mounting secrets tmpfs: %s", err)
+		}
+	}
+
+	var mounts []container.Mount
+	for i, ref := range c.HostConfig.Secrets {
+		s, err := daemon.secrets.Get(ref.Name)
+		if err != nil {
+			return nil, secretError(ref.Name, err)
+		}
+		fPath := filepath.Join(secretsPath, strconv.Itoa(i))
+		mode := ref.Mode
+		if mode == 0 {
+			mode = 0444
+		}
+		if err := ioutil.WriteFile(fPath, s.Data, mode); err != nil {
+			return nil, err
+		}
+		// WriteFile is subject to the umask of the daemon
+		if err := os.Chmod(fPath, mode); err != nil {
+			return nil, err
+		}
+		uid, err := idtools.ToHost(ref.UID, daemon.uidMaps)
+		if err != nil {
+			return nil, err
+		}
+		gid, err := idtools.ToHost(ref.GID, daemon.gidMaps)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chown(fPath, uid, gid); err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, container.Mount{
+			Source:      fPath,
+			Destination: secretTarget(ref),
+		})
+	}
+	return mounts, nil
+}
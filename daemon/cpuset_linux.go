@@ -0,0 +1,300 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/parsers"
+	"github.com/docker/docker/pkg/sysinfo"
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/specs/specs-go"
+)
+
+// cpusetDir returns the mountpoint of the cpuset hierarchy, and the
+// directory of the cgroup parent of the containers in it. A relative parent
+// is relative to the cgroup of the daemon. The directory is empty if the
+// hierarchy is not mounted.
+func cpusetDir(parent string) (string, string, error) {
+	mountpoint, root, err := cgroups.FindCgroupMountpointAndRoot("cpuset")
+	if err != nil {
+		return "", "", nil
+	}
+	if !filepath.IsAbs(parent) {
+		own, err := cgroups.GetThisCgroupDir("cpuset")
+		if err != nil {
+			return "", "", err
+		}
+		rel, err := filepath.Rel(root, own)
+		if err != nil {
+			return "", "", err
+		}
+		parent = filepath.Join(rel, parent)
+	}
+	return mountpoint, filepath.Join(mountpoint, parent), nil
+}
+
+// ensureCpuset creates the directories of the cpuset hierarchy from its
+// mountpoint to dir, and fills their cpuset.cpus and cpuset.mems if they are
+// empty with those of their parent: a process cannot join a cpuset without
+// CPUs or memory nodes.
+func ensureCpuset(mountpoint, dir string) error {
+	rel, err := filepath.Rel(mountpoint, dir)
+	if err != nil {
+		return err
+	}
+	current := mountpoint
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if name == "." || name == "" {
+			continue
+		}
+		parent := current
+		current = filepath.Join(current, name)
+		if err := os.Mkdir(current, 0755); err != nil && !os.IsExist(err) {
+			return err
+		}
+		if err := inheritCpuset(parent, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inheritCpuset fills the empty cpuset.cpus and cpuset.mems of dir with
+// those of its parent. The CPUs of the exclusive cpusets among the siblings
+// of dir are not inherited, the kernel refuses them.
+func inheritCpuset(parent, dir string) error {
+	for _, file := range []string{"cpuset.cpus", "cpuset.mems"} {
+		current, err := readCpusetFile(dir, file)
+		if err != nil || current != "" {
+			return err
+		}
+		value, err := readCpusetFile(parent, file)
+		if err != nil {
+			return err
+		}
+		if file == "cpuset.cpus" {
+			if value, err = withoutExclusiveCpus(parent, value, dir); err != nil {
+				return err
+			}
+		}
+		if err := writeCgroupFile("cpuset", dir, file, value); err != nil {
+			return fmt.Errorf("cannot inherit %s of %s: %v", file, parent, err)
+		}
+	}
+	return nil
+}
+
+// availableCpuset returns the CPUs and the memory nodes a container can use
+// in the cpuset dir: those of the nearest ancestor of dir, up to mountpoint,
+// having them, without the CPUs of the exclusive cpusets in dir.
+func availableCpuset(mountpoint, dir string) (string, string, error) {
+	var cpus, mems string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			var err error
+			if cpus == "" {
+				if cpus, err = readCpusetFile(d, "cpuset.cpus"); err != nil {
+					return "", "", err
+				}
+			}
+			if mems == "" {
+				if mems, err = readCpusetFile(d, "cpuset.mems"); err != nil {
+					return "", "", err
+				}
+			}
+		}
+		if (cpus != "" && mems != "") || d == mountpoint || d == filepath.Dir(d) {
+			break
+		}
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return cpus, mems, nil
+	}
+	cpus, err := withoutExclusiveCpus(dir, cpus, "")
+	return cpus, mems, err
+}
+
+// withoutExclusiveCpus returns the CPUs of the list cpus which are not in
+// the exclusive cpusets of the children of parent, but skip.
+func withoutExclusiveCpus(parent, cpus, skip string) (string, error) {
+	entries, err := ioutil.ReadDir(parent)
+	if err != nil {
+		return "", err
+	}
+	available, err := parsers.ParseUintList(cpus)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		child := filepath.Join(parent, e.Name())
+		if !e.IsDir() || child == skip {
+			continue
+		}
+		if exclusive, _ := readCpusetFile(child, "cpuset.cpu_exclusive"); exclusive != "1" {
+			continue
+		}
+		childCpus, err := readCpusetFile(child, "cpuset.cpus")
+		if err != nil {
+			return "", err
+		}
+		exclusiveCpus, err := parsers.ParseUintList(childCpus)
+		if err != nil {
+			return "", err
+		}
+		for cpu := range exclusiveCpus {
+			delete(available, cpu)
+		}
+	}
+	return formatUintList(available), nil
+}
+
+// cpusetMemsOf returns the memory nodes of the NUMA nodes of the list of
+// CPUs cpus, among the list of memory nodes available.
+func cpusetMemsOf(cpus string, nodes []sysinfo.NUMANode, available string) (string, error) {
+	requested, err := parsers.ParseUintList(cpus)
+	if err != nil {
+		return "", err
+	}
+	availableMems, err := parsers.ParseUintList(available)
+	if err != nil {
+		return "", err
+	}
+	mems := make(map[int]bool)
+	for _, n := range nodes {
+		if !availableMems[n.ID] {
+			continue
+		}
+		nodeCpus, err := parsers.ParseUintList(n.CPUs)
+		if err != nil {
+			return "", err
+		}
+		for cpu := range requested {
+			if nodeCpus[cpu] {
+				mems[n.ID] = true
+				break
+			}
+		}
+	}
+	return formatUintList(mems), nil
+}
+
+func readCpusetFile(dir, file string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// formatUintList formats a set of integers as a list like "0-3,8".
+func formatUintList(set map[int]bool) string {
+	var values []int
+	for v, ok := range set {
+		if ok {
+			values = append(values, v)
+		}
+	}
+	sort.Ints(values)
+	var ranges []string
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(values[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", values[i], values[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
+// cpusetAvailable returns whether all the CPUs or memory nodes of the list
+// provided are in the list available.
+func cpusetAvailable(provided, available string) (bool, error) {
+	p, err := parsers.ParseUintList(provided)
+	if err != nil {
+		return false, err
+	}
+	a, err := parsers.ParseUintList(available)
+	if err != nil {
+		return false, err
+	}
+	for v := range p {
+		if !a[v] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// verifyParentCpuset checks that the CPUs and the memory nodes of a
+// container are available in the cpuset of its cgroup parent, which may be
+// more restrictive than the host, with the cgroupfs driver.
+func (daemon *Daemon) verifyParentCpuset(hostConfig *containertypes.HostConfig) error {
+	if UsingSystemd(daemon.configStore) || (hostConfig.CpusetCpus == "" && hostConfig.CpusetMems == "") {
+		return nil
+	}
+	parent := daemon.cgroupParent(hostConfig)
+	mountpoint, dir, err := cpusetDir(parent)
+	if err != nil || dir == "" {
+		return err
+	}
+	cpus, mems, err := availableCpuset(mountpoint, dir)
+	if err != nil {
+		return err
+	}
+	if ok, err := cpusetAvailable(hostConfig.CpusetCpus, cpus); err != nil {
+		return fmt.Errorf("Invalid value %s for cpuset cpus", hostConfig.CpusetCpus)
+	} else if !ok {
+		return fmt.Errorf("Requested CPUs are not available in the cgroup parent %s - requested %s, available: %s", parent, hostConfig.CpusetCpus, cpus)
+	}
+	if ok, err := cpusetAvailable(hostConfig.CpusetMems, mems); err != nil {
+		return fmt.Errorf("Invalid value %s for cpuset mems", hostConfig.CpusetMems)
+	} else if !ok {
+		return fmt.Errorf("Requested memory nodes are not available in the cgroup parent %s - requested %s, available: %s", parent, hostConfig.CpusetMems, mems)
+	}
+	return nil
+}
+
+// setCpuset prepares the cpuset of the cgroup parent of a container with the
+// cgroupfs driver and, on a NUMA host, defaults the memory nodes of a
+// container with CPUs but without memory nodes to the nodes of its CPUs.
+func (daemon *Daemon) setCpuset(s *specs.Spec, c *container.Container, parent string, systemd bool) error {
+	mountpoint, dir, err := cpusetDir(parent)
+	if err != nil || dir == "" {
+		return err
+	}
+	if systemd {
+		dir = mountpoint
+	} else if err := ensureCpuset(mountpoint, dir); err != nil {
+		return err
+	}
+
+	if c.HostConfig.CpusetCpus == "" || c.HostConfig.CpusetMems != "" {
+		return nil
+	}
+	nodes, err := sysinfo.NUMANodes()
+	if err != nil || len(nodes) < 2 {
+		return err
+	}
+	_, available, err := availableCpuset(mountpoint, dir)
+	if err != nil {
+		return err
+	}
+	mems, err := cpusetMemsOf(c.HostConfig.CpusetCpus, nodes, available)
+	if err != nil || mems == "" {
+		return err
+	}
+	s.Linux.Resources.CPU.Mems = &mems
+	return nil
+}
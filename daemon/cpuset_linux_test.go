@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/sysinfo"
+)
+
+func writeCpuset(t *testing.T, dir string, files map[string]string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEnsureCpuset(t *testing.T) {
+	root, err := ioutil.TempDir("", "test-cpuset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	writeCpuset(t, root, map[string]string{"cpuset.cpus": "0-7", "cpuset.mems": "0-1"})
+	writeCpuset(t, filepath.Join(root, "isolated"), map[string]string{"cpuset.cpus": "6-7", "cpuset.mems": "1", "cpuset.cpu_exclusive": "1"})
+	// The kernel creates the files of a new cpuset empty.
+	writeCpuset(t, filepath.Join(root, "docker"), map[string]string{"cpuset.cpus": "", "cpuset.mems": ""})
+	writeCpuset(t, filepath.Join(root, "docker", "parent"), map[string]string{"cpuset.cpus": "", "cpuset.mems": "", "cpuset.cpu_exclusive": "0"})
+
+	if err := ensureCpuset(root, filepath.Join(root, "docker", "parent")); err != nil {
+		t.Fatal(err)
+	}
+	for dir, expected := range map[string][2]string{
+		"docker":        {"0-5", "0-1"},
+		"docker/parent": {"0-5", "0-1"},
+	} {
+		cpus, _ := readCpusetFile(filepath.Join(root, dir), "cpuset.cpus")
+		mems, _ := readCpusetFile(filepath.Join(root, dir), "cpuset.mems")
+		if cpus != expected[0] || mems != expected[1] {
+			t.Fatalf("expected %s to inherit %v, got %s and %s", dir, expected, cpus, mems)
+		}
+	}
+
+	cpus, mems, err := availableCpuset(root, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpus != "0-5" || mems != "0-1" {
+		t.Fatalf("expected the CPUs of the exclusive cpuset to be unavailable, got %s and %s", cpus, mems)
+	}
+	// A missing cpuset would inherit the cpuset of its parent.
+	cpus, mems, err = availableCpuset(root, filepath.Join(root, "missing", "parent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpus != "0-7" || mems != "0-1" {
+		t.Fatalf("expected the cpuset of the root, got %s and %s", cpus, mems)
+	}
+}
+
+func TestCpusetMemsOf(t *testing.T) {
+	nodes := []sysinfo.NUMANode{{ID: 0, CPUs: "0-3,8-11"}, {ID: 1, CPUs: "4-7,12-15"}}
+	for _, c := range []struct {
+		cpus, available, expected string
+	}{
+		{"8-9", "0-1", "0"},
+		{"3-4", "0-1", "0-1"},
+		{"12", "0-1", "1"},
+		{"3-4", "1", "1"},
+		{"16", "0-1", ""},
+	} {
+		mems, err := cpusetMemsOf(c.cpus, nodes, c.available)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mems != c.expected {
+			t.Fatalf("expected memory nodes %q for CPUs %s, got %q", c.expected, c.cpus, mems)
+		}
+	}
+}
+
+func TestFormatUintList(t *testing.T) {
+	for expected, set := range map[string]map[int]bool{
+		"":         {},
+		"3":        {3: true},
+		"0-3,8,10": {0: true, 1: true, 2: true, 3: true, 8: true, 10: true},
+		"1-2":      {1: true, 2: true, 5: false},
+	} {
+		if s := formatUintList(set); s != expected {
+			t.Fatalf("expected %q, got %q", expected, s)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package daemon
+
+import containertypes "github.com/docker/engine-api/types/container"
+
+// verifyParentCpuset does nothing: the cpusets of the cgroup parents are only
+// verified on Linux.
+func (daemon *Daemon) verifyParentCpuset(hostConfig *containertypes.HostConfig) error {
+	return nil
+}
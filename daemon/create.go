@@ -6,6 +6,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/hooks"
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -39,6 +40,12 @@ func (daemon *Daemon) containerCreate(params types.ContainerCreateConfig, manage
 		return types.ContainerCreateResponse{Warnings: warnings}, err
 	}
 
+	archWarnings, err := daemon.verifyArchitecture(params.Config)
+	warnings = append(warnings, archWarnings...)
+	if err != nil {
+		return types.ContainerCreateResponse{Warnings: warnings}, err
+	}
+
 	err = daemon.verifyNetworkingConfig(params.NetworkingConfig)
 	if err != nil {
 		return types.ContainerCreateResponse{}, err
@@ -47,11 +54,18 @@ func (daemon *Daemon) containerCreate(params types.ContainerCreateConfig, manage
 	if params.HostConfig == nil {
 		params.HostConfig = &containertypes.HostConfig{}
 	}
+	if err := daemon.setDefaultNetwork(&params); err != nil {
+		return types.ContainerCreateResponse{Warnings: warnings}, err
+	}
 	err = daemon.adaptContainerSettings(params.HostConfig, params.AdjustCPUShares)
 	if err != nil {
 		return types.ContainerCreateResponse{Warnings: warnings}, err
 	}
 
+	if err := daemon.hooks.Run(hooks.PreCreate, params); err != nil {
+		return types.ContainerCreateResponse{Warnings: warnings}, err
+	}
+
 	container, err := daemon.create(params, managed)
 	if err != nil {
 		return types.ContainerCreateResponse{Warnings: warnings}, daemon.imageNotExistToErrcode(err)
@@ -81,10 +95,16 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 		return nil, err
 	}
 
+	adaptSystemdSettings(params.Config, params.HostConfig)
+
 	if err := daemon.mergeAndVerifyLogConfig(&params.HostConfig.LogConfig); err != nil {
 		return nil, err
 	}
 
+	if err := daemon.resolveRequires(params.HostConfig); err != nil {
+		return nil, err
+	}
+
 	if container, err = daemon.newContainer(params.Name, params.Config, imgID, managed); err != nil {
 		return nil, err
 	}
@@ -258,3 +278,49 @@ func (daemon *Daemon) verifyNetworkingConfig(nwConfig *networktypes.NetworkingCo
 	err := fmt.Errorf("Container cannot be connected to network endpoints: %s", strings.Join(l, ", "))
 	return errors.NewBadRequestError(err)
 }
+
+// setDefaultNetwork connects a container run without a network to the
+// default network of the daemon, if one is configured. The endpoint settings
+// given for the built-in default network are moved to it, and the links are
+// set on the endpoint as the default network is a user-defined one.
+func (daemon *Daemon) setDefaultNetwork(params *types.ContainerCreateConfig) error {
+	name := daemon.configStore.DefaultNetwork
+	hostConfig := params.HostConfig
+	mode := hostConfig.NetworkMode
+	if name == "" || (mode != "" && !mode.IsDefault()) {
+		return nil
+	}
+	defaultMode := containertypes.NetworkMode(name)
+	if defaultMode.IsUserDefined() {
+		if _, err := daemon.FindNetwork(name); err != nil {
+			if isNoSuchNetworkError(err) {
+				err = fmt.Errorf("default network %s of the daemon not found: create it with `docker network create`, or connect the container to another network with --network", name)
+				return errors.NewRequestNotFoundError(err)
+			}
+			return err
+		}
+	}
+	hostConfig.NetworkMode = defaultMode
+
+	if params.NetworkingConfig == nil {
+		params.NetworkingConfig = &networktypes.NetworkingConfig{}
+	}
+	nwConfig := params.NetworkingConfig
+	epConfig, ok := nwConfig.EndpointsConfig[string(mode)]
+	if ok {
+		delete(nwConfig.EndpointsConfig, string(mode))
+	}
+	if defaultMode.IsUserDefined() && len(hostConfig.Links) > 0 {
+		if epConfig == nil {
+			epConfig = &networktypes.EndpointSettings{}
+		}
+		epConfig.Links = append([]string{}, hostConfig.Links...)
+	}
+	if epConfig != nil {
+		if nwConfig.EndpointsConfig == nil {
+			nwConfig.EndpointsConfig = make(map[string]*networktypes.EndpointSettings)
+		}
+		nwConfig.EndpointsConfig[name] = epConfig
+	}
+	return nil
+}
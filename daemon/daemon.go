@@ -15,6 +15,8 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -26,17 +28,20 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/events"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/daemon/hooks"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
 	"github.com/docker/libnetwork/cluster"
 	// register graph drivers
 	_ "github.com/docker/docker/daemon/graphdriver/register"
+	"github.com/docker/docker/distribution"
 	dmetadata "github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/migrate/v1"
+	"github.com/docker/docker/pkg/envmask"
 	"github.com/docker/docker/pkg/fileutils"
 	"github.com/docker/docker/pkg/graphdb"
 	"github.com/docker/docker/pkg/idtools"
@@ -50,6 +55,7 @@ import (
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
+	"github.com/docker/docker/secret"
 	"github.com/docker/docker/utils"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
@@ -86,9 +92,16 @@ type Daemon struct {
 	EventsService             *events.Events
 	netController             libnetwork.NetworkController
 	volumes                   *store.VolumeStore
+	secrets                   *secret.Store
+	envMasker                 *envmask.Masker
+	hooks                     *hooks.Hooks
+	cgroupLeaks               *cgroupLeakScanner
+	layerCache                distribution.LayerCache
+	groupsLock                sync.Mutex
 	discoveryWatcher          discoveryReloader
 	root                      string
 	seccompEnabled            bool
+	seccompProfile            []byte
 	shutdown                  bool
 	uidMaps                   []idtools.IDMap
 	gidMaps                   []idtools.IDMap
@@ -272,6 +285,19 @@ func (daemon *Daemon) restore() error {
 				}
 			}
 
+			// wait for the containers it requires to be started, and
+			// to be healthy if they have a healthcheck
+			for _, id := range c.HostConfig.Requires {
+				r, err := daemon.GetContainer(id)
+				if err != nil {
+					continue
+				}
+				if notifier, exists := restartContainers[r]; exists {
+					<-notifier
+				}
+				waitForHealth(r, requiresHealthTimeout)
+			}
+
 			// Make sure networks are available before starting
 			daemon.waitForNetworks(c)
 			if err := daemon.containerStart(c); err != nil {
@@ -526,6 +552,16 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 		return nil, err
 	}
 
+	secretStore, err := secret.NewStore(filepath.Join(config.Root, "secrets"))
+	if err != nil {
+		return nil, err
+	}
+
+	envMasker, err := envmask.New(config.SecretEnvPatterns)
+	if err != nil {
+		return nil, err
+	}
+
 	trustKey, err := api.LoadOrCreateTrustKey(config.TrustKeyPath)
 	if err != nil {
 		return nil, err
@@ -543,6 +579,13 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	}
 
 	eventsService := events.New()
+	journal, err := openEventsJournal(config)
+	if err != nil {
+		return nil, err
+	}
+	if journal != nil {
+		eventsService.SetJournal(journal)
+	}
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
 	if err != nil {
@@ -577,6 +620,9 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	d.trustKey = trustKey
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
+	if err := d.startStatsHistory(config); err != nil {
+		return nil, err
+	}
 	d.defaultLogConfig = containertypes.LogConfig{
 		Type:   config.LogConfig.Type,
 		Config: config.LogConfig.Config,
@@ -584,10 +630,16 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	d.RegistryService = registryService
 	d.EventsService = eventsService
 	d.volumes = volStore
+	d.secrets = secretStore
+	d.envMasker = envMasker
+	d.hooks = hooks.New(config.HooksDir, config.HookPlugins)
 	d.root = config.Root
 	d.uidMaps = uidMaps
 	d.gidMaps = gidMaps
 	d.seccompEnabled = sysInfo.Seccomp
+	if err := d.setupSeccompProfile(); err != nil {
+		return nil, err
+	}
 
 	d.nameIndex = registrar.NewRegistrar()
 	d.linkIndex = newLinkIndex()
@@ -603,29 +655,78 @@ func NewDaemon(config *Config, registryService registry.Service, containerdRemot
 	if err := d.restore(); err != nil {
 		return nil, err
 	}
+	if err := d.startCgroupLeakScanner(config); err != nil {
+		return nil, err
+	}
+	if err := d.startLayerCache(config); err != nil {
+		return nil, err
+	}
 
 	return d, nil
 }
 
-func (daemon *Daemon) shutdownContainer(c *container.Container) error {
+// shutdownPriorityLabel is the label ordering the containers stopped when the
+// daemon shuts down. The containers are stopped by increasing priority, those
+// of the same priority in parallel. The priority of the containers without the
+// label is 0.
+const shutdownPriorityLabel = "com.docker.shutdown-priority"
+
+// shutdownGroups returns the running containers, grouped by shutdown
+// priority, in the order the groups are stopped.
+func shutdownGroups(containers []*container.Container) [][]*container.Container {
+	byPriority := make(map[int][]*container.Container)
+	var priorities []int
+	for _, c := range containers {
+		if !c.IsRunning() {
+			continue
+		}
+		priority := 0
+		if value, ok := c.Config.Labels[shutdownPriorityLabel]; ok {
+			p, err := strconv.Atoi(value)
+			if err != nil {
+				logrus.Warnf("Invalid %s label %q of container %s, using 0", shutdownPriorityLabel, value, c.ID)
+			}
+			priority = p
+		}
+		if _, ok := byPriority[priority]; !ok {
+			priorities = append(priorities, priority)
+		}
+		byPriority[priority] = append(byPriority[priority], c)
+	}
+	sort.Ints(priorities)
+
+	groups := make([][]*container.Container, 0, len(priorities))
+	for _, p := range priorities {
+		groups = append(groups, byPriority[p])
+	}
+	return groups
+}
+
+// ShutdownTimeout returns the time, in seconds, to wait for Shutdown: the time
+// given to the containers to stop, and to clean up.
+func (daemon *Daemon) ShutdownTimeout() int {
+	daemon.configStore.reloadLock.Lock()
+	defer daemon.configStore.reloadLock.Unlock()
+	return daemon.configStore.ShutdownTimeout + 5
+}
+
+// shutdownContainer stops a container, sending it its stop signal then
+// killing it if it fails to exit in seconds.
+func (daemon *Daemon) shutdownContainer(c *container.Container, seconds int) error {
 	// TODO(windows): Handle docker restart with paused containers
 	if c.IsPaused() {
 		// To terminate a process in freezer cgroup, we should send
-		// SIGTERM to this process then unfreeze it, and the process will
-		// force to terminate immediately.
-		logrus.Debugf("Found container %s is paused, sending SIGTERM before unpausing it", c.ID)
-		sig, ok := signal.SignalMap["TERM"]
-		if !ok {
-			return fmt.Errorf("System does not support SIGTERM")
-		}
-		if err := daemon.kill(c, int(sig)); err != nil {
-			return fmt.Errorf("sending SIGTERM to container %s with error: %v", c.ID, err)
+		// the stop signal to this process then unfreeze it, and the
+		// process will force to terminate immediately.
+		logrus.Debugf("Found container %s is paused, sending its stop signal before unpausing it", c.ID)
+		if err := daemon.kill(c, c.StopSignal()); err != nil {
+			return fmt.Errorf("sending signal %d to container %s with error: %v", c.StopSignal(), c.ID, err)
 		}
 		if err := daemon.containerUnpause(c); err != nil {
 			return fmt.Errorf("Failed to unpause container %s with error: %v", c.ID, err)
 		}
-		if _, err := c.WaitStop(10 * time.Second); err != nil {
-			logrus.Debugf("container %s failed to exit in 10 seconds of SIGTERM, sending SIGKILL to force", c.ID)
+		if _, err := c.WaitStop(time.Duration(seconds) * time.Second); err != nil {
+			logrus.Debugf("container %s failed to exit in %d seconds of its stop signal, sending SIGKILL to force", c.ID, seconds)
 			sig, ok := signal.SignalMap["KILL"]
 			if !ok {
 				return fmt.Errorf("System does not support SIGKILL")
@@ -637,8 +738,8 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 			return err
 		}
 	}
-	// If container failed to exit in 10 seconds of SIGTERM, then using the force
-	if err := daemon.containerStop(c, 10); err != nil {
+	// If container failed to exit in seconds of its stop signal, then using the force
+	if err := daemon.containerStop(c, seconds); err != nil {
 		return fmt.Errorf("Failed to stop container %s with error: %v", c.ID, err)
 	}
 
@@ -660,20 +761,33 @@ func (daemon *Daemon) Shutdown() error {
 
 	if daemon.containers != nil {
 		logrus.Debug("starting clean shutdown of all containers...")
-		daemon.containers.ApplyAll(func(c *container.Container) {
-			if !c.IsRunning() {
-				return
+		groups := shutdownGroups(daemon.containers.List())
+		deadline := time.Now().Add(time.Duration(daemon.configStore.ShutdownTimeout) * time.Second)
+		for i, group := range groups {
+			// The groups left share the time left, so that a group gets
+			// the time not used by the groups stopped before it.
+			seconds := int(deadline.Sub(time.Now()).Seconds()) / (len(groups) - i)
+			if seconds < 0 {
+				seconds = 0
 			}
-			logrus.Debugf("stopping %s", c.ID)
-			if err := daemon.shutdownContainer(c); err != nil {
-				logrus.Errorf("Stop container error: %v", err)
-				return
-			}
-			if mountid, err := daemon.layerStore.GetMountID(c.ID); err == nil {
-				daemon.cleanupMountsByID(mountid)
+			var wg sync.WaitGroup
+			for _, c := range group {
+				wg.Add(1)
+				go func(c *container.Container) {
+					defer wg.Done()
+					logrus.Debugf("stopping %s", c.ID)
+					if err := daemon.shutdownContainer(c, seconds); err != nil {
+						logrus.Errorf("Stop container error: %v", err)
+						return
+					}
+					if mountid, err := daemon.layerStore.GetMountID(c.ID); err == nil {
+						daemon.cleanupMountsByID(mountid)
+					}
+					logrus.Debugf("container stopped %s", c.ID)
+				}(c)
 			}
-			logrus.Debugf("container stopped %s", c.ID)
-		})
+			wg.Wait()
+		}
 	}
 
 	// trigger libnetwork Stop only if it's initialized
@@ -842,6 +956,7 @@ func (daemon *Daemon) initDiscovery(config *Config) error {
 // These are the settings that Reload changes:
 // - Daemon labels.
 // - Daemon debug log level.
+// - Daemon log levels of the subsystems.
 // - Daemon max concurrent downloads
 // - Daemon max concurrent uploads
 // - Cluster discovery (reconfigure and restart).
@@ -874,6 +989,15 @@ func (daemon *Daemon) Reload(config *Config) error {
 	if config.IsValueSet("debug") {
 		daemon.configStore.Debug = config.Debug
 	}
+	if config.IsValueSet("log-level") {
+		daemon.configStore.LogLevel = config.LogLevel
+	}
+	if config.IsValueSet("log-levels") {
+		daemon.configStore.LogLevels = config.LogLevels
+	}
+	if config.IsValueSet("shutdown-timeout") {
+		daemon.configStore.ShutdownTimeout = config.ShutdownTimeout
+	}
 	if config.IsValueSet("live-restore") {
 		daemon.configStore.LiveRestore = config.LiveRestore
 		if err := daemon.containerdRemote.UpdateOptions(libcontainerd.WithLiveRestore(config.LiveRestore)); err != nil {
@@ -908,6 +1032,22 @@ func (daemon *Daemon) Reload(config *Config) error {
 		daemon.uploadManager.SetConcurrency(*daemon.configStore.MaxConcurrentUploads)
 	}
 
+	// The registries can only be configured in the configuration file, so
+	// they are always reset.
+	daemon.configStore.Registries = config.Registries
+	if daemon.RegistryService != nil {
+		daemon.RegistryService.ReloadRegistries(config.Registries)
+	}
+
+	if config.IsValueSet("registry-mirrors") {
+		daemon.configStore.Mirrors = config.Mirrors
+		if daemon.RegistryService != nil {
+			if err = daemon.RegistryService.ReloadMirrors(config.Mirrors); err != nil {
+				return err
+			}
+		}
+	}
+
 	// We emit daemon reload event here with updatable configurations
 	attributes["debug"] = fmt.Sprintf("%t", daemon.configStore.Debug)
 	attributes["cluster-store"] = daemon.configStore.ClusterStore
@@ -924,6 +1064,26 @@ func (daemon *Daemon) Reload(config *Config) error {
 	} else {
 		attributes["labels"] = "[]"
 	}
+	attributes["log-level"] = daemon.configStore.LogLevel
+	if daemon.configStore.LogLevels != nil {
+		logLevels, _ := json.Marshal(daemon.configStore.LogLevels)
+		attributes["log-levels"] = string(logLevels)
+	} else {
+		attributes["log-levels"] = "{}"
+	}
+	attributes["shutdown-timeout"] = fmt.Sprintf("%d", daemon.configStore.ShutdownTimeout)
+	if daemon.configStore.Mirrors != nil {
+		mirrors, _ := json.Marshal(daemon.configStore.Mirrors)
+		attributes["registry-mirrors"] = string(mirrors)
+	} else {
+		attributes["registry-mirrors"] = "[]"
+	}
+	if daemon.configStore.Registries != nil {
+		registries, _ := json.Marshal(daemon.configStore.Registries)
+		attributes["registries"] = string(registries)
+	} else {
+		attributes["registries"] = "{}"
+	}
 	attributes["max-concurrent-downloads"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentDownloads)
 	attributes["max-concurrent-uploads"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentUploads)
 
@@ -13,6 +13,7 @@ import (
 	_ "github.com/docker/docker/pkg/discovery/memory"
 	"github.com/docker/docker/pkg/registrar"
 	"github.com/docker/docker/pkg/truncindex"
+	"github.com/docker/docker/registry"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
@@ -335,6 +336,43 @@ func TestDaemonReloadLabels(t *testing.T) {
 	}
 }
 
+func TestDaemonReloadMirrorsAndLogLevel(t *testing.T) {
+	daemon := &Daemon{}
+	daemon.configStore = &Config{
+		CommonConfig: CommonConfig{
+			LogLevel: "info",
+			ServiceOptions: registry.ServiceOptions{
+				Mirrors: []string{"https://mirror.example.com/"},
+			},
+		},
+	}
+	daemon.RegistryService = registry.NewService(daemon.configStore.ServiceOptions)
+
+	valuesSets := make(map[string]interface{})
+	valuesSets["registry-mirrors"] = []string{"https://new-mirror.example.com"}
+	valuesSets["log-level"] = "warn"
+	newConfig := &Config{
+		CommonConfig: CommonConfig{
+			LogLevel: "warn",
+			ServiceOptions: registry.ServiceOptions{
+				Mirrors: []string{"https://new-mirror.example.com"},
+			},
+			valuesSet: valuesSets,
+		},
+	}
+
+	if err := daemon.Reload(newConfig); err != nil {
+		t.Fatal(err)
+	}
+	if daemon.configStore.LogLevel != "warn" {
+		t.Fatalf("Expected log level `warn`, got %s", daemon.configStore.LogLevel)
+	}
+	mirrors := daemon.RegistryService.ServiceConfig().Mirrors
+	if len(mirrors) != 1 || mirrors[0] != "https://new-mirror.example.com/" {
+		t.Fatalf("Expected the mirrors of the registry service to be reloaded, got %v", mirrors)
+	}
+}
+
 func TestDaemonReloadNotAffectOthers(t *testing.T) {
 	daemon := &Daemon{}
 	daemon.configStore = &Config{
@@ -530,3 +568,37 @@ func TestDaemonDiscoveryReloadOnlyClusterAdvertise(t *testing.T) {
 	}
 
 }
+
+func TestShutdownGroups(t *testing.T) {
+	newContainer := func(id string, running bool, labels map[string]string) *container.Container {
+		state := container.NewState()
+		state.Running = running
+		return &container.Container{
+			CommonContainer: container.CommonContainer{
+				ID:     id,
+				State:  state,
+				Config: &containertypes.Config{Labels: labels},
+			},
+		}
+	}
+	containers := []*container.Container{
+		newContainer("db", true, map[string]string{shutdownPriorityLabel: "10"}),
+		newContainer("web1", true, nil),
+		newContainer("stopped", false, nil),
+		newContainer("proxy", true, map[string]string{shutdownPriorityLabel: "-1"}),
+		newContainer("web2", true, map[string]string{shutdownPriorityLabel: "invalid"}),
+	}
+
+	var ids [][]string
+	for _, group := range shutdownGroups(containers) {
+		var groupIDs []string
+		for _, c := range group {
+			groupIDs = append(groupIDs, c.ID)
+		}
+		ids = append(ids, groupIDs)
+	}
+	expected := [][]string{{"proxy"}, {"web1", "web2"}, {"db"}}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected the shutdown groups %v, got %v", expected, ids)
+	}
+}
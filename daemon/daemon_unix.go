@@ -4,6 +4,7 @@ package daemon
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -18,7 +19,10 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/caps"
+	"github.com/docker/docker/daemon/icc"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/parsers/kernel"
@@ -449,13 +453,50 @@ func getCD(config *Config) string {
 	return ""
 }
 
-// VerifyCgroupDriver validates native.cgroupdriver
+// VerifyCgroupDriver validates native.cgroupdriver, and that the runtimes
+// manage the cgroups with the same driver as the daemon.
 func VerifyCgroupDriver(config *Config) error {
 	cd := getCD(config)
-	if cd == "" || cd == cgroupFsDriver || cd == cgroupSystemdDriver {
-		return nil
+	if cd != "" && cd != cgroupFsDriver && cd != cgroupSystemdDriver {
+		return fmt.Errorf("native.cgroupdriver option %s not supported", cd)
+	}
+	for _, option := range config.ExecOptions {
+		key, val, err := parsers.ParseKeyValueOpt(option)
+		if err == nil && strings.EqualFold(key, "native.cgroupdriver") && val != cd {
+			return fmt.Errorf("conflicting native.cgroupdriver options %s and %s", cd, val)
+		}
 	}
-	return fmt.Errorf("native.cgroupdriver option %s not supported", cd)
+	return verifyRuntimesCgroupDriver(config.Runtimes, UsingSystemd(config))
+}
+
+// verifyRuntimesCgroupDriver checks that none of runtimes is configured to
+// manage the cgroups with another driver than the daemon: the cgroups of
+// their containers would not be where the daemon, and the supervisors of
+// the host, expect them.
+func verifyRuntimesCgroupDriver(runtimes map[string]types.Runtime, systemd bool) error {
+	for name, rt := range runtimes {
+		if name == stockRuntimeName {
+			continue
+		}
+		for _, arg := range rt.Args {
+			var runtimeSystemd bool
+			switch arg {
+			case "--systemd-cgroup", "--systemd-cgroup=true":
+				runtimeSystemd = true
+			case "--systemd-cgroup=false":
+			default:
+				continue
+			}
+			if runtimeSystemd != systemd {
+				driver := cgroupFsDriver
+				if systemd {
+					driver = cgroupSystemdDriver
+				}
+				return fmt.Errorf("runtime %s is configured with %s, which conflicts with the %s cgroup driver of the daemon", name, arg, driver)
+			}
+		}
+	}
+	return nil
 }
 
 // UsingSystemd returns true if cli option includes native.cgroupdriver=systemd
@@ -465,6 +506,30 @@ func UsingSystemd(config *Config) bool {
 
 // verifyPlatformContainerSettings performs platform-specific validation of the
 // hostconfig and config structures.
+// verifySysctls checks that the sysctls of a container are whitelisted, and
+// that they are namespaced by a namespace of the container rather than one of
+// the host.
+func verifySysctls(hostConfig *containertypes.HostConfig) error {
+	for key, value := range hostConfig.Sysctls {
+		if _, err := opts.ValidateSysctl(key + "=" + value); err != nil {
+			return err
+		}
+		if strings.HasPrefix(key, "net.") {
+			if hostConfig.NetworkMode.IsHost() {
+				return fmt.Errorf("sysctl '%s' cannot be set in the host's network namespace", key)
+			}
+			if hostConfig.NetworkMode.IsContainer() {
+				return fmt.Errorf("sysctl '%s' cannot be set in the network namespace of another container", key)
+			}
+		} else if hostConfig.IpcMode.IsHost() {
+			return fmt.Errorf("sysctl '%s' cannot be set in the host's IPC namespace", key)
+		} else if hostConfig.IpcMode.IsContainer() {
+			return fmt.Errorf("sysctl '%s' cannot be set in the IPC namespace of another container", key)
+		}
+	}
+	return nil
+}
+
 func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.HostConfig, config *containertypes.Config, update bool) ([]string, error) {
 	warnings := []string{}
 	sysInfo := sysinfo.New(true)
@@ -474,11 +539,24 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, err
 	}
 
+	if daemon.configStore.CgroupDryRun {
+		if err := daemon.verifyCgroupDryRun(&hostConfig.Resources, sysInfo); err != nil {
+			return warnings, err
+		}
+	}
 	w, err := verifyContainerResources(&hostConfig.Resources, sysInfo, update)
 	if err != nil {
 		return warnings, err
 	}
 	warnings = append(warnings, w...)
+	if err := daemon.verifyParentCpuset(hostConfig); err != nil {
+		return warnings, err
+	}
+	for _, rule := range hostConfig.DeviceCgroupRules {
+		if _, err := parseDeviceCgroupRule(rule); err != nil {
+			return warnings, err
+		}
+	}
 
 	if hostConfig.ShmSize < 0 {
 		return warnings, fmt.Errorf("SHM size must be greater than 0")
@@ -488,6 +566,19 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, fmt.Errorf("Invalid value %d, range for oom score adj is [-1000, 1000]", hostConfig.OomScoreAdj)
 	}
 
+	if err := verifySysctls(hostConfig); err != nil {
+		return warnings, err
+	}
+
+	if hostConfig.DSCP != nil {
+		if *hostConfig.DSCP < 0 || *hostConfig.DSCP > 63 {
+			return warnings, fmt.Errorf("Invalid value %d, range for DSCP is [0, 63]", *hostConfig.DSCP)
+		}
+		if hostConfig.NetworkMode.IsContainer() {
+			return warnings, fmt.Errorf("Cannot set the DSCP mark of a container sharing the network namespace of another container")
+		}
+	}
+
 	// ip-forwarding does not affect container with '--net=host' (or '--net=none')
 	if sysInfo.IPv4ForwardingDisabled && !(hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsNone()) {
 		warnings = append(warnings, "IPv4 forwarding is disabled. Networking will not work.")
@@ -514,6 +605,16 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 			return warnings, fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+	if !hostConfig.Systemd.Valid() {
+		return warnings, fmt.Errorf("invalid systemd mode %q, use true, false or always", hostConfig.Systemd)
+	}
+	if hostConfig.CgroupDelegate && UsingSystemd(daemon.configStore) && !systemdSupports("Delegate") {
+		// systemd only lets the container manage the subtree of its scope
+		// if the scope is created with Delegate=yes, the container is
+		// still started without it
+		warnings = append(warnings, "Your systemd does not support the Delegate property, the scope of the container is not delegated.")
+		logrus.Warn("Your systemd does not support the Delegate property, the scope of the container is not delegated.")
+	}
 	if hostConfig.Runtime == "" {
 		hostConfig.Runtime = daemon.configStore.GetDefaultRuntimeName()
 	}
@@ -527,16 +628,31 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 
 // platformReload update configuration with platform specific options
 func (daemon *Daemon) platformReload(config *Config, attributes *map[string]string) {
+	// the cgroup hierarchies mounted since the daemon started are seen
+	// after a reload
+	sysinfo.InvalidateCgroupLayout()
+
 	if config.IsValueSet("runtimes") {
-		daemon.configStore.Runtimes = config.Runtimes
-		// Always set the default one
-		daemon.configStore.Runtimes[stockRuntimeName] = types.Runtime{Path: DefaultRuntimeBinary}
+		if err := verifyRuntimesCgroupDriver(config.Runtimes, UsingSystemd(daemon.configStore)); err != nil {
+			logrus.Errorf("Not reloading the runtimes: %v", err)
+		} else {
+			// Always set the default one, with the arguments of the
+			// cgroup driver of the daemon
+			config.Runtimes[stockRuntimeName] = daemon.configStore.Runtimes[stockRuntimeName]
+			daemon.configStore.Runtimes = config.Runtimes
+		}
 	}
 
 	if config.DefaultRuntime != "" {
 		daemon.configStore.DefaultRuntime = config.DefaultRuntime
 	}
 
+	// The default ulimits are applied when the containers are started, so
+	// the running containers keep their ulimits.
+	if config.IsValueSet("default-ulimits") {
+		daemon.configStore.Ulimits = config.Ulimits
+	}
+
 	// Update attributes
 	var runtimeList bytes.Buffer
 	for name, rt := range daemon.configStore.Runtimes {
@@ -548,6 +664,8 @@ func (daemon *Daemon) platformReload(config *Config, attributes *map[string]stri
 
 	(*attributes)["runtimes"] = runtimeList.String()
 	(*attributes)["default-runtime"] = daemon.configStore.DefaultRuntime
+	ulimits, _ := json.Marshal(daemon.configStore.Ulimits)
+	(*attributes)["default-ulimits"] = string(ulimits)
 }
 
 // verifyDaemonSettings performs validation of daemon config struct
@@ -559,17 +677,41 @@ func verifyDaemonSettings(config *Config) error {
 	if !config.bridgeConfig.EnableIPTables && !config.bridgeConfig.InterContainerCommunication {
 		return fmt.Errorf("You specified --iptables=false with --icc=false. ICC=false uses iptables to function. Please set --icc or --iptables to true")
 	}
+	if config.bridgeConfig.ICCRules != "" {
+		if !config.bridgeConfig.EnableIPTables {
+			return fmt.Errorf("You specified --iptables=false with --icc-rules. The inter-container communication rules use iptables to function. Please unset --icc-rules or set --iptables to true")
+		}
+		if _, err := icc.ParseRules(config.bridgeConfig.ICCRules); err != nil {
+			return err
+		}
+	}
 	if !config.bridgeConfig.EnableIPTables && config.bridgeConfig.EnableIPMasq {
 		config.bridgeConfig.EnableIPMasq = false
 	}
 	if err := VerifyCgroupDriver(config); err != nil {
 		return err
 	}
+	if UsingSystemd(config) && !systemdRunning() {
+		return fmt.Errorf("the systemd cgroup driver requires the host to run systemd")
+	}
 	if config.CgroupParent != "" && UsingSystemd(config) {
 		if len(config.CgroupParent) <= 6 || !strings.HasSuffix(config.CgroupParent, ".slice") {
 			return fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+	if _, err := caps.TweakCapabilities(nil, config.DefaultCapabilities, nil); err != nil {
+		return fmt.Errorf("Invalid default capabilities: %v", err)
+	}
+	for _, rule := range config.DefaultDeviceCgroupRules {
+		if _, err := parseDeviceCgroupRule(rule); err != nil {
+			return err
+		}
+	}
+	if config.DefaultAppArmorProfile != "" {
+		if err := checkAppArmorProfile(config.DefaultAppArmorProfile); err != nil {
+			return err
+		}
+	}
 
 	if config.DefaultRuntime == "" {
 		config.DefaultRuntime = stockRuntimeName
@@ -698,6 +840,9 @@ func initBridgeDriver(controller libnetwork.NetworkController, config *Config) e
 		bridge.EnableIPMasquerade: strconv.FormatBool(config.bridgeConfig.EnableIPMasq),
 		bridge.EnableICC:          strconv.FormatBool(config.bridgeConfig.InterContainerCommunication),
 	}
+	if config.bridgeConfig.ICCRules != "" {
+		netOption[icc.RulesOption] = config.bridgeConfig.ICCRules
+	}
 
 	// --ip processing
 	if config.bridgeConfig.DefaultIP != nil {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
 )
 
@@ -174,6 +175,65 @@ func TestParseSecurityOpt(t *testing.T) {
 	}
 }
 
+func TestVerifySysctls(t *testing.T) {
+	valid := []*containertypes.HostConfig{
+		{Sysctls: map[string]string{"net.core.somaxconn": "1024", "kernel.msgmax": "65536"}},
+		{Sysctls: map[string]string{"kernel.shmmax": "1"}, NetworkMode: "host"},
+		{Sysctls: map[string]string{"net.ipv4.ip_forward": "1"}, IpcMode: "host"},
+	}
+	for _, config := range valid {
+		if err := verifySysctls(config); err != nil {
+			t.Fatalf("Unexpected verifySysctls error for %v: %v", config.Sysctls, err)
+		}
+	}
+
+	invalid := []*containertypes.HostConfig{
+		{Sysctls: map[string]string{"kernel.hostname": "test"}},
+		{Sysctls: map[string]string{"vm.swappiness": "0"}},
+		{Sysctls: map[string]string{"net.core.somaxconn": "1024"}, NetworkMode: "host"},
+		{Sysctls: map[string]string{"net.core.somaxconn": "1024"}, NetworkMode: "container:test"},
+		{Sysctls: map[string]string{"fs.mqueue.msg_max": "16"}, IpcMode: "host"},
+		{Sysctls: map[string]string{"kernel.sem": "250"}, IpcMode: "container:test"},
+	}
+	for _, config := range invalid {
+		if err := verifySysctls(config); err == nil {
+			t.Fatalf("Expected verifySysctls error for %v, got nil", config.Sysctls)
+		}
+	}
+}
+
+func TestVerifyCgroupDriver(t *testing.T) {
+	valid := []*Config{
+		{},
+		{CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=cgroupfs"}}},
+		{CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=systemd", "native.cgroupdriver=systemd"}}},
+		{
+			CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=systemd"}},
+			Runtimes:     map[string]types.Runtime{"custom": {Path: "custom", Args: []string{"--debug", "--systemd-cgroup=true"}}},
+		},
+	}
+	for _, config := range valid {
+		if err := VerifyCgroupDriver(config); err != nil {
+			t.Fatalf("Unexpected VerifyCgroupDriver error for %v: %v", config.ExecOptions, err)
+		}
+	}
+
+	invalid := []*Config{
+		{CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=lxc"}}},
+		{CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=cgroupfs", "native.cgroupdriver=systemd"}}},
+		{Runtimes: map[string]types.Runtime{"custom": {Path: "custom", Args: []string{"--systemd-cgroup"}}}},
+		{
+			CommonConfig: CommonConfig{ExecOptions: []string{"native.cgroupdriver=systemd"}},
+			Runtimes:     map[string]types.Runtime{"custom": {Path: "custom", Args: []string{"--systemd-cgroup=false"}}},
+		},
+	}
+	for _, config := range invalid {
+		if err := VerifyCgroupDriver(config); err == nil {
+			t.Fatalf("Expected VerifyCgroupDriver error for %v, got nil", config.ExecOptions)
+		}
+	}
+}
+
 func TestNetworkOptions(t *testing.T) {
 	daemon := &Daemon{}
 	dconfigCorrect := &Config{
@@ -138,6 +138,14 @@ func verifyContainerResources(resources *containertypes.Resources, sysInfo *sysi
 func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.HostConfig, config *containertypes.Config, update bool) ([]string, error) {
 	warnings := []string{}
 
+	if len(hostConfig.Secrets) > 0 {
+		return warnings, fmt.Errorf("secrets are not supported on Windows")
+	}
+
+	if hostConfig.DSCP != nil {
+		return warnings, fmt.Errorf("DSCP marks are not supported on Windows")
+	}
+
 	w, err := verifyContainerResources(&hostConfig.Resources, nil)
 	warnings = append(warnings, w...)
 	if err != nil {
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"github.com/docker/docker/layer"
+	"github.com/docker/engine-api/types"
+)
+
+// SystemDiskUsage returns the disk space used by the images, the containers
+// and the volumes of the daemon.
+func (daemon *Daemon) SystemDiskUsage() (*types.DiskUsage, error) {
+	images, err := daemon.Images("", "", false)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := daemon.Containers(&types.ContainerListOptions{All: true, Size: true})
+	if err != nil {
+		return nil, err
+	}
+	layersSize, err := daemon.layersSize()
+	if err != nil {
+		return nil, err
+	}
+
+	vols, _, err := daemon.volumes.List()
+	if err != nil {
+		return nil, err
+	}
+	var volumes []*types.Volume
+	for _, v := range vols {
+		apiV := volumeToAPIType(v)
+		apiV.Mountpoint = v.Path()
+		apiV.UsageData = daemon.volumeUsage(v)
+		volumes = append(volumes, apiV)
+	}
+
+	return &types.DiskUsage{
+		LayersSize: layersSize,
+		Images:     images,
+		Containers: containers,
+		Volumes:    volumes,
+	}, nil
+}
+
+// layersSize returns the size of the layers of the images, counting the
+// layers shared by several images once.
+func (daemon *Daemon) layersSize() (int64, error) {
+	var size int64
+	seen := make(map[layer.ChainID]bool)
+	for _, img := range daemon.imageStore.Map() {
+		chainID := img.RootFS.ChainID()
+		if chainID == "" || seen[chainID] {
+			continue
+		}
+		l, err := daemon.layerStore.Get(chainID)
+		if err != nil {
+			return 0, err
+		}
+		for p := l; p != nil && !seen[p.ChainID()]; p = p.Parent() {
+			seen[p.ChainID()] = true
+			diffSize, err := p.DiffSize()
+			if err != nil {
+				layer.ReleaseAndLog(daemon.layerStore, l)
+				return 0, err
+			}
+			size += diffSize
+		}
+		layer.ReleaseAndLog(daemon.layerStore, l)
+	}
+	return size, nil
+}
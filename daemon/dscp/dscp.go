@@ -0,0 +1,54 @@
+// Package dscp marks the egress traffic of the containers with a DSCP value,
+// with iptables rules in the mangle table of their network namespace, so
+// that the switches can prioritize the traffic of some containers.
+package dscp
+
+import "strconv"
+
+// Option is the option of a network holding the DSCP value of the traffic
+// of its containers, from their address on the network. It does not apply
+// to the containers with their own DSCP value.
+const Option = "com.docker.network.dscp"
+
+// classIDMajor is the major number of the net_cls class identifiers of the
+// containers with a DSCP value, their minor number being the value.
+const classIDMajor = 0xd5c0
+
+// ClassID returns the net_cls class identifier of the containers marking
+// their traffic with dscp, which identifies it in the network namespace of
+// the host for the containers sharing it.
+func ClassID(dscp int) uint32 {
+	return classIDMajor<<16 | uint32(dscp)
+}
+
+// ChainName returns the name of the iptables chain of the marks of a
+// container.
+func ChainName(containerID string) string {
+	if len(containerID) > 12 {
+		containerID = containerID[:12]
+	}
+	return "DOCKER-DSCP-" + containerID
+}
+
+// Mark is the DSCP value of the traffic from Source, or of the traffic of the
+// net_cls class of the value if Source is empty.
+type Mark struct {
+	Source string
+	DSCP   int
+}
+
+// Args returns the arguments of the iptables rules of the chain of a
+// container applying marks.
+func Args(marks []Mark) [][]string {
+	var args [][]string
+	for _, m := range marks {
+		var a []string
+		if m.Source != "" {
+			a = []string{"-s", m.Source}
+		} else {
+			a = []string{"-m", "cgroup", "--cgroup", strconv.FormatUint(uint64(ClassID(m.DSCP)), 10)}
+		}
+		args = append(args, append(a, "-j", "DSCP", "--set-dscp", strconv.Itoa(m.DSCP)))
+	}
+	return args
+}
@@ -0,0 +1,118 @@
+package dscp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/docker/libnetwork/iptables"
+	"github.com/vishvananda/netns"
+)
+
+func init() {
+	reexec.Register("docker-dscp", programMain)
+}
+
+type programOptions struct {
+	Namespace string
+	Chain     string
+	Args      [][]string
+	Remove    bool
+}
+
+// Program replaces the rules of the chain of a container in the mangle table
+// of the network namespace of path with the rules of args, and makes the
+// egress traffic of the namespace go through it.
+func Program(path, chain string, args [][]string) error {
+	return program(programOptions{Namespace: path, Chain: chain, Args: args})
+}
+
+// Remove removes the chain of a container from the network namespace of
+// path, when the namespace outlives the container like the one of the host.
+func Remove(path, chain string) error {
+	return program(programOptions{Namespace: path, Chain: chain, Remove: true})
+}
+
+func program(options programOptions) error {
+	cmd := reexec.Command("docker-dscp")
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("dscp error on pipe creation: %v", err)
+	}
+
+	output := bytes.NewBuffer(nil)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("dscp error on re-exec cmd: %v", err)
+	}
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		return fmt.Errorf("dscp json encode to pipe failed: %v", err)
+	}
+	w.Close()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("dscp re-exec error: %v: output: %s", err, output)
+	}
+	return nil
+}
+
+func fatal(err error) {
+	fmt.Fprint(os.Stderr, err)
+	os.Exit(1)
+}
+
+// programMain is the entry-point for docker-dscp on re-exec.
+func programMain() {
+	runtime.LockOSThread()
+
+	var options programOptions
+	if err := json.NewDecoder(os.Stdin).Decode(&options); err != nil {
+		fatal(err)
+	}
+
+	f, err := os.Open(options.Namespace)
+	if err != nil {
+		fatal(fmt.Errorf("failed to open the network namespace %s: %v", options.Namespace, err))
+	}
+	defer f.Close()
+	if err := netns.Set(netns.NsHandle(f.Fd())); err != nil {
+		fatal(fmt.Errorf("failed to enter the network namespace %s: %v", options.Namespace, err))
+	}
+
+	mangle := string(iptables.Mangle)
+	exists := iptables.ExistChain(options.Chain, iptables.Mangle)
+	if options.Remove {
+		if exists {
+			iptables.RawCombinedOutputNative("-t", mangle, "-D", "POSTROUTING", "-j", options.Chain)
+			iptables.RawCombinedOutputNative("-t", mangle, "-F", options.Chain)
+			if err := iptables.RawCombinedOutputNative("-t", mangle, "-X", options.Chain); err != nil {
+				fatal(err)
+			}
+		}
+		os.Exit(0)
+	}
+
+	if exists {
+		if err := iptables.RawCombinedOutputNative("-t", mangle, "-F", options.Chain); err != nil {
+			fatal(err)
+		}
+	} else {
+		if err := iptables.RawCombinedOutputNative("-t", mangle, "-N", options.Chain); err != nil {
+			fatal(err)
+		}
+		if err := iptables.RawCombinedOutputNative("-t", mangle, "-A", "POSTROUTING", "-j", options.Chain); err != nil {
+			fatal(err)
+		}
+	}
+	for _, a := range options.Args {
+		if err := iptables.RawCombinedOutputNative(append([]string{"-t", mangle, "-A", options.Chain}, a...)...); err != nil {
+			fatal(err)
+		}
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,22 @@
+package dscp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	expected := [][]string{
+		{"-m", "cgroup", "--cgroup", "3586129966", "-j", "DSCP", "--set-dscp", "46"},
+		{"-s", "172.18.0.2", "-j", "DSCP", "--set-dscp", "34"},
+	}
+	if args := Args([]Mark{{DSCP: 46}, {Source: "172.18.0.2", DSCP: 34}}); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected the rules %v, got %v", expected, args)
+	}
+}
+
+func TestClassID(t *testing.T) {
+	if id := ClassID(46); id != 0xd5c0002e {
+		t.Fatalf("expected the class identifier 0xd5c0002e, got %#x", id)
+	}
+}
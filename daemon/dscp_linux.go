@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"sort"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/dscp"
+	"github.com/docker/docker/pkg/loglevel"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/libnetwork"
+)
+
+// networkDSCP returns the DSCP value of the traffic of the containers of the
+// network n, if it has one.
+func networkDSCP(n libnetwork.Network) (int, bool) {
+	v, ok := n.Info().DriverOptions()[dscp.Option]
+	if !ok {
+		return 0, false
+	}
+	value, err := runconfigopts.ParseDSCP(v)
+	return value, err == nil
+}
+
+// dscpMarks returns the DSCP marks of the egress traffic of a container: the
+// one of the container if it has a DSCP value, or the ones of its networks
+// with a DSCP value, from its address on them.
+func (daemon *Daemon) dscpMarks(container *container.Container) []dscp.Mark {
+	if container.HostConfig.DSCP != nil {
+		return []dscp.Mark{{DSCP: *container.HostConfig.DSCP}}
+	}
+	var names []string
+	for name := range container.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var marks []dscp.Mark
+	for _, name := range names {
+		epSettings := container.NetworkSettings.Networks[name]
+		if epSettings == nil || epSettings.IPAddress == "" {
+			continue
+		}
+		n, err := daemon.FindNetwork(name)
+		if err != nil {
+			continue
+		}
+		if v, ok := networkDSCP(n); ok {
+			marks = append(marks, dscp.Mark{Source: epSettings.IPAddress, DSCP: v})
+		}
+	}
+	return marks
+}
+
+// updateDSCPMarks programs the DSCP marks of the egress traffic of a
+// container in its network namespace, after it is connected to the network n
+// or disconnected from it.
+func (daemon *Daemon) updateDSCPMarks(container *container.Container, n libnetwork.Network) {
+	if container.HostConfig.NetworkMode.IsContainer() {
+		return
+	}
+	marks := daemon.dscpMarks(container)
+	if _, ok := networkDSCP(n); !ok && len(marks) == 0 {
+		return
+	}
+	sb, err := daemon.netController.SandboxByID(container.NetworkSettings.SandboxID)
+	if err != nil {
+		return
+	}
+	if err := dscp.Program(sb.Key(), dscp.ChainName(container.ID), dscp.Args(marks)); err != nil {
+		loglevel.WithSubsystem(loglevel.Network).Errorf("Could not program the DSCP marks of container %s: %v", container.ID, err)
+	}
+}
+
+// removeDSCPMarks removes the DSCP marks of a container from the network
+// namespace of the host, when the container sharing it stops.
+func (daemon *Daemon) removeDSCPMarks(container *container.Container, sb libnetwork.Sandbox) {
+	if !container.HostConfig.NetworkMode.IsHost() || container.HostConfig.DSCP == nil {
+		return
+	}
+	if err := dscp.Remove(sb.Key(), dscp.ChainName(container.ID)); err != nil {
+		loglevel.WithSubsystem(loglevel.Network).Warnf("Could not remove the DSCP marks of container %s: %v", container.ID, err)
+	}
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package daemon
+
+import (
+	"github.com/docker/docker/container"
+	"github.com/docker/libnetwork"
+)
+
+// updateDSCPMarks does nothing: the DSCP marks of the containers are
+// programmed with iptables, which this platform does not have.
+func (daemon *Daemon) updateDSCPMarks(container *container.Container, n libnetwork.Network) {
+}
+
+// removeDSCPMarks does nothing: the DSCP marks of the containers are
+// programmed with iptables, which this platform does not have.
+func (daemon *Daemon) removeDSCPMarks(container *container.Container, sb libnetwork.Sandbox) {
+}
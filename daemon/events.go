@@ -1,6 +1,8 @@
 package daemon
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -8,9 +10,14 @@ import (
 	daemonevents "github.com/docker/docker/daemon/events"
 	"github.com/docker/engine-api/types/events"
 	"github.com/docker/engine-api/types/filters"
+	"github.com/docker/go-units"
 	"github.com/docker/libnetwork"
 )
 
+// eventsJournalFile is the file of the events journal, in the root of the
+// daemon.
+const eventsJournalFile = "events.journal"
+
 // LogContainerEvent generates an event related to a container with only the default attributes.
 func (daemon *Daemon) LogContainerEvent(container *container.Container, action string) {
 	daemon.LogContainerEventWithAttributes(container, action, map[string]string{})
@@ -106,6 +113,28 @@ func (daemon *Daemon) UnsubscribeFromEvents(listener chan interface{}) {
 	daemon.EventsService.Evict(listener)
 }
 
+// openEventsJournal opens the journal persisting the events of the daemon,
+// if the daemon is configured with a maximum size for it.
+func openEventsJournal(config *Config) (*daemonevents.Journal, error) {
+	if config.EventsJournalSize == "" || config.EventsJournalSize == "0" {
+		if config.EventsJournalAge != "" {
+			return nil, fmt.Errorf("the events journal age requires an events journal size")
+		}
+		return nil, nil
+	}
+	size, err := units.RAMInBytes(config.EventsJournalSize)
+	if err != nil || size <= 0 {
+		return nil, fmt.Errorf("invalid events journal size %q", config.EventsJournalSize)
+	}
+	var age time.Duration
+	if config.EventsJournalAge != "" {
+		if age, err = time.ParseDuration(config.EventsJournalAge); err != nil || age <= 0 {
+			return nil, fmt.Errorf("invalid events journal age %q", config.EventsJournalAge)
+		}
+	}
+	return daemonevents.OpenJournal(filepath.Join(config.Root, eventsJournalFile), size, age)
+}
+
 // copyAttributes guarantees that labels are not mutated by event triggers.
 func copyAttributes(attributes, labels map[string]string) {
 	if labels == nil {
@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/pubsub"
 	eventtypes "github.com/docker/engine-api/types/events"
 )
@@ -15,9 +16,10 @@ const (
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
@@ -28,6 +30,14 @@ func New() *Events {
 	}
 }
 
+// SetJournal persists the events logged from now on to the journal j, from
+// which the events are replayed to the listeners asking for past events.
+func (e *Events) SetJournal(j *Journal) {
+	e.mu.Lock()
+	e.journal = j
+	e.mu.Unlock()
+}
+
 // Subscribe adds new listener to events, returns slice of 64 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -106,6 +116,11 @@ func (e *Events) Log(action, eventType string, actor eventtypes.Actor) {
 	} else {
 		e.events = append(e.events, jm)
 	}
+	if e.journal != nil {
+		if err := e.journal.Append(jm); err != nil {
+			logrus.Warnf("Could not persist the event %s of %s to the events journal: %v", action, actor.ID, err)
+		}
+	}
 	e.mu.Unlock()
 	e.pub.Publish(jm)
 }
@@ -115,8 +130,9 @@ func (e *Events) SubscribersCount() int {
 	return e.pub.Len()
 }
 
-// loadBufferedEvents iterates over the cached events in the buffer
-// and returns those that were emitted between two specific dates.
+// loadBufferedEvents iterates over the cached events in the buffer, or in
+// the journal if there is one, and returns those that were emitted between
+// two specific dates.
 // It uses `time.Unix(seconds, nanoseconds)` to generate valid dates with those arguments.
 // It filters those buffered messages with a topic function if it's not nil, otherwise it adds all messages.
 func (e *Events) loadBufferedEvents(since, until time.Time, topic func(interface{}) bool) []eventtypes.Message {
@@ -135,6 +151,14 @@ func (e *Events) loadBufferedEvents(since, until time.Time, topic func(interface
 		untilNanoUnix = until.UnixNano()
 	}
 
+	if e.journal != nil {
+		events, err := e.journal.Read(sinceNanoUnix, untilNanoUnix, topic)
+		if err == nil {
+			return events
+		}
+		logrus.Warnf("Could not read the events journal: %v", err)
+	}
+
 	for i := len(e.events) - 1; i >= 0; i-- {
 		ev := e.events[i]
 
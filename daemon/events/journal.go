@@ -0,0 +1,206 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/engine-api/types/events"
+)
+
+// journalAgeCheckInterval is how often the journal is compacted to drop the
+// events older than its maximum age.
+const journalAgeCheckInterval = time.Minute
+
+// Journal persists the events in a file, one JSON message per line, so that
+// they can be replayed after the daemon restarts. The journal is bounded:
+// once it is larger than its maximum size, or its oldest event older than
+// its maximum age, it is compacted to the newest events fitting in half of
+// its maximum size and younger than its maximum age.
+type Journal struct {
+	mu           sync.Mutex
+	path         string
+	maxSize      int64
+	maxAge       time.Duration
+	f            *os.File
+	size         int64
+	oldest       int64 // the time of the oldest event, in nanoseconds
+	nextAgeCheck time.Time
+}
+
+// OpenJournal opens the journal of events in path, creating it if it does
+// not exist. The journal holds at most maxSize bytes of events, and the
+// events younger than maxAge if maxAge is not 0.
+func OpenJournal(path string, maxSize int64, maxAge time.Duration) (*Journal, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the maximum size of an events journal must be positive")
+	}
+	j := &Journal{path: path, maxSize: maxSize, maxAge: maxAge}
+	if err := j.compact(time.Now()); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// Append appends an event to the journal, compacting it if needed.
+func (j *Journal) Append(m eventtypes.Message) error {
+	line, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return errors.New("the events journal is closed")
+	}
+	n, err := j.f.Write(line)
+	j.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if j.oldest == 0 {
+		j.oldest = m.TimeNano
+	}
+
+	now := time.Now()
+	if j.size > j.maxSize {
+		return j.compact(now)
+	}
+	if j.maxAge > 0 && now.After(j.nextAgeCheck) {
+		j.nextAgeCheck = now.Add(journalAgeCheckInterval)
+		if j.oldest < now.Add(-j.maxAge).UnixNano() {
+			return j.compact(now)
+		}
+	}
+	return nil
+}
+
+// Read returns the events of the journal emitted between since and until,
+// in nanoseconds, oldest first, for which topic returns true if it is not
+// nil. An until of 0 means no upper bound.
+func (j *Journal) Read(since, until int64, topic func(interface{}) bool) ([]eventtypes.Message, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var events []eventtypes.Message
+	err := j.scan(func(m eventtypes.Message, _ []byte) {
+		if m.TimeNano < since || (until > 0 && m.TimeNano > until) {
+			return
+		}
+		if topic == nil || topic(m) {
+			events = append(events, m)
+		}
+	})
+	return events, err
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
+
+// scan calls fn with each event of the journal, and its line. The lines
+// which cannot be decoded, like a line truncated by a crash, are skipped.
+func (j *Journal) scan(fn func(eventtypes.Message, []byte)) error {
+	f, err := os.Open(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), int(j.maxSize))
+	for scanner.Scan() {
+		var m eventtypes.Message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			continue
+		}
+		fn(m, scanner.Bytes())
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the journal with its newest events fitting in half of
+// its maximum size and younger than its maximum age, and reopens it.
+func (j *Journal) compact(now time.Time) error {
+	var minTime int64
+	if j.maxAge > 0 {
+		minTime = now.Add(-j.maxAge).UnixNano()
+	}
+
+	type entry struct {
+		time int64
+		line []byte
+	}
+	var kept []entry
+	var size int64
+	if err := j.scan(func(m eventtypes.Message, line []byte) {
+		if m.TimeNano < minTime {
+			return
+		}
+		kept = append(kept, entry{m.TimeNano, append(append([]byte{}, line...), '\n')})
+		size += int64(len(line) + 1)
+		for size > j.maxSize/2 && len(kept) > 0 {
+			size -= int64(len(kept[0].line))
+			kept = kept[1:]
+		}
+	}); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, e := range kept {
+		buf.Write(e.line)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(j.path), filepath.Base(j.path)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if j.f != nil {
+		j.f.Close()
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		j.f = nil
+		return err
+	}
+	j.f = f
+	j.size = size
+	j.oldest = 0
+	if len(kept) > 0 {
+		j.oldest = kept[0].time
+	}
+	j.nextAgeCheck = now.Add(journalAgeCheckInterval)
+	return nil
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eventtypes "github.com/docker/engine-api/types/events"
+)
+
+func TestJournalReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.journal")
+
+	j, err := OpenJournal(path, 1024*1024, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New()
+	e.SetJournal(j)
+	since := time.Now()
+	e.Log("create", eventtypes.ContainerEventType, eventtypes.Actor{ID: "foo"})
+	e.Log("start", eventtypes.ContainerEventType, eventtypes.Actor{ID: "foo"})
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the events are replayed by a new instance, as after a restart
+	j, err = OpenJournal(path, 1024*1024, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	e = New()
+	e.SetJournal(j)
+	e.Log("stop", eventtypes.ContainerEventType, eventtypes.Actor{ID: "bar"})
+
+	buffered, ch := e.SubscribeTopic(since, time.Time{}, nil)
+	defer e.Evict(ch)
+	var actions []string
+	for _, m := range buffered {
+		actions = append(actions, m.Action+" "+m.ID)
+	}
+	if len(actions) != 3 || actions[0] != "create foo" || actions[1] != "start foo" || actions[2] != "stop bar" {
+		t.Fatalf("expected the events create foo, start foo and stop bar, got %v", actions)
+	}
+}
+
+func TestJournalCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.journal")
+
+	j, err := OpenJournal(path, 4096, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	for i := int64(1); i <= 200; i++ {
+		if err := j.Append(eventtypes.Message{Action: "start", TimeNano: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() > 4096 {
+		t.Fatalf("expected the journal to be compacted to 4096 bytes at most, got %d", fi.Size())
+	}
+	events, err := j.Read(0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || events[len(events)-1].TimeNano != 200 {
+		t.Fatalf("expected the newest events to be kept, got %v", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].TimeNano != events[i-1].TimeNano+1 {
+			t.Fatalf("expected consecutive events, got %v", events)
+		}
+	}
+}
+
+func TestJournalMaxAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.journal")
+
+	j, err := OpenJournal(path, 1024*1024, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, ts := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Minute)} {
+		if err := j.Append(eventtypes.Message{Action: "start", TimeNano: ts.UnixNano()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	j.Close()
+
+	j, err = OpenJournal(path, 1024*1024, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	events, err := j.Read(0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].TimeNano != now.Add(-time.Minute).UnixNano() {
+		t.Fatalf("expected the event older than an hour to be dropped, got %v", events)
+	}
+}
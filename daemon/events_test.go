@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/daemon/events"
 	containertypes "github.com/docker/engine-api/types/container"
 	eventtypes "github.com/docker/engine-api/types/events"
+	networktypes "github.com/docker/engine-api/types/network"
 )
 
 func TestLogContainerEventCopyLabels(t *testing.T) {
@@ -75,6 +76,41 @@ func TestLogContainerEventWithAttributes(t *testing.T) {
 	})
 }
 
+func TestEndpointEventAttributes(t *testing.T) {
+	container := &container.Container{
+		CommonContainer: container.CommonContainer{
+			ID: "container_id",
+		},
+	}
+	attributes := endpointEventAttributes(container, &networktypes.EndpointSettings{
+		EndpointID: "endpoint_id",
+		IPAddress:  "172.18.0.2",
+		Gateway:    "172.18.0.1",
+		MacAddress: "02:42:ac:12:00:02",
+		Aliases:    []string{"web", "www"},
+	})
+	expected := map[string]string{
+		"container":   "container_id",
+		"endpointID":  "endpoint_id",
+		"ipv4Address": "172.18.0.2",
+		"gateway":     "172.18.0.1",
+		"macAddress":  "02:42:ac:12:00:02",
+		"aliases":     "web,www",
+	}
+	if len(attributes) != len(expected) {
+		t.Fatalf("Expected the attributes %v, got %v", expected, attributes)
+	}
+	for k, v := range expected {
+		if attributes[k] != v {
+			t.Fatalf("Expected the attributes %v, got %v", expected, attributes)
+		}
+	}
+
+	if attributes := endpointEventAttributes(container, nil); len(attributes) != 1 || attributes["container"] != "container_id" {
+		t.Fatalf("Expected only the container attribute without an endpoint, got %v", attributes)
+	}
+}
+
 func validateTestAttributes(t *testing.T, l chan interface{}, expectedAttributesToTest map[string]string) {
 	select {
 	case ev := <-l:
@@ -207,6 +207,7 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	if err := d.containerd.AddProcess(c.ID, name, p); err != nil {
 		return err
 	}
+	d.createExecCgroups(c, ec)
 
 	select {
 	case <-ctx.Done():
@@ -231,6 +232,31 @@ func (d *Daemon) ContainerExecStart(ctx context.Context, name string, stdin io.R
 	return nil
 }
 
+// ContainerExecStats returns the resource usage of the exec process name,
+// accounted in its nested cgroups, or its final resource usage if it exited.
+func (d *Daemon) ContainerExecStats(name string) (*types.ExecStats, error) {
+	ec := d.execCommands.Get(name)
+	if ec == nil {
+		return nil, errExecNotFound(name)
+	}
+
+	ec.Lock()
+	defer ec.Unlock()
+	if ec.Stats != nil {
+		return ec.Stats, nil
+	}
+	if len(ec.Cgroups) == 0 {
+		err := fmt.Errorf("exec %s is not accounted in a nested cgroup", name)
+		return nil, errors.NewRequestConflictError(err)
+	}
+	stats, err := execCgroupStats(ec.Cgroups)
+	if err != nil {
+		return nil, err
+	}
+	stats.Running = true
+	return stats, nil
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (d *Daemon) execCommandGC() {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/runconfig"
+	"github.com/docker/engine-api/types"
 )
 
 // Config holds the configurations for execs. The Daemon keeps
@@ -27,6 +28,11 @@ type Config struct {
 	Tty         bool
 	Privileged  bool
 	User        string
+
+	// Cgroups are the directories of the nested cgroups of the exec
+	// process, and Stats is its resource usage once it exited.
+	Cgroups []string
+	Stats   *types.ExecStats
 }
 
 // NewConfig initializes the a new exec configuration
@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/pkg/loglevel"
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// execCgroupSubsystems are the subsystems accounting for the exec processes
+// in their nested cgroups. The cgroups of the other hierarchies, like cpuset
+// whose cgroups must be configured before processes enter them, or the
+// systemd hierarchy, are left alone.
+var execCgroupSubsystems = map[string]bool{
+	"blkio":   true,
+	"cpu":     true,
+	"cpuacct": true,
+	"memory":  true,
+	"pids":    true,
+}
+
+// execCgroupPrefix prefixes the names of the nested cgroups of the exec
+// processes, in the cgroups of their container.
+const execCgroupPrefix = "exec-"
+
+// createExecCgroups moves the exec process ec of the container c to nested
+// cgroups of the cgroups of c, to account for its resource usage. An exec
+// process which cannot be moved keeps running in the cgroups of c.
+func (daemon *Daemon) createExecCgroups(c *container.Container, ec *exec.Config) {
+	var paths []string
+	pid, err := daemon.containerd.GetProcessPid(c.ID, ec.ID)
+	if err == nil {
+		paths, err = createExecCgroups(c.State.GetPID(), pid, ec.ID)
+	}
+
+	ec.Lock()
+	defer ec.Unlock()
+	if err != nil {
+		if ec.ExitCode == nil {
+			logrus.WithField(loglevel.SubsystemKey, loglevel.Cgroups).Warnf("exec %s of container %s is not accounted in a nested cgroup: %v", ec.ID, c.ID, err)
+		}
+		return
+	}
+	ec.Cgroups = paths
+	if ec.ExitCode != nil {
+		// the process exited while it was moved
+		releaseExecCgroupsLocked(ec)
+	}
+}
+
+// createExecCgroups creates the nested cgroups of the exec process id, in the
+// cgroups of the process containerPid, and moves the process pid to them.
+func createExecCgroups(containerPid, pid int, id string) ([]string, error) {
+	dirs, err := processCgroupDirs(containerPid)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, d := range dirs {
+		if !accountsExecs(d.subsystems) {
+			continue
+		}
+		p := filepath.Join(d.path, execCgroupPrefix+id)
+		if err := os.Mkdir(p, 0755); err != nil && !os.IsExist(err) {
+			removeExecCgroups(paths)
+			return nil, err
+		}
+		paths = append(paths, p)
+		if err := writeCgroupFile(strings.Join(d.subsystems, ","), p, "cgroup.procs", strconv.Itoa(pid)); err != nil {
+			removeExecCgroups(paths)
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// accountsExecs returns whether the exec processes are accounted in the
+// hierarchy of subsystems.
+func accountsExecs(subsystems []string) bool {
+	for _, s := range subsystems {
+		if !execCgroupSubsystems[s] {
+			return false
+		}
+	}
+	return len(subsystems) > 0
+}
+
+// releaseExecCgroups records the final resource usage of the exited exec
+// process ec, and removes its nested cgroups.
+func releaseExecCgroups(ec *exec.Config) {
+	ec.Lock()
+	releaseExecCgroupsLocked(ec)
+	ec.Unlock()
+}
+
+func releaseExecCgroupsLocked(ec *exec.Config) {
+	if len(ec.Cgroups) == 0 {
+		return
+	}
+	if stats, err := execCgroupStats(ec.Cgroups); err == nil {
+		ec.Stats = stats
+	}
+	removeExecCgroups(ec.Cgroups)
+	ec.Cgroups = nil
+}
+
+// removeExecCgroups removes the nested cgroups paths of an exec process. The
+// processes the exec process started and left running are moved back to the
+// cgroups of the container, so that the cgroups can be removed.
+func removeExecCgroups(paths []string) {
+	toRemove := make(map[string]string, len(paths))
+	for _, p := range paths {
+		if pids, err := cgroups.GetPids(p); err == nil {
+			for _, pid := range pids {
+				ioutil.WriteFile(filepath.Join(filepath.Dir(p), "cgroup.procs"), []byte(strconv.Itoa(pid)), 0700)
+			}
+		}
+		toRemove[p] = p
+	}
+	if err := cgroups.RemovePaths(toRemove); err != nil {
+		logrus.WithField(loglevel.SubsystemKey, loglevel.Cgroups).Warnf("removing the cgroups of an exec process: %v", err)
+	}
+}
+
+// execCgroupStats returns the resource usage of an exec process, accounted
+// in its nested cgroups paths.
+func execCgroupStats(paths []string) (*types.ExecStats, error) {
+	stats := &types.ExecStats{Read: time.Now()}
+	for _, p := range paths {
+		for file, value := range map[string]*uint64{
+			"cpuacct.usage":             &stats.CPUUsage,
+			"memory.usage_in_bytes":     &stats.MemoryUsage,
+			"memory.max_usage_in_bytes": &stats.MaxMemoryUsage,
+			"pids.current":              &stats.Pids,
+		} {
+			v, err := readCgroupUint(filepath.Join(p, file))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			*value = v
+		}
+	}
+	return stats, nil
+}
+
+func readCgroupUint(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %v", path, err)
+	}
+	return v, nil
+}
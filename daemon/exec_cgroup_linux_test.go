@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccountsExecs(t *testing.T) {
+	for _, c := range []struct {
+		subsystems []string
+		expected   bool
+	}{
+		{[]string{"cpu", "cpuacct"}, true},
+		{[]string{"memory"}, true},
+		{[]string{"cpuset"}, false},
+		{[]string{"name=systemd"}, false},
+		{nil, false},
+	} {
+		if accounts := accountsExecs(c.subsystems); accounts != c.expected {
+			t.Fatalf("expected the hierarchy %v to account for execs: %v, got %v", c.subsystems, c.expected, accounts)
+		}
+	}
+}
+
+func TestExecCgroupStats(t *testing.T) {
+	root, err := ioutil.TempDir("", "exec-cgroup-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	cpu := filepath.Join(root, "cpu,cpuacct", "exec-1")
+	memory := filepath.Join(root, "memory", "exec-1")
+	for dir, files := range map[string]map[string]string{
+		cpu:    {"cpuacct.usage": "123456789\n"},
+		memory: {"memory.usage_in_bytes": "4096\n", "memory.max_usage_in_bytes": "8192\n"},
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	stats, err := execCgroupStats([]string{cpu, memory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.CPUUsage != 123456789 || stats.MemoryUsage != 4096 || stats.MaxMemoryUsage != 8192 || stats.Pids != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
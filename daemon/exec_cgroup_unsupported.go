@@ -0,0 +1,23 @@
+// +build !linux
+
+package daemon
+
+import (
+	"errors"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/engine-api/types"
+)
+
+// createExecCgroups does nothing: the exec processes are only accounted in
+// nested cgroups on Linux.
+func (daemon *Daemon) createExecCgroups(c *container.Container, ec *exec.Config) {
+}
+
+func releaseExecCgroups(ec *exec.Config) {
+}
+
+func execCgroupStats(paths []string) (*types.ExecStats, error) {
+	return nil, errors.New("the resource usage of exec processes is only accounted on Linux")
+}
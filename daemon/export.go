@@ -1,22 +1,40 @@
 package daemon
 
 import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
+	containertypes "github.com/docker/engine-api/types/container"
 )
 
+// exportMetadataFile is the file, at the beginning of the archives exported
+// with their metadata, holding the runtime configuration of the container.
+// ImportImage uses it as the configuration of the imported image.
+const exportMetadataFile = ".docker-metadata.json"
+
 // ContainerExport writes the contents of the container to the given
-// writer. An error is returned if the container cannot be found.
-func (daemon *Daemon) ContainerExport(name string, out io.Writer) error {
+// writer. An error is returned if the container cannot be found. The runtime
+// configuration of the container is added to the archive if metadata is set.
+func (daemon *Daemon) ContainerExport(name string, metadata bool, out io.Writer) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
 	}
 
+	var header []byte
+	if metadata {
+		if header, err = exportMetadataHeader(exportMetadata(container.Config)); err != nil {
+			return fmt.Errorf("Error exporting container %s: %v", name, err)
+		}
+	}
+
 	data, err := daemon.containerExport(container)
 	if err != nil {
 		return fmt.Errorf("Error exporting container %s: %v", name, err)
@@ -24,7 +42,7 @@ func (daemon *Daemon) ContainerExport(name string, out io.Writer) error {
 	defer data.Close()
 
 	// Stream the entire contents of the container (basically a volatile snapshot)
-	if _, err := io.Copy(out, data); err != nil {
+	if _, err := io.Copy(out, io.MultiReader(bytes.NewReader(header), data)); err != nil {
 		return fmt.Errorf("Error exporting container %s: %v", name, err)
 	}
 	return nil
@@ -53,3 +71,49 @@ func (daemon *Daemon) containerExport(container *container.Container) (archive.A
 	daemon.LogContainerEvent(container, "export")
 	return arch, err
 }
+
+// exportMetadata returns the parts of the configuration of a container which
+// are kept by the images.
+func exportMetadata(config *containertypes.Config) *containertypes.Config {
+	return &containertypes.Config{
+		User:         config.User,
+		ExposedPorts: config.ExposedPorts,
+		Env:          config.Env,
+		Cmd:          config.Cmd,
+		Healthcheck:  config.Healthcheck,
+		Volumes:      config.Volumes,
+		WorkingDir:   config.WorkingDir,
+		Entrypoint:   config.Entrypoint,
+		OnBuild:      config.OnBuild,
+		Labels:       config.Labels,
+		StopSignal:   config.StopSignal,
+	}
+}
+
+// exportMetadataHeader returns the tar entry of the metadata file of config,
+// to put before the entries of an archive.
+func exportMetadataHeader(config *containertypes.Config) ([]byte, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     exportMetadataFile,
+		Mode:     0600,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	// Flush pads the entry, without the end of the archive written by Close.
+	if err := tw.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -7,11 +7,11 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/vbatts/tar-split/tar/storage"
 
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/loglevel"
 )
 
 // FsMagic unsigned id of the filesystem in use.
@@ -141,7 +141,7 @@ func GetDriver(name, home string, options []string, uidMaps, gidMaps []idtools.I
 	if pluginDriver, err := lookupPlugin(name, home, options); err == nil {
 		return pluginDriver, nil
 	}
-	logrus.Errorf("Failed to GetDriver graph %s %s", name, home)
+	loglevel.WithSubsystem(loglevel.GraphDriver).Errorf("Failed to GetDriver graph %s %s", name, home)
 	return nil, ErrNotSupported
 }
 
@@ -150,14 +150,14 @@ func getBuiltinDriver(name, home string, options []string, uidMaps, gidMaps []id
 	if initFunc, exists := drivers[name]; exists {
 		return initFunc(filepath.Join(home, name), options, uidMaps, gidMaps)
 	}
-	logrus.Errorf("Failed to built-in GetDriver graph %s %s", name, home)
+	loglevel.WithSubsystem(loglevel.GraphDriver).Errorf("Failed to built-in GetDriver graph %s %s", name, home)
 	return nil, ErrNotSupported
 }
 
 // New creates the driver and initializes it at the specified root.
 func New(root string, name string, options []string, uidMaps, gidMaps []idtools.IDMap) (Driver, error) {
 	if name != "" {
-		logrus.Debugf("[graphdriver] trying provided driver %q", name) // so the logs show specified driver
+		loglevel.WithSubsystem(loglevel.GraphDriver).Debugf("[graphdriver] trying provided driver %q", name) // so the logs show specified driver
 		return GetDriver(name, root, options, uidMaps, gidMaps)
 	}
 
@@ -177,7 +177,7 @@ func New(root string, name string, options []string, uidMaps, gidMaps []idtools.
 				// state, and now it is no longer supported/prereq/compatible, so
 				// something changed and needs attention. Otherwise the daemon's
 				// images would just "disappear".
-				logrus.Errorf("[graphdriver] prior storage driver %q failed: %s", name, err)
+				loglevel.WithSubsystem(loglevel.GraphDriver).Errorf("[graphdriver] prior storage driver %q failed: %s", name, err)
 				return nil, err
 			}
 
@@ -192,7 +192,7 @@ func New(root string, name string, options []string, uidMaps, gidMaps []idtools.
 				return nil, fmt.Errorf("%q contains several valid graphdrivers: %s; Please cleanup or explicitly choose storage driver (-s <DRIVER>)", root, strings.Join(driversSlice, ", "))
 			}
 
-			logrus.Infof("[graphdriver] using prior storage driver %q", name)
+			loglevel.WithSubsystem(loglevel.GraphDriver).Infof("[graphdriver] using prior storage driver %q", name)
 			return driver, nil
 		}
 	}
@@ -27,6 +27,8 @@ import (
 var (
 	// ErrApplyDiffFallback is returned to indicate that a normal ApplyDiff is applied as a fallback from Naive diff writer.
 	ErrApplyDiffFallback = fmt.Errorf("Fall back to normal ApplyDiff")
+	// ErrChangesFallback is returned to indicate that the changes are computed by the Naive diff writer as a fallback.
+	ErrChangesFallback = fmt.Errorf("Fall back to normal Changes")
 	backingFs            = "<unknown>"
 )
 
@@ -60,6 +62,27 @@ func (d *naiveDiffDriverWithApply) ApplyDiff(id, parent string, diff archive.Rea
 	return b, err
 }
 
+// changesProtoDriver is implemented by the drivers computing the changes of
+// their layers natively.
+type changesProtoDriver interface {
+	// Changes produces a list of changes between the specified layer and
+	// its parent layer. An error ErrChangesFallback is returned if the
+	// changes cannot be computed natively.
+	Changes(id, parent string) ([]archive.Change, error)
+}
+
+// Changes produces a list of changes between the specified layer and its
+// parent layer, natively or with the NaiveDiffDriver as a fallback.
+func (d *naiveDiffDriverWithApply) Changes(id, parent string) ([]archive.Change, error) {
+	if cd, ok := d.applyDiff.(changesProtoDriver); ok {
+		changes, err := cd.Changes(id, parent)
+		if err != ErrChangesFallback {
+			return changes, err
+		}
+	}
+	return d.Driver.Changes(id, parent)
+}
+
 // This backend uses the overlay union filesystem for containers
 // plus hard link file sharing for images.
 
@@ -437,6 +460,35 @@ func (d *Driver) ApplyDiff(id string, parent string, diff archive.Reader) (size
 	return
 }
 
+// Changes produces a list of changes between the specified layer and its
+// parent layer by walking the "upper" directory of the layer, instead of the
+// whole filesystem. When the parent has a "root" dir, the changes are those
+// of the "upper" directory overlaid on it. When the layer was created as a
+// copy of the "upper" dir of its parent, the entries which are the same in
+// both "upper" directories are skipped. Otherwise ErrChangesFallback is
+// returned.
+func (d *Driver) Changes(id, parent string) ([]archive.Change, error) {
+	if parent == "" {
+		return nil, ErrChangesFallback
+	}
+	lowerID, err := ioutil.ReadFile(path.Join(d.dir(id), "lower-id"))
+	if err != nil {
+		return nil, ErrChangesFallback
+	}
+	upperDir := path.Join(d.dir(id), "upper")
+	lowerDir := path.Join(d.dir(string(lowerID)), "root")
+	if string(lowerID) == parent {
+		return archive.OverlayChanges([]string{lowerDir}, upperDir)
+	}
+
+	parentLowerID, err := ioutil.ReadFile(path.Join(d.dir(parent), "lower-id"))
+	if err != nil || string(parentLowerID) != string(lowerID) {
+		return nil, ErrChangesFallback
+	}
+	parentUpperDir := path.Join(d.dir(parent), "upper")
+	return archive.OverlayCopyChanges([]string{parentUpperDir, lowerDir}, upperDir, parentUpperDir)
+}
+
 // Exists checks to see if the id is already mounted.
 func (d *Driver) Exists(id string) bool {
 	_, err := os.Stat(d.dir(id))
@@ -0,0 +1,441 @@
+package daemon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/runconfig"
+	"github.com/docker/docker/utils"
+	"github.com/docker/docker/volume"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// groupLabel is the label of the containers, networks and volumes of a
+	// group, holding its name.
+	groupLabel = "com.docker.group"
+	// groupDefaultNetwork is the network of the containers of a group
+	// which do not have one.
+	groupDefaultNetwork = "default"
+	// groupStopTimeout is the time given to the containers of a group to
+	// stop before they are killed when the group is removed.
+	groupStopTimeout = 10
+)
+
+// groupResources are the resources created for a group, removed together.
+type groupResources struct {
+	containers []string // IDs, in the order they are stopped
+	networks   []string // IDs
+	volumes    []string // names
+}
+
+func groupResourceName(group, name string) string {
+	return group + "_" + name
+}
+
+func groupLabels(group string, labels map[string]string) map[string]string {
+	l := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
+	}
+	l[groupLabel] = group
+	return l
+}
+
+// GroupCreate creates the networks, volumes and containers of a group, and
+// starts its containers, after the containers they require. The resources
+// created are removed if one of them cannot be.
+func (daemon *Daemon) GroupCreate(req types.GroupCreateRequest) (*types.Group, error) {
+	if !utils.RestrictedVolumeNamePattern.MatchString(req.Name) {
+		return nil, errors.NewBadRequestError(fmt.Errorf("invalid group name %q: only %s are allowed", req.Name, utils.RestrictedNameChars))
+	}
+	if len(req.Containers) == 0 {
+		return nil, errors.NewBadRequestError(fmt.Errorf("group %s has no container", req.Name))
+	}
+	order, err := groupStartOrder(req.Containers)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err)
+	}
+
+	daemon.groupsLock.Lock()
+	defer daemon.groupsLock.Unlock()
+
+	if _, exists := daemon.groupResources()[req.Name]; exists {
+		return nil, errors.NewRequestConflictError(fmt.Errorf("a group named %s already exists", req.Name))
+	}
+
+	created := &groupResources{}
+	if err := daemon.createGroup(req, order, created); err != nil {
+		daemon.removeGroupResources(req.Name, created)
+		return nil, err
+	}
+	return daemon.groups()[req.Name], nil
+}
+
+func (daemon *Daemon) createGroup(req types.GroupCreateRequest, order []string, created *groupResources) error {
+	networks := map[string]bool{}
+	for _, n := range req.Networks {
+		networks[n] = true
+	}
+	volumes := map[string]bool{}
+	for _, v := range req.Volumes {
+		volumes[v] = true
+	}
+	for _, c := range req.Containers {
+		if len(c.Networks) == 0 {
+			networks[groupDefaultNetwork] = true
+		}
+		for _, n := range c.Networks {
+			networks[n] = true
+		}
+		for _, spec := range c.Volumes {
+			if source := strings.SplitN(spec, ":", 2)[0]; !strings.HasPrefix(source, "/") {
+				volumes[source] = true
+			}
+		}
+	}
+
+	labels := groupLabels(req.Name, nil)
+	for _, n := range sortedKeys(networks) {
+		resp, err := daemon.CreateNetwork(types.NetworkCreateRequest{
+			Name: groupResourceName(req.Name, n),
+			NetworkCreate: types.NetworkCreate{
+				CheckDuplicate: true,
+				Driver:         runconfig.DefaultDaemonNetworkMode().NetworkName(),
+				Labels:         labels,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating network %s of group %s: %v", n, req.Name, err)
+		}
+		created.networks = append(created.networks, resp.ID)
+	}
+	for _, v := range sortedKeys(volumes) {
+		if _, err := daemon.VolumeCreate(groupResourceName(req.Name, v), volume.DefaultDriverName, nil, labels); err != nil {
+			return fmt.Errorf("error creating volume %s of group %s: %v", v, req.Name, err)
+		}
+		created.volumes = append(created.volumes, groupResourceName(req.Name, v))
+	}
+
+	ids := make(map[string]string)
+	for _, name := range order {
+		id, err := daemon.createGroupContainer(req.Name, name, req.Containers[name])
+		if id != "" {
+			// the containers are stopped in the reverse order they are started
+			created.containers = append([]string{id}, created.containers...)
+		}
+		if err != nil {
+			return fmt.Errorf("error creating container %s of group %s: %v", name, req.Name, err)
+		}
+		ids[name] = id
+	}
+	for _, name := range order {
+		id := ids[name]
+		if err := daemon.ContainerStart(id, nil, true); err != nil {
+			return fmt.Errorf("error starting container %s of group %s: %v", name, req.Name, err)
+		}
+		if c, err := daemon.GetContainer(id); err == nil {
+			waitForHealth(c, requiresHealthTimeout)
+		}
+	}
+	return nil
+}
+
+func (daemon *Daemon) createGroupContainer(group, name string, spec types.GroupContainer) (string, error) {
+	config := &containertypes.Config{
+		Image:        spec.Image,
+		Entrypoint:   spec.Command,
+		Cmd:          spec.Args,
+		Env:          spec.Env,
+		Labels:       groupLabels(group, spec.Labels),
+		WorkingDir:   spec.WorkingDir,
+		User:         spec.User,
+		ExposedPorts: nat.PortSet{},
+	}
+	hostConfig := &containertypes.HostConfig{
+		PortBindings: nat.PortMap{},
+	}
+	for _, p := range spec.Ports {
+		proto := p.Protocol
+		if proto == "" {
+			proto = "tcp"
+		}
+		port, err := nat.NewPort(proto, fmt.Sprint(p.Port))
+		if err != nil {
+			return "", err
+		}
+		config.ExposedPorts[port] = struct{}{}
+		if p.PublishedPort != 0 {
+			hostConfig.PortBindings[port] = append(hostConfig.PortBindings[port], nat.PortBinding{HostPort: fmt.Sprint(p.PublishedPort)})
+		}
+	}
+	for _, v := range spec.Volumes {
+		if !strings.HasPrefix(v, "/") {
+			v = groupResourceName(group, v)
+		}
+		hostConfig.Binds = append(hostConfig.Binds, v)
+	}
+	for _, r := range spec.Requires {
+		hostConfig.Requires = append(hostConfig.Requires, groupResourceName(group, r))
+	}
+
+	networks := spec.Networks
+	if len(networks) == 0 {
+		networks = []string{groupDefaultNetwork}
+	}
+	hostConfig.NetworkMode = containertypes.NetworkMode(groupResourceName(group, networks[0]))
+	endpoint := func() *networktypes.EndpointSettings {
+		return &networktypes.EndpointSettings{Aliases: []string{name}}
+	}
+
+	resp, err := daemon.ContainerCreate(types.ContainerCreateConfig{
+		Name:       groupResourceName(group, name),
+		Config:     config,
+		HostConfig: hostConfig,
+		NetworkingConfig: &networktypes.NetworkingConfig{
+			EndpointsConfig: map[string]*networktypes.EndpointSettings{
+				string(hostConfig.NetworkMode): endpoint(),
+			},
+		},
+	}, true)
+	if err != nil {
+		return "", err
+	}
+	for _, w := range resp.Warnings {
+		logrus.Warnf("container %s of group %s: %s", name, group, w)
+	}
+	for _, n := range networks[1:] {
+		if err := daemon.ConnectContainerToNetwork(resp.ID, groupResourceName(group, n), endpoint()); err != nil {
+			return resp.ID, err
+		}
+	}
+	return resp.ID, nil
+}
+
+// groupStartOrder returns the names of the containers of a group, each
+// after the containers it requires.
+func groupStartOrder(containers map[string]types.GroupContainer) ([]string, error) {
+	var (
+		order    []string
+		visiting = map[string]bool{}
+		visited  = map[string]bool{}
+		visit    func(name string) error
+	)
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("container %s has a circular requirement", name)
+		}
+		visiting[name] = true
+		for _, r := range containers[name].Requires {
+			if _, exists := containers[r]; !exists {
+				return fmt.Errorf("container %s requires the unknown container %s", name, r)
+			}
+			if err := visit(r); err != nil {
+				return err
+			}
+		}
+		visited[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	var names []string
+	for name, c := range containers {
+		if !utils.RestrictedVolumeNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid container name %q: only %s are allowed", name, utils.RestrictedNameChars)
+		}
+		if c.Image == "" {
+			return nil, fmt.Errorf("container %s has no image", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Groups returns the groups of containers, networks and volumes.
+func (daemon *Daemon) Groups() []*types.Group {
+	var groups []*types.Group
+	for _, g := range daemon.groups() {
+		groups = append(groups, g)
+	}
+	sort.Sort(byGroupName(groups))
+	return groups
+}
+
+func (daemon *Daemon) groups() map[string]*types.Group {
+	groups := make(map[string]*types.Group)
+	for name, r := range daemon.groupResources() {
+		g := &types.Group{Name: name, Containers: []string{}, Networks: []string{}, Volumes: r.volumes}
+		for _, id := range r.containers {
+			if c, err := daemon.GetContainer(id); err == nil {
+				g.Containers = append(g.Containers, strings.TrimPrefix(c.Name, "/"))
+			}
+		}
+		for _, id := range r.networks {
+			if n, err := daemon.FindNetwork(id); err == nil {
+				g.Networks = append(g.Networks, n.Name())
+			}
+		}
+		sort.Strings(g.Containers)
+		sort.Strings(g.Networks)
+		sort.Strings(g.Volumes)
+		groups[name] = g
+	}
+	return groups
+}
+
+// groupResources returns the resources of the groups, by name.
+func (daemon *Daemon) groupResources() map[string]*groupResources {
+	groups := make(map[string]*groupResources)
+	get := func(name string) *groupResources {
+		if groups[name] == nil {
+			groups[name] = &groupResources{containers: []string{}, networks: []string{}, volumes: []string{}}
+		}
+		return groups[name]
+	}
+
+	var containers []string
+	for _, c := range daemon.List() {
+		if name, ok := c.Config.Labels[groupLabel]; ok {
+			get(name)
+			containers = append(containers, c.ID)
+		}
+	}
+	// stop the containers before the ones they require
+	for _, id := range daemon.groupStopOrder(containers) {
+		c, err := daemon.GetContainer(id)
+		if err != nil {
+			continue
+		}
+		r := get(c.Config.Labels[groupLabel])
+		r.containers = append(r.containers, id)
+	}
+	for _, n := range daemon.GetNetworks() {
+		if name, ok := n.Info().Labels()[groupLabel]; ok {
+			r := get(name)
+			r.networks = append(r.networks, n.ID())
+		}
+	}
+	vols, _, err := daemon.volumes.List()
+	if err != nil {
+		logrus.Warnf("error listing the volumes of the groups: %v", err)
+	}
+	for _, v := range vols {
+		if lv, ok := v.(volume.LabeledVolume); ok {
+			if name, ok := lv.Labels()[groupLabel]; ok {
+				r := get(name)
+				r.volumes = append(r.volumes, v.Name())
+			}
+		}
+	}
+	return groups
+}
+
+// groupStopOrder sorts the IDs of containers so that each comes before the
+// containers it requires.
+func (daemon *Daemon) groupStopOrder(ids []string) []string {
+	var (
+		order   []string
+		visited = map[string]bool{}
+		visit   func(id string)
+	)
+	visit = func(id string) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+		if c, err := daemon.GetContainer(id); err == nil {
+			for _, r := range c.HostConfig.Requires {
+				visit(r)
+			}
+		}
+		order = append(order, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		visit(id)
+	}
+	keep := map[string]bool{}
+	for _, id := range ids {
+		keep[id] = true
+	}
+	var reversed []string
+	for i := len(order) - 1; i >= 0; i-- {
+		if keep[order[i]] {
+			reversed = append(reversed, order[i])
+		}
+	}
+	return reversed
+}
+
+// GroupRm stops and removes the containers of a group, and then its networks
+// and volumes.
+func (daemon *Daemon) GroupRm(name string) error {
+	daemon.groupsLock.Lock()
+	defer daemon.groupsLock.Unlock()
+
+	r, exists := daemon.groupResources()[name]
+	if !exists {
+		return errors.NewRequestNotFoundError(fmt.Errorf("no such group: %s", name))
+	}
+	if errs := daemon.removeGroupResources(name, r); len(errs) > 0 {
+		return fmt.Errorf("error removing group %s: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func (daemon *Daemon) removeGroupResources(name string, r *groupResources) []string {
+	var errs []string
+	for _, id := range r.containers {
+		if err := daemon.ContainerStop(id, groupStopTimeout); err != nil {
+			logrus.Debugf("error stopping container %s of group %s: %v", id, name, err)
+		}
+		if err := daemon.ContainerRm(id, &types.ContainerRmConfig{ForceRemove: true}); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, id := range r.networks {
+		if err := daemon.DeleteNetwork(id); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, v := range r.volumes {
+		if err := daemon.VolumeRm(v); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	for _, err := range errs {
+		logrus.Errorf("error removing a resource of group %s: %s", name, err)
+	}
+	return errs
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type byGroupName []*types.Group
+
+func (g byGroupName) Len() int           { return len(g) }
+func (g byGroupName) Less(i, j int) bool { return g[i].Name < g[j].Name }
+func (g byGroupName) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestGroupStartOrder(t *testing.T) {
+	order, err := groupStartOrder(map[string]types.GroupContainer{
+		"web":    {Image: "nginx", Requires: []string{"app"}},
+		"app":    {Image: "app", Requires: []string{"db", "cache"}},
+		"db":     {Image: "postgres"},
+		"cache":  {Image: "redis"},
+		"worker": {Image: "app", Requires: []string{"db"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"db", "cache", "app", "web", "worker"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestGroupStartOrderInvalid(t *testing.T) {
+	for _, containers := range []map[string]types.GroupContainer{
+		{"app": {Image: "app", Requires: []string{"db"}}},
+		{"app": {Image: "app", Requires: []string{"db"}}, "db": {Image: "postgres", Requires: []string{"app"}}},
+		{"app": {}},
+		{"a/b": {Image: "app"}},
+	} {
+		if _, err := groupStartOrder(containers); err == nil {
+			t.Fatalf("expected an error for %v", containers)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// execHook runs an executable, with the stage as argument and the
+// configuration on its standard input.
+type execHook struct {
+	path    string
+	timeout time.Duration
+}
+
+// execHooks returns the hooks of the executables of dir, sorted by name.
+// The hidden files are ignored, and there is no hook if dir does not exist.
+func execHooks(dir string, timeout time.Duration) ([]Hook, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var hooks []Hook
+	for _, f := range files {
+		if !f.Mode().IsRegular() || !isExecutable(f) || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		hooks = append(hooks, &execHook{path: filepath.Join(dir, f.Name()), timeout: timeout})
+	}
+	return hooks, nil
+}
+
+func (e *execHook) Name() string {
+	return e.path
+}
+
+func (e *execHook) Run(stage Stage, config []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(e.path, string(stage))
+	cmd.Stdin = bytes.NewReader(config)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "DOCKER_HOOK_STAGE="+string(stage))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%v: %s", err, msg)
+			}
+			return err
+		}
+		return nil
+	case <-time.After(e.timeout):
+		cmd.Process.Kill()
+		<-done
+		return fmt.Errorf("timed out after %s", e.timeout)
+	}
+}
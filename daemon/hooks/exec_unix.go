@@ -0,0 +1,10 @@
+// +build !windows
+
+package hooks
+
+import "os"
+
+// isExecutable returns whether a file may be run as a hook.
+func isExecutable(fi os.FileInfo) bool {
+	return fi.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,16 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isExecutable returns whether a file may be run as a hook.
+func isExecutable(fi os.FileInfo) bool {
+	switch strings.ToLower(filepath.Ext(fi.Name())) {
+	case ".exe", ".bat", ".cmd":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// Package hooks runs the hooks of the daemon at the lifecycle transitions
+// of the containers. A hook is an executable of the hooks directory or a
+// plugin, given the configuration of the container as JSON.
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Stage is a lifecycle transition of a container.
+type Stage string
+
+const (
+	// PreCreate is the stage before a container is created. A hook failing
+	// at this stage prevents the container from being created.
+	PreCreate Stage = "pre-create"
+	// PreStart is the stage before the process of a container is started,
+	// after its networking is set up. A hook failing at this stage prevents
+	// the container from being started.
+	PreStart Stage = "pre-start"
+	// PostStop is the stage after the process of a container exited, and
+	// its resources were released.
+	PostStop Stage = "post-stop"
+)
+
+// DefaultTimeout is the time given to a hook to complete before it is
+// killed.
+const DefaultTimeout = 30 * time.Second
+
+// Hook is run at the lifecycle transitions of the containers.
+type Hook interface {
+	// Name returns the name of the hook, for its errors.
+	Name() string
+	// Run runs the hook at a stage with the configuration of a container.
+	Run(stage Stage, config []byte) error
+}
+
+// Hooks are the hooks of the daemon.
+type Hooks struct {
+	dir     string
+	plugins []Hook
+	timeout time.Duration
+}
+
+// New returns the hooks of the executables of dir, unless it is empty, and
+// of the plugins named. The executables are listed at every run, so that
+// hooks may be added or removed without restarting the daemon.
+func New(dir string, pluginNames []string) *Hooks {
+	h := &Hooks{dir: dir, timeout: DefaultTimeout}
+	seen := make(map[string]bool)
+	for _, name := range pluginNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		h.plugins = append(h.plugins, newPluginHook(name))
+	}
+	return h
+}
+
+// Run runs the hooks at a stage, the executables in the order of their
+// names and then the plugins, with the JSON of v on their standard input.
+// Before a transition, the first hook failing stops it and its error is
+// returned. After a transition, all the hooks run and their errors are
+// logged.
+func (h *Hooks) Run(stage Stage, v interface{}) error {
+	if h == nil {
+		return nil
+	}
+	hooks, err := h.hooks()
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+	config, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if err := hook.Run(stage, config); err != nil {
+			err = fmt.Errorf("hook %s failed at %s: %v", hook.Name(), stage, err)
+			if stage == PostStop {
+				logrus.Error(err)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Hooks) hooks() ([]Hook, error) {
+	var hooks []Hook
+	if h.dir != "" {
+		execs, err := execHooks(h.dir, h.timeout)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, execs...)
+	}
+	return append(hooks, h.plugins...), nil
+}
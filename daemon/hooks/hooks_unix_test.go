@@ -0,0 +1,90 @@
+// +build !windows
+
+package hooks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHook(t *testing.T, dir, name, script string, mode os.FileMode) {
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunExecHooks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	writeHook(t, dir, "20-second", `echo "second $1 $DOCKER_HOOK_STAGE" >> `+out+"\n", 0755)
+	writeHook(t, dir, "10-first", `echo "first $1 $(cat)" >> `+out+"\n", 0755)
+	writeHook(t, dir, "30-not-executable", `echo "not executable" >> `+out+"\n", 0644)
+	writeHook(t, dir, ".hidden", `echo hidden >> `+out+"\n", 0755)
+
+	if err := New(dir, nil).Run(PreStart, map[string]string{"Image": "busybox"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "first pre-start {\"Image\":\"busybox\"}\nsecond pre-start pre-start\n"
+	if string(b) != expected {
+		t.Fatalf("expected the hooks to run in order with %q, got %q", expected, b)
+	}
+}
+
+func TestRunExecHooksFailing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	writeHook(t, dir, "10-deny", "echo 'privileged containers are not allowed' >&2\nexit 1\n", 0755)
+	writeHook(t, dir, "20-after", `echo "$1" >> `+out+"\n", 0755)
+
+	err = New(dir, nil).Run(PreCreate, nil)
+	if err == nil || !strings.Contains(err.Error(), "privileged containers are not allowed") || !strings.Contains(err.Error(), "pre-create") {
+		t.Fatalf("expected the error of the hook, got %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Fatal("expected the hooks after a failing pre-create hook not to run")
+	}
+
+	if err := New(dir, nil).Run(PostStop, nil); err != nil {
+		t.Fatalf("expected the errors of the post-stop hooks to be logged, got %v", err)
+	}
+	if b, err := ioutil.ReadFile(out); err != nil || string(b) != "post-stop\n" {
+		t.Fatalf("expected the hooks after a failing post-stop hook to run, got %q, %v", b, err)
+	}
+}
+
+func TestRunExecHooksTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hooks-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeHook(t, dir, "sleep", "exec sleep 10\n", 0755)
+	h := New(dir, nil)
+	h.timeout = 100 * time.Millisecond
+	if err := h.Run(PreStart, nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected the hook to time out, got %v", err)
+	}
+
+	if err := New(filepath.Join(dir, "missing"), nil).Run(PreStart, nil); err != nil {
+		t.Fatalf("expected no hook without a hooks directory, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/docker/docker/pkg/plugins"
+)
+
+const (
+	// HookAPIRun is the url of the method of the hook plugins run at the
+	// lifecycle transitions of the containers
+	HookAPIRun = "ContainerHook.Run"
+
+	// HookAPIImplements is the name of the interface all hook plugins implement
+	HookAPIImplements = "ContainerHook"
+)
+
+// PluginRequest is the request to a hook plugin.
+type PluginRequest struct {
+	// Stage is the lifecycle transition of the container
+	Stage Stage
+	// Container is the configuration of the container
+	Container json.RawMessage
+}
+
+// PluginResponse is the response of a hook plugin.
+type PluginResponse struct {
+	// Err is the error of the hook, failing it if not empty
+	Err string
+}
+
+// pluginHook is an internal adapter to docker plugin system
+type pluginHook struct {
+	name   string
+	mu     sync.Mutex
+	plugin *plugins.Client
+}
+
+func newPluginHook(name string) Hook {
+	return &pluginHook{name: name}
+}
+
+func (p *pluginHook) Name() string {
+	return p.name
+}
+
+func (p *pluginHook) Run(stage Stage, config []byte) error {
+	if err := p.initPlugin(); err != nil {
+		return err
+	}
+	var res PluginResponse
+	if err := p.plugin.Call(HookAPIRun, &PluginRequest{Stage: stage, Container: config}, &res); err != nil {
+		return err
+	}
+	if res.Err != "" {
+		return errors.New(res.Err)
+	}
+	return nil
+}
+
+// initPlugin initializes the hook plugin if needed. It is retried until the
+// plugin is found, as it may be started after the daemon.
+func (p *pluginHook) initPlugin() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.plugin != nil {
+		return nil
+	}
+	plugin, err := plugins.Get(p.name, HookAPIImplements)
+	if err != nil {
+		return err
+	}
+	p.plugin = plugin.Client()
+	return nil
+}
@@ -0,0 +1,57 @@
+package icc
+
+import (
+	"sync"
+
+	"github.com/docker/libnetwork/iptables"
+)
+
+// mu serializes the programming of the chains.
+var mu sync.Mutex
+
+// Program replaces the rules of the chain of a network with the rules of
+// args, and makes the traffic between the containers on bridge go through it
+// before the enable_icc rule of the network.
+func Program(chain, bridge string, args [][]string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !iptables.ExistChain(chain, iptables.Filter) {
+		if _, err := iptables.Raw("-t", string(iptables.Filter), "-N", chain); err != nil {
+			return err
+		}
+	}
+	if _, err := iptables.Raw("-t", string(iptables.Filter), "-F", chain); err != nil {
+		return err
+	}
+	for _, a := range args {
+		if _, err := iptables.Raw(append([]string{"-t", string(iptables.Filter), "-A", chain}, a...)...); err != nil {
+			return err
+		}
+	}
+	jump := []string{"-i", bridge, "-o", bridge, "-j", chain}
+	if !iptables.Exists(iptables.Filter, "FORWARD", jump...) {
+		if _, err := iptables.Raw(append([]string{"-t", string(iptables.Filter), "-I", "FORWARD"}, jump...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove removes the chain of a network, and its jump from the traffic
+// between the containers on bridge.
+func Remove(chain, bridge string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	jump := []string{"-i", bridge, "-o", bridge, "-j", chain}
+	if iptables.Exists(iptables.Filter, "FORWARD", jump...) {
+		if _, err := iptables.Raw(append([]string{"-t", string(iptables.Filter), "-D", "FORWARD"}, jump...)...); err != nil {
+			return err
+		}
+	}
+	if !iptables.ExistChain(chain, iptables.Filter) {
+		return nil
+	}
+	return iptables.RemoveExistingChain(chain, iptables.Filter)
+}
@@ -0,0 +1,213 @@
+// Package icc implements the rules of the communication between the
+// containers of a bridge network, allowing or denying the traffic from some
+// containers to others, selected by name or label.
+package icc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// RulesOption is the option of a bridge network holding its rules, separated
+// by semicolons, like:
+//
+//	deny from=label:tenant=a to=label:tenant=b; allow from=name:web to=name:db port=5432/tcp
+//
+// The first rule matching the traffic applies, the traffic matching no rule
+// is allowed or denied by the enable_icc option of the network.
+const RulesOption = "com.docker.network.icc.rules"
+
+// Rule allows or denies the traffic from the containers matching From to the
+// containers matching To, on Port with Proto if Port is not 0.
+type Rule struct {
+	Allow  bool
+	From   Selector
+	To     Selector
+	Port   int
+	Proto  string
+	source string
+}
+
+// String returns the rule as it was given.
+func (r Rule) String() string {
+	return r.source
+}
+
+// Selector selects the containers by name or by label, or all of them if it
+// is empty.
+type Selector struct {
+	Name       string
+	LabelKey   string
+	LabelValue string
+}
+
+// Any returns whether the selector selects all the containers.
+func (s Selector) Any() bool {
+	return s.Name == "" && s.LabelKey == ""
+}
+
+// Match returns whether the selector selects the endpoint of a container.
+func (s Selector) Match(ep Endpoint) bool {
+	if s.Name != "" {
+		return s.Name == ep.Name
+	}
+	if s.LabelKey != "" {
+		v, ok := ep.Labels[s.LabelKey]
+		return ok && (s.LabelValue == "" || s.LabelValue == v)
+	}
+	return true
+}
+
+// Endpoint is the endpoint of a container on the network.
+type Endpoint struct {
+	Name   string
+	Labels map[string]string
+	IP     net.IP
+}
+
+// ParseRules parses the rules of RulesOption.
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	for _, s := range strings.Split(spec, ";") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		r, err := parseRule(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inter-container communication rule %q: %v", s, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func parseRule(s string) (Rule, error) {
+	fields := strings.Fields(s)
+	r := Rule{source: s}
+	switch fields[0] {
+	case "allow":
+		r.Allow = true
+	case "deny":
+	default:
+		return r, fmt.Errorf("the action must be allow or deny")
+	}
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return r, fmt.Errorf("expected key=value, got %s", f)
+		}
+		var err error
+		switch kv[0] {
+		case "from":
+			r.From, err = parseSelector(kv[1])
+		case "to":
+			r.To, err = parseSelector(kv[1])
+		case "port":
+			r.Port, r.Proto, err = parsePort(kv[1])
+		default:
+			err = fmt.Errorf("unknown key %s, use from, to or port", kv[0])
+		}
+		if err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+func parseSelector(s string) (Selector, error) {
+	if s == "*" {
+		return Selector{}, nil
+	}
+	kv := strings.SplitN(s, ":", 2)
+	if len(kv) != 2 || kv[1] == "" {
+		return Selector{}, fmt.Errorf("the containers must be selected with *, name:<name> or label:<key>[=<value>], got %s", s)
+	}
+	switch kv[0] {
+	case "name":
+		return Selector{Name: strings.TrimPrefix(kv[1], "/")}, nil
+	case "label":
+		label := strings.SplitN(kv[1], "=", 2)
+		sel := Selector{LabelKey: label[0]}
+		if len(label) == 2 {
+			sel.LabelValue = label[1]
+		}
+		return sel, nil
+	}
+	return Selector{}, fmt.Errorf("the containers must be selected with *, name:<name> or label:<key>[=<value>], got %s", s)
+}
+
+func parsePort(s string) (int, string, error) {
+	proto := "tcp"
+	if i := strings.Index(s, "/"); i >= 0 {
+		s, proto = s[:i], s[i+1:]
+	}
+	if proto != "tcp" && proto != "udp" {
+		return 0, "", fmt.Errorf("the protocol must be tcp or udp, got %s", proto)
+	}
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, "", fmt.Errorf("invalid port %s", s)
+	}
+	return port, proto, nil
+}
+
+// ChainName returns the name of the iptables chain of the rules of a
+// network.
+func ChainName(networkID string) string {
+	if len(networkID) > 12 {
+		networkID = networkID[:12]
+	}
+	return "DOCKER-ICC-" + networkID
+}
+
+// Args returns the arguments of the iptables rules of the chain of a network
+// enforcing rules for the endpoints of its containers. The replies to the
+// connections allowed are accepted first.
+func Args(rules []Rule, endpoints []Endpoint) [][]string {
+	args := [][]string{{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}}
+	for _, r := range rules {
+		target := "DROP"
+		if r.Allow {
+			target = "ACCEPT"
+		}
+		var match []string
+		if r.Port != 0 {
+			match = []string{"-p", r.Proto, "--dport", strconv.Itoa(r.Port)}
+		}
+		for _, src := range addresses(r.From, endpoints) {
+			for _, dst := range addresses(r.To, endpoints) {
+				if src != "" && src == dst {
+					continue
+				}
+				var a []string
+				if src != "" {
+					a = append(a, "-s", src)
+				}
+				if dst != "" {
+					a = append(a, "-d", dst)
+				}
+				a = append(a, match...)
+				args = append(args, append(a, "-j", target))
+			}
+		}
+	}
+	return args
+}
+
+// addresses returns the IP addresses of the endpoints selected, or a single
+// empty address if the selector selects all of them.
+func addresses(s Selector, endpoints []Endpoint) []string {
+	if s.Any() {
+		return []string{""}
+	}
+	var ips []string
+	for _, ep := range endpoints {
+		if ep.IP != nil && s.Match(ep) {
+			ips = append(ips, ep.IP.String())
+		}
+	}
+	return ips
+}
@@ -0,0 +1,72 @@
+package icc
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("deny from=label:tenant=a to=label:tenant ; allow from=name:/web to=name:db port=5432;allow to=* port=53/udp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Rule{
+		{From: Selector{LabelKey: "tenant", LabelValue: "a"}, To: Selector{LabelKey: "tenant"}},
+		{Allow: true, From: Selector{Name: "web"}, To: Selector{Name: "db"}, Port: 5432, Proto: "tcp"},
+		{Allow: true, Port: 53, Proto: "udp"},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("expected %d rules, got %v", len(expected), rules)
+	}
+	for i := range expected {
+		expected[i].source = rules[i].source
+		if !reflect.DeepEqual(rules[i], expected[i]) {
+			t.Fatalf("expected the rule %d to be %+v, got %+v", i, expected[i], rules[i])
+		}
+	}
+
+	for spec, msg := range map[string]string{
+		"reject from=name:web":          "allow or deny",
+		"allow from=host:web":           "must be selected",
+		"allow from=name:":              "must be selected",
+		"allow to=name:db port=http":    "invalid port",
+		"allow to=name:db port=53/sctp": "tcp or udp",
+		"allow via=name:db":             "unknown key",
+		"allow from":                    "key=value",
+	} {
+		if _, err := ParseRules(spec); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expected an error containing %q for %q, got %v", msg, spec, err)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	rules, err := ParseRules("allow from=name:web to=label:tier=db port=5432; deny from=* to=label:tier=db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoints := []Endpoint{
+		{Name: "web", IP: net.ParseIP("172.18.0.2")},
+		{Name: "db1", Labels: map[string]string{"tier": "db"}, IP: net.ParseIP("172.18.0.3")},
+		{Name: "db2", Labels: map[string]string{"tier": "db"}, IP: net.ParseIP("172.18.0.4")},
+		{Name: "cache", Labels: map[string]string{"tier": "cache"}, IP: net.ParseIP("172.18.0.5")},
+	}
+	expected := [][]string{
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"},
+		{"-s", "172.18.0.2", "-d", "172.18.0.3", "-p", "tcp", "--dport", "5432", "-j", "ACCEPT"},
+		{"-s", "172.18.0.2", "-d", "172.18.0.4", "-p", "tcp", "--dport", "5432", "-j", "ACCEPT"},
+		{"-d", "172.18.0.3", "-j", "DROP"},
+		{"-d", "172.18.0.4", "-j", "DROP"},
+	}
+	if args := Args(rules, endpoints); !reflect.DeepEqual(args, expected) {
+		t.Fatalf("expected the rules %v, got %v", expected, args)
+	}
+}
+
+func TestChainName(t *testing.T) {
+	if name := ChainName("8b111217944ba0ba844a65b13efcd57dc494932ee2527577758f939315ba2c5b"); name != "DOCKER-ICC-8b111217944b" {
+		t.Fatalf("expected DOCKER-ICC-8b111217944b, got %s", name)
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"net"
+	"strings"
+
+	"github.com/docker/docker/daemon/icc"
+	"github.com/docker/docker/pkg/loglevel"
+	"github.com/docker/libnetwork"
+	"github.com/docker/libnetwork/drivers/bridge"
+)
+
+// iccRules returns the rules of the communication between the containers of
+// a network, and the name of its bridge, or no rules if the network is not a
+// bridge network or has none.
+func iccRules(n libnetwork.Network) ([]icc.Rule, string, error) {
+	if n.Type() != "bridge" {
+		return nil, "", nil
+	}
+	options := n.Info().DriverOptions()
+	rules, err := icc.ParseRules(options[icc.RulesOption])
+	if err != nil || len(rules) == 0 {
+		return nil, "", err
+	}
+	bridgeName := options[bridge.BridgeName]
+	if bridgeName == "" {
+		bridgeName = "br-" + n.ID()[:12]
+	}
+	return rules, bridgeName, nil
+}
+
+// updateICCRules programs the rules of the communication between the
+// containers of the network n with the addresses of its containers, if it
+// has rules, after a container is connected or disconnected.
+func (daemon *Daemon) updateICCRules(n libnetwork.Network) {
+	if !daemon.configStore.bridgeConfig.EnableIPTables {
+		return
+	}
+	rules, bridgeName, err := iccRules(n)
+	if err != nil || len(rules) == 0 {
+		return
+	}
+
+	var endpoints []icc.Endpoint
+	for _, c := range daemon.List() {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		epSettings, ok := c.NetworkSettings.Networks[n.Name()]
+		if !ok || epSettings == nil || epSettings.IPAddress == "" {
+			continue
+		}
+		endpoints = append(endpoints, icc.Endpoint{
+			Name:   strings.TrimPrefix(c.Name, "/"),
+			Labels: c.Config.Labels,
+			IP:     net.ParseIP(epSettings.IPAddress),
+		})
+	}
+	if err := icc.Program(icc.ChainName(n.ID()), bridgeName, icc.Args(rules, endpoints)); err != nil {
+		loglevel.WithSubsystem(loglevel.Network).Errorf("Could not program the inter-container communication rules of network %s: %v", n.Name(), err)
+	}
+}
+
+// removeICCRules removes the rules of the communication between the
+// containers of the network n, when it is deleted.
+func (daemon *Daemon) removeICCRules(n libnetwork.Network) {
+	rules, bridgeName, err := iccRules(n)
+	if err != nil || len(rules) == 0 {
+		return
+	}
+	if err := icc.Remove(icc.ChainName(n.ID()), bridgeName); err != nil {
+		loglevel.WithSubsystem(loglevel.Network).Warnf("Could not remove the inter-container communication rules of network %s: %v", n.Name(), err)
+	}
+}
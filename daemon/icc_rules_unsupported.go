@@ -0,0 +1,15 @@
+// +build !linux
+
+package daemon
+
+import "github.com/docker/libnetwork"
+
+// updateICCRules does nothing: the rules of the communication between the
+// containers are enforced with iptables, which this platform does not have.
+func (daemon *Daemon) updateICCRules(n libnetwork.Network) {
+}
+
+// removeICCRules does nothing: the rules of the communication between the
+// containers are enforced with iptables, which this platform does not have.
+func (daemon *Daemon) removeICCRules(n libnetwork.Network) {
+}
@@ -1,24 +1,59 @@
 package daemon
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/docker/docker/image/tarexport"
+	"github.com/docker/docker/pkg/archive"
 )
 
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
-// outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
+// the same tag are exported. names is the set of tags to export, exclude
+// the images whose layers are not exported, format is the layout of the
+// archive, "docker" or "oci", compression is the compression of the
+// archive, "none" or "gzip", and outStream is the writer which the images
+// are written to.
+func (daemon *Daemon) ExportImage(names, exclude []string, format, compression string, outStream io.Writer) error {
+	var c archive.Compression
+	switch compression {
+	case "", "none":
+		c = archive.Uncompressed
+	case "gzip":
+		c = archive.Gzip
+	default:
+		return fmt.Errorf("invalid export compression %q: must be \"none\" or \"gzip\"", compression)
+	}
+	if format == "oci" && len(exclude) > 0 {
+		return fmt.Errorf("excluding the layers of images is not supported with the \"oci\" export format")
+	}
+
+	compressed, err := archive.CompressStream(outStream, c)
+	if err != nil {
+		return err
+	}
+
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, daemon)
-	return imageExporter.Save(names, outStream)
+	switch format {
+	case "", "docker":
+		err = imageExporter.SaveExcluding(names, exclude, compressed)
+	case "oci":
+		err = imageExporter.SaveOCILayout(names, compressed)
+	default:
+		err = fmt.Errorf("invalid export format %q: must be \"docker\" or \"oci\"", format)
+	}
+	if err != nil {
+		return err
+	}
+	return compressed.Close()
 }
 
 // LoadImage uploads a set of images into the repository. This is the
-// complement of ImageExport.  The input stream is an uncompressed tar
-// ball containing images and metadata.
+// complement of ImageExport.  The input stream is a tar ball, uncompressed
+// or compressed, containing images and metadata, in the docker or the OCI
+// image layout format. The layers already present are not loaded again.
 func (daemon *Daemon) LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore, daemon)
 	return imageExporter.Load(inTar, outStream, quiet)
@@ -3,54 +3,52 @@ package daemon
 import (
 	"fmt"
 
-	"github.com/docker/docker/layer"
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
 )
 
 // ImageHistory returns a slice of ImageHistory structures for the specified image
-// name by walking the image lineage.
-func (daemon *Daemon) ImageHistory(name string) ([]*types.ImageHistory, error) {
+// name by walking the image lineage. The disk space used by the layers is
+// only computed if diskSize is set.
+func (daemon *Daemon) ImageHistory(name string, diskSize bool) ([]*types.ImageHistory, error) {
 	img, err := daemon.GetImage(name)
 	if err != nil {
 		return nil, err
 	}
 
+	layers, err := daemon.imageMetadataService().Layers(img, diskSize)
+	if err != nil {
+		return nil, err
+	}
+
 	history := []*types.ImageHistory{}
 
 	layerCounter := 0
-	rootFS := *img.RootFS
-	rootFS.DiffIDs = nil
-
 	for _, h := range img.History {
-		var layerSize int64
+		entry := &types.ImageHistory{
+			ID:        "<missing>",
+			Created:   h.Created.Unix(),
+			CreatedBy: h.CreatedBy,
+			Comment:   h.Comment,
+		}
 
 		if !h.EmptyLayer {
-			if len(img.RootFS.DiffIDs) <= layerCounter {
+			if len(layers) <= layerCounter {
 				return nil, fmt.Errorf("too many non-empty layers in History section")
 			}
 
-			rootFS.Append(img.RootFS.DiffIDs[layerCounter])
-			l, err := daemon.layerStore.Get(rootFS.ChainID())
-			if err != nil {
-				return nil, err
-			}
-			layerSize, err = l.DiffSize()
-			layer.ReleaseAndLog(daemon.layerStore, l)
-			if err != nil {
-				return nil, err
+			l := layers[layerCounter]
+			entry.Size = l.Size
+			entry.DiskSize = l.DiskSize
+			entry.DiffID = l.DiffID.String()
+			for _, dgst := range l.Digests {
+				entry.Digests = append(entry.Digests, dgst.String())
 			}
 
 			layerCounter++
 		}
 
-		history = append([]*types.ImageHistory{{
-			ID:        "<missing>",
-			Created:   h.Created.Unix(),
-			CreatedBy: h.CreatedBy,
-			Comment:   h.Comment,
-			Size:      layerSize,
-		}}, history...)
+		history = append([]*types.ImageHistory{entry}, history...)
 	}
 
 	// Fill in image IDs and tags
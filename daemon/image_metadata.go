@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// layerMetadata describes a layer of an image.
+type layerMetadata struct {
+	DiffID layer.DiffID
+	// Size is the size of the files of the layer.
+	Size int64
+	// DiskSize is the disk space used by the storage driver for the
+	// layer, if it was requested and the driver reports it.
+	DiskSize int64
+	// Digests are the digests of the layer in the registries the image
+	// was pulled from or pushed to.
+	Digests []digest.Digest
+}
+
+// imageMetadataService describes the layers of the images, from the layer
+// store and from the distribution metadata recorded by pulls and pushes.
+type imageMetadataService struct {
+	layerStore        layer.Store
+	v2MetadataService *metadata.V2MetadataService
+}
+
+func (daemon *Daemon) imageMetadataService() *imageMetadataService {
+	return &imageMetadataService{
+		layerStore:        daemon.layerStore,
+		v2MetadataService: metadata.NewV2MetadataService(daemon.distributionMetadataStore),
+	}
+}
+
+// Layers returns the metadata of the layers of img, from the base layer to
+// the top layer. The disk space used by the layers is only computed if
+// diskSize is set, as it may walk the files of the layers.
+func (s *imageMetadataService) Layers(img *image.Image, diskSize bool) ([]layerMetadata, error) {
+	var layers []layerMetadata
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		m, err := s.layer(rootFS.ChainID(), diskSize)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, m)
+	}
+	return layers, nil
+}
+
+func (s *imageMetadataService) layer(chainID layer.ChainID, diskSize bool) (layerMetadata, error) {
+	l, err := s.layerStore.Get(chainID)
+	if err != nil {
+		return layerMetadata{}, err
+	}
+	defer layer.ReleaseAndLog(s.layerStore, l)
+
+	m := layerMetadata{DiffID: l.DiffID()}
+	if m.Size, err = l.DiffSize(); err != nil {
+		return layerMetadata{}, err
+	}
+	if ds, ok := l.(layer.DiskSizer); ok && diskSize {
+		if m.DiskSize, err = ds.DiskSize(); err != nil {
+			return layerMetadata{}, err
+		}
+	}
+
+	// The layer has no distribution metadata if it was never pulled or
+	// pushed.
+	v2Metadata, _ := s.v2MetadataService.GetMetadata(m.DiffID)
+	seen := make(map[digest.Digest]struct{})
+	for _, v := range v2Metadata {
+		if _, ok := seen[v.Digest]; !ok {
+			seen[v.Digest] = struct{}{}
+			m.Digests = append(m.Digests, v.Digest)
+		}
+	}
+	return m, nil
+}
@@ -1,7 +1,9 @@
 package daemon
 
 import (
+	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/distribution/digest"
@@ -15,8 +17,9 @@ import (
 )
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
-// tag may be either empty, or indicate a specific tag to pull.
-func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// tag may be either empty, or indicate a specific tag to pull. platform selects
+// the image to pull from a manifest list, it defaults to the daemon's platform.
+func (daemon *Daemon) PullImage(ctx context.Context, image, tag, platform string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Special case: "pull -a" may send an image name with a
 	// trailing :. This is ugly, but let's not break API
 	// compatibility.
@@ -27,7 +30,20 @@ func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHead
 		return err
 	}
 
+	if _, err := distribution.ParsePlatform(platform); err != nil {
+		return err
+	}
+
 	if tag != "" {
+		// A digest-pinned reference must not be resolved again through a
+		// tag, or the image pulled could differ from the one requested.
+		if canonical, ok := ref.(reference.Canonical); ok {
+			if tag != canonical.Digest().String() {
+				return fmt.Errorf("cannot pull %s by tag %q: the reference is pinned to a digest", ref.String(), tag)
+			}
+			return daemon.pullImageWithReference(ctx, ref, platform, metaHeaders, authConfig, outStream)
+		}
+
 		// The "tag" could actually be a digest.
 		var dgst digest.Digest
 		dgst, err = digest.ParseDigest(tag)
@@ -41,11 +57,12 @@ func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHead
 		}
 	}
 
-	return daemon.pullImageWithReference(ctx, ref, metaHeaders, authConfig, outStream)
+	return daemon.pullImageWithReference(ctx, ref, platform, metaHeaders, authConfig, outStream)
 }
 
-// PullOnBuild tells Docker to pull image referenced by `name`.
-func (daemon *Daemon) PullOnBuild(ctx context.Context, name string, authConfigs map[string]types.AuthConfig, output io.Writer) (builder.Image, error) {
+// PullOnBuild tells Docker to pull image referenced by `name` for the given
+// platform.
+func (daemon *Daemon) PullOnBuild(ctx context.Context, name string, authConfigs map[string]types.AuthConfig, platform string, output io.Writer) (builder.Image, error) {
 	ref, err := reference.ParseNamed(name)
 	if err != nil {
 		return nil, err
@@ -67,13 +84,32 @@ func (daemon *Daemon) PullOnBuild(ctx context.Context, name string, authConfigs
 		pullRegistryAuth = &resolvedConfig
 	}
 
-	if err := daemon.pullImageWithReference(ctx, ref, nil, pullRegistryAuth, output); err != nil {
+	if err := daemon.pullImageWithReference(ctx, ref, platform, nil, pullRegistryAuth, output); err != nil {
 		return nil, err
 	}
 	return daemon.GetImage(name)
 }
 
-func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, platform string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	// With content trust, the signed digest of a tag is pulled and then
+	// tagged, so that the tag can't be resolved to another image.
+	var tagged reference.NamedTagged
+	if daemon.configStore.ContentTrust {
+		if reference.IsNameOnly(ref) {
+			return fmt.Errorf("cannot pull all the tags of %s: only signed images can be pulled", ref.String())
+		}
+		trustConfig := &distribution.TrustConfig{
+			Dir:    filepath.Join(daemon.configStore.Root, "trust"),
+			Server: daemon.configStore.ContentTrustServer,
+		}
+		trustedRef, err := distribution.ResolveTrustedReference(ctx, trustConfig, ref, authConfig)
+		if err != nil {
+			return err
+		}
+		tagged, _ = ref.(reference.NamedTagged)
+		ref = trustedRef
+	}
+
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -97,10 +133,24 @@ func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		DownloadManager:  daemon.downloadManager,
+		Platform:         platform,
+		LayerCache:       daemon.layerCache,
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
 	close(progressChan)
 	<-writesDone
-	return err
+	if err != nil || tagged == nil {
+		return err
+	}
+
+	imageID, err := daemon.referenceStore.Get(ref)
+	if err != nil {
+		return err
+	}
+	if err := daemon.referenceStore.AddTag(tagged, imageID, true); err != nil {
+		return err
+	}
+	daemon.LogImageEvent(imageID.String(), tagged.String(), "tag")
+	return nil
 }
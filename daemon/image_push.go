@@ -49,6 +49,7 @@ func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHead
 		ReferenceStore:   daemon.referenceStore,
 		TrustKey:         daemon.trustKey,
 		UploadManager:    daemon.uploadManager,
+		Compression:      daemon.configStore.pushCompression(),
 	}
 
 	err = distribution.Push(ctx, ref, imagePushConfig)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"sort"
+	"strings"
 
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
@@ -13,10 +14,11 @@ import (
 )
 
 var acceptedImageFilterTags = map[string]bool{
-	"dangling": true,
-	"label":    true,
-	"before":   true,
-	"since":    true,
+	"dangling":  true,
+	"label":     true,
+	"before":    true,
+	"since":     true,
+	"reference": true,
 }
 
 // byCreated is a temporary type used to sort a list of images by creation
@@ -136,6 +138,20 @@ func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Imag
 		newImage := newImage(img, size)
 
 		for _, ref := range daemon.referenceStore.References(id) {
+			if imageFilters.Include("reference") {
+				var found bool
+				for _, pattern := range imageFilters.Get("reference") {
+					if found, err = matchReference(pattern, ref); err != nil {
+						return nil, err
+					}
+					if found {
+						break
+					}
+				}
+				if !found {
+					continue
+				}
+			}
 			if filter != "" { // filter by tag/repo name
 				if filterTagged { // filter by tag, require full ref match
 					if ref.String() != filter {
@@ -159,7 +175,7 @@ func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Imag
 					//dangling=false case, so dangling image is not needed
 					continue
 				}
-				if filter != "" { // skip images with no references if filtering by tag
+				if filter != "" || imageFilters.Include("reference") { // skip images with no references if filtering by tag
 					continue
 				}
 				newImage.RepoDigests = []string{"<none>@<none>"}
@@ -179,6 +195,21 @@ func (daemon *Daemon) Images(filterArgs, filter string, all bool) ([]*types.Imag
 	return images, nil
 }
 
+// matchReference reports whether ref matches the shell glob pattern. A
+// pattern with a tag or digest is matched against the whole reference,
+// other patterns against the repository name only.
+func matchReference(pattern string, ref reference.Named) (bool, error) {
+	target := ref.Name()
+	if strings.ContainsAny(pattern[strings.LastIndex(pattern, "/")+1:], ":@") {
+		target = ref.String()
+	}
+	matched, err := path.Match(pattern, target)
+	if err != nil {
+		return false, fmt.Errorf("Invalid filter 'reference=%s': %v", pattern, err)
+	}
+	return matched, nil
+}
+
 func newImage(image *image.Image, size int64) *types.Image {
 	newImage := new(types.Image)
 	newImage.ParentID = image.Parent.String()
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/reference"
+)
+
+func TestMatchReference(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		ref      string
+		expected bool
+	}{
+		{"busybox", "busybox:latest", true},
+		{"busy*", "busybox:latest", true},
+		{"busybox", "myregistry:5000/busybox:latest", false},
+		{"*/busybox", "myregistry:5000/busybox:latest", true},
+		{"myregistry:5000/*", "myregistry:5000/busybox:latest", true},
+		{"busybox:latest", "busybox:latest", true},
+		{"busybox:1.*", "busybox:1.24", true},
+		{"busybox:1.*", "busybox:latest", false},
+		{"team/*:v*", "team/app:v2", true},
+		{"team/*", "team/app:v2", true},
+		{"team/*", "other/app:v2", false},
+		{"busybox@sha256:*", "busybox@sha256:cbbf2f9a99b47fc460d422812b6a5adff7dfee951d8fa2e4a98caa0382cfbdbf", true},
+	}
+
+	for _, c := range cases {
+		ref, err := reference.ParseNamed(c.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matched, err := matchReference(c.pattern, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != c.expected {
+			t.Fatalf("Expected %v for pattern %q and reference %q, got %v", c.expected, c.pattern, c.ref, matched)
+		}
+	}
+
+	ref, _ := reference.ParseNamed("busybox")
+	if _, err := matchReference("[", ref); err == nil {
+		t.Fatal("Expected an error for an invalid pattern")
+	}
+}
@@ -1,9 +1,13 @@
 package daemon
 
 import (
+	"archive/tar"
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -24,7 +28,9 @@ import (
 // ImportImage imports an image, getting the archived layer data either from
 // inConfig (if src is "-"), or from a URI specified in src. Progress output is
 // written to outStream. Repository and tag names can optionally be given in
-// the repo and tag arguments, respectively.
+// the repo and tag arguments, respectively. The runtime configuration of an
+// archive exported with its metadata is the base of the configuration of the
+// image, changes being applied to it.
 func (daemon *Daemon) ImportImage(src string, repository, tag string, msg string, inConfig io.ReadCloser, outStream io.Writer, changes []string) error {
 	var (
 		sf     = streamformatter.NewJSONStreamFormatter()
@@ -87,8 +93,17 @@ func (daemon *Daemon) ImportImage(src string, repository, tag string, msg string
 	if err != nil {
 		return err
 	}
+	metadata, layerData, err := readExportMetadata(inflatedLayerData)
+	if err != nil {
+		return err
+	}
+	if metadata != nil {
+		if config, err = dockerfile.BuildFromConfig(metadata, changes); err != nil {
+			return err
+		}
+	}
 	// TODO: support windows baselayer?
-	l, err := daemon.layerStore.Register(inflatedLayerData, "")
+	l, err := daemon.layerStore.Register(layerData, "")
 	if err != nil {
 		return err
 	}
@@ -133,3 +148,28 @@ func (daemon *Daemon) ImportImage(src string, repository, tag string, msg string
 	outStream.Write(sf.FormatStatus("", id.String()))
 	return nil
 }
+
+// readExportMetadata reads the metadata file at the beginning of an archive
+// exported by ContainerExport, if any. It returns the metadata, or nil, and
+// the archive without the metadata file.
+func readExportMetadata(r io.Reader) (*container.Config, io.Reader, error) {
+	var buf bytes.Buffer
+	tr := tar.NewReader(io.TeeReader(r, &buf))
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != exportMetadataFile || hdr.Typeflag != tar.TypeReg {
+		// The errors of the archive are reported by the layer store.
+		return nil, io.MultiReader(&buf, r), nil
+	}
+	headerSize := int64(buf.Len())
+
+	config := &container.Config{}
+	if err := json.NewDecoder(tr).Decode(config); err != nil {
+		return nil, nil, fmt.Errorf("Error reading the metadata of the archive: %v", err)
+	}
+	// Skip what was not read of the entry, and its padding.
+	entrySize := headerSize + (hdr.Size+511)&^511
+	if _, err := io.CopyN(ioutil.Discard, r, entrySize-int64(buf.Len())); err != nil {
+		return nil, nil, fmt.Errorf("Error reading the metadata of the archive: %v", err)
+	}
+	return config, r, nil
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/engine-api/types/container"
+)
+
+func readArchiveNames(t *testing.T, r io.Reader) []string {
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestReadExportMetadata(t *testing.T) {
+	config := &container.Config{
+		Env:    []string{"PATH=/bin"},
+		Cmd:    []string{"/bin/sh"},
+		Labels: map[string]string{"com.example": "value"},
+	}
+	header, err := exportMetadataHeader(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arch, err := archive.Generate("a", "content of a", "b", "content of b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, rest, err := readExportMetadata(io.MultiReader(bytes.NewReader(header), arch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(metadata, config) {
+		t.Fatalf("expected the metadata %+v, got %+v", config, metadata)
+	}
+	if names := readArchiveNames(t, rest); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Fatalf("expected the archive without the metadata, got %v", names)
+	}
+}
+
+func TestReadExportMetadataWithoutMetadata(t *testing.T) {
+	arch, err := archive.Generate("a", "content of a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, rest, err := readExportMetadata(arch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata != nil {
+		t.Fatalf("expected no metadata, got %+v", metadata)
+	}
+	if names := readArchiveNames(t, rest); !reflect.DeepEqual(names, []string{"a"}) {
+		t.Fatalf("expected the archive to be unchanged, got %v", names)
+	}
+}
@@ -1,8 +1,10 @@
 package daemon
 
 import (
+	"fmt"
 	"os"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/docker/docker/pkg/parsers/kernel"
 	"github.com/docker/docker/pkg/parsers/operatingsystem"
 	"github.com/docker/docker/pkg/platform"
+	"github.com/docker/docker/pkg/plugins"
 	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/registry"
@@ -136,6 +139,28 @@ func (daemon *Daemon) SystemInfo() (*types.Info, error) {
 		v.DefaultRuntime = daemon.configStore.GetDefaultRuntimeName()
 	}
 
+	if capabilities, err := getSystemdCapabilities(); err != nil {
+		logrus.Warnf("Could not get the capabilities of systemd: %v", err)
+	} else {
+		v.SystemdCapabilities = capabilities
+	}
+
+	if nodes, err := sysinfo.NUMANodes(); err != nil {
+		logrus.Warnf("Could not get the NUMA nodes: %v", err)
+	} else {
+		for _, n := range nodes {
+			v.NUMANodes = append(v.NUMANodes, types.NUMANode{ID: n.ID, CPUs: n.CPUs, MemTotal: n.MemTotal})
+		}
+	}
+
+	v.CgroupLeaks = daemon.cgroupLeaksInfo()
+
+	for _, h := range pluginsHealth() {
+		if !h.Healthy {
+			v.Warnings = append(v.Warnings, fmt.Sprintf("plugin %s is not responding: %s", h.Name, h.Error))
+		}
+	}
+
 	hostname := ""
 	if hn, err := os.Hostname(); err != nil {
 		logrus.Warnf("Could not get hostname: %v", err)
@@ -147,6 +172,89 @@ func (daemon *Daemon) SystemInfo() (*types.Info, error) {
 	return v, nil
 }
 
+// SystemDiagnostics returns the self-diagnostics of the daemon: the
+// features of the host it cannot use, and the health of its drivers and
+// plugins.
+func (daemon *Daemon) SystemDiagnostics() (*types.Diagnostics, error) {
+	capabilities, err := getSystemdCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	return &types.Diagnostics{
+		CgroupDriver:        daemon.getCgroupDriver(),
+		SystemdCapabilities: capabilities,
+		Driver:              daemon.GraphDriverName(),
+		DriverStatus:        daemon.layerStore.DriverStatus(),
+		KernelWarnings:      kernelWarnings(sysinfo.New(true)),
+		Plugins:             pluginsHealth(),
+	}, nil
+}
+
+// kernelWarnings returns the features of the kernel missing to the daemon.
+func kernelWarnings(sysInfo *sysinfo.SysInfo) []string {
+	// sysInfo.cgroupMemInfo and sysInfo.cgroupCpuInfo are nil on Windows.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	var warnings []string
+	for _, w := range []struct {
+		missing bool
+		warning string
+	}{
+		{!sysInfo.MemoryLimit, "No memory limit support"},
+		{!sysInfo.SwapLimit, "No swap limit support"},
+		{!sysInfo.KernelMemory, "No kernel memory limit support"},
+		{!sysInfo.OomKillDisable, "No oom kill disable support"},
+		{!sysInfo.CPUCfsQuota, "No cpu cfs quota support"},
+		{!sysInfo.CPUCfsPeriod, "No cpu cfs period support"},
+		{!sysInfo.CPUShares, "No cpu shares support"},
+		{!sysInfo.Cpuset, "No cpuset support"},
+		{sysInfo.IPv4ForwardingDisabled, "IPv4 forwarding is disabled"},
+		{sysInfo.BridgeNFCallIPTablesDisabled, "bridge-nf-call-iptables is disabled"},
+		{sysInfo.BridgeNFCallIP6TablesDisabled, "bridge-nf-call-ip6tables is disabled"},
+	} {
+		if w.missing {
+			warnings = append(warnings, w.warning)
+		}
+	}
+	return warnings
+}
+
+// pluginPingTimeout is the time a plugin has to respond to be healthy.
+const pluginPingTimeout = 5 * time.Second
+
+// pluginsHealth pings the plugins loaded by the daemon, concurrently.
+func pluginsHealth() []types.PluginHealth {
+	loaded := plugins.Loaded()
+	health := make([]types.PluginHealth, len(loaded))
+	var wg sync.WaitGroup
+	for i, p := range loaded {
+		health[i] = types.PluginHealth{Name: p.Name()}
+		if p.Manifest != nil {
+			health[i].Implements = p.Manifest.Implements
+		}
+		wg.Add(1)
+		go func(h *types.PluginHealth, p *plugins.Plugin) {
+			defer wg.Done()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- p.Ping()
+			}()
+			select {
+			case err := <-errCh:
+				if err != nil {
+					h.Error = err.Error()
+				}
+			case <-time.After(pluginPingTimeout):
+				h.Error = fmt.Sprintf("no response in %v", pluginPingTimeout)
+			}
+			h.Healthy = h.Error == ""
+		}(&health[i], p)
+	}
+	wg.Wait()
+	return health
+}
+
 // SystemVersion returns version information about the daemon.
 func (daemon *Daemon) SystemVersion() types.Version {
 	v := types.Version{
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/engine-api/types/versions"
 	"github.com/docker/engine-api/types/versions/v1p20"
@@ -37,6 +38,12 @@ func (daemon *Daemon) ContainerInspectCurrent(name string, size bool) (*types.Co
 	container.Lock()
 	defer container.Unlock()
 
+	return daemon.containerInspectCurrent(container, size)
+}
+
+// containerInspectCurrent returns low-level information about a locked
+// container.
+func (daemon *Daemon) containerInspectCurrent(container *container.Container, size bool) (*types.ContainerJSON, error) {
 	base, err := daemon.getInspectData(container, size)
 	if err != nil {
 		return nil, err
@@ -62,11 +69,34 @@ func (daemon *Daemon) ContainerInspectCurrent(name string, size bool) (*types.Co
 	return &types.ContainerJSON{
 		ContainerJSONBase: base,
 		Mounts:            mountPoints,
-		Config:            container.Config,
+		Config:            daemon.inspectConfig(container),
 		NetworkSettings:   networkSettings,
 	}, nil
 }
 
+// ContainerEnv returns the environment of the process of a container, as
+// resolved by the daemon from its configuration and its links, with the
+// values of its secret variables masked.
+func (daemon *Daemon) ContainerEnv(name string) ([]string, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	container.Lock()
+	defer container.Unlock()
+
+	// the links only contribute to the environment of a running container
+	var linkedEnv []string
+	if container.IsRunning() {
+		linkedEnv, err = daemon.setupLinkedContainers(container)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return daemon.envMasker.Env(container.CreateDaemonEnvironment(linkedEnv)), nil
+}
+
 // containerInspect120 serializes the master version of a container into a json type.
 func (daemon *Daemon) containerInspect120(name string) (*v1p20.ContainerJSON, error) {
 	container, err := daemon.GetContainer(name)
@@ -84,7 +114,7 @@ func (daemon *Daemon) containerInspect120(name string) (*v1p20.ContainerJSON, er
 
 	mountPoints := addMountPoints(container)
 	config := &v1p20.ContainerConfig{
-		Config:          container.Config,
+		Config:          daemon.inspectConfig(container),
 		MacAddress:      container.Config.MacAddress,
 		NetworkDisabled: container.Config.NetworkDisabled,
 		ExposedPorts:    container.Config.ExposedPorts,
@@ -100,6 +130,17 @@ func (daemon *Daemon) containerInspect120(name string) (*v1p20.ContainerJSON, er
 	}, nil
 }
 
+// inspectConfig returns the configuration of a container, with the values
+// of its secret environment variables masked.
+func (daemon *Daemon) inspectConfig(container *container.Container) *containertypes.Config {
+	if daemon.envMasker == nil {
+		return container.Config
+	}
+	config := *container.Config
+	config.Env = daemon.envMasker.Env(config.Env)
+	return &config
+}
+
 func (daemon *Daemon) getInspectData(container *container.Container, size bool) (*types.ContainerJSONBase, error) {
 	// make a copy to play with
 	hostConfig := *container.HostConfig
@@ -128,6 +169,7 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		Dead:       container.State.Dead,
 		Pid:        container.State.Pid,
 		ExitCode:   container.State.ExitCode(),
+		Signal:     signalName(container.State.Signal),
 		Error:      container.State.Error(),
 		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
 		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
@@ -209,6 +251,7 @@ func (daemon *Daemon) VolumeInspect(name string) (*types.Volume, error) {
 	apiV := volumeToAPIType(v)
 	apiV.Mountpoint = v.Path()
 	apiV.Status = v.Status()
+	apiV.UsageData = daemon.volumeUsage(v)
 	return apiV, nil
 }
 
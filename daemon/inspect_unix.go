@@ -43,7 +43,7 @@ func (daemon *Daemon) containerInspectPre120(name string) (*v1p19.ContainerJSON,
 	}
 
 	config := &v1p19.ContainerConfig{
-		Config:          container.Config,
+		Config:          daemon.inspectConfig(container),
 		MacAddress:      container.Config.MacAddress,
 		NetworkDisabled: container.Config.NetworkDisabled,
 		ExposedPorts:    container.Config.ExposedPorts,
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/distribution/layercache"
+)
+
+// startLayerCache serves the layers of the daemon to the other daemons with
+// the same token, and sets up the layer caches of the other daemons as a
+// source of the layers of the pulled images, if the daemon is configured to.
+func (daemon *Daemon) startLayerCache(config *Config) error {
+	if config.LayerCacheListen == "" && len(config.LayerCachePeers) == 0 {
+		return nil
+	}
+	if config.LayerCacheToken == "" {
+		return errors.New("the layer cache requires a token, set with --layer-cache-token")
+	}
+	if len(config.LayerCachePeers) > 0 {
+		daemon.layerCache = layercache.NewClient(config.LayerCachePeers, config.LayerCacheToken)
+	}
+	if config.LayerCacheListen == "" {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", config.LayerCacheListen)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Serving the layer cache on %s", l.Addr())
+	go func() {
+		if err := http.Serve(l, layercache.NewHandler(daemon.layerStore, config.LayerCacheToken)); err != nil {
+			logrus.Errorf("The layer cache stopped serving: %v", err)
+		}
+	}()
+	return nil
+}
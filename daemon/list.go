@@ -26,9 +26,11 @@ var acceptedPsFilterTags = map[string]bool{
 	"ancestor":  true,
 	"before":    true,
 	"exited":    true,
+	"health":    true,
 	"id":        true,
 	"isolation": true,
 	"label":     true,
+	"label!":    true,
 	"name":      true,
 	"status":    true,
 	"since":     true,
@@ -65,6 +67,8 @@ func (daemon *Daemon) List() []*container.Container {
 type listContext struct {
 	// idx is the container iteration index for this context
 	idx int
+	// skipped is the number of containers skipped for the offset
+	skipped int
 	// ancestorFilter tells whether it should check ancestors or not
 	ancestorFilter bool
 	// names is a list of container names to filter with
@@ -196,6 +200,12 @@ func (daemon *Daemon) reducePsContainer(container *container.Container, ctx *lis
 		return nil, errStopIteration
 	}
 
+	// skip the first containers of the list up to the offset
+	if ctx.skipped < ctx.Offset {
+		ctx.skipped++
+		return nil, nil
+	}
+
 	// transform internal container struct into api structs
 	return reducer(container, ctx)
 }
@@ -234,6 +244,16 @@ func (daemon *Daemon) foldFilter(config *types.ContainerListOptions) (*listConte
 		return nil, err
 	}
 
+	err = psFilters.WalkValues("health", func(value string) error {
+		if !container.IsValidHealthString(value) {
+			return fmt.Errorf("Unrecognised filter value for health: %s", value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var beforeContFilter, sinceContFilter *container.Container
 
 	err = psFilters.WalkValues("before", func(value string) error {
@@ -323,6 +343,11 @@ func includeContainerInList(container *container.Container, ctx *listContext) it
 		return excludeContainer
 	}
 
+	// Do not include container if any of the negated labels match
+	if matchAnyLabel(ctx.filters, "label!", container.Config.Labels) {
+		return excludeContainer
+	}
+
 	// Do not include container if isolation doesn't match
 	if excludeContainer == excludeByIsolation(container, ctx) {
 		return excludeContainer
@@ -352,6 +377,11 @@ func includeContainerInList(container *container.Container, ctx *listContext) it
 		return excludeContainer
 	}
 
+	// Do not include container if its health status doesn't match the filter
+	if !ctx.filters.ExactMatch("health", container.State.HealthString()) {
+		return excludeContainer
+	}
+
 	if ctx.filters.Include("volume") {
 		volumesByName := make(map[string]*volume.MountPoint)
 		for _, m := range container.MountPoints {
@@ -407,6 +437,20 @@ func includeContainerInList(container *container.Container, ctx *listContext) it
 	return includeContainer
 }
 
+// matchAnyLabel returns whether any of the values of the field of the
+// filters, "key" or "key=value", matches the labels.
+func matchAnyLabel(psFilters filters.Args, field string, labels map[string]string) bool {
+	matched := fmt.Errorf("label matched")
+	err := psFilters.WalkValues(field, func(value string) error {
+		kv := strings.SplitN(value, "=", 2)
+		if v, ok := labels[kv[0]]; ok && (len(kv) == 1 || kv[1] == v) {
+			return matched
+		}
+		return nil
+	})
+	return err == matched
+}
+
 // transformContainer generates the container type expected by the docker ps command.
 func (daemon *Daemon) transformContainer(container *container.Container, ctx *listContext) (*types.Container, error) {
 	newC := &types.Container{
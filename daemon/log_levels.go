@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/loglevel"
+	"github.com/docker/engine-api/types"
+)
+
+// LogLevels returns the levels of the logs of the daemon.
+func (daemon *Daemon) LogLevels() types.LogLevels {
+	levels := types.LogLevels{
+		Level:      loglevel.Level().String(),
+		Subsystems: make(map[string]string),
+	}
+	for name, l := range loglevel.SubsystemLevels() {
+		levels.Subsystems[name] = l.String()
+	}
+	return levels
+}
+
+// SetLogLevels changes the levels of the logs of the daemon, until it
+// restarts or reloads its configuration. The level is not changed if it is
+// empty, and the levels of the subsystems are not changed if they are nil.
+func (daemon *Daemon) SetLogLevels(levels types.LogLevels) error {
+	var level logrus.Level
+	if levels.Level != "" {
+		var err error
+		if level, err = logrus.ParseLevel(levels.Level); err != nil {
+			return errors.NewBadRequestError(fmt.Errorf("invalid log level: %s", levels.Level))
+		}
+	}
+	subsystems, err := loglevel.ParseSubsystemLevels(levels.Subsystems)
+	if err != nil {
+		return errors.NewBadRequestError(err)
+	}
+
+	if levels.Level != "" {
+		loglevel.SetLevel(level)
+	}
+	if levels.Subsystems != nil {
+		loglevel.SetSubsystemLevels(subsystems)
+	}
+	logrus.Infof("Log levels changed to %v, subsystems %v", loglevel.Level(), loglevel.SubsystemLevels())
+	return nil
+}
@@ -27,6 +27,9 @@ type JSONFileLogger struct {
 	mu      sync.Mutex
 	readers map[*logger.LogWatcher]struct{} // stores the active log followers
 	extra   []byte                          // json-encoded extra attributes
+	// maxReadRate is the maximum number of bytes per second read from the
+	// logs already written, or 0 for no limit.
+	maxReadRate int64
 }
 
 func init() {
@@ -61,6 +64,18 @@ func New(ctx logger.Context) (logger.Logger, error) {
 		}
 	}
 
+	var maxReadRate int64
+	if rate, ok := ctx.Config["max-read-rate"]; ok {
+		var err error
+		maxReadRate, err = units.FromHumanSize(rate)
+		if err != nil {
+			return nil, err
+		}
+		if maxReadRate < 1 {
+			return nil, fmt.Errorf("max-read-rate must be a positive size")
+		}
+	}
+
 	writer, err := loggerutils.NewRotateFileWriter(ctx.LogPath, capval, maxFiles)
 	if err != nil {
 		return nil, err
@@ -76,10 +91,11 @@ func New(ctx logger.Context) (logger.Logger, error) {
 	}
 
 	return &JSONFileLogger{
-		buf:     bytes.NewBuffer(nil),
-		writer:  writer,
-		readers: make(map[*logger.LogWatcher]struct{}),
-		extra:   extra,
+		buf:         bytes.NewBuffer(nil),
+		writer:      writer,
+		readers:     make(map[*logger.LogWatcher]struct{}),
+		extra:       extra,
+		maxReadRate: maxReadRate,
 	}, nil
 }
 
@@ -109,12 +125,14 @@ func (l *JSONFileLogger) Log(msg *logger.Message) error {
 	return err
 }
 
-// ValidateLogOpt looks for json specific log options max-file & max-size.
+// ValidateLogOpt looks for json specific log options max-file, max-size &
+// max-read-rate.
 func ValidateLogOpt(cfg map[string]string) error {
 	for key := range cfg {
 		switch key {
 		case "max-file":
 		case "max-size":
+		case "max-read-rate":
 		case "labels":
 		case "env":
 		default:
@@ -1,7 +1,7 @@
 package jsonfilelog
 
 import (
-	"bytes"
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -66,7 +66,7 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 
 	if config.Tail != 0 {
 		tailer := ioutils.MultiReadSeeker(append(files, latestFile)...)
-		tailFile(tailer, logWatcher, config.Tail, config.Since)
+		tailFile(tailer, logWatcher, config.Tail, config.Since, l.maxReadRate)
 	}
 
 	// close all the rotated files
@@ -101,15 +101,41 @@ func (l *JSONFileLogger) readLogs(logWatcher *logger.LogWatcher, config logger.R
 	l.writer.NotifyRotateEvict(notifyRotate)
 }
 
-func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time) {
-	var rdr io.Reader = f
+// tailFile sends the last tail lines of f, or all of them if tail is
+// negative, written at or after since. The first line sent is found by
+// seeking f, so that f is streamed from it, at most at maxReadRate bytes per
+// second if maxReadRate is positive.
+func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since time.Time, maxReadRate int64) {
+	var offset int64
 	if tail > 0 {
-		ls, err := tailfile.TailFile(f, tail)
+		o, err := tailfile.TailOffset(f, tail)
 		if err != nil {
 			logWatcher.Err <- err
 			return
 		}
-		rdr = bytes.NewBuffer(bytes.Join(ls, []byte("\n")))
+		offset = o
+	}
+	if !since.IsZero() {
+		size, err := f.Seek(0, os.SEEK_END)
+		if err != nil {
+			logWatcher.Err <- err
+			return
+		}
+		o, err := sinceOffset(f, offset, size, since)
+		if err != nil {
+			logWatcher.Err <- err
+			return
+		}
+		offset = o
+	}
+	if _, err := f.Seek(offset, os.SEEK_SET); err != nil {
+		logWatcher.Err <- err
+		return
+	}
+
+	var rdr io.Reader = f
+	if maxReadRate > 0 {
+		rdr = &rateLimitedReader{r: f, rate: maxReadRate, start: time.Now()}
 	}
 	dec := json.NewDecoder(rdr)
 	l := &jsonlog.JSONLog{}
@@ -128,6 +154,86 @@ func tailFile(f io.ReadSeeker, logWatcher *logger.LogWatcher, tail int, since ti
 	}
 }
 
+// sinceOffset returns the offset in f, of size size, of the first line
+// starting in [lo, size] written at or after since. As the lines are written
+// in order, they are searched by dichotomy instead of being all decoded.
+func sinceOffset(f io.ReadSeeker, lo, size int64, since time.Time) (int64, error) {
+	hi := size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		_, created, err := lineAfter(f, mid)
+		if err != nil {
+			return 0, err
+		}
+		if created == nil || !created.Before(since) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	offset, _, err := lineAfter(f, lo)
+	return offset, err
+}
+
+// lineAfter returns the offset in f of the first line starting at or after
+// p, and the time it was written, or a nil time if there is no such line.
+func lineAfter(f io.ReadSeeker, p int64) (int64, *time.Time, error) {
+	offset := p
+	if p > 0 {
+		// read from the previous byte, so that a line starting at p is
+		// found after the newline ending the previous line
+		offset--
+	}
+	if _, err := f.Seek(offset, os.SEEK_SET); err != nil {
+		return 0, nil, err
+	}
+	rdr := bufio.NewReader(f)
+	if p > 0 {
+		skipped, err := rdr.ReadBytes('\n')
+		offset += int64(len(skipped))
+		if err == io.EOF {
+			return offset, nil, nil
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	line, err := rdr.ReadBytes('\n')
+	if err == io.EOF {
+		return offset, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	var l jsonlog.JSONLog
+	if err := json.Unmarshal(line, &l); err != nil {
+		return 0, nil, err
+	}
+	return offset, &l.Created, nil
+}
+
+// rateLimitedReader reads r at most at rate bytes per second.
+type rateLimitedReader struct {
+	r     io.Reader
+	rate  int64
+	start time.Time
+	read  int64
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.rate {
+		p = p[:r.rate]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		if wait := time.Duration(r.read*int64(time.Second)/r.rate) - time.Since(r.start); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	return n, err
+}
+
 func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, since time.Time) {
 	dec := json.NewDecoder(f)
 	l := &jsonlog.JSONLog{}
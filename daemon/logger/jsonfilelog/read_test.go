@@ -0,0 +1,72 @@
+package jsonfilelog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonlog"
+)
+
+func TestSinceOffset(t *testing.T) {
+	start := time.Date(2016, 7, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	var offsets []int64
+	for i := 0; i < 100; i++ {
+		offsets = append(offsets, int64(buf.Len()))
+		timestamp, err := jsonlog.FastTimeMarshalJSON(start.Add(time.Duration(i) * time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = (&jsonlog.JSONLogs{
+			Log:     []byte(strings.Repeat("x", i) + "\n"),
+			Stream:  "stdout",
+			Created: timestamp,
+		}).MarshalJSONBuf(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteByte('\n')
+	}
+	size := int64(buf.Len())
+	f := bytes.NewReader(buf.Bytes())
+
+	tests := []struct {
+		lo     int64
+		since  time.Time
+		offset int64
+	}{
+		{0, start.Add(-time.Hour), 0},
+		{0, start, 0},
+		{0, start.Add(500 * time.Millisecond), offsets[1]},
+		{0, start.Add(42 * time.Second), offsets[42]},
+		{0, start.Add(99 * time.Second), offsets[99]},
+		{0, start.Add(time.Hour), size},
+		{offsets[50], start.Add(42 * time.Second), offsets[50]},
+	}
+	for _, test := range tests {
+		offset, err := sinceOffset(f, test.lo, size, test.since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if offset != test.offset {
+			t.Fatalf("expected offset %d for the lines since %v, got %d", test.offset, test.since, offset)
+		}
+	}
+}
+
+func TestRateLimitedReader(t *testing.T) {
+	r := &rateLimitedReader{r: strings.NewReader(strings.Repeat("x", 300)), rate: 1000, start: time.Now()}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 300 {
+		t.Fatalf("expected to read 300 bytes, got %d", len(data))
+	}
+	if elapsed := time.Since(r.start); elapsed < 250*time.Millisecond {
+		t.Fatalf("expected reading 300 bytes at 1000 bytes per second to take at least 300ms, took %v", elapsed)
+	}
+}
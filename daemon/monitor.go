@@ -6,10 +6,12 @@ import (
 	"io"
 	"runtime"
 	"strconv"
+	"syscall"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/libcontainerd"
+	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/docker/runconfig"
 )
 
@@ -37,6 +39,9 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
+		if c.Signal != 0 {
+			attributes["signal"] = signalName(c.Signal)
+		}
 		daemon.updateHealthMonitor(c)
 		daemon.LogContainerEventWithAttributes(c, "die", attributes)
 		daemon.Cleanup(c)
@@ -46,6 +51,7 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		if err := c.ToDisk(); err != nil {
 			return err
 		}
+		daemon.runPostStopHooks(c)
 		return daemon.postRunProcessing(c, e)
 	case libcontainerd.StateRestart:
 		c.Lock()
@@ -56,6 +62,9 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
+		if c.Signal != 0 {
+			attributes["signal"] = signalName(c.Signal)
+		}
 		daemon.LogContainerEventWithAttributes(c, "die", attributes)
 		daemon.updateHealthMonitor(c)
 		return c.ToDisk()
@@ -66,6 +75,7 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 			ec := int(e.ExitCode)
 			execConfig.ExitCode = &ec
 			execConfig.Running = false
+			releaseExecCgroups(execConfig)
 			execConfig.Wait()
 			if err := execConfig.CloseStreams(); err != nil {
 				logrus.Errorf("%s: %s", c.ID, err)
@@ -163,3 +173,12 @@ func (daemon *Daemon) AttachStreams(id string, iop libcontainerd.IOPipe) error {
 
 	return nil
 }
+
+// signalName returns the name of the signal that killed a container, or an
+// empty string if it exited.
+func signalName(sig int) string {
+	if sig == 0 {
+		return ""
+	}
+	return signal.SignalName(syscall.Signal(sig))
+}
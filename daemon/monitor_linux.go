@@ -1,8 +1,11 @@
 package daemon
 
 import (
+	"syscall"
+
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/libcontainerd"
+	"github.com/docker/docker/pkg/signal"
 )
 
 // platformConstructExitStatus returns a platform specific exit status structure
@@ -10,7 +13,21 @@ func platformConstructExitStatus(e libcontainerd.StateInfo) *container.ExitStatu
 	return &container.ExitStatus{
 		ExitCode:  int(e.ExitCode),
 		OOMKilled: e.OOMKilled,
+		Signal:    exitSignal(int(e.ExitCode), e.OOMKilled),
+	}
+}
+
+// exitSignal returns the signal that killed the init of a container from its
+// exit status, or 0 if it exited. containerd reports the exit status of a
+// process killed by a signal as 128 plus the number of the signal.
+func exitSignal(exitCode int, oomKilled bool) int {
+	if oomKilled {
+		return int(syscall.SIGKILL)
+	}
+	if exitCode > 128 && signal.ValidSignalForPlatform(syscall.Signal(exitCode-128)) {
+		return exitCode - 128
 	}
+	return 0
 }
 
 // postRunProcessing perfoms any processing needed on the container after it has stopped.
@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestExitSignal(t *testing.T) {
+	for _, c := range []struct {
+		exitCode  int
+		oomKilled bool
+		signal    int
+	}{
+		{0, false, 0},
+		{1, false, 0},
+		{128, false, 0},
+		{137, false, int(syscall.SIGKILL)},
+		{143, false, int(syscall.SIGTERM)},
+		{139, false, int(syscall.SIGSEGV)},
+		{1, true, int(syscall.SIGKILL)},
+		{255, false, 0},
+	} {
+		if signal := exitSignal(c.exitCode, c.oomKilled); signal != c.signal {
+			t.Fatalf("expected signal %d for exit code %d (OOM killed: %v), got %d", c.signal, c.exitCode, c.oomKilled, signal)
+		}
+	}
+}
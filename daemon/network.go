@@ -5,10 +5,13 @@ import (
 	"net"
 	"strings"
 
-	"github.com/Sirupsen/logrus"
 	clustertypes "github.com/docker/docker/daemon/cluster/provider"
+	"github.com/docker/docker/daemon/dscp"
+	"github.com/docker/docker/daemon/icc"
 	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/loglevel"
 	"github.com/docker/docker/runconfig"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/network"
 	"github.com/docker/libnetwork"
@@ -68,6 +71,24 @@ func (daemon *Daemon) GetNetworkByName(name string) (libnetwork.Network, error)
 	return c.NetworkByName(name)
 }
 
+// hostGatewayIP returns the IPv4 address of the gateway of the default
+// bridge network, the address of the host for the containers, which the extra
+// hosts set to host-gateway resolve to.
+func (daemon *Daemon) hostGatewayIP() (string, error) {
+	name := runconfig.DefaultDaemonNetworkMode().NetworkName()
+	n, err := daemon.GetNetworkByName(name)
+	if err != nil {
+		return "", err
+	}
+	ipv4, _ := n.Info().IpamInfo()
+	for _, info := range ipv4 {
+		if info.Gateway != nil {
+			return info.Gateway.IP.String(), nil
+		}
+	}
+	return "", fmt.Errorf("the %s network has no gateway", name)
+}
+
 // GetNetworksByID returns a list of networks whose ID partially matches zero or more networks
 func (daemon *Daemon) GetNetworksByID(partialID string) []libnetwork.Network {
 	c := daemon.netController
@@ -123,7 +144,7 @@ func (daemon *Daemon) SetupIngress(create clustertypes.NetworkCreateRequest, nod
 
 		if n, err := daemon.GetNetworkByName(create.Name); err == nil && n != nil && n.ID() != create.ID {
 			if err := controller.SandboxDestroy("ingress-sbox"); err != nil {
-				logrus.Errorf("Failed to delete stale ingress sandbox: %v", err)
+				loglevel.WithSubsystem(loglevel.Network).Errorf("Failed to delete stale ingress sandbox: %v", err)
 				return
 			}
 
@@ -131,12 +152,12 @@ func (daemon *Daemon) SetupIngress(create clustertypes.NetworkCreateRequest, nod
 			epList := n.Endpoints()
 			for _, ep := range epList {
 				if err := ep.Delete(true); err != nil {
-					logrus.Errorf("Failed to delete endpoint %s (%s): %v", ep.Name(), ep.ID(), err)
+					loglevel.WithSubsystem(loglevel.Network).Errorf("Failed to delete endpoint %s (%s): %v", ep.Name(), ep.ID(), err)
 				}
 			}
 
 			if err := n.Delete(); err != nil {
-				logrus.Errorf("Failed to delete stale ingress network %s: %v", n.ID(), err)
+				loglevel.WithSubsystem(loglevel.Network).Errorf("Failed to delete stale ingress network %s: %v", n.ID(), err)
 				return
 			}
 		}
@@ -145,7 +166,7 @@ func (daemon *Daemon) SetupIngress(create clustertypes.NetworkCreateRequest, nod
 			// If it is any other error other than already
 			// exists error log error and return.
 			if _, ok := err.(libnetwork.NetworkNameError); !ok {
-				logrus.Errorf("Failed creating ingress network: %v", err)
+				loglevel.WithSubsystem(loglevel.Network).Errorf("Failed creating ingress network: %v", err)
 				return
 			}
 
@@ -154,26 +175,26 @@ func (daemon *Daemon) SetupIngress(create clustertypes.NetworkCreateRequest, nod
 
 		n, err := daemon.GetNetworkByID(create.ID)
 		if err != nil {
-			logrus.Errorf("Failed getting ingress network by id after creating: %v", err)
+			loglevel.WithSubsystem(loglevel.Network).Errorf("Failed getting ingress network by id after creating: %v", err)
 			return
 		}
 
 		sb, err := controller.NewSandbox("ingress-sbox", libnetwork.OptionIngress())
 		if err != nil {
 			if _, ok := err.(networktypes.ForbiddenError); !ok {
-				logrus.Errorf("Failed creating ingress sandbox: %v", err)
+				loglevel.WithSubsystem(loglevel.Network).Errorf("Failed creating ingress sandbox: %v", err)
 			}
 			return
 		}
 
 		ep, err := n.CreateEndpoint("ingress-endpoint", libnetwork.CreateOptionIpam(ip, nil, nil, nil))
 		if err != nil {
-			logrus.Errorf("Failed creating ingress endpoint: %v", err)
+			loglevel.WithSubsystem(loglevel.Network).Errorf("Failed creating ingress endpoint: %v", err)
 			return
 		}
 
 		if err := ep.Join(sb, nil); err != nil {
-			logrus.Errorf("Failed joining ingress sandbox to ingress endpoint: %v", err)
+			loglevel.WithSubsystem(loglevel.Network).Errorf("Failed joining ingress sandbox to ingress endpoint: %v", err)
 		}
 	}()
 
@@ -233,6 +254,27 @@ func (daemon *Daemon) createNetwork(create types.NetworkCreateRequest, id string
 		driver = c.Config().Daemon.DefaultDriver
 	}
 
+	for _, upstream := range create.DNSUpstreams {
+		if _, _, err := libnetwork.ParseDNSUpstream(upstream); err != nil {
+			return nil, errors.NewBadRequestError(err)
+		}
+	}
+
+	if spec, ok := create.Options[icc.RulesOption]; ok {
+		if driver != "bridge" {
+			return nil, errors.NewBadRequestError(fmt.Errorf("the inter-container communication rules are only supported by bridge networks"))
+		}
+		if _, err := icc.ParseRules(spec); err != nil {
+			return nil, errors.NewBadRequestError(err)
+		}
+	}
+
+	if v, ok := create.Options[dscp.Option]; ok {
+		if _, err := runconfigopts.ParseDSCP(v); err != nil {
+			return nil, errors.NewBadRequestError(err)
+		}
+	}
+
 	ipam := create.IPAM
 	v4Conf, v6Conf, err := getIpamConfig(ipam.Config)
 	if err != nil {
@@ -244,6 +286,7 @@ func (daemon *Daemon) createNetwork(create types.NetworkCreateRequest, id string
 		libnetwork.NetworkOptionEnableIPv6(create.EnableIPv6),
 		libnetwork.NetworkOptionDriverOpts(create.Options),
 		libnetwork.NetworkOptionLabels(create.Labels),
+		libnetwork.NetworkOptionDNSUpstreams(create.DNSUpstreams),
 	}
 	if create.Internal {
 		nwOptions = append(nwOptions, libnetwork.NetworkOptionInternalNetwork())
@@ -371,6 +414,7 @@ func (daemon *Daemon) deleteNetwork(networkID string, dynamic bool) error {
 	if err := nw.Delete(); err != nil {
 		return err
 	}
+	daemon.removeICCRules(nw)
 	daemon.LogNetworkEvent(nw, "destroy")
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/caps"
+	"github.com/docker/docker/daemon/dscp"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/oci"
 	"github.com/docker/docker/pkg/idtools"
@@ -77,7 +78,10 @@ func setResources(s *specs.Spec, r containertypes.Resources) error {
 	return nil
 }
 
-func setDevices(s *specs.Spec, c *container.Container) error {
+// setDevices adds the devices of a container to its spec, and allows their
+// access in its devices cgroup, with the default rules of the daemon and the
+// rules of the container.
+func setDevices(s *specs.Spec, c *container.Container, defaultRules []string) error {
 	// Build lists of devices allowed and created within the container.
 	var devs []specs.Device
 	devPermissions := s.Linux.Resources.Devices
@@ -97,6 +101,13 @@ func setDevices(s *specs.Spec, c *container.Container) error {
 			},
 		}
 	} else {
+		for _, rule := range defaultRules {
+			p, err := parseDeviceCgroupRule(rule)
+			if err != nil {
+				return err
+			}
+			devPermissions = append(devPermissions, p)
+		}
 		for _, deviceMapping := range c.HostConfig.Devices {
 			d, dPermissions, err := getDevicesFromPath(deviceMapping)
 			if err != nil {
@@ -105,6 +116,13 @@ func setDevices(s *specs.Spec, c *container.Container) error {
 			devs = append(devs, d...)
 			devPermissions = append(devPermissions, dPermissions...)
 		}
+		for _, rule := range c.HostConfig.DeviceCgroupRules {
+			p, err := parseDeviceCgroupRule(rule)
+			if err != nil {
+				return err
+			}
+			devPermissions = append(devPermissions, p)
+		}
 	}
 
 	s.Linux.Devices = append(s.Linux.Devices, devs...)
@@ -213,13 +231,21 @@ func setNamespace(s *specs.Spec, ns specs.Namespace) {
 	s.Linux.Namespaces = append(s.Linux.Namespaces, ns)
 }
 
-func setCapabilities(s *specs.Spec, c *container.Container) error {
+// setCapabilities sets the capabilities of the container: the default set,
+// replaced by defaults if any, tweaked by its --cap-add and --cap-drop.
+func setCapabilities(s *specs.Spec, c *container.Container, defaults []string) error {
 	var caplist []string
 	var err error
 	if c.HostConfig.Privileged {
 		caplist = caps.GetAllCapabilities()
 	} else {
-		caplist, err = caps.TweakCapabilities(s.Process.Capabilities, c.HostConfig.CapAdd, c.HostConfig.CapDrop)
+		basics := s.Process.Capabilities
+		if len(defaults) > 0 {
+			if basics, err = caps.TweakCapabilities(nil, defaults, nil); err != nil {
+				return err
+			}
+		}
+		caplist, err = caps.TweakCapabilities(basics, c.HostConfig.CapAdd, c.HostConfig.CapDrop)
 		if err != nil {
 			return err
 		}
@@ -480,7 +506,7 @@ func setMounts(daemon *Daemon, s *specs.Spec, c *container.Container, mounts []c
 		}
 
 		if m.Source == "tmpfs" {
-			data := c.HostConfig.Tmpfs[m.Destination]
+			data := m.Data
 			options := []string{"noexec", "nosuid", "nodev", volume.DefaultPropagationMode}
 			if data != "" {
 				options = append(options, strings.Split(data, ",")...)
@@ -572,6 +598,12 @@ func setMounts(daemon *Daemon, s *specs.Spec, c *container.Container, mounts []c
 		}
 	}
 
+	if c.HostConfig.CgroupDelegate && !c.HostConfig.Privileged {
+		if err := delegateCgroup(s); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -601,6 +633,21 @@ func (daemon *Daemon) populateCommonSpec(s *specs.Spec, c *container.Container)
 	return nil
 }
 
+// cgroupParent returns the cgroup parent of a container: its own, or else
+// the one of the daemon, or else the default of the cgroup driver.
+func (daemon *Daemon) cgroupParent(hostConfig *containertypes.HostConfig) string {
+	if hostConfig.CgroupParent != "" {
+		return hostConfig.CgroupParent
+	}
+	if daemon.configStore.CgroupParent != "" {
+		return daemon.configStore.CgroupParent
+	}
+	if UsingSystemd(daemon.configStore) {
+		return "system.slice"
+	}
+	return "/docker"
+}
+
 func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, error) {
 	s := oci.DefaultSpec()
 	if err := daemon.populateCommonSpec(&s, c); err != nil {
@@ -609,17 +656,8 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 
 	var cgroupsPath string
 	scopePrefix := "docker"
-	parent := "/docker"
+	parent := daemon.cgroupParent(c.HostConfig)
 	useSystemd := UsingSystemd(daemon.configStore)
-	if useSystemd {
-		parent = "system.slice"
-	}
-
-	if c.HostConfig.CgroupParent != "" {
-		parent = c.HostConfig.CgroupParent
-	} else if daemon.configStore.CgroupParent != "" {
-		parent = daemon.configStore.CgroupParent
-	}
 
 	if useSystemd {
 		cgroupsPath = parent + ":" + scopePrefix + ":" + c.ID
@@ -632,9 +670,19 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 	if err := setResources(&s, c.HostConfig.Resources); err != nil {
 		return nil, fmt.Errorf("linux runtime spec resources: %v", err)
 	}
+	if c.HostConfig.DSCP != nil {
+		classID := dscp.ClassID(*c.HostConfig.DSCP)
+		s.Linux.Resources.Network = &specs.Network{ClassID: &classID}
+	}
+	if useSystemd {
+		daemon.adaptSystemdProperties(c, &s, systemdSupports)
+	}
+	if err := daemon.setCpuset(&s, c, parent, useSystemd); err != nil {
+		return nil, fmt.Errorf("linux runtime spec cpuset: %v", err)
+	}
 	s.Linux.Resources.OOMScoreAdj = &c.HostConfig.OomScoreAdj
 	s.Linux.Sysctl = c.HostConfig.Sysctls
-	if err := setDevices(&s, c); err != nil {
+	if err := setDevices(&s, c, daemon.configStore.DefaultDeviceCgroupRules); err != nil {
 		return nil, fmt.Errorf("linux runtime spec devices: %v", err)
 	}
 	if err := setRlimits(daemon, &s, c); err != nil {
@@ -646,7 +694,7 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 	if err := setNamespaces(daemon, &s, c); err != nil {
 		return nil, fmt.Errorf("linux spec namespaces: %v", err)
 	}
-	if err := setCapabilities(&s, c); err != nil {
+	if err := setCapabilities(&s, c, daemon.configStore.DefaultCapabilities); err != nil {
 		return nil, fmt.Errorf("linux spec capabilities: %v", err)
 	}
 	if err := setSeccomp(daemon, &s, c); err != nil {
@@ -663,6 +711,11 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 	}
 	ms = append(ms, c.IpcMounts()...)
 	ms = append(ms, c.TmpfsMounts()...)
+	secretMounts, err := daemon.setupSecrets(c)
+	if err != nil {
+		return nil, err
+	}
+	ms = append(ms, secretMounts...)
 	sort.Sort(mounts(ms))
 	if err := setMounts(daemon, &s, c, ms); err != nil {
 		return nil, fmt.Errorf("linux mounts: %v", err)
@@ -685,7 +738,10 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 	}
 
 	if apparmor.IsEnabled() {
-		appArmorProfile := "docker-default"
+		appArmorProfile := defaultApparmorProfile
+		if daemon.configStore.DefaultAppArmorProfile != "" {
+			appArmorProfile = daemon.configStore.DefaultAppArmorProfile
+		}
 		if len(c.AppArmorProfile) > 0 {
 			appArmorProfile = c.AppArmorProfile
 		} else if c.HostConfig.Privileged {
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/oci"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+func TestSetCapabilitiesWithDefaults(t *testing.T) {
+	c := &container.Container{HostConfig: &containertypes.HostConfig{
+		CapAdd:  []string{"NET_ADMIN"},
+		CapDrop: []string{"CHOWN"},
+	}}
+
+	s := oci.DefaultSpec()
+	if err := setCapabilities(&s, c, []string{"CHOWN", "KILL"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"CAP_KILL", "CAP_NET_ADMIN"}
+	if !reflect.DeepEqual(s.Process.Capabilities, expected) {
+		t.Fatalf("expected the capabilities %v, got %v", expected, s.Process.Capabilities)
+	}
+
+	s = oci.DefaultSpec()
+	defaults := s.Process.Capabilities
+	c.HostConfig.CapAdd, c.HostConfig.CapDrop = nil, nil
+	if err := setCapabilities(&s, c, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Process.Capabilities, defaults) {
+		t.Fatalf("expected the built-in capabilities %v, got %v", defaults, s.Process.Capabilities)
+	}
+
+	if err := setCapabilities(&s, c, []string{"NOT_A_CAPABILITY"}); err == nil {
+		t.Fatal("expected an error with an unknown default capability")
+	}
+}
+
+func TestSetDevicesWithCgroupRules(t *testing.T) {
+	c := &container.Container{HostConfig: &containertypes.HostConfig{
+		Resources: containertypes.Resources{DeviceCgroupRules: []string{"b 8:* rw"}},
+	}}
+
+	s := oci.DefaultSpec()
+	defaults := len(s.Linux.Resources.Devices)
+	if err := setDevices(&s, c, []string{"c 189:* rmw"}); err != nil {
+		t.Fatal(err)
+	}
+	rules := s.Linux.Resources.Devices[defaults:]
+	if len(rules) != 2 {
+		t.Fatalf("expected the default rule and the rule of the container, got %v", rules)
+	}
+	if r := rules[0]; !r.Allow || *r.Type != "c" || *r.Major != 189 || r.Minor != nil || *r.Access != "rmw" {
+		t.Fatalf("unexpected default rule %+v", r)
+	}
+	if r := rules[1]; !r.Allow || *r.Type != "b" || *r.Major != 8 || r.Minor != nil || *r.Access != "rw" {
+		t.Fatalf("unexpected rule %+v", r)
+	}
+
+	s = oci.DefaultSpec()
+	c.HostConfig.DeviceCgroupRules = []string{"c 189 rmw"}
+	if err := setDevices(&s, c, nil); err == nil {
+		t.Fatal("expected an error with an invalid rule")
+	}
+}
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// requiresHealthTimeout is the time the containers restarted with the daemon
+// wait for the containers they require to get healthy.
+const requiresHealthTimeout = 2 * time.Minute
+
+// resolveRequires replaces the names of the containers required by a new
+// container by their IDs, so that they are still found once renamed.
+func (daemon *Daemon) resolveRequires(hostConfig *containertypes.HostConfig) error {
+	for i, name := range hostConfig.Requires {
+		c, err := daemon.GetContainer(name)
+		if err != nil {
+			return fmt.Errorf("Could not get container for required container %s: %v", name, err)
+		}
+		hostConfig.Requires[i] = c.ID
+	}
+	return nil
+}
+
+// checkRequires checks that the containers required by c are running, and
+// are not unhealthy.
+func (daemon *Daemon) checkRequires(c *container.Container) error {
+	for _, id := range c.HostConfig.Requires {
+		r, err := daemon.GetContainer(id)
+		if err != nil {
+			return fmt.Errorf("Cannot start container %s: required container %s: %v", c.ID, id, err)
+		}
+		name := strings.TrimPrefix(r.Name, "/")
+		if !r.IsRunning() {
+			return fmt.Errorf("Cannot start container %s: required container %s is not running", c.ID, name)
+		}
+		if healthStatus(r) == types.Unhealthy {
+			return fmt.Errorf("Cannot start container %s: required container %s is unhealthy", c.ID, name)
+		}
+	}
+	return nil
+}
+
+// waitForHealth waits for a running container with a healthcheck to be
+// healthy or unhealthy, at most for timeout.
+func waitForHealth(c *container.Container, timeout time.Duration) {
+	deadline := time.After(timeout)
+	for c.IsRunning() && healthStatus(c) == types.Starting {
+		select {
+		case <-deadline:
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// healthStatus returns the health status of a container, or "" if it has no
+// healthcheck.
+func healthStatus(c *container.Container) string {
+	c.Lock()
+	defer c.Unlock()
+	if c.State.Health == nil {
+		return ""
+	}
+	return c.State.Health.Status
+}
@@ -17,3 +17,10 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	}
 	return nil
 }
+
+func (daemon *Daemon) setupSeccompProfile() error {
+	if daemon.configStore.SeccompProfile != "" {
+		return fmt.Errorf("seccomp profiles are not supported on this daemon, you cannot specify a default seccomp profile")
+	}
+	return nil
+}
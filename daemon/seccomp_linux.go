@@ -4,6 +4,7 @@ package daemon
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
@@ -36,6 +37,11 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 		if err != nil {
 			return err
 		}
+	} else if daemon.seccompProfile != nil {
+		profile, err = seccomp.LoadProfile(string(daemon.seccompProfile))
+		if err != nil {
+			return err
+		}
 	} else {
 		profile, err = seccomp.GetDefaultProfile(rs)
 		if err != nil {
@@ -46,3 +52,20 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	rs.Linux.Seccomp = profile
 	return nil
 }
+
+// setupSeccompProfile loads the default seccomp profile of the containers set
+// by --seccomp-profile, if any.
+func (daemon *Daemon) setupSeccompProfile() error {
+	if daemon.configStore.SeccompProfile == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(daemon.configStore.SeccompProfile)
+	if err != nil {
+		return fmt.Errorf("opening the seccomp profile (%s) failed: %v", daemon.configStore.SeccompProfile, err)
+	}
+	if _, err := seccomp.LoadProfile(string(b)); err != nil {
+		return fmt.Errorf("invalid seccomp profile (%s): %v", daemon.configStore.SeccompProfile, err)
+	}
+	daemon.seccompProfile = b
+	return nil
+}
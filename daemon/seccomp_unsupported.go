@@ -3,3 +3,7 @@
 package daemon
 
 var supportsSeccomp = false
+
+func (daemon *Daemon) setupSeccompProfile() error {
+	return nil
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/secret"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// secretsMountPath is the directory of the secrets mounted into a container
+// without a target.
+const secretsMountPath = "/run/secrets"
+
+func secretToAPIType(s *secret.Secret) types.Secret {
+	return types.Secret{
+		ID:        s.ID,
+		Name:      s.Name,
+		Labels:    s.Labels,
+		CreatedAt: s.CreatedAt.Format(time.RFC3339Nano),
+		Size:      len(s.Data),
+	}
+}
+
+func secretError(nameOrID string, err error) error {
+	switch err {
+	case secret.ErrDoesNotExist:
+		return errors.NewRequestNotFoundError(fmt.Errorf("no such secret: %s", nameOrID))
+	case secret.ErrNameConflict:
+		return errors.NewRequestConflictError(fmt.Errorf("a secret named %s already exists", nameOrID))
+	}
+	return err
+}
+
+// Secrets returns the secrets of the daemon, without their data.
+func (daemon *Daemon) Secrets() []types.Secret {
+	secrets := []types.Secret{}
+	for _, s := range daemon.secrets.List() {
+		secrets = append(secrets, secretToAPIType(s))
+	}
+	return secrets
+}
+
+// SecretCreate creates a secret.
+func (daemon *Daemon) SecretCreate(req types.SecretCreateRequest) (*types.Secret, error) {
+	s, err := daemon.secrets.Create(req.Name, req.Data, req.Labels)
+	if err != nil {
+		if err == secret.ErrNameConflict {
+			return nil, secretError(req.Name, err)
+		}
+		return nil, errors.NewBadRequestError(err)
+	}
+	created := secretToAPIType(s)
+	return &created, nil
+}
+
+// SecretRm removes a secret, which must not be used by a container.
+func (daemon *Daemon) SecretRm(nameOrID string) error {
+	s, err := daemon.secrets.Get(nameOrID)
+	if err != nil {
+		return secretError(nameOrID, err)
+	}
+	for _, c := range daemon.List() {
+		for _, ref := range c.HostConfig.Secrets {
+			if used, err := daemon.secrets.Get(ref.Name); err == nil && used.ID == s.ID {
+				return errors.NewRequestConflictError(fmt.Errorf("unable to remove secret %s: it is used by container %s", s.Name, c.ID))
+			}
+		}
+	}
+	return secretError(nameOrID, daemon.secrets.Remove(s.ID))
+}
+
+// secretTarget returns the path of a secret in a container.
+func secretTarget(ref containertypes.SecretReference) string {
+	if ref.Target == "" {
+		return path.Join(secretsMountPath, ref.Name)
+	}
+	return ref.Target
+}
+
+// verifySecrets verifies that the secrets of a container exist, and that
+// their targets are distinct absolute paths.
+func (daemon *Daemon) verifySecrets(hostConfig *containertypes.HostConfig) error {
+	targets := make(map[string]bool)
+	for _, ref := range hostConfig.Secrets {
+		if _, err := daemon.secrets.Get(ref.Name); err != nil {
+			return secretError(ref.Name, err)
+		}
+		target := path.Clean(secretTarget(ref))
+		if !path.IsAbs(target) || target == "/" {
+			return fmt.Errorf("invalid target %q of secret %s: it must be an absolute path to a file", ref.Target, ref.Name)
+		}
+		if targets[target] {
+			return fmt.Errorf("duplicate target %s of secret %s", target, ref.Name)
+		}
+		targets[target] = true
+		if ref.Mode&^0777 != 0 {
+			return fmt.Errorf("invalid mode %o of secret %s", ref.Mode, ref.Name)
+		}
+	}
+	return nil
+}
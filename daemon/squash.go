@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// SquashImage creates a new image with the diff of the specified image and
+// the specified parent. The new image contains the layers of the parent plus
+// one layer holding the changes of all the layers in between, and keeps the
+// config and history of the specified image. The existing images are not
+// removed. If no parent is given, all the layers of the image are merged
+// into a single layer.
+func (daemon *Daemon) SquashImage(id, parent string) (string, error) {
+	img, err := daemon.imageStore.Get(image.ID(id))
+	if err != nil {
+		return "", err
+	}
+
+	var parentImg *image.Image
+	var parentChainID layer.ChainID
+	if len(parent) != 0 {
+		parentImg, err = daemon.imageStore.Get(image.ID(parent))
+		if err != nil {
+			return "", fmt.Errorf("error getting specified parent layer: %v", err)
+		}
+		parentChainID = parentImg.RootFS.ChainID()
+	} else {
+		parentImg = &image.Image{RootFS: image.NewRootFS()}
+	}
+
+	l, err := daemon.layerStore.Get(img.RootFS.ChainID())
+	if err != nil {
+		return "", fmt.Errorf("error getting image layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+
+	ts, err := l.TarStreamFrom(parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error getting tar stream to parent: %v", err)
+	}
+	defer ts.Close()
+
+	newL, err := daemon.layerStore.Register(ts, parentChainID)
+	if err != nil {
+		return "", fmt.Errorf("error registering layer: %v", err)
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, newL)
+
+	newImage := *img
+	rootFS := *parentImg.RootFS
+	rootFS.DiffIDs = append(append([]layer.DiffID(nil), rootFS.DiffIDs...), newL.DiffID())
+	newImage.RootFS = &rootFS
+
+	// the history entries created after the parent don't have layers anymore,
+	// the merged layer is described by a new entry
+	newImage.History = make([]image.History, len(img.History))
+	for i, h := range img.History {
+		if i >= len(parentImg.History) {
+			h.EmptyLayer = true
+		}
+		newImage.History[i] = h
+	}
+
+	now := time.Now().UTC()
+	historyComment := fmt.Sprintf("create new from %s", id)
+	if len(parent) > 0 {
+		historyComment = fmt.Sprintf("merge %s to %s", id, parent)
+	}
+	newImage.History = append(newImage.History, image.History{
+		Created: now,
+		Comment: historyComment,
+	})
+	newImage.Created = now
+
+	b, err := json.Marshal(&newImage)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling image config: %v", err)
+	}
+
+	newImgID, err := daemon.imageStore.Create(b)
+	if err != nil {
+		return "", fmt.Errorf("error creating new image after squash: %v", err)
+	}
+	return string(newImgID), nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/hooks"
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/runconfig"
@@ -101,6 +102,10 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return fmt.Errorf("Container is marked for removal and cannot be started.")
 	}
 
+	if err := daemon.checkRequires(container); err != nil {
+		return err
+	}
+
 	// if we encounter an error during start we need to ensure that any other
 	// setup has been cleaned up properly
 	defer func() {
@@ -127,6 +132,10 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return err
 	}
 
+	if err := daemon.runContainerHooks(hooks.PreStart, container); err != nil {
+		return err
+	}
+
 	spec, err := daemon.createSpec(container)
 	if err != nil {
 		return err
@@ -172,6 +181,7 @@ func (daemon *Daemon) Cleanup(container *container.Container) {
 	daemon.releaseNetwork(container)
 
 	container.UnmountIpcMounts(detachMounted)
+	container.UnmountSecrets(detachMounted)
 
 	if err := daemon.conditionalUnmountOnCleanup(container); err != nil {
 		// FIXME: remove once reference counting for graphdrivers has been refactored
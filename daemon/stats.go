@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/statshistory"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/versions"
@@ -123,6 +126,82 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 	}
 }
 
+// ContainerStatsAll writes the samples of all the running containers to the
+// stream given in the config object, until the context is done.
+func (daemon *Daemon) ContainerStatsAll(ctx context.Context, config *backend.ContainerStatsConfig) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("Windows does not support stats")
+	}
+
+	wf := ioutils.NewWriteFlusher(config.OutStream)
+	defer wf.Close()
+	wf.Flush()
+	enc := json.NewEncoder(wf)
+
+	updates := daemon.statsCollector.collectAll()
+	defer daemon.statsCollector.unsubscribeAll(updates)
+
+	preCPUStats := make(map[string]types.CPUStats)
+	for {
+		select {
+		case v, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			ss := v.(types.ContainerStatsJSON)
+			ss.PreCPUStats = preCPUStats[ss.ID]
+			preCPUStats[ss.ID] = ss.CPUStats
+			if err := enc.Encode(&ss); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// ContainerStatsHistory returns the samples of the stats history of the
+// container taken after since, oldest first. There is none if the daemon
+// does not keep the stats history.
+func (daemon *Daemon) ContainerStatsHistory(prefixOrName string, since time.Time) ([]types.StatsSample, error) {
+	container, err := daemon.GetContainer(prefixOrName)
+	if err != nil {
+		return nil, err
+	}
+	path, err := container.StatsHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	return statshistory.Read(path, since)
+}
+
+// statsSample returns the sample of the stats history of a container for
+// its stats s.
+func statsSample(s *types.StatsJSON) types.StatsSample {
+	sample := types.StatsSample{
+		Read:           s.Read,
+		CPUUsage:       s.CPUStats.CPUUsage.TotalUsage,
+		SystemCPUUsage: s.CPUStats.SystemUsage,
+		OnlineCPUs:     uint64(len(s.CPUStats.CPUUsage.PercpuUsage)),
+		MemoryUsage:    s.MemoryStats.Usage,
+		MemoryLimit:    s.MemoryStats.Limit,
+		Pids:           s.PidsStats.Current,
+	}
+	for _, n := range s.Networks {
+		sample.NetworkRx += n.RxBytes
+		sample.NetworkTx += n.TxBytes
+	}
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			sample.BlockRead += e.Value
+		case "write":
+			sample.BlockWrite += e.Value
+		}
+	}
+	return sample
+}
+
 func (daemon *Daemon) subscribeToContainerStats(c *container.Container) chan interface{} {
 	return daemon.statsCollector.collect(c)
 }
@@ -159,7 +238,16 @@ func (daemon *Daemon) getNetworkSandboxID(c *container.Container) (string, error
 	return curr.NetworkSettings.SandboxID, nil
 }
 
+// getNetworkStats returns the stats of the network interfaces of a
+// container: those of its network namespace, whatever its network mode, or
+// else those of its sandbox.
 func (daemon *Daemon) getNetworkStats(c *container.Container) (map[string]types.NetworkStats, error) {
+	if pid := c.State.GetPID(); pid > 0 {
+		if stats, err := netnsStats(pid); err == nil {
+			return stats, nil
+		}
+	}
+
 	sandboxID, err := daemon.getNetworkSandboxID(c)
 	if err != nil {
 		return nil, err
@@ -22,6 +22,8 @@ import (
 type statsSupervisor interface {
 	// GetContainerStats collects all the stats related to a container
 	GetContainerStats(container *container.Container) (*types.StatsJSON, error)
+	// List returns all the containers
+	List() []*container.Container
 }
 
 // newStatsCollector returns a new statsCollector that collections
@@ -33,6 +35,7 @@ func (daemon *Daemon) newStatsCollector(interval time.Duration) *statsCollector
 		interval:            interval,
 		supervisor:          daemon,
 		publishers:          make(map[*container.Container]*pubsub.Publisher),
+		all:                 pubsub.NewPublisher(100*time.Millisecond, 1024),
 		clockTicksPerSecond: uint64(system.GetClockTicks()),
 		bufReader:           bufio.NewReaderSize(nil, 128),
 	}
@@ -54,6 +57,10 @@ type statsCollector struct {
 	publishers          map[*container.Container]*pubsub.Publisher
 	bufReader           *bufio.Reader
 	machineMemory       uint64
+
+	// all publishes the samples of all the running containers, as
+	// types.ContainerStatsJSON.
+	all *pubsub.Publisher
 }
 
 // collect registers the container with the collector and adds it to
@@ -70,6 +77,18 @@ func (s *statsCollector) collect(c *container.Container) chan interface{} {
 	return publisher.Subscribe()
 }
 
+// collectAll returns a channel for the subscriber to receive the samples
+// of all the running containers on.
+func (s *statsCollector) collectAll() chan interface{} {
+	return s.all.Subscribe()
+}
+
+// unsubscribeAll removes a subscriber from receiving the samples of all the
+// running containers.
+func (s *statsCollector) unsubscribeAll(ch chan interface{}) {
+	s.all.Evict(ch)
+}
+
 // stopCollection closes the channels for all subscribers and removes
 // the container from metrics collection.
 func (s *statsCollector) stopCollection(c *container.Container) {
@@ -114,6 +133,20 @@ func (s *statsCollector) run() {
 			pairs = append(pairs, publishersPair{container, publisher})
 		}
 		s.m.Unlock()
+		sampleAll := s.all.Len() > 0
+		if sampleAll {
+			// the running containers without subscribers of their own are
+			// sampled for the subscribers of all the containers.
+			sampled := make(map[*container.Container]bool, len(pairs))
+			for _, pair := range pairs {
+				sampled[pair.container] = true
+			}
+			for _, c := range s.supervisor.List() {
+				if !sampled[c] && c.IsRunning() {
+					pairs = append(pairs, publishersPair{c, nil})
+				}
+			}
+		}
 		if len(pairs) == 0 {
 			continue
 		}
@@ -135,7 +168,16 @@ func (s *statsCollector) run() {
 			// FIXME: move to containerd
 			stats.CPUStats.SystemUsage = systemUsage
 
-			pair.publisher.Publish(*stats)
+			if pair.publisher != nil {
+				pair.publisher.Publish(*stats)
+			}
+			if sampleAll {
+				s.all.Publish(types.ContainerStatsJSON{
+					ID:        pair.container.ID,
+					Name:      pair.container.Name,
+					StatsJSON: *stats,
+				})
+			}
 		}
 	}
 }
@@ -25,6 +25,17 @@ func (s *statsCollector) collect(c *container.Container) chan interface{} {
 	return nil
 }
 
+// collectAll returns a channel for the subscriber to receive the samples
+// of all the running containers on.
+func (s *statsCollector) collectAll() chan interface{} {
+	return nil
+}
+
+// unsubscribeAll removes a subscriber from receiving the samples of all the
+// running containers.
+func (s *statsCollector) unsubscribeAll(ch chan interface{}) {
+}
+
 // stopCollection closes the channels for all subscribers and removes
 // the container from metrics collection.
 func (s *statsCollector) stopCollection(c *container.Container) {
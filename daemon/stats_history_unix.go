@@ -0,0 +1,61 @@
+// +build !windows,!solaris
+
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/statshistory"
+	"github.com/docker/engine-api/types"
+)
+
+// statsHistoryInterval is the interval between the samples of the stats
+// history of a container.
+const statsHistoryInterval = 10 * time.Second
+
+// startStatsHistory starts recording the stats history of the running
+// containers, if the daemon is configured to keep it.
+func (daemon *Daemon) startStatsHistory(config *Config) error {
+	if config.StatsHistory == "" {
+		return nil
+	}
+	retention, err := time.ParseDuration(config.StatsHistory)
+	if err != nil {
+		return fmt.Errorf("invalid stats history %q: %v", config.StatsHistory, err)
+	}
+	if retention < statsHistoryInterval {
+		return fmt.Errorf("invalid stats history %q: it must be at least %v", config.StatsHistory, statsHistoryInterval)
+	}
+	go daemon.recordStatsHistory(int(retention / statsHistoryInterval))
+	return nil
+}
+
+// recordStatsHistory appends a sample of each running container to its
+// stats history every statsHistoryInterval, keeping capacity samples.
+func (daemon *Daemon) recordStatsHistory(capacity int) {
+	updates := daemon.statsCollector.collectAll()
+	defer daemon.statsCollector.unsubscribeAll(updates)
+
+	last := make(map[string]time.Time)
+	for v := range updates {
+		s := v.(types.ContainerStatsJSON)
+		if s.Read.Sub(last[s.ID]) < statsHistoryInterval {
+			continue
+		}
+		c, err := daemon.GetContainer(s.ID)
+		if err != nil {
+			delete(last, s.ID)
+			continue
+		}
+		last[s.ID] = s.Read
+		path, err := c.StatsHistoryPath()
+		if err == nil {
+			err = statshistory.Append(path, capacity, statsSample(&s.StatsJSON))
+		}
+		if err != nil {
+			logrus.Warnf("recording the stats history of %s: %v", s.ID, err)
+		}
+	}
+}
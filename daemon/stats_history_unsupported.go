@@ -0,0 +1,9 @@
+// +build windows solaris
+
+package daemon
+
+// startStatsHistory does nothing: the stats of the containers are not
+// collected on this platform.
+func (daemon *Daemon) startStatsHistory(config *Config) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/engine-api/types"
+)
+
+// netnsStats returns the stats of the network interfaces, but the loopback
+// one, of the network namespace of the process pid. Unlike the stats of the
+// sandbox of a container, they include the interfaces not managed by
+// libnetwork, like those of the host network namespace or those created in
+// the namespace by the processes of the container.
+func netnsStats(pid int) (map[string]types.NetworkStats, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/net/dev", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseNetDev(f)
+}
+
+// parseNetDev parses the stats of the network interfaces in the format of
+// /proc/net/dev: two lines of headers, then one line per interface with its
+// name, 8 receive counters and 8 transmit counters, of which the bytes,
+// packets, errors and drops are the first 4.
+func parseNetDev(r io.Reader) (map[string]types.NetworkStats, error) {
+	stats := make(map[string]types.NetworkStats)
+	scanner := bufio.NewScanner(r)
+	for i := 0; scanner.Scan(); i++ {
+		if i < 2 {
+			continue
+		}
+		line := scanner.Text()
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid network interface stats %q", line)
+		}
+		name := strings.TrimSpace(line[:sep])
+		fields := strings.Fields(line[sep+1:])
+		if len(fields) < 12 {
+			return nil, fmt.Errorf("invalid stats of network interface %s: %q", name, line)
+		}
+		if name == "lo" {
+			continue
+		}
+		var values [12]uint64
+		for j := range values {
+			v, err := strconv.ParseUint(fields[j], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stats of network interface %s: %v", name, err)
+			}
+			values[j] = v
+		}
+		stats[name] = types.NetworkStats{
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		}
+	}
+	return stats, scanner.Err()
+}
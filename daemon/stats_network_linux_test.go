@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestParseNetDev(t *testing.T) {
+	netDev := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1296      16    0    0    0     0          0         0     1296      16    0    0    0     0       0          0
+  eth0:    5338      36    1    2    0     0          0         0      648       8    3    4    0     0       0          0
+veth-a.1:1234567890123 10    0    0    0     0          0         0        0       0    0    0    0     0       0          0
+`
+	stats, err := parseNetDev(strings.NewReader(netDev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]types.NetworkStats{
+		"eth0": {RxBytes: 5338, RxPackets: 36, RxErrors: 1, RxDropped: 2, TxBytes: 648, TxPackets: 8, TxErrors: 3, TxDropped: 4},
+		// the name and the bytes received are not separated by a space
+		"veth-a.1": {RxBytes: 1234567890123, RxPackets: 10},
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("expected the stats of %d interfaces, got %v", len(expected), stats)
+	}
+	for name, s := range expected {
+		if stats[name] != s {
+			t.Fatalf("expected the stats of %s to be %+v, got %+v", name, s, stats[name])
+		}
+	}
+
+	if _, err := parseNetDev(strings.NewReader("header\nheader\n  eth0: 1 2 3\n")); err == nil {
+		t.Fatal("expected an error for truncated stats")
+	}
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package daemon
+
+import (
+	"errors"
+
+	"github.com/docker/engine-api/types"
+)
+
+// netnsStats is not supported: the stats of the network interfaces are
+// those of the sandbox of a container.
+func netnsStats(pid int) (map[string]types.NetworkStats, error) {
+	return nil, errors.New("the stats of network namespaces are only supported on Linux")
+}
@@ -0,0 +1,161 @@
+// Package statshistory stores the recent history of the resource usage of a
+// container in a file, as a ring buffer of samples of a fixed size: once the
+// ring is full, each sample replaces the oldest one.
+package statshistory
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+const (
+	magic = "DSH1"
+	// headerSize is the size of the header of a ring: the magic string, the
+	// capacity, and the count of the samples ever appended.
+	headerSize = 4 + 8 + 8
+	// sampleSize is the size of a sample: its time and ten counters.
+	sampleSize = 8 + 10*8
+)
+
+var errInvalidRing = errors.New("invalid stats history")
+
+type header struct {
+	capacity uint64
+	count    uint64
+}
+
+// Append appends s to the ring in path, holding the last capacity samples.
+// The ring is created if it does not exist, and reset if it was created
+// with another capacity.
+func Append(path string, capacity int, s types.StatsSample) error {
+	if capacity <= 0 {
+		return errors.New("the capacity of a stats history must be positive")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h, err := readHeader(f)
+	if err != nil || h.capacity != uint64(capacity) {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		h = header{capacity: uint64(capacity)}
+	}
+
+	offset := int64(headerSize + (h.count%h.capacity)*sampleSize)
+	if _, err := f.WriteAt(encodeSample(s), offset); err != nil {
+		return err
+	}
+	h.count++
+	return writeHeader(f, h)
+}
+
+// Read returns the samples of the ring in path taken after since, oldest
+// first. There is no sample if the ring does not exist.
+func Read(path string, since time.Time) ([]types.StatsSample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	h, err := readHeader(f)
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	n := h.count
+	if n > h.capacity {
+		n = h.capacity
+	}
+	b := make([]byte, n*sampleSize)
+	if _, err := f.ReadAt(b, headerSize); err != nil {
+		return nil, err
+	}
+
+	var samples []types.StatsSample
+	for i := uint64(0); i < n; i++ {
+		s := decodeSample(b[i*sampleSize : (i+1)*sampleSize])
+		if s.Read.After(since) {
+			samples = append(samples, s)
+		}
+	}
+	sort.Sort(byTime(samples))
+	return samples, nil
+}
+
+func readHeader(f *os.File) (header, error) {
+	b := make([]byte, headerSize)
+	if _, err := f.ReadAt(b, 0); err != nil {
+		return header{}, err
+	}
+	if string(b[:4]) != magic {
+		return header{}, errInvalidRing
+	}
+	h := header{
+		capacity: binary.LittleEndian.Uint64(b[4:12]),
+		count:    binary.LittleEndian.Uint64(b[12:20]),
+	}
+	if h.capacity == 0 {
+		return header{}, errInvalidRing
+	}
+	return h, nil
+}
+
+func writeHeader(f *os.File, h header) error {
+	b := make([]byte, headerSize)
+	copy(b, magic)
+	binary.LittleEndian.PutUint64(b[4:12], h.capacity)
+	binary.LittleEndian.PutUint64(b[12:20], h.count)
+	_, err := f.WriteAt(b, 0)
+	return err
+}
+
+func counters(s *types.StatsSample) []*uint64 {
+	return []*uint64{
+		&s.CPUUsage, &s.SystemCPUUsage, &s.OnlineCPUs,
+		&s.MemoryUsage, &s.MemoryLimit,
+		&s.NetworkRx, &s.NetworkTx,
+		&s.BlockRead, &s.BlockWrite,
+		&s.Pids,
+	}
+}
+
+func encodeSample(s types.StatsSample) []byte {
+	b := make([]byte, sampleSize)
+	binary.LittleEndian.PutUint64(b[0:8], uint64(s.Read.UnixNano()))
+	for i, c := range counters(&s) {
+		binary.LittleEndian.PutUint64(b[8+i*8:16+i*8], *c)
+	}
+	return b
+}
+
+func decodeSample(b []byte) types.StatsSample {
+	var s types.StatsSample
+	s.Read = time.Unix(0, int64(binary.LittleEndian.Uint64(b[0:8])))
+	for i, c := range counters(&s) {
+		*c = binary.LittleEndian.Uint64(b[8+i*8 : 16+i*8])
+	}
+	return s
+}
+
+type byTime []types.StatsSample
+
+func (s byTime) Len() int           { return len(s) }
+func (s byTime) Less(i, j int) bool { return s[i].Read.Before(s[j].Read) }
+func (s byTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,78 @@
+package statshistory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestAppendRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statshistory-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats-history")
+
+	samples, err := Read(path, time.Time{})
+	if err != nil || len(samples) != 0 {
+		t.Fatalf("expected no sample before the first one is appended, got %v, %v", samples, err)
+	}
+
+	start := time.Unix(1000, 0)
+	for i := 0; i < 5; i++ {
+		s := types.StatsSample{Read: start.Add(time.Duration(i) * time.Second), CPUUsage: uint64(i), Pids: 3}
+		if err := Append(path, 3, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The ring only holds the last 3 samples.
+	samples, err = Read(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+	for i, s := range samples {
+		if s.CPUUsage != uint64(i+2) || s.Pids != 3 || !s.Read.Equal(start.Add(time.Duration(i+2)*time.Second)) {
+			t.Fatalf("unexpected sample %d: %+v", i, s)
+		}
+	}
+
+	samples, err = Read(path, start.Add(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].CPUUsage != 4 {
+		t.Fatalf("expected the last sample only, got %+v", samples)
+	}
+}
+
+func TestAppendResetsOnCapacityChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statshistory-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "stats-history")
+
+	if err := Append(path, 2, types.StatsSample{Read: time.Unix(1, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Append(path, 4, types.StatsSample{Read: time.Unix(2, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := Read(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || !samples[0].Read.Equal(time.Unix(2, 0)) {
+		t.Fatalf("expected the history to be reset, got %+v", samples)
+	}
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus"
+)
+
+// systemdCapabilities are the features of systemd the daemon uses, and the
+// first version of systemd having them.
+var systemdCapabilities = []struct {
+	name    string
+	version int
+}{
+	{"StartTransientUnit", 205},
+	{"DefaultDependencies", 207},
+	{"Delegate", 218},
+}
+
+// systemdRunning returns whether the host runs systemd as its init system.
+func systemdRunning() bool {
+	_, err := os.Stat("/run/systemd/system")
+	return err == nil
+}
+
+// getSystemdCapabilities returns the features of systemd the daemon can use,
+// or nil if the host does not run systemd.
+func getSystemdCapabilities() ([]string, error) {
+	if !systemdRunning() {
+		return nil, nil
+	}
+	version, err := getSystemdVersion()
+	if err != nil {
+		return nil, err
+	}
+	return systemdCapabilitiesOf(version), nil
+}
+
+// getSystemdVersion returns the major version of the running systemd.
+func getSystemdVersion() (int, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return 0, err
+	}
+	v, err := conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1").GetProperty("org.freedesktop.systemd1.Manager.Version")
+	if err != nil {
+		return 0, err
+	}
+	version, _ := v.Value().(string)
+	return parseSystemdVersion(version), nil
+}
+
+// parseSystemdVersion returns the major version of systemd, from a version
+// like "systemd 229" or "231.1-2", or 0 if it cannot be parsed.
+func parseSystemdVersion(version string) int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "systemd ")
+	version = strings.TrimPrefix(version, "v")
+	end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		version = version[:end]
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func systemdCapabilitiesOf(version int) []string {
+	var capabilities []string
+	for _, c := range systemdCapabilities {
+		if version >= c.version {
+			capabilities = append(capabilities, c.name)
+		}
+	}
+	return capabilities
+}
@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSystemdVersion(t *testing.T) {
+	for version, expected := range map[string]int{
+		"229":            229,
+		"systemd 219":    219,
+		"231.1-2ubuntu1": 231,
+		"v237":           237,
+		"":               0,
+		"unknown":        0,
+	} {
+		if v := parseSystemdVersion(version); v != expected {
+			t.Fatalf("expected %d for %q, got %d", expected, version, v)
+		}
+	}
+}
+
+func TestSystemdCapabilitiesOf(t *testing.T) {
+	if capabilities := systemdCapabilitiesOf(204); capabilities != nil {
+		t.Fatalf("expected no capabilities, got %v", capabilities)
+	}
+	expected := []string{"StartTransientUnit", "DefaultDependencies"}
+	if capabilities := systemdCapabilitiesOf(209); !reflect.DeepEqual(capabilities, expected) {
+		t.Fatalf("expected %v, got %v", expected, capabilities)
+	}
+}
@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/volume"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// systemdStopSignal is the signal systemd shuts down on.
+const systemdStopSignal = "RTMIN+3"
+
+// systemdTmpfs are the tmpfs mounts systemd expects, with their options.
+var systemdTmpfs = map[string]string{
+	"/run": "rw,nosuid,nodev,mode=755",
+	"/tmp": "rw,nosuid,nodev",
+}
+
+// runsSystemd returns whether a container runs systemd, in its systemd mode:
+// always, or if its command is an init system when the mode is true.
+func runsSystemd(config *containertypes.Config, hostConfig *containertypes.HostConfig) bool {
+	if hostConfig.Systemd.IsAlways() {
+		return true
+	}
+	if !hostConfig.Systemd.IsDetected() {
+		return false
+	}
+	var command []string
+	command = append(command, config.Entrypoint...)
+	command = append(command, config.Cmd...)
+	if len(command) == 0 {
+		return false
+	}
+	switch filepath.Base(command[0]) {
+	case "init", "systemd":
+		return true
+	}
+	return false
+}
+
+// adaptSystemdSettings sets up a container running systemd: its cgroup is
+// delegated, it is stopped with SIGRTMIN+3, and /run and /tmp are tmpfs
+// unless something else is mounted there. The settings given by the user are
+// kept.
+func adaptSystemdSettings(config *containertypes.Config, hostConfig *containertypes.HostConfig) {
+	if !runsSystemd(config, hostConfig) {
+		return
+	}
+
+	hostConfig.CgroupDelegate = true
+	if config.StopSignal == "" {
+		config.StopSignal = systemdStopSignal
+	}
+
+	hasContainerEnv := false
+	for _, env := range config.Env {
+		if strings.HasPrefix(env, "container=") {
+			hasContainerEnv = true
+		}
+	}
+	if !hasContainerEnv {
+		// systemd detects it runs in a container from this variable
+		config.Env = append(config.Env, "container=docker")
+	}
+
+	for dest, options := range systemdTmpfs {
+		if isMountedBy(dest, config, hostConfig) {
+			continue
+		}
+		if hostConfig.Tmpfs == nil {
+			hostConfig.Tmpfs = make(map[string]string)
+		}
+		hostConfig.Tmpfs[dest] = options
+	}
+}
+
+// isMountedBy returns whether the configuration of a container mounts a
+// volume, a bind mount or a tmpfs on dest.
+func isMountedBy(dest string, config *containertypes.Config, hostConfig *containertypes.HostConfig) bool {
+	if _, ok := hostConfig.Tmpfs[dest]; ok {
+		return true
+	}
+	if _, ok := config.Volumes[dest]; ok {
+		return true
+	}
+	for _, bind := range hostConfig.Binds {
+		if mp, err := volume.ParseMountSpec(bind, hostConfig.VolumeDriver); err == nil && mp.Destination == dest {
+			return true
+		}
+	}
+	return false
+}
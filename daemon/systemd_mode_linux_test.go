@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/strslice"
+)
+
+func TestAdaptSystemdSettings(t *testing.T) {
+	config := &containertypes.Config{Cmd: strslice.StrSlice{"/usr/sbin/init"}}
+	hostConfig := &containertypes.HostConfig{
+		Systemd: "true",
+		Binds:   []string{"/srv/tmp:/tmp"},
+	}
+	adaptSystemdSettings(config, hostConfig)
+
+	if !hostConfig.CgroupDelegate {
+		t.Fatal("expected the cgroup to be delegated")
+	}
+	if config.StopSignal != "RTMIN+3" {
+		t.Fatalf("expected the stop signal RTMIN+3, got %q", config.StopSignal)
+	}
+	if len(config.Env) != 1 || config.Env[0] != "container=docker" {
+		t.Fatalf("expected the container environment variable, got %v", config.Env)
+	}
+	if _, ok := hostConfig.Tmpfs["/run"]; !ok {
+		t.Fatal("expected a tmpfs on /run")
+	}
+	if _, ok := hostConfig.Tmpfs["/tmp"]; ok {
+		t.Fatal("expected no tmpfs on /tmp, bind mounted by the user")
+	}
+}
+
+func TestAdaptSystemdSettingsNotDetected(t *testing.T) {
+	config := &containertypes.Config{Cmd: strslice.StrSlice{"/bin/sh"}}
+	hostConfig := &containertypes.HostConfig{Systemd: "true"}
+	adaptSystemdSettings(config, hostConfig)
+
+	if hostConfig.CgroupDelegate || config.StopSignal != "" || hostConfig.Tmpfs != nil {
+		t.Fatal("expected a container not running an init system not to be set up for systemd")
+	}
+
+	hostConfig.Systemd = "always"
+	adaptSystemdSettings(config, hostConfig)
+	if !hostConfig.CgroupDelegate {
+		t.Fatal("expected the cgroup to be delegated when systemd always runs")
+	}
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon
+
+import containertypes "github.com/docker/engine-api/types/container"
+
+// adaptSystemdSettings does nothing: systemd only runs on Linux.
+func adaptSystemdSettings(config *containertypes.Config, hostConfig *containertypes.HostConfig) {
+}
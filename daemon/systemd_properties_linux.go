@@ -0,0 +1,165 @@
+package daemon
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/godbus/dbus"
+	"github.com/opencontainers/specs/specs-go"
+)
+
+const (
+	systemdSliceInterface = "org.freedesktop.systemd1.Slice"
+	systemdScopeInterface = "org.freedesktop.systemd1.Scope"
+)
+
+// systemdProperties are the properties of the scope of a container the
+// systemd cgroup driver may set, the interface of the units having them, and
+// the first version of systemd accepting them in a transient unit. The
+// version is only used when the interface cannot be introspected.
+var systemdProperties = map[string]struct {
+	iface   string
+	version int
+}{
+	"CPUShares":          {systemdSliceInterface, 208},
+	"MemoryLimit":        {systemdSliceInterface, 208},
+	"BlockIOWeight":      {systemdSliceInterface, 208},
+	"CPUQuotaPerSecUSec": {systemdSliceInterface, 213},
+	"TasksMax":           {systemdSliceInterface, 227},
+	"Delegate":           {systemdScopeInterface, 218},
+}
+
+var (
+	systemdPropertiesOnce      sync.Once
+	systemdInterfaceProperties map[string]map[string]bool
+	systemdPropertiesVersion   int
+)
+
+// systemdSupports returns whether the running systemd accepts the property
+// in the scope of a container. The properties of the units are introspected
+// once, falling back to the version of systemd.
+func systemdSupports(property string) bool {
+	systemdPropertiesOnce.Do(func() {
+		var err error
+		systemdInterfaceProperties, err = introspectSystemdProperties()
+		if err != nil {
+			logrus.Warnf("Could not introspect the properties of the systemd units: %v", err)
+		}
+		if systemdPropertiesVersion, err = getSystemdVersion(); err != nil {
+			logrus.Warnf("Could not get the version of systemd: %v", err)
+		}
+	})
+	p, ok := systemdProperties[property]
+	if !ok {
+		return false
+	}
+	if properties, ok := systemdInterfaceProperties[p.iface]; ok {
+		return properties[property]
+	}
+	return systemdPropertiesVersion >= p.version
+}
+
+// systemdUnitStatus is a unit returned by the ListUnits method of systemd.
+type systemdUnitStatus struct {
+	Name        string
+	Description string
+	LoadState   string
+	ActiveState string
+	SubState    string
+	Followed    string
+	Path        dbus.ObjectPath
+	JobID       uint32
+	JobType     string
+	JobPath     dbus.ObjectPath
+}
+
+// introspectSystemdProperties returns the properties of the interfaces of
+// the root slice, and of a scope if there is one: init.scope only exists
+// since systemd 226, and the scopes of the sessions and of the containers
+// only while they run.
+func introspectSystemdProperties() (map[string]map[string]bool, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	paths := []dbus.ObjectPath{"/org/freedesktop/systemd1/unit/_2d_2eslice"}
+	var units []systemdUnitStatus
+	if err := conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1").Call("org.freedesktop.systemd1.Manager.ListUnits", 0).Store(&units); err != nil {
+		return nil, err
+	}
+	for _, u := range units {
+		if strings.HasSuffix(u.Name, ".scope") {
+			paths = append(paths, u.Path)
+			break
+		}
+	}
+
+	interfaces := make(map[string]map[string]bool)
+	for _, path := range paths {
+		var data string
+		if err := conn.Object("org.freedesktop.systemd1", path).Call("org.freedesktop.DBus.Introspectable.Introspect", 0).Store(&data); err != nil {
+			return nil, err
+		}
+		found, err := parseIntrospection(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid introspection of %s: %v", path, err)
+		}
+		for iface, properties := range found {
+			interfaces[iface] = properties
+		}
+	}
+	return interfaces, nil
+}
+
+// parseIntrospection returns the properties of each interface of the XML
+// introspection data of a D-Bus object.
+func parseIntrospection(data string) (map[string]map[string]bool, error) {
+	var node struct {
+		Interfaces []struct {
+			Name       string `xml:"name,attr"`
+			Properties []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"property"`
+		} `xml:"interface"`
+	}
+	if err := xml.Unmarshal([]byte(data), &node); err != nil {
+		return nil, err
+	}
+	interfaces := make(map[string]map[string]bool)
+	for _, iface := range node.Interfaces {
+		properties := make(map[string]bool)
+		for _, p := range iface.Properties {
+			properties[p.Name] = true
+		}
+		interfaces[iface.Name] = properties
+	}
+	return interfaces, nil
+}
+
+// adaptSystemdProperties drops from the spec of a container the limits the
+// systemd cgroup driver would set with a property the running systemd does
+// not accept, which would fail the start of the container, and logs a
+// warning event for each of them.
+func (daemon *Daemon) adaptSystemdProperties(c *container.Container, s *specs.Spec, supports func(string) bool) {
+	var dropped []string
+	r := s.Linux.Resources
+	if r.Pids != nil && r.Pids.Limit != nil && *r.Pids.Limit > 0 && !supports("TasksMax") {
+		r.Pids = nil
+		dropped = append(dropped, "the pids limit is not applied: systemd does not support the TasksMax property")
+	}
+	if r.CPU != nil && r.CPU.Quota != nil && *r.CPU.Quota > 0 && !supports("CPUQuotaPerSecUSec") {
+		r.CPU.Quota = nil
+		dropped = append(dropped, "the CPU quota is not applied: systemd does not support the CPUQuotaPerSecUSec property")
+	}
+	if c.HostConfig.CgroupDelegate && !supports("Delegate") {
+		dropped = append(dropped, "the scope of the container is not delegated: systemd does not support the Delegate property")
+	}
+	for _, message := range dropped {
+		logrus.Warnf("Container %s: %s", c.ID, message)
+		daemon.LogContainerEventWithAttributes(c, "warning", map[string]string{"message": message})
+	}
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	containertypes "github.com/docker/engine-api/types/container"
+	eventtypes "github.com/docker/engine-api/types/events"
+	"github.com/opencontainers/specs/specs-go"
+)
+
+func TestParseIntrospection(t *testing.T) {
+	data := `<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN"
+"http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">
+<node>
+ <interface name="org.freedesktop.systemd1.Scope">
+  <property name="Controller" type="s" access="read"/>
+  <property name="Delegate" type="b" access="read"/>
+  <method name="Abandon"/>
+ </interface>
+ <interface name="org.freedesktop.DBus.Peer">
+  <method name="Ping"/>
+ </interface>
+</node>`
+	interfaces, err := parseIntrospection(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !interfaces[systemdScopeInterface]["Delegate"] {
+		t.Fatalf("expected the Delegate property of the scopes, got %v", interfaces)
+	}
+	if interfaces[systemdScopeInterface]["TasksMax"] {
+		t.Fatal("expected no TasksMax property")
+	}
+	if _, ok := interfaces["org.freedesktop.DBus.Peer"]; !ok {
+		t.Fatal("expected the interfaces without properties")
+	}
+	if _, err := parseIntrospection("<node"); err == nil {
+		t.Fatal("expected an error for invalid data")
+	}
+}
+
+func TestAdaptSystemdProperties(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+	daemon := &Daemon{EventsService: e}
+	c := &container.Container{CommonContainer: container.CommonContainer{
+		ID:         "test",
+		Config:     &containertypes.Config{},
+		HostConfig: &containertypes.HostConfig{CgroupDelegate: true},
+	}}
+	pids := int64(100)
+	quota := uint64(50000)
+	s := &specs.Spec{Linux: specs.Linux{Resources: &specs.Resources{
+		Pids: &specs.Pids{Limit: &pids},
+		CPU:  &specs.CPU{Quota: &quota},
+	}}}
+
+	daemon.adaptSystemdProperties(c, s, func(property string) bool { return property == "CPUQuotaPerSecUSec" })
+	if s.Linux.Resources.Pids != nil {
+		t.Fatal("expected the pids limit to be dropped")
+	}
+	if s.Linux.Resources.CPU.Quota == nil {
+		t.Fatal("expected the CPU quota to be kept")
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-l:
+			if m, ok := msg.(eventtypes.Message); !ok || m.Action != "warning" || m.Actor.Attributes["message"] == "" {
+				t.Fatalf("expected a warning event, got %v", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a warning event for the pids limit and the delegation")
+		}
+	}
+}
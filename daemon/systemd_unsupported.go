@@ -0,0 +1,18 @@
+// +build !linux
+
+package daemon
+
+// systemdRunning returns false: systemd only runs on Linux.
+func systemdRunning() bool {
+	return false
+}
+
+// getSystemdCapabilities returns nil: systemd only runs on Linux.
+func getSystemdCapabilities() ([]string, error) {
+	return nil, nil
+}
+
+// systemdSupports returns false: systemd only runs on Linux.
+func systemdSupports(property string) bool {
+	return false
+}
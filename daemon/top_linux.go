@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// cgroupDir is the directory of a cgroup in a cgroup hierarchy.
+type cgroupDir struct {
+	// subsystems are the subsystems of the hierarchy
+	subsystems []string
+	path       string
+}
+
+// processCgroupDirs returns the directories of the cgroups of the process
+// pid, in all the cgroup hierarchies.
+func processCgroupDirs(pid int) ([]cgroupDir, error) {
+	paths, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, err
+	}
+	mounts, err := sysinfo.CgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []cgroupDir
+	for _, m := range mounts {
+		p, err := m.GetThisCgroupDir(paths)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(m.Root, p)
+		if err != nil {
+			continue
+		}
+		dirs = append(dirs, cgroupDir{subsystems: m.Subsystems, path: filepath.Join(m.Mountpoint, rel)})
+	}
+	return dirs, nil
+}
+
+// containerCgroupProcesses returns the processes in the cgroups of the
+// process pid, in all the cgroup hierarchies, and in their sub-cgroups. Each
+// process is mapped to the path of its sub-cgroup, relative to the cgroup of
+// pid, or to "/" if it is not in a sub-cgroup.
+func containerCgroupProcesses(pid int) (map[int]string, error) {
+	dirs, err := processCgroupDirs(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	procs := make(map[int]string)
+	for _, d := range dirs {
+		dir := d.path
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				var pids []int
+				if pids, err = cgroups.GetPids(p); err == nil {
+					err = addCgroupProcesses(procs, dir, p, pids)
+				}
+			}
+			// the sub-cgroups can be removed during the walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return procs, nil
+}
+
+// addCgroupProcesses maps the processes pids of the cgroup p, a sub-cgroup
+// of dir, to p relative to dir. A process is annotated with the first
+// sub-cgroup it is found in.
+func addCgroupProcesses(procs map[int]string, dir, p string, pids []int) error {
+	sub, err := filepath.Rel(dir, p)
+	if err != nil {
+		return err
+	}
+	sub = filepath.Join("/", sub)
+	for _, pid := range pids {
+		if procs[pid] == "" || procs[pid] == "/" {
+			procs[pid] = sub
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/docker/docker/pkg/loglevel"
 	"github.com/docker/engine-api/types"
 )
 
@@ -81,11 +82,47 @@ func parsePSOutput(output []byte, pids []int) (*types.ContainerProcessList, erro
 	return procList, nil
 }
 
+// annotateCgroups adds a CGROUP column, with the sub-cgroup of each process,
+// to a list of processes of which some are in a sub-cgroup of the container.
+func annotateCgroups(procList *types.ContainerProcessList, cgroups map[int]string) {
+	pidIndex := -1
+	for i, name := range procList.Titles {
+		if name == "PID" {
+			pidIndex = i
+		}
+	}
+
+	annotations := make([]string, len(procList.Processes))
+	inSubCgroup := false
+	for i, process := range procList.Processes {
+		annotations[i] = "/"
+		if pid, err := strconv.Atoi(process[pidIndex]); err == nil && cgroups[pid] != "" {
+			annotations[i] = cgroups[pid]
+		}
+		if annotations[i] != "/" {
+			inSubCgroup = true
+		}
+	}
+	if !inSubCgroup {
+		return
+	}
+
+	procList.Titles = append([]string{"CGROUP"}, procList.Titles...)
+	for i, process := range procList.Processes {
+		procList.Processes[i] = append([]string{annotations[i]}, process...)
+	}
+}
+
 // ContainerTop lists the processes running inside of the given
 // container by calling ps with the given args, or with the flags
 // "-ef" if no args are given.  An error is returned if the container
 // is not found, or is not running, or if there are any problems
 // running ps, or parsing the output.
+//
+// The processes are those reported by containerd, and those found in the
+// cgroups of the container, in all the cgroup hierarchies, and in their
+// sub-cgroups. If some processes are in a sub-cgroup, a CGROUP column
+// gives the sub-cgroup of each process.
 func (daemon *Daemon) ContainerTop(name string, psArgs string) (*types.ContainerProcessList, error) {
 	if psArgs == "" {
 		psArgs = "-ef"
@@ -112,6 +149,19 @@ func (daemon *Daemon) ContainerTop(name string, psArgs string) (*types.Container
 	if err != nil {
 		return nil, err
 	}
+	cgroups, err := containerCgroupProcesses(container.GetPID())
+	if err != nil {
+		loglevel.WithSubsystem(loglevel.Cgroups).Warnf("Failed to list the processes in the cgroups of container %s: %v", container.ID, err)
+	}
+	known := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		known[pid] = true
+	}
+	for pid := range cgroups {
+		if !known[pid] {
+			pids = append(pids, pid)
+		}
+	}
 
 	output, err := exec.Command("ps", strings.Split(psArgs, " ")...).Output()
 	if err != nil {
@@ -121,6 +171,7 @@ func (daemon *Daemon) ContainerTop(name string, psArgs string) (*types.Container
 	if err != nil {
 		return nil, err
 	}
+	annotateCgroups(procList, cgroups)
 	daemon.LogContainerEvent(container, "top")
 	return procList, nil
 }
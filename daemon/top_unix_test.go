@@ -3,7 +3,10 @@
 package daemon
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/docker/engine-api/types"
 )
 
 func TestContainerTopValidatePSArgs(t *testing.T) {
@@ -74,3 +77,23 @@ func TestContainerTopParsePSOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestContainerTopAnnotateCgroups(t *testing.T) {
+	procList := &types.ContainerProcessList{
+		Titles:    []string{"PID", "COMMAND"},
+		Processes: [][]string{{"42", "init"}, {"43", "sh"}},
+	}
+	annotateCgroups(procList, map[int]string{42: "/", 43: "/"})
+	if len(procList.Titles) != 2 {
+		t.Fatalf("expected no CGROUP column without sub-cgroups, got %v", procList.Titles)
+	}
+
+	annotateCgroups(procList, map[int]string{42: "/", 43: "/session"})
+	if !reflect.DeepEqual(procList.Titles, []string{"CGROUP", "PID", "COMMAND"}) {
+		t.Fatalf("expected a CGROUP column, got %v", procList.Titles)
+	}
+	expected := [][]string{{"/", "42", "init"}, {"/session", "43", "sh"}}
+	if !reflect.DeepEqual(procList.Processes, expected) {
+		t.Fatalf("expected %v, got %v", expected, procList.Processes)
+	}
+}
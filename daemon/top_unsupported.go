@@ -0,0 +1,9 @@
+// +build !linux,!windows
+
+package daemon
+
+// containerCgroupProcesses is not supported on this platform: the processes
+// are only those reported by containerd.
+func containerCgroupProcesses(pid int) (map[int]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"io"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+)
+
+// VolumeExport returns a tar archive of the content of the volume name. If
+// pause is true, the running containers using the volume are paused until
+// the archive is closed, so that the content is consistent.
+func (daemon *Daemon) VolumeExport(name string, pause bool) (io.ReadCloser, error) {
+	v, path, release, err := daemon.mountVolumeForArchive(name, pause)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := archive.TarWithOptions(path, &archive.TarOptions{
+		Compression: archive.Uncompressed,
+		UIDMaps:     daemon.uidMaps,
+		GIDMaps:     daemon.gidMaps,
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	daemon.LogVolumeEvent(v.Name(), "export", map[string]string{"driver": v.DriverName()})
+	return ioutils.NewReadCloserWrapper(data, func() error {
+		err := data.Close()
+		release()
+		return err
+	}), nil
+}
+
+// VolumeImport extracts the tar archive content into the volume name. If
+// pause is true, the running containers using the volume are paused during
+// the extraction.
+func (daemon *Daemon) VolumeImport(name string, content io.Reader, pause bool) error {
+	v, path, release, err := daemon.mountVolumeForArchive(name, pause)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := chrootarchive.Untar(content, path, &archive.TarOptions{
+		UIDMaps: daemon.uidMaps,
+		GIDMaps: daemon.gidMaps,
+	}); err != nil {
+		return err
+	}
+
+	daemon.LogVolumeEvent(v.Name(), "import", map[string]string{"driver": v.DriverName()})
+	return nil
+}
+
+// mountVolumeForArchive mounts the volume name, referencing it so that it
+// cannot be removed, and pauses the running containers using it if pause is
+// true. The returned function resumes the containers and releases the
+// volume.
+func (daemon *Daemon) mountVolumeForArchive(name string, pause bool) (volume.Volume, string, func(), error) {
+	v, err := daemon.volumes.Get(name)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ref := "archive-" + stringid.GenerateNonCryptoID()
+	if v, err = daemon.volumes.GetWithRef(v.Name(), v.DriverName(), ref); err != nil {
+		return nil, "", nil, err
+	}
+	path, err := v.Mount(ref)
+	if err != nil {
+		daemon.volumes.Dereference(v, ref)
+		return nil, "", nil, err
+	}
+
+	var paused []*container.Container
+	release := func() {
+		for _, c := range paused {
+			if err := daemon.containerUnpause(c); err != nil {
+				logrus.Errorf("Could not unpause container %s after archiving volume %s: %v", c.ID, v.Name(), err)
+			}
+		}
+		if err := v.Unmount(ref); err != nil {
+			logrus.Warnf("Could not unmount volume %s after archiving it: %v", v.Name(), err)
+		}
+		daemon.volumes.Dereference(v, ref)
+	}
+
+	if pause {
+		for _, id := range daemon.volumes.Refs(v) {
+			c := daemon.containers.Get(id)
+			if c == nil || !c.IsRunning() || c.IsPaused() {
+				continue
+			}
+			if err := daemon.containerPause(c); err != nil {
+				release()
+				return nil, "", nil, err
+			}
+			paused = append(paused, c)
+		}
+	}
+	return v, path, release, nil
+}
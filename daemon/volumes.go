@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/directory"
 	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
@@ -37,6 +39,25 @@ func volumeToAPIType(v volume.Volume) *types.Volume {
 	return tv
 }
 
+// volumeUsage returns the usage of a volume: the disk space used by the
+// volumes of the local driver, the other drivers not reporting it, and the
+// number of containers referencing it.
+func (daemon *Daemon) volumeUsage(v volume.Volume) *types.VolumeUsageData {
+	usage := &types.VolumeUsageData{
+		Size:     -1,
+		RefCount: len(daemon.volumes.Refs(v)),
+	}
+	if v.DriverName() == volume.DefaultDriverName {
+		size, err := directory.Size(v.Path())
+		if err != nil {
+			logrus.Warnf("Could not compute the size of volume %s: %v", v.Name(), err)
+		} else {
+			usage.Size = size
+		}
+	}
+	return usage
+}
+
 // Len returns the number of mounts. Used in sorting.
 func (m mounts) Len() int {
 	return len(m)
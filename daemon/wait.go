@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"golang.org/x/net/context"
+
+	"github.com/docker/engine-api/types"
 )
 
 // ContainerWait stops processing until the given container is
@@ -20,6 +22,18 @@ func (daemon *Daemon) ContainerWait(name string, timeout time.Duration) (int, er
 	return container.WaitStop(timeout)
 }
 
+// ContainerWaitStatus waits like ContainerWait, and returns how the container
+// stopped: its exit code, the signal that killed it, if any, and whether it
+// ran out of memory.
+func (daemon *Daemon) ContainerWaitStatus(name string, timeout time.Duration) (types.ContainerWaitResponse, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return types.ContainerWaitResponse{StatusCode: -1}, err
+	}
+
+	return container.WaitStopStatus(timeout)
+}
+
 // ContainerWaitWithContext returns a channel where exit code is sent
 // when container stops. Channel can be cancelled with a context.
 func (daemon *Daemon) ContainerWaitWithContext(ctx context.Context, name string) error {
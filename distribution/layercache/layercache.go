@@ -0,0 +1,133 @@
+// Package layercache shares the layers of a daemon with other daemons, so
+// that a rack of daemons pulling the same images only pulls each layer once
+// from the registry. A daemon serves the uncompressed tar streams of its
+// layers by chain ID over HTTP, to the daemons with the same token; a
+// daemon pulling an image asks its peers for each layer of the image before
+// the registry, and verifies the layers it gets against the DiffIDs of the
+// image configuration.
+package layercache
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/layer"
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// layersPath is the path of the layers served by a daemon, followed by their
+// chain ID.
+const layersPath = "/layers/"
+
+// handler serves the layers of a layer store.
+type handler struct {
+	store layer.Store
+	token string
+}
+
+// NewHandler returns an HTTP handler serving the tar streams of the layers
+// of store, by chain ID, to the clients authenticated with token as a bearer
+// token.
+func NewHandler(store layer.Store, token string) http.Handler {
+	return &handler{store: store, token: token}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, layersPath) {
+		http.NotFound(w, r)
+		return
+	}
+	chainID, err := digest.ParseDigest(strings.TrimPrefix(r.URL.Path, layersPath))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	l, err := h.store.Get(layer.ChainID(chainID))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer layer.ReleaseAndLog(h.store, l)
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Docker-Layer-DiffID", l.DiffID().String())
+	if r.Method == "HEAD" {
+		return
+	}
+	stream, err := l.TarStream()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer stream.Close()
+	if _, err := io.Copy(w, stream); err != nil {
+		logrus.Debugf("Error serving layer %s from the layer cache: %v", chainID, err)
+	}
+}
+
+// Client gets layers from the layer caches of other daemons.
+type Client struct {
+	peers  []string
+	token  string
+	client *http.Client
+}
+
+// NewClient returns a client of the layer caches of the daemons at the URLs
+// peers, authenticating with token.
+func NewClient(peers []string, token string) *Client {
+	return &Client{
+		peers: peers,
+		token: token,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: 10 * time.Second,
+			},
+		},
+	}
+}
+
+// Open returns the tar stream of the layer chainID from the first peer
+// having it.
+func (c *Client) Open(ctx context.Context, chainID layer.ChainID) (io.ReadCloser, error) {
+	for _, peer := range c.peers {
+		req, err := http.NewRequest("GET", strings.TrimSuffix(peer, "/")+layersPath+chainID.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		resp, err := ctxhttp.Do(ctx, c.client, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logrus.Debugf("Error getting layer %s from the layer cache %s: %v", chainID, peer, err)
+			continue
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp.Body, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			logrus.Debugf("Error getting layer %s from the layer cache %s: %s", chainID, peer, resp.Status)
+		}
+	}
+	return nil, fmt.Errorf("layer %s is not in the layer caches", chainID)
+}
@@ -0,0 +1,87 @@
+package layercache
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/layer"
+	"golang.org/x/net/context"
+)
+
+type fakeLayer struct {
+	layer.Layer
+	content []byte
+}
+
+func (l *fakeLayer) DiffID() layer.DiffID {
+	return layer.DiffID(digest.FromBytes(l.content))
+}
+
+func (l *fakeLayer) TarStream() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.content)), nil
+}
+
+type fakeStore struct {
+	layer.Store
+	layers   map[layer.ChainID]*fakeLayer
+	released int
+}
+
+func (s *fakeStore) Get(chainID layer.ChainID) (layer.Layer, error) {
+	l, ok := s.layers[chainID]
+	if !ok {
+		return nil, layer.ErrLayerDoesNotExist
+	}
+	return l, nil
+}
+
+func (s *fakeStore) Release(layer.Layer) ([]layer.Metadata, error) {
+	s.released++
+	return nil, nil
+}
+
+func TestLayerCache(t *testing.T) {
+	l := &fakeLayer{content: []byte("layer content")}
+	chainID := layer.CreateChainID([]layer.DiffID{l.DiffID()})
+	store := &fakeStore{layers: map[layer.ChainID]*fakeLayer{chainID: l}}
+
+	empty := httptest.NewServer(NewHandler(&fakeStore{}, "secret"))
+	defer empty.Close()
+	server := httptest.NewServer(NewHandler(store, "secret"))
+	defer server.Close()
+
+	// the first peer does not have the layer, the second one does
+	rc, err := NewClient([]string{empty.URL, server.URL + "/"}, "secret").Open(context.Background(), chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(content) != "layer content" {
+		t.Fatalf("expected the content of the layer, got %q (%v)", content, err)
+	}
+	if store.released != 1 {
+		t.Fatalf("expected the layer to be released once, got %d", store.released)
+	}
+
+	if _, err := NewClient([]string{server.URL}, "wrong").Open(context.Background(), chainID); err == nil {
+		t.Fatal("expected an error with a wrong token")
+	}
+	if _, err := NewClient([]string{server.URL}, "secret").Open(context.Background(), layer.ChainID(digest.FromBytes([]byte("other")))); err == nil {
+		t.Fatal("expected an error for a missing layer")
+	}
+
+	resp, err := http.Get(server.URL + "/layers/" + chainID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected an unauthenticated request to be refused, got %s", resp.Status)
+	}
+}
@@ -17,6 +17,8 @@ type V2MetadataService struct {
 type V2Metadata struct {
 	Digest           digest.Digest
 	SourceRepository string
+	// MediaType is the mediaType of the blob, empty if it is not known.
+	MediaType string `json:",omitempty"`
 }
 
 // maxMetadata is the number of metadata entries to keep per layer DiffID.
@@ -81,7 +83,7 @@ func (serv *V2MetadataService) Add(diffID layer.DiffID, metadata V2Metadata) err
 
 	// Copy all other metadata to new slice
 	for _, oldMeta := range oldMetadata {
-		if oldMeta != metadata {
+		if !oldMeta.sameBlob(metadata) {
 			newMetadata = append(newMetadata, oldMeta)
 		}
 	}
@@ -119,7 +121,7 @@ func (serv *V2MetadataService) Remove(metadata V2Metadata) error {
 
 	// Copy all other metadata to new slice
 	for _, oldMeta := range oldMetadata {
-		if oldMeta != metadata {
+		if !oldMeta.sameBlob(metadata) {
 			newMetadata = append(newMetadata, oldMeta)
 		}
 	}
@@ -135,3 +137,9 @@ func (serv *V2MetadataService) Remove(metadata V2Metadata) error {
 
 	return serv.store.Set(serv.diffIDNamespace(), serv.diffIDKey(diffID), jsonBytes)
 }
+
+// sameBlob returns whether two metadata entries are about the same blob in
+// the same repository, whatever else is known about it.
+func (meta V2Metadata) sameBlob(other V2Metadata) bool {
+	return meta.Digest == other.Digest && meta.SourceRepository == other.SourceRepository
+}
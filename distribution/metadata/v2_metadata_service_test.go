@@ -103,6 +103,20 @@ func TestV2MetadataService(t *testing.T) {
 	if diffID != testVectors[1].diffID {
 		t.Fatal("GetDiffID returned incorrect diffID")
 	}
+
+	// Adding the mediaType of a blob replaces its entry
+	withMediaType := testVectors[0].metadata[0]
+	withMediaType.MediaType = "application/vnd.docker.image.rootfs.diff.tar.zstd"
+	if err := V2MetadataService.Add(testVectors[0].diffID, withMediaType); err != nil {
+		t.Fatalf("error calling Add: %v", err)
+	}
+	metadata, err := V2MetadataService.GetMetadata(testVectors[0].diffID)
+	if err != nil {
+		t.Fatalf("error calling GetMetadata: %v", err)
+	}
+	if !reflect.DeepEqual(metadata, []V2Metadata{withMediaType}) {
+		t.Fatalf("expected the entry to be replaced, got %v", metadata)
+	}
 }
 
 func randomDigest() digest.Digest {
@@ -0,0 +1,65 @@
+package distribution
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+)
+
+// ParsePlatform parses a platform given in the os[/arch[/variant]] form, such
+// as "linux/arm64" or "linux/arm/v7". The architecture defaults to the one of
+// the daemon. An empty string is the platform of the daemon.
+func ParsePlatform(platform string) (manifestlist.PlatformSpec, error) {
+	spec := manifestlist.PlatformSpec{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+	if platform == "" {
+		return spec, nil
+	}
+
+	parts := strings.Split(strings.ToLower(platform), "/")
+	if len(parts) > 3 {
+		return spec, fmt.Errorf("invalid platform %q: must be os[/arch[/variant]]", platform)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return spec, fmt.Errorf("invalid platform %q: must be os[/arch[/variant]]", platform)
+		}
+	}
+	spec.OS = parts[0]
+	if len(parts) > 1 {
+		spec.Architecture = parts[1]
+	}
+	if len(parts) > 2 {
+		spec.Variant = parts[2]
+	}
+	return spec, nil
+}
+
+// matchesPlatform returns whether a manifest list entry is suitable for the
+// requested platform. A variant is only compared when one was requested.
+func matchesPlatform(entry, requested manifestlist.PlatformSpec) bool {
+	if entry.OS != requested.OS || entry.Architecture != requested.Architecture {
+		return false
+	}
+	return requested.Variant == "" || entry.Variant == requested.Variant
+}
+
+// platformString formats a platform in the os/arch[/variant] form.
+func platformString(p manifestlist.PlatformSpec) string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// PlatformArchitecture returns the architecture of a platform given in the
+// os[/arch[/variant]] form, or an empty string if it has none.
+func PlatformArchitecture(platform string) (string, error) {
+	spec, err := ParsePlatform(platform)
+	if err != nil || !strings.Contains(platform, "/") {
+		return "", err
+	}
+	return spec.Architecture, nil
+}
@@ -0,0 +1,67 @@
+package distribution
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+)
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		platform string
+		expected manifestlist.PlatformSpec
+	}{
+		{"", manifestlist.PlatformSpec{OS: runtime.GOOS, Architecture: runtime.GOARCH}},
+		{"windows", manifestlist.PlatformSpec{OS: "windows", Architecture: runtime.GOARCH}},
+		{"linux/arm64", manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64"}},
+		{"Linux/ARM/v7", manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}},
+	}
+	for _, test := range tests {
+		spec, err := ParsePlatform(test.platform)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.platform, err)
+		}
+		if spec.OS != test.expected.OS || spec.Architecture != test.expected.Architecture || spec.Variant != test.expected.Variant {
+			t.Fatalf("expected %+v for %q, got %+v", test.expected, test.platform, spec)
+		}
+	}
+
+	for _, platform := range []string{"/", "linux/", "/amd64", "linux/arm/v7/extra"} {
+		if _, err := ParsePlatform(platform); err == nil {
+			t.Fatalf("expected an error for %q", platform)
+		}
+	}
+}
+
+func TestMatchesPlatform(t *testing.T) {
+	armv7 := manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}
+
+	if !matchesPlatform(armv7, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"}) {
+		t.Fatal("expected any variant to match when none is requested")
+	}
+	if !matchesPlatform(armv7, armv7) {
+		t.Fatal("expected the same platform to match")
+	}
+	if matchesPlatform(armv7, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v6"}) {
+		t.Fatal("expected a different variant not to match")
+	}
+	if matchesPlatform(armv7, manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}) {
+		t.Fatal("expected a different architecture not to match")
+	}
+}
+
+func TestPlatformArchitecture(t *testing.T) {
+	for platform, expected := range map[string]string{"": "", "linux": "", "linux/arm64": "arm64", "linux/ARM/v7": "arm"} {
+		arch, err := PlatformArchitecture(platform)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", platform, err)
+		}
+		if arch != expected {
+			t.Fatalf("expected architecture %q for %q, got %q", expected, platform, arch)
+		}
+	}
+	if _, err := PlatformArchitecture("linux/"); err == nil {
+		t.Fatal("expected an error for an invalid platform")
+	}
+}
@@ -2,6 +2,7 @@ package distribution
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/distribution/digest"
@@ -9,6 +10,7 @@ import (
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -40,6 +42,20 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// Platform selects the entry of a manifest list to pull, in the
+	// os[/arch[/variant]] form. The platform of the daemon is used when
+	// empty.
+	Platform string
+	// LayerCache, if not nil, is asked for the layers of the images before
+	// the registry.
+	LayerCache LayerCache
+}
+
+// LayerCache is a source of layers other than the registries, like the
+// layer caches of other daemons.
+type LayerCache interface {
+	// Open returns the tar stream of the layer of the chain chainID.
+	Open(ctx context.Context, chainID layer.ChainID) (io.ReadCloser, error)
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -89,7 +105,7 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 		return err
 	}
 
-	endpoints, err := imagePullConfig.RegistryService.LookupPullEndpoints(repoInfo.Hostname())
+	endpoints, err := imagePullConfig.RegistryService.LookupPullEndpoints(repoInfo)
 	if err != nil {
 		return err
 	}
@@ -134,6 +134,13 @@ type v2LayerDescriptor struct {
 	tmpFile           *os.File
 	verifier          digest.Verifier
 	src               distribution.Descriptor
+	// layerCache is asked for the layer before the registry, if not nil.
+	// The DiffID and the chain ID of the layer are then known, from the
+	// image configuration.
+	layerCache LayerCache
+	diffID     layer.DiffID
+	chainID    layer.ChainID
+	cacheTried bool
 }
 
 func (ld *v2LayerDescriptor) Key() string {
@@ -145,10 +152,25 @@ func (ld *v2LayerDescriptor) ID() string {
 }
 
 func (ld *v2LayerDescriptor) DiffID() (layer.DiffID, error) {
+	if ld.diffID != "" {
+		return ld.diffID, nil
+	}
 	return ld.V2MetadataService.GetDiffID(ld.digest)
 }
 
 func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progress.Output) (io.ReadCloser, int64, error) {
+	if ld.layerCache != nil && !ld.cacheTried {
+		ld.cacheTried = true
+		rc, size, err := ld.downloadFromCache(ctx, progressOutput)
+		if err == nil {
+			return rc, size, nil
+		}
+		if ctx.Err() != nil {
+			return nil, 0, xfer.DoNotRetry{Err: ctx.Err()}
+		}
+		logrus.Debugf("pulling blob %q from the registry: %v", ld.digest, err)
+	}
+
 	logrus.Debugf("pulling blob %q", ld.digest)
 
 	var (
@@ -292,6 +314,52 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	}), size, nil
 }
 
+// downloadFromCache downloads the uncompressed layer from the layer cache
+// to a temporary file, and verifies it against its DiffID.
+func (ld *v2LayerDescriptor) downloadFromCache(ctx context.Context, progressOutput progress.Output) (io.ReadCloser, int64, error) {
+	verifier, err := digest.NewDigestVerifier(digest.Digest(ld.diffID))
+	if err != nil {
+		return nil, 0, err
+	}
+	layerDownload, err := ld.layerCache.Open(ctx, ld.chainID)
+	if err != nil {
+		return nil, 0, err
+	}
+	tmpFile, err := createDownloadFile()
+	if err != nil {
+		layerDownload.Close()
+		return nil, 0, err
+	}
+	removeTmpFile := func() {
+		tmpFile.Close()
+		if err := os.RemoveAll(tmpFile.Name()); err != nil {
+			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
+		}
+	}
+
+	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerDownload), progressOutput, 0, ld.ID(), "Downloading from cache")
+	size, err := io.Copy(tmpFile, io.TeeReader(reader, verifier))
+	reader.Close()
+	if err != nil {
+		removeTmpFile()
+		return nil, 0, err
+	}
+	if !verifier.Verified() {
+		removeTmpFile()
+		return nil, 0, fmt.Errorf("layer verification failed for DiffID %s", ld.diffID)
+	}
+	if _, err := tmpFile.Seek(0, os.SEEK_SET); err != nil {
+		removeTmpFile()
+		return nil, 0, err
+	}
+	progress.Update(progressOutput, ld.ID(), "Download complete")
+
+	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
+		removeTmpFile()
+		return nil
+	}), size, nil
+}
+
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
 		ld.tmpFile.Close()
@@ -320,7 +388,7 @@ func (ld *v2LayerDescriptor) truncateDownloadFile() error {
 
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
-	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.FullName()})
+	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.FullName(), MediaType: ld.src.MediaType})
 }
 
 func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdated bool, err error) {
@@ -542,7 +610,9 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		unmarshalledConfig image.Image  // deserialized image config
 		downloadRootFS     image.RootFS // rootFS to use for registering layers.
 	)
-	if runtime.GOOS == "windows" {
+	if runtime.GOOS == "windows" || p.config.LayerCache != nil {
+		// The layers are asked to the layer cache by chain ID, and
+		// verified against their DiffID, so the config is needed first.
 		configJSON, unmarshalledConfig, err = receiveConfig(configChan, errChan)
 		if err != nil {
 			return "", "", err
@@ -550,6 +620,16 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		if unmarshalledConfig.RootFS == nil {
 			return "", "", errors.New("image config has no rootfs section")
 		}
+		if p.config.LayerCache != nil && len(unmarshalledConfig.RootFS.DiffIDs) == len(descriptors) {
+			for i, d := range descriptors {
+				ld := d.(*v2LayerDescriptor)
+				ld.layerCache = p.config.LayerCache
+				ld.diffID = unmarshalledConfig.RootFS.DiffIDs[i]
+				ld.chainID = layer.CreateChainID(unmarshalledConfig.RootFS.DiffIDs[:i+1])
+			}
+		}
+	}
+	if runtime.GOOS == "windows" {
 		downloadRootFS = *unmarshalledConfig.RootFS
 		downloadRootFS.DiffIDs = []layer.DiffID{}
 	} else {
@@ -627,19 +707,23 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		return "", "", err
 	}
 
+	platform, err := ParsePlatform(p.config.Platform)
+	if err != nil {
+		return "", "", err
+	}
+
 	var manifestDigest digest.Digest
 	for _, manifestDescriptor := range mfstList.Manifests {
-		// TODO(aaronl): The manifest list spec supports optional
-		// "features" and "variant" fields. These are not yet used.
-		// Once they are, their values should be interpreted here.
-		if manifestDescriptor.Platform.Architecture == runtime.GOARCH && manifestDescriptor.Platform.OS == runtime.GOOS {
+		// TODO(aaronl): The manifest list spec supports an optional
+		// "features" field. It is not used yet.
+		if matchesPlatform(manifestDescriptor.Platform, platform) {
 			manifestDigest = manifestDescriptor.Digest
 			break
 		}
 	}
 
 	if manifestDigest == "" {
-		return "", "", errors.New("no supported platform found in manifest list")
+		return "", "", fmt.Errorf("no matching manifest for %s in the manifest list entries", platformString(platform))
 	}
 
 	manSvc, err := p.repo.Manifests(ctx)
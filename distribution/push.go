@@ -2,11 +2,15 @@ package distribution
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"fmt"
 	"io"
+	"os/exec"
+	"strconv"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
@@ -48,6 +52,61 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// Compression is the compression of the layers pushed.
+	Compression LayerCompression
+}
+
+const (
+	// CompressionGzip compresses the layers with gzip.
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses the layers with zstd.
+	CompressionZstd = "zstd"
+
+	// MediaTypeLayerZstd is the mediaType of the layers compressed with
+	// zstd.
+	MediaTypeLayerZstd = "application/vnd.docker.image.rootfs.diff.tar.zstd"
+)
+
+// LayerCompression is the compression of the layers pushed.
+type LayerCompression struct {
+	// Codec is CompressionGzip or CompressionZstd, gzip if empty.
+	Codec string
+	// Level is the compression level, from 1 to 9 with gzip and from 1 to
+	// 19 with zstd. 0 is the default level of the codec.
+	Level int
+}
+
+// Validate returns an error if the codec or the level of the compression is
+// not supported.
+func (c LayerCompression) Validate() error {
+	var maxLevel int
+	switch c.Codec {
+	case "", CompressionGzip:
+		maxLevel = gzip.BestCompression
+	case CompressionZstd:
+		maxLevel = 19
+	default:
+		return fmt.Errorf("unsupported layer compression %q, expected %s or %s", c.Codec, CompressionGzip, CompressionZstd)
+	}
+	if c.Level < 0 || c.Level > maxLevel {
+		return fmt.Errorf("invalid %s compression level %d, expected a level from 1 to %d", c.codec(), c.Level, maxLevel)
+	}
+	return nil
+}
+
+func (c LayerCompression) codec() string {
+	if c.Codec == "" {
+		return CompressionGzip
+	}
+	return c.Codec
+}
+
+// mediaType returns the mediaType of the layers compressed with c.
+func (c LayerCompression) mediaType() string {
+	if c.codec() == CompressionZstd {
+		return MediaTypeLayerZstd
+	}
+	return schema2.MediaTypeLayer
 }
 
 // Pusher is an interface that abstracts pushing for different API versions.
@@ -178,8 +237,8 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 	return lastErr
 }
 
-// compress returns an io.ReadCloser which will supply a compressed version of
-// the provided Reader. The caller must close the ReadCloser after reading the
+// compress returns an io.ReadCloser which will supply a version of the
+// provided Reader compressed with compression. The caller must close the ReadCloser after reading the
 // compressed data.
 //
 // Note that this function returns a reader instead of taking a writer as an
@@ -191,18 +250,19 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 // is finished. This allows the caller to make sure the goroutine finishes
 // before it releases any resources connected with the reader that was
 // passed in.
-func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
+func compress(in io.Reader, compression LayerCompression) (io.ReadCloser, chan struct{}) {
 	compressionDone := make(chan struct{})
 
 	pipeReader, pipeWriter := io.Pipe()
 	// Use a bufio.Writer to avoid excessive chunking in HTTP request.
 	bufWriter := bufio.NewWriterSize(pipeWriter, compressionBufSize)
-	compressor := gzip.NewWriter(bufWriter)
 
 	go func() {
-		_, err := io.Copy(compressor, in)
-		if err == nil {
-			err = compressor.Close()
+		var err error
+		if compression.codec() == CompressionZstd {
+			err = zstdCompress(bufWriter, in, compression.Level)
+		} else {
+			err = gzipCompress(bufWriter, in, compression.Level)
 		}
 		if err == nil {
 			err = bufWriter.Flush()
@@ -217,3 +277,35 @@ func compress(in io.Reader) (io.ReadCloser, chan struct{}) {
 
 	return pipeReader, compressionDone
 }
+
+func gzipCompress(w io.Writer, in io.Reader, level int) error {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	compressor, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(compressor, in); err != nil {
+		return err
+	}
+	return compressor.Close()
+}
+
+// zstdCompress compresses in with the zstd binary, as there is no zstd
+// support in Go.
+func zstdCompress(w io.Writer, in io.Reader, level int) error {
+	args := []string{"-c", "-q"}
+	if level > 0 {
+		args = append(args, "-"+strconv.Itoa(level))
+	}
+	cmd := exec.Command("zstd", args...)
+	cmd.Stdin = in
+	cmd.Stdout = w
+	var errBuf bytes.Buffer
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("zstd compression failed: %v: %s", err, errBuf.String())
+	}
+	return nil
+}
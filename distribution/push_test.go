@@ -0,0 +1,58 @@
+package distribution
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"testing"
+
+	"github.com/docker/docker/pkg/archive"
+)
+
+func TestLayerCompressionValidate(t *testing.T) {
+	for _, c := range []LayerCompression{
+		{},
+		{Codec: CompressionGzip, Level: 9},
+		{Codec: CompressionZstd},
+		{Codec: CompressionZstd, Level: 19},
+	} {
+		if err := c.Validate(); err != nil {
+			t.Fatalf("expected %v to be valid, got %v", c, err)
+		}
+	}
+	for _, c := range []LayerCompression{
+		{Codec: "xz"},
+		{Codec: CompressionGzip, Level: 10},
+		{Codec: CompressionZstd, Level: -1},
+		{Codec: CompressionZstd, Level: 20},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("expected %v to be invalid", c)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	compressions := []LayerCompression{{}, {Codec: CompressionGzip, Level: 1}}
+	if _, err := exec.LookPath("zstd"); err == nil {
+		compressions = append(compressions, LayerCompression{Codec: CompressionZstd, Level: 3})
+	}
+	content := bytes.Repeat([]byte("layer content "), 1000)
+	for _, c := range compressions {
+		compressed, done := compress(bytes.NewReader(content), c)
+		r, err := archive.DecompressStream(compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decompressed, err := ioutil.ReadAll(r)
+		r.Close()
+		compressed.Close()
+		<-done
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decompressed, content) {
+			t.Fatalf("expected the content to be decompressed with %v", c)
+		}
+	}
+}
@@ -140,6 +140,7 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, ima
 		ref:               p.ref,
 		repo:              p.repo,
 		pushState:         &p.pushState,
+		compression:       p.config.Compression,
 	}
 
 	// Loop bounds condition is to avoid pushing the base layer on Windows.
@@ -232,6 +233,7 @@ type v2PushDescriptor struct {
 	repo              distribution.Repository
 	pushState         *pushState
 	remoteDescriptor  distribution.Descriptor
+	compression       LayerCompression
 }
 
 func (pd *v2PushDescriptor) Key() string {
@@ -334,7 +336,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
 
-			err.Descriptor.MediaType = schema2.MediaTypeLayer
+			err.Descriptor.MediaType = layerMediaType(mountFrom)
 
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
@@ -342,7 +344,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 			pd.pushState.Unlock()
 
 			// Cache mapping from this layer's DiffID to the blobsum
-			if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: mountFrom.Digest, SourceRepository: pd.repoInfo.FullName()}); err != nil {
+			if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: mountFrom.Digest, SourceRepository: pd.repoInfo.FullName(), MediaType: mountFrom.MediaType}); err != nil {
 				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 			}
 			return err.Descriptor, nil
@@ -374,7 +376,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	size, _ := pd.layer.DiffSize()
 
 	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, arch), progressOutput, size, pd.ID(), "Pushing")
-	compressedReader, compressionDone := compress(reader)
+	compressedReader, compressionDone := compress(reader, pd.compression)
 	defer func() {
 		reader.Close()
 		<-compressionDone
@@ -398,7 +400,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	progress.Update(progressOutput, pd.ID(), "Pushed")
 
 	// Cache mapping from this layer's DiffID to the blobsum
-	if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: pushDigest, SourceRepository: pd.repoInfo.FullName()}); err != nil {
+	if err := pd.v2MetadataService.Add(diffID, metadata.V2Metadata{Digest: pushDigest, SourceRepository: pd.repoInfo.FullName(), MediaType: pd.compression.mediaType()}); err != nil {
 		return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 	}
 
@@ -410,7 +412,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 
 	descriptor := distribution.Descriptor{
 		Digest:    pushDigest,
-		MediaType: schema2.MediaTypeLayer,
+		MediaType: pd.compression.mediaType(),
 		Size:      nn,
 	}
 	pd.pushState.remoteLayers[diffID] = descriptor
@@ -440,7 +442,7 @@ func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, rep
 		descriptor, err := repo.Blobs(ctx).Stat(ctx, meta.Digest)
 		switch err {
 		case nil:
-			descriptor.MediaType = schema2.MediaTypeLayer
+			descriptor.MediaType = layerMediaType(meta)
 			return descriptor, true, nil
 		case distribution.ErrBlobUnknown:
 			// nop
@@ -450,3 +452,12 @@ func layerAlreadyExists(ctx context.Context, metadata []metadata.V2Metadata, rep
 	}
 	return distribution.Descriptor{}, false, nil
 }
+
+// layerMediaType returns the mediaType of the blob of a layer, the layers
+// pushed or pulled before it was recorded being compressed with gzip.
+func layerMediaType(meta metadata.V2Metadata) string {
+	if meta.MediaType == "" {
+		return schema2.MediaTypeLayer
+	}
+	return meta.MediaType
+}
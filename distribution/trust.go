@@ -0,0 +1,182 @@
+package distribution
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/notary/client"
+	"github.com/docker/notary/passphrase"
+	"github.com/docker/notary/trustpinning"
+	"github.com/docker/notary/tuf/data"
+	"github.com/docker/notary/tuf/store"
+	"golang.org/x/net/context"
+)
+
+// releasesRole is the delegation role holding the tags released by the
+// collaborators of a repository. Like the top level targets role, it is
+// trusted for the resolution of tags.
+var releasesRole = path.Join(data.CanonicalTargetsRole, "releases")
+
+// TrustConfig configures the verification of the signatures of the pulled
+// images.
+type TrustConfig struct {
+	// Dir is the directory where the trust data of the repositories is
+	// cached.
+	Dir string
+	// Server is the URL of the trust server. If empty, the official trust
+	// server is used for the official index, and the registry host for the
+	// others.
+	Server string
+}
+
+// trustServer returns the URL of the trust server holding the signatures of
+// the repository.
+func (config *TrustConfig) trustServer(repoInfo *registry.RepositoryInfo) string {
+	if config.Server != "" {
+		return config.Server
+	}
+	if repoInfo.Index.Official {
+		return registry.NotaryServer
+	}
+	return "https://" + repoInfo.Index.Name
+}
+
+// ResolveTrustedReference returns the digest reference of the signed image
+// referenced by ref. A tag is resolved to the digest it was signed with, and
+// a digest is returned as is if it was signed by any tag of the repository.
+// An error is returned if the image is not signed.
+func ResolveTrustedReference(ctx context.Context, config *TrustConfig, ref reference.Named, authConfig *types.AuthConfig) (reference.Canonical, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := newNotaryRepository(ctx, config, repoInfo, authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing connection to the trust repository of %s: %v", repoInfo.FullName(), err)
+	}
+
+	switch r := ref.(type) {
+	case reference.Canonical:
+		targets, err := repo.ListTargets(releasesRole, data.CanonicalTargetsRole)
+		if err != nil {
+			return nil, notaryError(repoInfo.FullName(), err)
+		}
+		for _, t := range targets {
+			if t.Role != releasesRole && t.Role != data.CanonicalTargetsRole {
+				continue
+			}
+			if dgst, err := targetDigest(t.Target); err == nil && dgst == r.Digest() {
+				return r, nil
+			}
+		}
+		return nil, fmt.Errorf("%s is not signed", ref.String())
+	case reference.NamedTagged:
+		t, err := repo.GetTargetByName(r.Tag(), releasesRole, data.CanonicalTargetsRole)
+		if err != nil {
+			return nil, notaryError(repoInfo.FullName(), err)
+		}
+		// Only the tags of the top level targets role and of the releases
+		// delegation role are trusted.
+		if t.Role != releasesRole && t.Role != data.CanonicalTargetsRole {
+			return nil, fmt.Errorf("%s is not signed", ref.String())
+		}
+		dgst, err := targetDigest(t.Target)
+		if err != nil {
+			return nil, err
+		}
+		return reference.WithDigest(ref, dgst)
+	}
+	return nil, fmt.Errorf("%s must be a tag or digest reference to be verified", ref.String())
+}
+
+// targetDigest returns the digest of the image signed by a target.
+func targetDigest(t client.Target) (digest.Digest, error) {
+	h, ok := t.Hashes["sha256"]
+	if !ok {
+		return "", fmt.Errorf("no valid hash for %s, expecting sha256", t.Name)
+	}
+	return digest.NewDigestFromHex("sha256", hex.EncodeToString(h)), nil
+}
+
+// notaryError makes the errors of the trust client more explicit.
+func notaryError(repoName string, err error) error {
+	switch err.(type) {
+	case client.ErrRepositoryNotExist, store.ErrMetaNotFound:
+		return fmt.Errorf("no trust data for %s: %v", repoName, err)
+	}
+	return fmt.Errorf("failed to verify the signatures of %s: %v", repoName, err)
+}
+
+// newNotaryRepository returns a client for the trust data of the repository,
+// authenticating with the credentials used to pull from the registry.
+func newNotaryRepository(ctx context.Context, config *TrustConfig, repoInfo *registry.RepositoryInfo, authConfig *types.AuthConfig) (*client.NotaryRepository, error) {
+	server := config.trustServer(repoInfo)
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := tlsconfig.Client(tlsconfig.Options{InsecureSkipVerify: !repoInfo.Index.Secure})
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.ReadCertsDirectory(cfg, filepath.Join(registry.CertsDir, u.Host)); err != nil {
+		return nil, err
+	}
+
+	base := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     cfg,
+		DisableKeepAlives:   true,
+	}
+
+	modifiers := registry.DockerHeaders(dockerversion.DockerUserAgent(ctx), http.Header{})
+	authTransport := transport.NewTransport(base, modifiers...)
+	pingClient := &http.Client{
+		Transport: authTransport,
+		Timeout:   5 * time.Second,
+	}
+	challengeManager := auth.NewSimpleChallengeManager()
+	resp, err := pingClient.Get(server + "/v2/")
+	if err != nil {
+		// The cached trust data is used if the server can't be reached
+		logrus.Debugf("Error pinging trust server %q: %v", server, err)
+	} else {
+		defer resp.Body.Close()
+		if err := challengeManager.AddResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	creds := dumbCredentialStore{auth: authConfig}
+	tokenHandler := auth.NewTokenHandler(authTransport, creds, repoInfo.FullName(), "pull")
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, transport.RequestModifier(auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler)))
+
+	// Only the public keys of the repositories are stored, no passphrase
+	// is ever needed.
+	return client.NewNotaryRepository(config.Dir, repoInfo.FullName(), server,
+		transport.NewTransport(base, modifiers...), passphrase.ConstantRetriever(""),
+		trustpinning.TrustPinConfig{})
+}
@@ -0,0 +1,28 @@
+package distribution
+
+import (
+	"testing"
+
+	"github.com/docker/docker/registry"
+	registrytypes "github.com/docker/engine-api/types/registry"
+)
+
+func TestTrustServer(t *testing.T) {
+	official := &registry.RepositoryInfo{Index: &registrytypes.IndexInfo{Name: "docker.io", Official: true}}
+	private := &registry.RepositoryInfo{Index: &registrytypes.IndexInfo{Name: "registry.example.com:5000"}}
+
+	config := &TrustConfig{}
+	if s := config.trustServer(official); s != registry.NotaryServer {
+		t.Fatalf("expected %s for the official index, got %s", registry.NotaryServer, s)
+	}
+	if s := config.trustServer(private); s != "https://registry.example.com:5000" {
+		t.Fatalf("expected the registry host, got %s", s)
+	}
+
+	config.Server = "https://notary.example.com"
+	for _, repoInfo := range []*registry.RepositoryInfo{official, private} {
+		if s := config.trustServer(repoInfo); s != config.Server {
+			t.Fatalf("expected %s, got %s", config.Server, s)
+		}
+	}
+}
@@ -82,6 +82,15 @@ func (img *Image) RunConfig() *container.Config {
 	return img.Config
 }
 
+// TopLayer returns the digest of the top layer of the image, or an empty
+// string if it has no layer.
+func (img *Image) TopLayer() string {
+	if img.RootFS == nil || len(img.RootFS.DiffIDs) == 0 {
+		return ""
+	}
+	return img.RootFS.DiffIDs[len(img.RootFS.DiffIDs)-1].String()
+}
+
 // MarshalJSON serializes the image to JSON. It sorts the top-level keys so
 // that JSON that's been manipulated by a push/pull cycle with a legacy
 // registry won't end up with a different key order.
@@ -121,6 +130,11 @@ type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
 	Save([]string, io.Writer) error
+	// SaveExcluding saves the images without the layers of the images
+	// excluded, which must be present where the archive is loaded.
+	SaveExcluding(names, excluded []string, outStream io.Writer) error
+	// SaveOCILayout saves the images in the OCI image layout format.
+	SaveOCILayout([]string, io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
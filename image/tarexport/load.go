@@ -42,6 +42,14 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
+	// an oci-layout file marks an OCI image layout
+	layoutPath, err := safePath(tmpDir, ociLayoutFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(layoutPath); err == nil {
+		return l.ociLoad(tmpDir, outStream, progressOutput)
+	}
 	// read manifest, if no file then load in legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
@@ -95,6 +103,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 			r.Append(diffID)
 			newLayer, err := l.ls.Get(r.ChainID())
 			if err != nil {
+				if _, err := os.Lstat(layerPath); os.IsNotExist(err) {
+					return fmt.Errorf("layer %s is not in the archive, and is not present: load the images it was saved without first", diffID)
+				}
 				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), m.LayerSources[diffID], progressOutput)
 				if err != nil {
 					return err
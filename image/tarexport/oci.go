@@ -0,0 +1,383 @@
+package tarexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/system"
+	"github.com/docker/docker/reference"
+)
+
+const (
+	ociLayoutFileName = "oci-layout"
+	ociIndexFileName  = "index.json"
+	ociBlobsDirName   = "blobs"
+	ociLayoutVersion  = "1.0.0"
+
+	ociMediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeIndex    = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeConfig   = "application/vnd.oci.image.config.v1+json"
+	ociMediaTypeLayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociRefNameAnnotation holds the reference an image of the index is
+	// tagged with.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+type ociLayout struct {
+	Version string `json:"imageLayoutVersion"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	URLs        []string          `json:"urls,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// SaveOCILayout writes the images to outStream as a tar archive in the OCI
+// image layout format: an index.json referencing the manifest of each
+// image, and the manifests, configurations and layers stored by digest in
+// blobs.
+func (l *tarexporter) SaveOCILayout(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s := &saveSession{tarexporter: l, outDir: tempDir, images: images}
+	index := ociIndex{SchemaVersion: 2, Manifests: []ociDescriptor{}}
+
+	for id, imageDescr := range s.images {
+		desc, err := s.saveOCIImage(id)
+		if err != nil {
+			return err
+		}
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range imageDescr.refs {
+			d := desc
+			d.Annotations = map[string]string{ociRefNameAnnotation: ref.String()}
+			index.Manifests = append(index.Manifests, d)
+		}
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	for name, v := range map[string]interface{}{
+		ociLayoutFileName: ociLayout{Version: ociLayoutVersion},
+		ociIndexFileName:  index,
+	} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := writeOCIFile(filepath.Join(tempDir, name), b); err != nil {
+			return err
+		}
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveOCIImage writes the configuration, layers and manifest of an image to
+// the blobs of the layout, and returns the descriptor of the manifest. The
+// configuration is stored unchanged, so the image keeps its ID once loaded,
+// and the layers are stored uncompressed under their DiffID.
+func (s *saveSession) saveOCIImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	config, err := s.writeOCIBlob(img.RawJSON())
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	config.MediaType = ociMediaTypeConfig
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeManifest,
+		Config:        config,
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		desc, err := s.saveOCILayer(rootFS.ChainID())
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc, err := s.writeOCIBlob(b)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc.MediaType = ociMediaTypeManifest
+	return desc, nil
+}
+
+func (s *saveSession) saveOCILayer(id layer.ChainID) (ociDescriptor, error) {
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	desc := ociDescriptor{
+		MediaType: ociMediaTypeLayer,
+		Digest:    digest.Digest(l.DiffID()),
+	}
+	if fs, ok := l.(distribution.Describable); ok {
+		desc.URLs = fs.Descriptor().URLs
+	}
+
+	blobPath := s.ociBlobPath(desc.Digest)
+	if fi, err := os.Stat(blobPath); err == nil {
+		desc.Size = fi.Size()
+		return desc, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	tarFile, err := os.Create(blobPath)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer tarFile.Close()
+
+	if desc.Size, err = io.Copy(tarFile, arch); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+	return desc, nil
+}
+
+func (s *saveSession) ociBlobPath(dgst digest.Digest) string {
+	return filepath.Join(s.outDir, ociBlobsDirName, string(dgst.Algorithm()), dgst.Hex())
+}
+
+func (s *saveSession) writeOCIBlob(b []byte) (ociDescriptor, error) {
+	dgst := digest.FromBytes(b)
+	blobPath := s.ociBlobPath(dgst)
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+	if err := writeOCIFile(blobPath, b); err != nil {
+		return ociDescriptor{}, err
+	}
+	return ociDescriptor{Digest: dgst, Size: int64(len(b))}, nil
+}
+
+func writeOCIFile(path string, b []byte) error {
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+// ociLoad loads the images referenced by the index of an OCI image layout
+// extracted to tmpDir. An image is tagged with the reference annotating
+// its manifest in the index, if it is a tagged reference.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ociLayout
+	if err := readOCIJSON(tmpDir, ociLayoutFileName, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ociLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.Version)
+	}
+	var index ociIndex
+	if err := readOCIJSON(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	var imageIDsStr string
+	var imageRefCount int
+
+	for _, desc := range index.Manifests {
+		switch desc.MediaType {
+		case ociMediaTypeManifest, schema2.MediaTypeManifest:
+		case ociMediaTypeIndex:
+			return fmt.Errorf("loading nested image indexes is not supported: %s", desc.Digest)
+		default:
+			return fmt.Errorf("unsupported manifest media type %q for %s", desc.MediaType, desc.Digest)
+		}
+
+		b, err := readOCIBlob(tmpDir, desc.Digest)
+		if err != nil {
+			return err
+		}
+		var manifest ociManifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return err
+		}
+		config, err := readOCIBlob(tmpDir, manifest.Config.Digest)
+		if err != nil {
+			return err
+		}
+		img, err := image.NewFromJSON(config)
+		if err != nil {
+			return err
+		}
+		if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+			return fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+		}
+
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = nil
+		for i, diffID := range img.RootFS.DiffIDs {
+			r := rootFS
+			r.Append(diffID)
+			newLayer, err := l.ls.Get(r.ChainID())
+			if err != nil {
+				layerPath, err := ociBlobPath(tmpDir, manifest.Layers[i].Digest)
+				if err != nil {
+					return err
+				}
+				var foreignSrc distribution.Descriptor
+				if len(manifest.Layers[i].URLs) > 0 {
+					foreignSrc = distribution.Descriptor{
+						MediaType: manifest.Layers[i].MediaType,
+						Digest:    manifest.Layers[i].Digest,
+						Size:      manifest.Layers[i].Size,
+						URLs:      manifest.Layers[i].URLs,
+					}
+				}
+				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), foreignSrc, progressOutput)
+				if err != nil {
+					return err
+				}
+			}
+			defer layer.ReleaseAndLog(l.ls, newLayer)
+			if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+				return fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+			}
+			rootFS.Append(diffID)
+		}
+
+		imgID, err := l.is.Create(config)
+		if err != nil {
+			return err
+		}
+		imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+
+		if name := desc.Annotations[ociRefNameAnnotation]; name != "" {
+			named, err := reference.ParseNamed(name)
+			if err != nil {
+				return err
+			}
+			if ref, ok := named.(reference.NamedTagged); ok {
+				l.setLoadedTag(ref, imgID, outStream)
+				outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", ref)))
+				imageRefCount++
+			}
+		}
+
+		l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+
+	return nil
+}
+
+func readOCIJSON(tmpDir, name string, v interface{}) error {
+	p, err := safePath(tmpDir, name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(v)
+}
+
+func ociBlobPath(tmpDir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(tmpDir, filepath.Join(ociBlobsDirName, string(dgst.Algorithm()), dgst.Hex()))
+}
+
+// readOCIBlob returns the content of a blob, after verifying its digest.
+func readOCIBlob(tmpDir string, dgst digest.Digest) ([]byte, error) {
+	p, err := ociBlobPath(tmpDir, dgst)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := digest.NewDigestVerifier(dgst)
+	if err != nil {
+		return nil, err
+	}
+	verifier.Write(b)
+	if !verifier.Verified() {
+		return nil, fmt.Errorf("invalid content for blob %s", dgst)
+	}
+	return b, nil
+}
@@ -30,15 +30,52 @@ type saveSession struct {
 	images      map[image.ID]*imageDescriptor
 	savedLayers map[string]struct{}
 	diffIDPaths map[layer.DiffID]string // cache every diffID blob to avoid duplicates
+	// excludedLayers are the layers whose tar is not saved
+	excludedLayers map[layer.ChainID]struct{}
 }
 
 func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+	return l.SaveExcluding(names, nil, outStream)
+}
+
+// SaveExcluding saves the images names without the tars of the layers of
+// the images excluded. The manifest still lists the excluded layers, which
+// are only loaded if they are already present.
+func (l *tarexporter) SaveExcluding(names, excluded []string, outStream io.Writer) error {
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
 	}
+	excludedLayers, err := l.layersOf(excluded)
+	if err != nil {
+		return err
+	}
 
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	return (&saveSession{tarexporter: l, images: images, excludedLayers: excludedLayers}).save(outStream)
+}
+
+// layersOf returns the chain IDs of all the layers of the images names.
+func (l *tarexporter) layersOf(names []string) (map[layer.ChainID]struct{}, error) {
+	layers := make(map[layer.ChainID]struct{})
+	if len(names) == 0 {
+		return layers, nil
+	}
+	images, err := l.parseNames(names)
+	if err != nil {
+		return nil, err
+	}
+	for id := range images {
+		img, err := l.is.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		rootFS := *img.RootFS
+		for i := range img.RootFS.DiffIDs {
+			rootFS.DiffIDs = img.RootFS.DiffIDs[:i+1]
+			layers[rootFS.ChainID()] = struct{}{}
+		}
+	}
+	return layers, nil
 }
 
 func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor, error) {
@@ -298,6 +335,16 @@ func (s *saveSession) saveLayer(id layer.ChainID, legacyImg image.V1Image, creat
 		return distribution.Descriptor{}, err
 	}
 
+	if _, excluded := s.excludedLayers[id]; excluded {
+		for _, fname := range []string{"", legacyVersionFileName, legacyConfigFileName} {
+			if err := system.Chtimes(filepath.Join(outDir, fname), createdTime, createdTime); err != nil {
+				return distribution.Descriptor{}, err
+			}
+		}
+		s.savedLayers[legacyImg.ID] = struct{}{}
+		return distribution.Descriptor{}, nil
+	}
+
 	// serialize filesystem
 	layerPath := filepath.Join(outDir, legacyLayerFileName)
 	l, err := s.ls.Get(id)
@@ -84,6 +84,10 @@ type TarStreamer interface {
 type Layer interface {
 	TarStreamer
 
+	// TarStreamFrom returns a tar archive stream for all the layer chain
+	// with arbitrary depth, relative to the given parent layer.
+	TarStreamFrom(ChainID) (io.ReadCloser, error)
+
 	// ChainID returns the content hash of the entire layer chain. The hash
 	// chain is made up of DiffID of top layer and all of its parents.
 	ChainID() ChainID
@@ -108,6 +112,14 @@ type Layer interface {
 	Metadata() (map[string]string, error)
 }
 
+// DiskSizer is implemented by the layers which can report the disk space
+// used by their storage driver.
+type DiskSizer interface {
+	// DiskSize returns the disk space used by the storage driver for
+	// the top layer, which may be costly to compute.
+	DiskSize() (int64, error)
+}
+
 // RWLayer represents a layer which is
 // read and writable
 type RWLayer interface {
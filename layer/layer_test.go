@@ -1,6 +1,7 @@
 package layer
 
 import (
+	"archive/tar"
 	"bytes"
 	"io"
 	"io/ioutil"
@@ -304,6 +305,41 @@ func TestMountAndRegister(t *testing.T) {
 	}
 }
 
+func TestLayerDiskSize(t *testing.T) {
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("layer1.txt", []byte("layer 1 file"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("layer2.txt", []byte("layer 2 file, longer"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		layer    Layer
+		expected int64
+	}{
+		{layer1, 12},
+		{layer2, 20},
+	} {
+		ds, ok := tc.layer.(DiskSizer)
+		if !ok {
+			t.Fatalf("Layer %s does not report its disk size", tc.layer.ChainID())
+		}
+		size, err := ds.DiskSize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size != tc.expected {
+			t.Fatalf("Unexpected disk size %d for layer %s, expected %d", size, tc.layer.ChainID(), tc.expected)
+		}
+	}
+}
+
 func TestLayerRelease(t *testing.T) {
 	// TODO Windows: Figure out why this is failing
 	if runtime.GOOS == "windows" {
@@ -766,3 +802,61 @@ func TestTarStreamVerification(t *testing.T) {
 		t.Fatalf("wrong error returned from tarstream: %q", err)
 	}
 }
+
+func TestTarStreamFrom(t *testing.T) {
+	// TODO Windows: Figure out why this is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("/layer1.txt", []byte("layer 1 file"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("/layer2.txt", []byte("layer 2 file"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer3, err := createLayer(ls, layer2.ChainID(), initWithFiles(newTestFile("/layer3.txt", []byte("layer 3 file"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tarNames := func(parent ChainID) map[string]bool {
+		ts, err := layer3.TarStreamFrom(parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ts.Close()
+
+		names := make(map[string]bool)
+		tr := tar.NewReader(ts)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names[strings.TrimPrefix(hdr.Name, "/")] = true
+		}
+		return names
+	}
+
+	names := tarNames(layer1.ChainID())
+	if names["layer1.txt"] || !names["layer2.txt"] || !names["layer3.txt"] {
+		t.Fatalf("Unexpected files in diff from layer 1: %v", names)
+	}
+
+	names = tarNames("")
+	if !names["layer1.txt"] || !names["layer2.txt"] || !names["layer3.txt"] {
+		t.Fatalf("Unexpected files in diff from scratch: %v", names)
+	}
+
+	if _, err := layer1.TarStreamFrom(layer3.ChainID()); err == nil {
+		t.Fatal("Expected error getting the diff to a non-parent layer")
+	}
+}
@@ -43,6 +43,24 @@ func (rl *roLayer) TarStream() (io.ReadCloser, error) {
 	return rc, nil
 }
 
+// TarStreamFrom returns a tar archive stream of the differences between
+// the layer and the given parent layer. An empty parent returns the content
+// of the whole layer chain.
+func (rl *roLayer) TarStreamFrom(parent ChainID) (io.ReadCloser, error) {
+	var parentCacheID string
+	for pl := rl.parent; pl != nil; pl = pl.parent {
+		if pl.chainID == parent {
+			parentCacheID = pl.cacheID
+			break
+		}
+	}
+
+	if parent != ChainID("") && parentCacheID == "" {
+		return nil, fmt.Errorf("layer ID '%s' is not a parent of the specified layer: cannot provide diff to non-parent", parent)
+	}
+	return rl.layerStore.driver.Diff(rl.cacheID, parentCacheID)
+}
+
 func (rl *roLayer) ChainID() ChainID {
 	return rl.chainID
 }
@@ -73,6 +91,14 @@ func (rl *roLayer) DiffSize() (size int64, err error) {
 	return rl.size, nil
 }
 
+func (rl *roLayer) DiskSize() (size int64, err error) {
+	var parent string
+	if rl.parent != nil {
+		parent = rl.parent.cacheID
+	}
+	return rl.layerStore.driver.DiffSize(rl.cacheID, parent)
+}
+
 func (rl *roLayer) Metadata() (map[string]string, error) {
 	return rl.layerStore.driver.GetMetadata(rl.cacheID)
 }
@@ -314,6 +314,21 @@ func (clnt *client) GetPidsForContainer(containerID string) ([]int, error) {
 	return pids, nil
 }
 
+// GetProcessPid returns the process ID on the host of a process of a
+// container.
+func (clnt *client) GetProcessPid(containerID, processFriendlyName string) (int, error) {
+	cont, err := clnt.getContainerdContainer(containerID)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range cont.Processes {
+		if p.Pid == processFriendlyName {
+			return int(p.SystemPid), nil
+		}
+	}
+	return 0, fmt.Errorf("process %s not found in container %s", processFriendlyName, containerID)
+}
+
 // Summary returns a summary of the processes running in a container.
 // This is a no-op on Linux.
 func (clnt *client) Summary(containerID string) ([]Summary, error) {
@@ -18,6 +18,10 @@ func (clnt *client) Signal(containerID string, sig int) error {
 	return nil
 }
 
+func (clnt *client) SignalProcess(containerID string, processFriendlyName string, sig int) error {
+	return nil
+}
+
 func (clnt *client) Resize(containerID, processFriendlyName string, width, height int) error {
 	return nil
 }
@@ -43,6 +47,10 @@ func (clnt *client) GetPidsForContainer(containerID string) ([]int, error) {
 	return nil, nil
 }
 
+func (clnt *client) GetProcessPid(containerID, processFriendlyName string) (int, error) {
+	return 0, nil
+}
+
 // Summary returns a summary of the processes running in a container.
 func (clnt *client) Summary(containerID string) ([]Summary, error) {
 	return nil, nil
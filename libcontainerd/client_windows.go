@@ -405,6 +405,12 @@ func (clnt *client) GetPidsForContainer(containerID string) ([]int, error) {
 	return pids, nil
 }
 
+// GetProcessPid returns the process ID on the host of a process of a
+// container. This is not implemented on Windows.
+func (clnt *client) GetProcessPid(containerID, processFriendlyName string) (int, error) {
+	return 0, errors.New("Windows: GetProcessPid not implemented")
+}
+
 // Summary returns a summary of the processes running in a container.
 // This is present in Windows to support docker top. In linux, the
 // engine shells out to ps to get process information. On Windows, as
@@ -0,0 +1,5 @@
+package libcontainerd
+
+type container struct {
+	containerCommon
+}
@@ -0,0 +1,6 @@
+package libcontainerd
+
+// process keeps the state for both main container process and exec process.
+type process struct {
+	processCommon
+}
@@ -0,0 +1,378 @@
+// Package storage migrates the images and the containers of a daemon from a
+// storage driver to another, by re-applying the diffs of their layers in the
+// format of the new driver, so that switching drivers does not mean pulling
+// the images again.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+const (
+	imageDirName       = "image"
+	containersDirName  = "containers"
+	configFileName     = "config.v2.json"
+	hostConfigFileName = "hostconfig.json"
+)
+
+// imageMetadata is the metadata of the images which does not depend on the
+// storage driver, and is copied as is.
+var imageMetadata = []string{"imagedb", "distribution", "repositories.json"}
+
+// Options are the options of a migration.
+type Options struct {
+	// Root is the root directory of the daemon.
+	Root string
+	// From and To are the names of the storage drivers to migrate from and
+	// to.
+	From string
+	To   string
+	// GraphOptions are the options of the storage drivers.
+	GraphOptions []string
+	UIDMaps      []idtools.IDMap
+	GIDMaps      []idtools.IDMap
+	// InitLayer initializes the init layers of the containers.
+	InitLayer layer.MountInit
+}
+
+// ParseSpec parses the value of --storage-migrate, like
+// from=aufs,to=overlay2, and returns the names of the drivers.
+func ParseSpec(spec string) (string, string, error) {
+	var from, to string
+	for _, field := range strings.Split(spec, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return "", "", fmt.Errorf("invalid storage migration %q: expected from=<driver>,to=<driver>", spec)
+		}
+		switch kv[0] {
+		case "from":
+			from = kv[1]
+		case "to":
+			to = kv[1]
+		default:
+			return "", "", fmt.Errorf("invalid storage migration %q: unknown key %s", spec, kv[0])
+		}
+	}
+	if from == "" || to == "" {
+		return "", "", fmt.Errorf("invalid storage migration %q: expected from=<driver>,to=<driver>", spec)
+	}
+	if from == to {
+		return "", "", fmt.Errorf("invalid storage migration %q: the drivers must be different", spec)
+	}
+	return from, to, nil
+}
+
+// migration is a migration in progress.
+type migration struct {
+	Options
+	source     layer.Store
+	target     layer.Store
+	images     image.Store
+	containers []*containerConfig
+}
+
+// containerConfig is the configuration of a container, which is rewritten
+// as is but for its driver.
+type containerConfig struct {
+	path       string
+	raw        map[string]*json.RawMessage
+	ID         string
+	Driver     string
+	ImageID    image.ID
+	MountLabel string
+	StorageOpt map[string]string
+}
+
+func loadContainerConfig(dir string) (*containerConfig, error) {
+	c := &containerConfig{path: filepath.Join(dir, configFileName)}
+	config, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(config, &c.raw); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(config, c); err != nil {
+		return nil, err
+	}
+	hostConfig, err := ioutil.ReadFile(filepath.Join(dir, hostConfigFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var hc struct{ StorageOpt map[string]string }
+		if err := json.Unmarshal(hostConfig, &hc); err != nil {
+			return nil, err
+		}
+		c.StorageOpt = hc.StorageOpt
+	}
+	return c, nil
+}
+
+// setDriver writes the configuration of the container with the driver name.
+func (c *containerConfig) setDriver(name string) error {
+	driver, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	raw := json.RawMessage(driver)
+	c.raw["Driver"] = &raw
+	config, err := json.Marshal(c.raw)
+	if err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(c.path, config, 0600)
+}
+
+// Migrate migrates the layers of the images, the metadata of the images and
+// the writable layers of the containers of the daemon from the driver From to
+// the driver To. The state of the driver From is left untouched, and the
+// state created for the driver To is removed if the migration fails, so that
+// the daemon can still be started with the driver From.
+func Migrate(opts Options) (err error) {
+	m := &migration{Options: opts}
+	// the target driver must have no state, so that all of it can be
+	// removed if the migration fails
+	for _, dir := range []string{filepath.Join(opts.Root, imageDirName, opts.To), filepath.Join(opts.Root, opts.To)} {
+		if _, err := os.Stat(dir); err == nil {
+			return fmt.Errorf("the storage driver %s already has data in %s, remove it to migrate the images of %s", opts.To, dir, opts.From)
+		}
+	}
+
+	if m.source, err = m.newLayerStore(opts.From); err != nil {
+		return fmt.Errorf("error initializing the storage driver %s: %v", opts.From, err)
+	}
+	defer m.source.Cleanup()
+	if m.target, err = m.newLayerStore(opts.To); err != nil {
+		m.rollback()
+		return fmt.Errorf("error initializing the storage driver %s: %v", opts.To, err)
+	}
+	defer func() {
+		if cleanupErr := m.target.Cleanup(); cleanupErr != nil {
+			logrus.Warnf("Failed to clean up the storage driver %s: %v", opts.To, cleanupErr)
+		}
+		if err != nil {
+			logrus.Errorf("Storage migration from %s to %s failed, rolling back: %v", opts.From, opts.To, err)
+			m.rollback()
+		}
+	}()
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(opts.Root, imageDirName, opts.From, "imagedb"))
+	if err != nil {
+		return err
+	}
+	if m.images, err = image.NewImageStore(fs, m.source); err != nil {
+		return err
+	}
+
+	if err := m.migrateLayers(); err != nil {
+		return err
+	}
+	if err := m.migrateContainers(); err != nil {
+		return err
+	}
+	if err := m.migrateImageMetadata(); err != nil {
+		return err
+	}
+	// the containers are switched to the new driver last, nothing can fail
+	// after this but the writing of their configuration
+	for i, c := range m.containers {
+		if err := c.setDriver(opts.To); err != nil {
+			for _, c := range m.containers[:i] {
+				c.setDriver(c.Driver)
+			}
+			return err
+		}
+	}
+	logrus.Infof("Storage migration from %s to %s done, start the daemon with --storage-driver=%s", opts.From, opts.To, opts.To)
+	return nil
+}
+
+func (m *migration) newLayerStore(driverName string) (layer.Store, error) {
+	driver, err := graphdriver.GetDriver(driverName, m.Root, m.GraphOptions, m.UIDMaps, m.GIDMaps)
+	if err != nil {
+		return nil, err
+	}
+	fms, err := layer.NewFSMetadataStore(filepath.Join(m.Root, imageDirName, driverName, "layerdb"))
+	if err != nil {
+		driver.Cleanup()
+		return nil, err
+	}
+	return layer.NewStoreFromGraphDriver(fms, driver)
+}
+
+// migrateLayers registers the layers of all the images in the target store,
+// parents first.
+func (m *migration) migrateLayers() error {
+	var chains [][]layer.DiffID
+	seen := make(map[layer.ChainID]bool)
+	for _, img := range m.images.Map() {
+		diffIDs := img.RootFS.DiffIDs
+		for i := range diffIDs {
+			chainID := layer.CreateChainID(diffIDs[:i+1])
+			if !seen[chainID] {
+				seen[chainID] = true
+				chains = append(chains, diffIDs[:i+1])
+			}
+		}
+	}
+	// a chain is always longer than the chains of its parents
+	sort.Sort(byLength(chains))
+
+	for i, diffIDs := range chains {
+		chainID := layer.CreateChainID(diffIDs)
+		var parent layer.ChainID
+		if len(diffIDs) > 1 {
+			parent = layer.CreateChainID(diffIDs[:len(diffIDs)-1])
+		}
+		logrus.Infof("Migrating layer %d of %d (%s)", i+1, len(chains), chainID)
+		if err := m.migrateLayer(chainID, parent); err != nil {
+			return fmt.Errorf("error migrating the layer %s: %v", chainID, err)
+		}
+	}
+	return nil
+}
+
+func (m *migration) migrateLayer(chainID, parent layer.ChainID) error {
+	l, err := m.source.Get(chainID)
+	if err != nil {
+		return err
+	}
+	defer m.source.Release(l)
+	ts, err := l.TarStream()
+	if err != nil {
+		return err
+	}
+	defer ts.Close()
+	migrated, err := m.target.Register(ts, parent)
+	if err != nil {
+		return err
+	}
+	if migrated.ChainID() != chainID {
+		return fmt.Errorf("the layer was migrated as %s", migrated.ChainID())
+	}
+	return nil
+}
+
+// migrateContainers creates the writable layers of the containers of the
+// source driver in the target store, and applies their changes.
+func (m *migration) migrateContainers() error {
+	dir, err := ioutil.ReadDir(filepath.Join(m.Root, containersDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, v := range dir {
+		c, err := loadContainerConfig(filepath.Join(m.Root, containersDirName, v.Name()))
+		if err != nil {
+			logrus.Warnf("Skipping the migration of the container %s: %v", v.Name(), err)
+			continue
+		}
+		if c.Driver != m.From && !(c.Driver == "" && m.From == "aufs") {
+			continue
+		}
+		m.containers = append(m.containers, c)
+	}
+
+	for i, c := range m.containers {
+		logrus.Infof("Migrating container %d of %d (%s)", i+1, len(m.containers), c.ID)
+		if err := m.migrateContainer(c); err != nil {
+			return fmt.Errorf("error migrating the container %s: %v", c.ID, err)
+		}
+	}
+	return nil
+}
+
+func (m *migration) migrateContainer(c *containerConfig) error {
+	var parent layer.ChainID
+	if c.ImageID != "" {
+		img, err := m.images.Get(c.ImageID)
+		if err != nil {
+			return err
+		}
+		parent = img.RootFS.ChainID()
+	}
+	rwLayer, err := m.source.GetRWLayer(c.ID)
+	if err != nil {
+		return err
+	}
+	changes, err := rwLayer.TarStream()
+	if err != nil {
+		return err
+	}
+	defer changes.Close()
+
+	migrated, err := m.target.CreateRWLayer(c.ID, parent, c.MountLabel, m.InitLayer, c.StorageOpt)
+	if err != nil {
+		return err
+	}
+	path, err := migrated.Mount(c.MountLabel)
+	if err != nil {
+		return err
+	}
+	defer migrated.Unmount()
+	_, err = chrootarchive.ApplyUncompressedLayer(path, changes, &archive.TarOptions{
+		UIDMaps: m.UIDMaps,
+		GIDMaps: m.GIDMaps,
+	})
+	return err
+}
+
+// migrateImageMetadata copies the metadata of the images to the directory of
+// the target driver.
+func (m *migration) migrateImageMetadata() error {
+	for _, name := range imageMetadata {
+		src := filepath.Join(m.Root, imageDirName, m.From, name)
+		fi, err := os.Stat(src)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		dst := filepath.Join(m.Root, imageDirName, m.To, name)
+		if fi.IsDir() {
+			err = archive.CopyWithTar(src, dst)
+		} else {
+			err = archive.CopyFileWithTar(src, dst)
+		}
+		if err != nil {
+			return fmt.Errorf("error copying the image metadata %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// rollback removes the state created for the target driver.
+func (m *migration) rollback() {
+	if err := os.RemoveAll(filepath.Join(m.Root, imageDirName, m.To)); err != nil {
+		logrus.Warnf("Failed to remove the images of the storage driver %s: %v", m.To, err)
+	}
+	if err := os.RemoveAll(filepath.Join(m.Root, m.To)); err != nil {
+		logrus.Warnf("Failed to remove the directory of the storage driver %s: %v", m.To, err)
+	}
+}
+
+// byLength sorts the chains of layers by their length.
+type byLength [][]layer.DiffID
+
+func (c byLength) Len() int           { return len(c) }
+func (c byLength) Less(i, j int) bool { return len(c[i]) < len(c[j]) }
+func (c byLength) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
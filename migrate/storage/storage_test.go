@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/daemon/graphdriver"
+	"github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+)
+
+func init() {
+	graphdriver.Register("migrate-test-a", vfs.Init)
+	graphdriver.Register("migrate-test-b", vfs.Init)
+}
+
+func TestParseSpec(t *testing.T) {
+	from, to, err := ParseSpec("from=aufs,to=overlay2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != "aufs" || to != "overlay2" {
+		t.Fatalf("expected a migration from aufs to overlay2, got %s to %s", from, to)
+	}
+	for _, spec := range []string{"", "from=aufs", "aufs,overlay2", "from=aufs,to=aufs", "from=aufs,into=overlay2"} {
+		if _, _, err := ParseSpec(spec); err == nil {
+			t.Fatalf("expected an error for %q", spec)
+		}
+	}
+}
+
+// newTestImage registers a layer for each of files in the store of the
+// driver migrate-test-a, and an image of these layers.
+func newTestImage(t *testing.T, root string, files ...string) image.ID {
+	m := &migration{Options: Options{Root: root}}
+	ls, err := m.newLayerStore("migrate-test-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ls.Cleanup()
+
+	var parent layer.ChainID
+	var diffIDs []string
+	for _, name := range files {
+		dir, err := ioutil.TempDir("", "migrate-layer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		ts, err := archive.Tar(dir, archive.Uncompressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := ls.Register(ts, parent)
+		ts.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parent = l.ChainID()
+		diffIDs = append(diffIDs, fmt.Sprintf("%q", l.DiffID()))
+	}
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, imageDirName, "migrate-test-a", "imagedb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(fs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := is.Create([]byte(`{"architecture":"amd64","os":"linux","rootfs":{"type":"layers","diff_ids":[` + strings.Join(diffIDs, ",") + `]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestMigrate(t *testing.T) {
+	root, err := ioutil.TempDir("", "migrate-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	id := newTestImage(t, root, "foo", "bar")
+
+	if err := Migrate(Options{Root: root, From: "migrate-test-a", To: "migrate-test-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &migration{Options: Options{Root: root}}
+	ls, err := m.newLayerStore("migrate-test-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ls.Cleanup()
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, imageDirName, "migrate-test-b", "imagedb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is, err := image.NewImageStore(fs, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := is.Get(id)
+	if err != nil {
+		t.Fatalf("expected the image to be migrated: %v", err)
+	}
+	l, err := ls.Get(img.RootFS.ChainID())
+	if err != nil {
+		t.Fatalf("expected the layers of the image to be migrated: %v", err)
+	}
+	defer ls.Release(l)
+	rwLayer, err := ls.CreateRWLayer("test", l.ChainID(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ls.ReleaseRWLayer(rwLayer)
+	path, err := rwLayer.Mount("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rwLayer.Unmount()
+	for _, name := range []string{"foo", "bar"} {
+		content, err := ioutil.ReadFile(filepath.Join(path, name))
+		if err != nil || string(content) != name {
+			t.Fatalf("expected the file %s of the image to be migrated, got %q: %v", name, content, err)
+		}
+	}
+
+	// the driver migrate-test-b has data now
+	if err := Migrate(Options{Root: root, From: "migrate-test-a", To: "migrate-test-b"}); err == nil || !strings.Contains(err.Error(), "already has data") {
+		t.Fatalf("expected an error for a driver having data, got %v", err)
+	}
+}
+
+func TestMigrateRollback(t *testing.T) {
+	root, err := ioutil.TempDir("", "migrate-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	newTestImage(t, root, "foo")
+
+	// a container of an image which does not exist cannot be migrated
+	containerRoot := filepath.Join(root, containersDirName, "abc")
+	if err := os.MkdirAll(containerRoot, 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"ID":"abc","Driver":"migrate-test-a","ImageID":"sha256:4b825dc642cb6eb9a060e54bf8d69288fbee4904d1a3ed0b8e9744e8c9c7e9d5"}`
+	if err := ioutil.WriteFile(filepath.Join(containerRoot, "config.v2.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(Options{Root: root, From: "migrate-test-a", To: "migrate-test-b"}); err == nil {
+		t.Fatal("expected the migration to fail")
+	}
+	for _, dir := range []string{filepath.Join(root, imageDirName, "migrate-test-b"), filepath.Join(root, "migrate-test-b")} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, got %v", dir, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, imageDirName, "migrate-test-a", "imagedb")); err != nil {
+		t.Fatalf("expected the images of the driver migrate-test-a to be kept: %v", err)
+	}
+}
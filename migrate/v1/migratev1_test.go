@@ -407,6 +407,10 @@ func (l *mockLayer) TarStream() (io.ReadCloser, error) {
 	return nil, nil
 }
 
+func (l *mockLayer) TarStreamFrom(layer.ChainID) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (l *mockLayer) ChainID() layer.ChainID {
 	return layer.CreateChainID(l.diffIDs)
 }
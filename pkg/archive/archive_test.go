@@ -136,6 +136,31 @@ func TestDecompressStreamXz(t *testing.T) {
 	}
 }
 
+func TestDecompressStreamZstd(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd not present")
+	}
+	cmd := exec.Command("sh", "-c", "echo hello > /tmp/archive && zstd -f -q --rm /tmp/archive")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Fail to create an archive file for test : %s.", output)
+	}
+	archive, err := os.Open(tmp + "archive.zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	r, err := DecompressStream(archive)
+	if err != nil {
+		t.Fatalf("Failed to decompress a zstd file.")
+	}
+	content, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil || string(content) != "hello\n" {
+		t.Fatalf("Expected the decompressed content to be hello, got %q (%v)", content, err)
+	}
+}
+
 func TestCompressStreamXzUnsuported(t *testing.T) {
 	dest, err := os.Create(tmp + "dest")
 	if err != nil {
@@ -205,6 +230,13 @@ func TestExtensionXz(t *testing.T) {
 		t.Fatalf("The extension of a bzip2 archive should be 'tar.xz'")
 	}
 }
+func TestExtensionZstd(t *testing.T) {
+	compression := Zstd
+	output := compression.Extension()
+	if output != "tar.zst" {
+		t.Fatalf("The extension of a zstd archive should be 'tar.zst'")
+	}
+}
 
 func TestCmdStreamLargeStderr(t *testing.T) {
 	cmd := exec.Command("sh", "-c", "dd if=/dev/zero bs=1k count=1000 of=/dev/stderr; echo hello")
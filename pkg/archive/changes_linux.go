@@ -290,6 +290,78 @@ func OverlayChanges(layers []string, rw string) ([]Change, error) {
 	return changes(layers, rw, overlayDeletedFile, nil)
 }
 
+// OverlayCopyChanges walks the path rw, the upper directory of an overlay
+// layer which was created as a copy of parentRW, the upper directory of its
+// parent, and determines the changes for the files in the path, with respect
+// to the parent. The entries of rw which are the same as in parentRW are
+// skipped, and the entries of parentRW missing from rw are deleted, so only
+// the upper directories are walked. layers are the lower layers of the
+// parent, parentRW first.
+func OverlayCopyChanges(layers []string, rw, parentRW string) ([]Change, error) {
+	skip := func(path string) (bool, error) {
+		fi, err := os.Lstat(filepath.Join(rw, path))
+		if err != nil {
+			return false, err
+		}
+		parentFi, err := os.Lstat(filepath.Join(parentRW, path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return sameEntry(fi, parentFi), nil
+	}
+	changes, err := changes(layers, rw, overlayDeletedFile, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(parentRW, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		path, err = filepath.Rel(parentRW, path)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(string(os.PathSeparator), path)
+		if path == string(os.PathSeparator) {
+			return nil
+		}
+		if _, err := os.Lstat(filepath.Join(rw, path)); err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			changes = append(changes, Change{Path: path, Kind: ChangeDelete})
+			if f.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sort.Sort(changesByPath(changes))
+	return changes, nil
+}
+
+// sameEntry returns whether two entries of directories have the same type,
+// mode, size and modification time, and for devices the same device number.
+func sameEntry(a, b os.FileInfo) bool {
+	if a.Mode() != b.Mode() || !sameFsTime(a.ModTime(), b.ModTime()) {
+		return false
+	}
+	if !a.IsDir() && a.Size() != b.Size() {
+		return false
+	}
+	if a.Mode()&os.ModeDevice != 0 {
+		return a.Sys().(*syscall.Stat_t).Rdev == b.Sys().(*syscall.Stat_t).Rdev
+	}
+	return true
+}
+
 func overlayDeletedFile(root, path string, fi os.FileInfo) (string, error) {
 	if fi.Mode()&os.ModeCharDevice != 0 {
 		s := fi.Sys().(*syscall.Stat_t)
@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverlayCopyChanges(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "docker-test-overlay-changes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	lower := filepath.Join(tmp, "lower")
+	parentRW := filepath.Join(tmp, "parent")
+	rw := filepath.Join(tmp, "rw")
+	for _, dir := range []string{filepath.Join(lower, "bin"), filepath.Join(parentRW, "etc")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{
+		filepath.Join(lower, "bin", "sh"),
+		filepath.Join(parentRW, "etc", "hosts"),
+		filepath.Join(parentRW, "etc", "hostname"),
+		filepath.Join(parentRW, ".dockerenv"),
+	} {
+		if err := ioutil.WriteFile(f, []byte("parent"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := copyDir(parentRW, rw); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(rw, "etc", "hosts"), []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(rw, ".dockerenv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(rw, "added"), []byte("added"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := OverlayCopyChanges([]string{parentRW, lower}, rw, parentRW)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChanges := []Change{
+		{"/.dockerenv", ChangeDelete},
+		{"/added", ChangeAdd},
+		{"/etc/hosts", ChangeModify},
+	}
+	checkChanges(expectedChanges, changes, t)
+}
@@ -28,6 +28,9 @@ type Request struct {
 	// RequestBody stores the raw request body sent to the docker daemon
 	RequestBody []byte `json:"RequestBody,omitempty"`
 
+	// RequestBodyDigest holds the sha256 digest of the request body (e.g., sha256:<hex>)
+	RequestBodyDigest string `json:"RequestBodyDigest,omitempty"`
+
 	// RequestHeaders stores the raw request headers sent to the docker daemon
 	RequestHeaders map[string]string `json:"RequestHeaders,omitempty"`
 
@@ -3,6 +3,8 @@ package authorization
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -68,12 +70,13 @@ func (ctx *Ctx) AuthZRequest(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	ctx.authReq = &Request{
-		User:            ctx.user,
-		UserAuthNMethod: ctx.userAuthNMethod,
-		RequestMethod:   ctx.requestMethod,
-		RequestURI:      ctx.requestURI,
-		RequestBody:     body,
-		RequestHeaders:  headers(r.Header),
+		User:              ctx.user,
+		UserAuthNMethod:   ctx.userAuthNMethod,
+		RequestMethod:     ctx.requestMethod,
+		RequestURI:        ctx.requestURI,
+		RequestBody:       body,
+		RequestBodyDigest: bodyDigest(body),
+		RequestHeaders:    headers(r.Header),
 	}
 
 	for _, plugin := range ctx.plugins {
@@ -138,6 +141,15 @@ func drainBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
 	return nil, newBody, err
 }
 
+// bodyDigest returns the sha256 digest of body, or an empty string if there is no body
+func bodyDigest(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
 // sendBody returns true when request/response body should be sent to AuthZPlugin
 func sendBody(url string, header http.Header) bool {
 	// Skip body for auth endpoint
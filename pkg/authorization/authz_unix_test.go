@@ -172,6 +172,16 @@ func TestDrainBody(t *testing.T) {
 	}
 }
 
+func TestBodyDigest(t *testing.T) {
+	if digest := bodyDigest(nil); digest != "" {
+		t.Fatalf("Empty body must have no digest, got '%s'", digest)
+	}
+	expected := "sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if digest := bodyDigest([]byte("hello world")); digest != expected {
+		t.Fatalf("Expected digest '%s', got '%s'", expected, digest)
+	}
+}
+
 func TestResponseModifierOverride(t *testing.T) {
 	r := httptest.NewRecorder()
 	m := NewResponseModifier(r)
@@ -0,0 +1,92 @@
+// Package envmask masks the values of the environment variables whose name
+// looks like the one of a secret, such as DB_PASSWORD or API_TOKEN.
+package envmask
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces the value of a masked variable.
+const Mask = "********"
+
+// assignment matches the NAME=value assignments in a string, such as the
+// changes of a commit (`ENV NAME=value`).
+var assignment = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.]*)=([^\s]*)`)
+
+// Masker masks the variables whose name matches one of its patterns. A nil
+// Masker masks nothing.
+type Masker struct {
+	patterns []*regexp.Regexp
+}
+
+// New returns a Masker of the variables whose name matches one of patterns,
+// which are regular expressions. It returns nil if there is no pattern.
+func New(patterns []string) (*Masker, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	m := &Masker{}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret environment pattern %q: %v", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Masked returns whether the value of the variable name is masked.
+func (m *Masker) Masked(name string) bool {
+	if m == nil {
+		return false
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Env returns a copy of env, a list of NAME=value variables, in which the
+// values of the masked variables are replaced by Mask. env is returned
+// unchanged if no variable is masked.
+func (m *Masker) Env(env []string) []string {
+	if m == nil {
+		return env
+	}
+	var masked []string
+	for i, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !m.Masked(parts[0]) {
+			continue
+		}
+		if masked == nil {
+			masked = make([]string, len(env))
+			copy(masked, env)
+		}
+		masked[i] = parts[0] + "=" + Mask
+	}
+	if masked == nil {
+		return env
+	}
+	return masked
+}
+
+// String replaces by Mask the values of the NAME=value assignments of the
+// masked variables in s.
+func (m *Masker) String(s string) string {
+	if m == nil {
+		return s
+	}
+	return assignment.ReplaceAllStringFunc(s, func(a string) string {
+		name := a[:strings.Index(a, "=")]
+		if !m.Masked(name) {
+			return a
+		}
+		return name + "=" + Mask
+	})
+}
@@ -0,0 +1,53 @@
+package envmask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewInvalidPattern(t *testing.T) {
+	if _, err := New([]string{"(PASSWORD"}); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+	m, err := New(nil)
+	if err != nil || m != nil {
+		t.Fatalf("expected no masker without pattern, got %v, %v", m, err)
+	}
+}
+
+func TestMaskEnv(t *testing.T) {
+	m, err := New([]string{"(?i)password", "_TOKEN$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := []string{"PATH=/usr/bin", "DB_Password=s3cr3t", "API_TOKEN=abc", "TOKEN_TYPE=bearer", "EMPTY_PASSWORD"}
+	expected := []string{"PATH=/usr/bin", "DB_Password=" + Mask, "API_TOKEN=" + Mask, "TOKEN_TYPE=bearer", "EMPTY_PASSWORD"}
+	if masked := m.Env(env); !reflect.DeepEqual(masked, expected) {
+		t.Fatalf("expected %v, got %v", expected, masked)
+	}
+	if env[1] != "DB_Password=s3cr3t" {
+		t.Fatalf("expected the environment not to be modified, got %v", env)
+	}
+
+	var none *Masker
+	if masked := none.Env(env); !reflect.DeepEqual(masked, env) {
+		t.Fatalf("expected a nil masker to mask nothing, got %v", masked)
+	}
+}
+
+func TestMaskString(t *testing.T) {
+	m, err := New([]string{"PASSWORD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s, expected := range map[string]string{
+		"ENV DB_PASSWORD=s3cr3t":           "ENV DB_PASSWORD=" + Mask,
+		"ENV USER=admin PASSWORD=s3cr3t":   "ENV USER=admin PASSWORD=" + Mask,
+		"CMD [\"echo\", \"PASSWORD\"]":     "CMD [\"echo\", \"PASSWORD\"]",
+		"LABEL com.example.password=value": "LABEL com.example.password=value",
+	} {
+		if masked := m.String(s); masked != expected {
+			t.Fatalf("expected %q, got %q", expected, masked)
+		}
+	}
+}
@@ -13,10 +13,15 @@ import (
 )
 
 // JSONError wraps a concrete Code and Message, `Code` is
-// is an integer error code, `Message` is the error message.
+// is an integer error code, `Message` is the error message. Errors located in
+// a file, such as Dockerfile parse errors, also have a `Line` and `Column`,
+// and may come with a `Suggestion` on how to fix them.
 type JSONError struct {
-	Code    int    `json:"code,omitempty"`
-	Message string `json:"message,omitempty"`
+	Code       int    `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
 }
 
 func (e *JSONError) Error() string {
@@ -12,7 +12,7 @@ import (
 )
 
 func TestError(t *testing.T) {
-	je := JSONError{404, "Not found"}
+	je := JSONError{Code: 404, Message: "Not found"}
 	if je.Error() != "Not found" {
 		t.Fatalf("Expected 'Not found' got '%s'", je.Error())
 	}
@@ -160,14 +160,14 @@ func TestJSONMessageDisplay(t *testing.T) {
 // Test JSONMessage with an Error. It will return an error with the text as error, not the meaning of the HTTP code.
 func TestJSONMessageDisplayWithJSONError(t *testing.T) {
 	data := bytes.NewBuffer([]byte{})
-	jsonMessage := JSONMessage{Error: &JSONError{404, "Can't find it"}}
+	jsonMessage := JSONMessage{Error: &JSONError{Code: 404, Message: "Can't find it"}}
 
 	err := jsonMessage.Display(data, true)
 	if err == nil || err.Error() != "Can't find it" {
 		t.Fatalf("Expected a JSONError 404, got [%v]", err)
 	}
 
-	jsonMessage = JSONMessage{Error: &JSONError{401, "Anything"}}
+	jsonMessage = JSONMessage{Error: &JSONError{Code: 401, Message: "Anything"}}
 	err = jsonMessage.Display(data, true)
 	if err == nil || err.Error() != "Authentication is required." {
 		t.Fatalf("Expected an error [Authentication is required.], got [%v]", err)
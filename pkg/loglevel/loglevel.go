@@ -0,0 +1,143 @@
+// Package loglevel sets the level of the logs of the daemon for each of its
+// subsystems, on top of the level of all its logs.
+package loglevel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// SubsystemKey is the field of the logs of a subsystem, with its name.
+const SubsystemKey = "subsystem"
+
+// The subsystems of the daemon whose logs have their own level.
+const (
+	API         = "api"
+	Cgroups     = "cgroups"
+	GraphDriver = "graphdriver"
+	Network     = "network"
+)
+
+var subsystems = map[string]bool{
+	API:         true,
+	Cgroups:     true,
+	GraphDriver: true,
+	Network:     true,
+}
+
+var (
+	mu     sync.RWMutex
+	level  = logrus.InfoLevel
+	levels = map[string]logrus.Level{}
+)
+
+// WithSubsystem returns an entry logging with the field of the subsystem
+// name.
+func WithSubsystem(name string) *logrus.Entry {
+	return logrus.WithField(SubsystemKey, name)
+}
+
+// SetLevel sets the level of the logs of the subsystems without their own
+// level, and of the logs of no subsystem.
+func SetLevel(l logrus.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+	apply()
+}
+
+// Level returns the level of the logs without their own level.
+func Level() logrus.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	return level
+}
+
+// SetSubsystemLevels replaces the levels of the logs of the subsystems.
+func SetSubsystemLevels(l map[string]logrus.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	levels = make(map[string]logrus.Level, len(l))
+	for name, lvl := range l {
+		levels[name] = lvl
+	}
+	apply()
+}
+
+// SubsystemLevels returns the levels of the logs of the subsystems having
+// their own level.
+func SubsystemLevels() map[string]logrus.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	l := make(map[string]logrus.Level, len(levels))
+	for name, lvl := range levels {
+		l[name] = lvl
+	}
+	return l
+}
+
+// ParseSubsystemLevels parses the levels of the logs of subsystems, like
+// {"graphdriver": "debug"}.
+func ParseSubsystemLevels(l map[string]string) (map[string]logrus.Level, error) {
+	parsed := make(map[string]logrus.Level, len(l))
+	for name, s := range l {
+		if !subsystems[name] {
+			var names []string
+			for n := range subsystems {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("invalid log subsystem %q: the subsystems are %v", name, names)
+		}
+		lvl, err := logrus.ParseLevel(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q of subsystem %s", s, name)
+		}
+		parsed[name] = lvl
+	}
+	return parsed, nil
+}
+
+// apply sets the level of logrus to the most verbose level, for the logs to
+// reach the formatter, which discards those above their level.
+func apply() {
+	max := level
+	for _, lvl := range levels {
+		if lvl > max {
+			max = lvl
+		}
+	}
+	logrus.SetLevel(max)
+}
+
+// enabled returns whether the logs of level of the subsystem name, which is
+// empty for the logs of no subsystem, are written.
+func enabled(name string, l logrus.Level) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if lvl, ok := levels[name]; ok {
+		return l <= lvl
+	}
+	return l <= level
+}
+
+// NewFormatter returns a formatter discarding the logs above the level of
+// their subsystem, and formatting the others with f.
+func NewFormatter(f logrus.Formatter) logrus.Formatter {
+	return &formatter{f}
+}
+
+type formatter struct {
+	logrus.Formatter
+}
+
+func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	name, _ := entry.Data[SubsystemKey].(string)
+	if !enabled(name, entry.Level) {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
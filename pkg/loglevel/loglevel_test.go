@@ -0,0 +1,52 @@
+package loglevel
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestSubsystemLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.StandardLogger()
+	out, f := logger.Out, logger.Formatter
+	defer func() {
+		logger.Out, logger.Formatter = out, f
+		SetSubsystemLevels(nil)
+		SetLevel(logrus.InfoLevel)
+	}()
+	logger.Out = &buf
+	logrus.SetFormatter(NewFormatter(&logrus.JSONFormatter{}))
+
+	SetLevel(logrus.WarnLevel)
+	levels, err := ParseSubsystemLevels(map[string]string{GraphDriver: "debug", Network: "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetSubsystemLevels(levels)
+	if logrus.GetLevel() != logrus.DebugLevel {
+		t.Fatalf("expected the level of logrus to be debug, got %v", logrus.GetLevel())
+	}
+
+	logrus.Info("discarded info")
+	logrus.Warn("written warning")
+	WithSubsystem(GraphDriver).Debug("written graphdriver debug")
+	WithSubsystem(Network).Warn("discarded network warning")
+	WithSubsystem(API).Info("discarded api info")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "written warning") || !strings.Contains(lines[1], `"subsystem":"graphdriver"`) {
+		t.Fatalf("unexpected logs:\n%s", buf.String())
+	}
+}
+
+func TestParseSubsystemLevels(t *testing.T) {
+	if _, err := ParseSubsystemLevels(map[string]string{"unknown": "debug"}); err == nil {
+		t.Fatal("expected an error for an unknown subsystem")
+	}
+	if _, err := ParseSubsystemLevels(map[string]string{API: "verbose"}); err == nil {
+		t.Fatal("expected an error for an invalid level")
+	}
+}
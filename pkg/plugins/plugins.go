@@ -24,6 +24,7 @@ package plugins
 
 import (
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -116,14 +117,16 @@ func (p *Plugin) activateWithLock() error {
 	}
 	p.client = c
 
-	m := new(Manifest)
-	if err = p.client.Call("Plugin.Activate", nil, m); err != nil {
-		return err
+	// The plugins added by Register already have their manifest.
+	if p.Manifest == nil {
+		m := new(Manifest)
+		if err = p.client.Call("Plugin.Activate", nil, m); err != nil {
+			return err
+		}
+		p.Manifest = m
 	}
 
-	p.Manifest = m
-
-	for _, iface := range m.Implements {
+	for _, iface := range p.Manifest.Implements {
 		handler, handled := extpointHandlers[iface]
 		if !handled {
 			continue
@@ -142,6 +145,19 @@ func (p *Plugin) waitActive() error {
 	return p.activateErr
 }
 
+// Ping calls the activation handshake of an active plugin once, without
+// retrying, to check that it still responds.
+func (p *Plugin) Ping() error {
+	if err := p.waitActive(); err != nil {
+		return err
+	}
+	body, err := p.client.callWithRetry("Plugin.Activate", nil, false)
+	if err != nil {
+		return err
+	}
+	return body.Close()
+}
+
 func (p *Plugin) implements(kind string) bool {
 	if err := p.waitActive(); err != nil {
 		return false
@@ -219,6 +235,42 @@ func Get(name, imp string) (*Plugin, error) {
 	return nil, ErrNotImplements
 }
 
+// Loaded returns the plugins loaded by the daemon, sorted by name.
+func Loaded() []*Plugin {
+	storage.Lock()
+	defer storage.Unlock()
+	var out []*Plugin
+	for _, pl := range storage.plugins {
+		out = append(out, pl)
+	}
+	sort.Sort(byName(out))
+	return out
+}
+
+type byName []*Plugin
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Less(i, j int) bool { return s[i].name < s[j].name }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Register adds a plugin listening on addr, such as a plugin managed by the
+// daemon, to the plugins returned by Get. The plugin is not asked for the
+// interfaces it implements: it is activated with implements.
+func Register(name, addr string, implements []string) {
+	p := NewLocalPlugin(name, addr)
+	p.Manifest = &Manifest{Implements: implements}
+	storage.Lock()
+	storage.plugins[name] = p
+	storage.Unlock()
+}
+
+// Unregister removes a plugin added by Register.
+func Unregister(name string) {
+	storage.Lock()
+	delete(storage.plugins, name)
+	storage.Unlock()
+}
+
 // Handle adds the specified function to the extpointHandlers.
 func Handle(iface string, fn func(string, *Client)) {
 	extpointHandlers[iface] = fn
@@ -0,0 +1,70 @@
+package plugins
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/pkg/plugins/transport"
+)
+
+func TestPluginPing(t *testing.T) {
+	addr := setupRemotePluginServer()
+	defer teardownRemotePluginServer()
+
+	mux.HandleFunc("/Plugin.Activate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", transport.VersionMimetype)
+		fmt.Fprintln(w, `{"Implements": ["VolumeDriver"]}`)
+	})
+
+	p := NewLocalPlugin("ping", addr)
+	if err := p.activate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Close()
+	if err := p.Ping(); err == nil {
+		t.Fatal("expected an error pinging a plugin which stopped")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	addr := setupRemotePluginServer()
+	defer teardownRemotePluginServer()
+
+	mux.HandleFunc("/Plugin.Activate", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("expected a registered plugin not to be asked for its manifest")
+	})
+
+	var handled string
+	Handle("TestDriver", func(name string, c *Client) {
+		handled = name
+	})
+	defer delete(extpointHandlers, "TestDriver")
+
+	Register("managed", addr, []string{"TestDriver"})
+	p, err := Get("managed", "TestDriver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Client() == nil {
+		t.Fatal("expected the registered plugin to have a client")
+	}
+	if handled != "managed" {
+		t.Fatalf("expected the handler of the interface to be called with the registered plugin, got %q", handled)
+	}
+	if _, err := Get("managed", "VolumeDriver"); err != ErrNotImplements {
+		t.Fatalf("expected %v, got %v", ErrNotImplements, err)
+	}
+
+	Unregister("managed")
+	storage.Lock()
+	_, ok := storage.plugins["managed"]
+	storage.Unlock()
+	if ok {
+		t.Fatal("expected the plugin to be unregistered")
+	}
+}
@@ -43,6 +43,22 @@ func ParseSignal(rawSignal string) (syscall.Signal, error) {
 	return signal, nil
 }
 
+// SignalName returns the name of a signal, such as SIGKILL, or its number if
+// the signal map doesn't include it. For a signal of several names, the first
+// in alphabetical order is returned.
+func SignalName(sig syscall.Signal) string {
+	name := ""
+	for n, s := range SignalMap {
+		if s == sig && (name == "" || n < name) {
+			name = n
+		}
+	}
+	if name == "" {
+		return strconv.Itoa(int(sig))
+	}
+	return "SIG" + name
+}
+
 // ValidSignalForPlatform returns true if a signal is valid on the platform
 func ValidSignalForPlatform(sig syscall.Signal) bool {
 	for _, v := range SignalMap {
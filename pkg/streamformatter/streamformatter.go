@@ -31,8 +31,12 @@ var streamNewlineBytes = []byte(streamNewline)
 
 // FormatStream formats the specified stream.
 func (sf *StreamFormatter) FormatStream(str string) []byte {
+	return sf.formatStream("", str)
+}
+
+func (sf *StreamFormatter) formatStream(id, str string) []byte {
 	if sf.json {
-		b, err := json.Marshal(&jsonmessage.JSONMessage{Stream: str})
+		b, err := json.Marshal(&jsonmessage.JSONMessage{ID: id, Stream: str})
 		if err != nil {
 			return sf.FormatError(err)
 		}
@@ -54,12 +58,42 @@ func (sf *StreamFormatter) FormatStatus(id, format string, a ...interface{}) []b
 	return []byte(str + streamNewline)
 }
 
+// FormatAux formats out-of-band data, such as the progress of a build step,
+// for the specified id. Only JSON streams have out-of-band data.
+func (sf *StreamFormatter) FormatAux(id string, aux interface{}) []byte {
+	if !sf.json {
+		return nil
+	}
+	auxJSONBytes, err := json.Marshal(aux)
+	if err != nil {
+		return sf.FormatError(err)
+	}
+	auxJSON := json.RawMessage(auxJSONBytes)
+	b, err := json.Marshal(&jsonmessage.JSONMessage{ID: id, Aux: &auxJSON})
+	if err != nil {
+		return sf.FormatError(err)
+	}
+	return append(b, streamNewlineBytes...)
+}
+
+// PositionalError is an error located in a file, such as a Dockerfile parse
+// error. Its location is sent along with the message in the JSON stream.
+type PositionalError interface {
+	error
+	Position() (line, column int)
+	Hint() string
+}
+
 // FormatError formats the specified error.
 func (sf *StreamFormatter) FormatError(err error) []byte {
 	if sf.json {
 		jsonError, ok := err.(*jsonmessage.JSONError)
 		if !ok {
 			jsonError = &jsonmessage.JSONError{Message: err.Error()}
+			if perr, ok := err.(PositionalError); ok {
+				jsonError.Line, jsonError.Column = perr.Position()
+				jsonError.Suggestion = perr.Hint()
+			}
 		}
 		if b, err := json.Marshal(&jsonmessage.JSONMessage{Error: jsonError, ErrorMessage: err.Error()}); err == nil {
 			return append(b, streamNewlineBytes...)
@@ -142,13 +176,15 @@ func (out *progressOutput) WriteProgress(prog progress.Progress) error {
 }
 
 // StdoutFormatter is a streamFormatter that writes to the standard output.
+// The stream is tagged with ID, if not empty.
 type StdoutFormatter struct {
 	io.Writer
 	*StreamFormatter
+	ID string
 }
 
 func (sf *StdoutFormatter) Write(buf []byte) (int, error) {
-	formattedBuf := sf.StreamFormatter.FormatStream(string(buf))
+	formattedBuf := sf.StreamFormatter.formatStream(sf.ID, string(buf))
 	n, err := sf.Writer.Write(formattedBuf)
 	if n != len(formattedBuf) {
 		return n, io.ErrShortWrite
@@ -157,13 +193,15 @@ func (sf *StdoutFormatter) Write(buf []byte) (int, error) {
 }
 
 // StderrFormatter is a streamFormatter that writes to the standard error.
+// The stream is tagged with ID, if not empty.
 type StderrFormatter struct {
 	io.Writer
 	*StreamFormatter
+	ID string
 }
 
 func (sf *StderrFormatter) Write(buf []byte) (int, error) {
-	formattedBuf := sf.StreamFormatter.FormatStream("\033[91m" + string(buf) + "\033[0m")
+	formattedBuf := sf.StreamFormatter.formatStream(sf.ID, "\033[91m"+string(buf)+"\033[0m")
 	n, err := sf.Writer.Write(formattedBuf)
 	if n != len(formattedBuf) {
 		return n, io.ErrShortWrite
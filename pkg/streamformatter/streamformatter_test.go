@@ -1,6 +1,7 @@
 package streamformatter
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"reflect"
@@ -67,6 +68,20 @@ func TestJSONFormatJSONError(t *testing.T) {
 	}
 }
 
+type positionalError struct{}
+
+func (positionalError) Error() string                { return "unknown instruction" }
+func (positionalError) Position() (line, column int) { return 3, 1 }
+func (positionalError) Hint() string                 { return "did you mean RUN?" }
+
+func TestJSONFormatPositionalError(t *testing.T) {
+	sf := NewJSONStreamFormatter()
+	res := sf.FormatError(positionalError{})
+	if string(res) != `{"errorDetail":{"message":"unknown instruction","line":3,"column":1,"suggestion":"did you mean RUN?"},"error":"unknown instruction"}`+"\r\n" {
+		t.Fatalf("%q", res)
+	}
+}
+
 func TestJSONFormatProgress(t *testing.T) {
 	sf := NewJSONStreamFormatter()
 	progress := &jsonmessage.JSONProgress{
@@ -106,3 +121,25 @@ func TestJSONFormatProgress(t *testing.T) {
 		t.Fatal("Original progress not equals progress from FormatProgress")
 	}
 }
+
+func TestJSONFormatAux(t *testing.T) {
+	sf := NewJSONStreamFormatter()
+	res := sf.FormatAux("1", map[string]int{"index": 1})
+	if string(res) != `{"id":"1","aux":{"index":1}}`+"\r\n" {
+		t.Fatalf("%q", res)
+	}
+	if res := NewStreamFormatter().FormatAux("1", map[string]int{"index": 1}); res != nil {
+		t.Fatalf("expected no out-of-band data in a plain stream, got %q", res)
+	}
+}
+
+func TestJSONStdoutFormatterID(t *testing.T) {
+	var buf bytes.Buffer
+	stdout := &StdoutFormatter{Writer: &buf, StreamFormatter: NewJSONStreamFormatter(), ID: "2"}
+	if _, err := stdout.Write([]byte("output\n")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `{"stream":"output\n","id":"2"}`+"\r\n" {
+		t.Fatalf("%q", buf.String())
+	}
+}
@@ -0,0 +1,43 @@
+package sysinfo
+
+import (
+	"sync"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// getCgroupMounts parses the cgroup mounts; it is replaced by the tests.
+var getCgroupMounts = cgroups.GetCgroupMounts
+
+// cgroupLayout caches the cgroup mounts of the process, which are looked up
+// for every container created, started, updated or listed by top, until it is
+// invalidated.
+var cgroupLayout struct {
+	sync.Mutex
+	mounts []cgroups.Mount
+	valid  bool
+}
+
+// CgroupMounts returns the cgroup mounts of the process, parsing
+// /proc/self/mountinfo only the first time, and after the layout is
+// invalidated. The mounts must not be modified.
+func CgroupMounts() ([]cgroups.Mount, error) {
+	cgroupLayout.Lock()
+	defer cgroupLayout.Unlock()
+	if !cgroupLayout.valid {
+		mounts, err := getCgroupMounts()
+		if err != nil {
+			return nil, err
+		}
+		cgroupLayout.mounts, cgroupLayout.valid = mounts, true
+	}
+	return cgroupLayout.mounts, nil
+}
+
+// InvalidateCgroupLayout discards the cached cgroup mounts, for a cgroup
+// hierarchy mounted or unmounted since they were parsed to be seen.
+func InvalidateCgroupLayout() {
+	cgroupLayout.Lock()
+	defer cgroupLayout.Unlock()
+	cgroupLayout.mounts, cgroupLayout.valid = nil, false
+}
@@ -0,0 +1,42 @@
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+func TestCgroupMountsCached(t *testing.T) {
+	defer func(f func() ([]cgroups.Mount, error)) {
+		getCgroupMounts = f
+		InvalidateCgroupLayout()
+	}(getCgroupMounts)
+
+	var parsed int
+	getCgroupMounts = func() ([]cgroups.Mount, error) {
+		parsed++
+		return []cgroups.Mount{{Mountpoint: "/sys/fs/cgroup/memory", Root: "/", Subsystems: []string{"memory"}}}, nil
+	}
+	InvalidateCgroupLayout()
+
+	for i := 0; i < 3; i++ {
+		mounts, err := CgroupMounts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mounts) != 1 || mounts[0].Mountpoint != "/sys/fs/cgroup/memory" {
+			t.Fatalf("unexpected mounts %v", mounts)
+		}
+	}
+	if parsed != 1 {
+		t.Fatalf("expected the mounts to be parsed once, got %d", parsed)
+	}
+
+	InvalidateCgroupLayout()
+	if _, err := CgroupMounts(); err != nil {
+		t.Fatal(err)
+	}
+	if parsed != 2 {
+		t.Fatalf("expected the mounts to be parsed again after the invalidation, got %d", parsed)
+	}
+}
@@ -0,0 +1,11 @@
+package sysinfo
+
+// NUMANode is a NUMA node of the host.
+type NUMANode struct {
+	// ID is the number of the node.
+	ID int
+	// CPUs is the list of the CPUs of the node, like "0-3,8-11".
+	CPUs string
+	// MemTotal is the memory of the node, in bytes.
+	MemTotal int64
+}
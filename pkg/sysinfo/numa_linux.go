@@ -0,0 +1,68 @@
+package sysinfo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const numaNodesRoot = "/sys/devices/system/node"
+
+// NUMANodes returns the NUMA nodes of the host, ordered by ID, or none if
+// the kernel does not report them.
+func NUMANodes() ([]NUMANode, error) {
+	return numaNodes(numaNodesRoot)
+}
+
+func numaNodes(root string) ([]NUMANode, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	var nodes []NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		cpus, err := ioutil.ReadFile(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		node := NUMANode{ID: id, CPUs: strings.TrimSpace(string(cpus))}
+		if meminfo, err := ioutil.ReadFile(filepath.Join(dir, "meminfo")); err == nil {
+			node.MemTotal = parseNodeMemTotal(string(meminfo))
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Sort(byNUMANodeID(nodes))
+	return nodes, nil
+}
+
+// parseNodeMemTotal returns the total memory of a NUMA node, in bytes, from
+// its meminfo, like "Node 0 MemTotal:       16303688 kB".
+func parseNodeMemTotal(meminfo string) int64 {
+	for _, line := range strings.Split(meminfo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+		v, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return 0
+		}
+		if len(fields) > 4 && fields[4] == "kB" {
+			v *= 1024
+		}
+		return v
+	}
+	return 0
+}
+
+type byNUMANodeID []NUMANode
+
+func (n byNUMANodeID) Len() int           { return len(n) }
+func (n byNUMANodeID) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n byNUMANodeID) Less(i, j int) bool { return n[i].ID < n[j].ID }
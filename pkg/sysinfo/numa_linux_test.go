@@ -0,0 +1,49 @@
+package sysinfo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNUMANodes(t *testing.T) {
+	root, err := ioutil.TempDir("", "test-numa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	for name, files := range map[string]map[string]string{
+		"node0":    {"cpulist": "0-3,8-11\n", "meminfo": "Node 0 MemTotal:       16303688 kB\nNode 0 MemFree:        1024 kB\n"},
+		"node10":   {"cpulist": "4-7\n"},
+		"possible": {},
+	} {
+		dir := filepath.Join(root, name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	nodes, err := numaNodes(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []NUMANode{
+		{ID: 0, CPUs: "0-3,8-11", MemTotal: 16303688 * 1024},
+		{ID: 10, CPUs: "4-7"},
+	}
+	if !reflect.DeepEqual(nodes, expected) {
+		t.Fatalf("expected %v, got %v", expected, nodes)
+	}
+
+	if nodes, err := numaNodes(filepath.Join(root, "missing")); err != nil || len(nodes) != 0 {
+		t.Fatalf("expected no node, got %v, %v", nodes, err)
+	}
+}
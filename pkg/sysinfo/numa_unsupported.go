@@ -0,0 +1,8 @@
+// +build !linux
+
+package sysinfo
+
+// NUMANodes returns no node: the NUMA topology is only reported on Linux.
+func NUMANodes() ([]NUMANode, error) {
+	return nil, nil
+}
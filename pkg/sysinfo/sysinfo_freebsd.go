@@ -5,3 +5,7 @@ func New(quiet bool) *SysInfo {
 	sysInfo := &SysInfo{}
 	return sysInfo
 }
+
+// InvalidateCgroupLayout does nothing, as there are no cgroups on freebsd.
+func InvalidateCgroupLayout() {
+}
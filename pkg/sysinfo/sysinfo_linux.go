@@ -8,8 +8,7 @@ import (
 	"strings"
 	"syscall"
 
-	"github.com/Sirupsen/logrus"
-	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/docker/docker/pkg/loglevel"
 )
 
 const (
@@ -18,7 +17,7 @@ const (
 )
 
 func findCgroupMountpoints() (map[string]string, error) {
-	cgMounts, err := cgroups.GetCgroupMounts()
+	cgMounts, err := CgroupMounts()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse cgroup information: %v", err)
 	}
@@ -38,13 +37,13 @@ func New(quiet bool) *SysInfo {
 	sysInfo := &SysInfo{}
 	cgMounts, err := findCgroupMountpoints()
 	if err != nil {
-		logrus.Warnf("Failed to parse cgroup information: %v", err)
+		loglevel.WithSubsystem(loglevel.Cgroups).Warnf("Failed to parse cgroup information: %v", err)
 	} else {
 		sysInfo.cgroupMemInfo = checkCgroupMem(cgMounts, quiet)
 		sysInfo.cgroupCPUInfo = checkCgroupCPU(cgMounts, quiet)
 		sysInfo.cgroupBlkioInfo = checkCgroupBlkioInfo(cgMounts, quiet)
 		sysInfo.cgroupCpusetInfo = checkCgroupCpusetInfo(cgMounts, quiet)
-		sysInfo.cgroupPids = checkCgroupPids(quiet)
+		sysInfo.cgroupPids = checkCgroupPids(cgMounts, quiet)
 	}
 
 	_, ok := cgMounts["devices"]
@@ -75,30 +74,30 @@ func checkCgroupMem(cgMounts map[string]string, quiet bool) cgroupMemInfo {
 	mountPoint, ok := cgMounts["memory"]
 	if !ok {
 		if !quiet {
-			logrus.Warn("Your kernel does not support cgroup memory limit")
+			loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup memory limit")
 		}
 		return cgroupMemInfo{}
 	}
 
 	swapLimit := cgroupEnabled(mountPoint, "memory.memsw.limit_in_bytes")
 	if !quiet && !swapLimit {
-		logrus.Warn("Your kernel does not support swap memory limit.")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support swap memory limit.")
 	}
 	memoryReservation := cgroupEnabled(mountPoint, "memory.soft_limit_in_bytes")
 	if !quiet && !memoryReservation {
-		logrus.Warn("Your kernel does not support memory reservation.")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support memory reservation.")
 	}
 	oomKillDisable := cgroupEnabled(mountPoint, "memory.oom_control")
 	if !quiet && !oomKillDisable {
-		logrus.Warn("Your kernel does not support oom control.")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support oom control.")
 	}
 	memorySwappiness := cgroupEnabled(mountPoint, "memory.swappiness")
 	if !quiet && !memorySwappiness {
-		logrus.Warn("Your kernel does not support memory swappiness.")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support memory swappiness.")
 	}
 	kernelMemory := cgroupEnabled(mountPoint, "memory.kmem.limit_in_bytes")
 	if !quiet && !kernelMemory {
-		logrus.Warn("Your kernel does not support kernel memory limit.")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support kernel memory limit.")
 	}
 
 	return cgroupMemInfo{
@@ -116,24 +115,24 @@ func checkCgroupCPU(cgMounts map[string]string, quiet bool) cgroupCPUInfo {
 	mountPoint, ok := cgMounts["cpu"]
 	if !ok {
 		if !quiet {
-			logrus.Warn("Unable to find cpu cgroup in mounts")
+			loglevel.WithSubsystem(loglevel.Cgroups).Warn("Unable to find cpu cgroup in mounts")
 		}
 		return cgroupCPUInfo{}
 	}
 
 	cpuShares := cgroupEnabled(mountPoint, "cpu.shares")
 	if !quiet && !cpuShares {
-		logrus.Warn("Your kernel does not support cgroup cpu shares")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup cpu shares")
 	}
 
 	cpuCfsPeriod := cgroupEnabled(mountPoint, "cpu.cfs_period_us")
 	if !quiet && !cpuCfsPeriod {
-		logrus.Warn("Your kernel does not support cgroup cfs period")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup cfs period")
 	}
 
 	cpuCfsQuota := cgroupEnabled(mountPoint, "cpu.cfs_quota_us")
 	if !quiet && !cpuCfsQuota {
-		logrus.Warn("Your kernel does not support cgroup cfs quotas")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup cfs quotas")
 	}
 	return cgroupCPUInfo{
 		CPUShares:    cpuShares,
@@ -147,38 +146,38 @@ func checkCgroupBlkioInfo(cgMounts map[string]string, quiet bool) cgroupBlkioInf
 	mountPoint, ok := cgMounts["blkio"]
 	if !ok {
 		if !quiet {
-			logrus.Warn("Unable to find blkio cgroup in mounts")
+			loglevel.WithSubsystem(loglevel.Cgroups).Warn("Unable to find blkio cgroup in mounts")
 		}
 		return cgroupBlkioInfo{}
 	}
 
 	weight := cgroupEnabled(mountPoint, "blkio.weight")
 	if !quiet && !weight {
-		logrus.Warn("Your kernel does not support cgroup blkio weight")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio weight")
 	}
 
 	weightDevice := cgroupEnabled(mountPoint, "blkio.weight_device")
 	if !quiet && !weightDevice {
-		logrus.Warn("Your kernel does not support cgroup blkio weight_device")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio weight_device")
 	}
 
 	readBpsDevice := cgroupEnabled(mountPoint, "blkio.throttle.read_bps_device")
 	if !quiet && !readBpsDevice {
-		logrus.Warn("Your kernel does not support cgroup blkio throttle.read_bps_device")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio throttle.read_bps_device")
 	}
 
 	writeBpsDevice := cgroupEnabled(mountPoint, "blkio.throttle.write_bps_device")
 	if !quiet && !writeBpsDevice {
-		logrus.Warn("Your kernel does not support cgroup blkio throttle.write_bps_device")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio throttle.write_bps_device")
 	}
 	readIOpsDevice := cgroupEnabled(mountPoint, "blkio.throttle.read_iops_device")
 	if !quiet && !readIOpsDevice {
-		logrus.Warn("Your kernel does not support cgroup blkio throttle.read_iops_device")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio throttle.read_iops_device")
 	}
 
 	writeIOpsDevice := cgroupEnabled(mountPoint, "blkio.throttle.write_iops_device")
 	if !quiet && !writeIOpsDevice {
-		logrus.Warn("Your kernel does not support cgroup blkio throttle.write_iops_device")
+		loglevel.WithSubsystem(loglevel.Cgroups).Warn("Your kernel does not support cgroup blkio throttle.write_iops_device")
 	}
 	return cgroupBlkioInfo{
 		BlkioWeight:          weight,
@@ -195,7 +194,7 @@ func checkCgroupCpusetInfo(cgMounts map[string]string, quiet bool) cgroupCpusetI
 	mountPoint, ok := cgMounts["cpuset"]
 	if !ok {
 		if !quiet {
-			logrus.Warn("Unable to find cpuset cgroup in mounts")
+			loglevel.WithSubsystem(loglevel.Cgroups).Warn("Unable to find cpuset cgroup in mounts")
 		}
 		return cgroupCpusetInfo{}
 	}
@@ -218,11 +217,10 @@ func checkCgroupCpusetInfo(cgMounts map[string]string, quiet bool) cgroupCpusetI
 }
 
 // checkCgroupPids reads the pids information from the pids cgroup mount point.
-func checkCgroupPids(quiet bool) cgroupPids {
-	_, err := cgroups.FindCgroupMountpoint("pids")
-	if err != nil {
+func checkCgroupPids(cgMounts map[string]string, quiet bool) cgroupPids {
+	if _, ok := cgMounts["pids"]; !ok {
 		if !quiet {
-			logrus.Warn(err)
+			loglevel.WithSubsystem(loglevel.Cgroups).Warn("Unable to find pids cgroup in mounts")
 		}
 		return cgroupPids{}
 	}
@@ -64,3 +64,43 @@ func TailFile(f io.ReadSeeker, n int) ([][]byte, error) {
 	}
 	return lines[:len(lines)-1], nil
 }
+
+// TailOffset returns the offset in reader f of the first of its last n lines.
+// f is read backwards by blocks, without keeping the lines in memory.
+func TailOffset(f io.ReadSeeker, n int) (int64, error) {
+	if n <= 0 {
+		return 0, ErrNonPositiveLinesNumber
+	}
+	end, err := f.Seek(0, os.SEEK_END)
+	if err != nil {
+		return 0, err
+	}
+	b := make([]byte, blockSize)
+	var cnt int
+	for first := true; end > 0; first = false {
+		start := end - blockSize
+		if start < 0 {
+			start = 0
+		}
+		if _, err := f.Seek(start, os.SEEK_SET); err != nil {
+			return 0, err
+		}
+		block := b[:end-start]
+		if _, err := io.ReadFull(f, block); err != nil {
+			return 0, err
+		}
+		// a last line without a newline is counted as a line
+		if first && block[len(block)-1] != eol[0] {
+			cnt++
+		}
+		for i := len(block) - 1; i >= 0; i-- {
+			if block[i] == eol[0] {
+				if cnt++; cnt > n {
+					return start + int64(i) + 1, nil
+				}
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
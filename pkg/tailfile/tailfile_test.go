@@ -3,6 +3,7 @@ package tailfile
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -146,3 +147,32 @@ func BenchmarkTail(b *testing.B) {
 		}
 	}
 }
+
+func TestTailOffset(t *testing.T) {
+	tests := []struct {
+		data   string
+		n      int
+		offset int64
+	}{
+		{"", 1, 0},
+		{"first\nsecond\nthird\n", 1, 13},
+		{"first\nsecond\nthird\n", 2, 6},
+		{"first\nsecond\nthird\n", 3, 0},
+		{"first\nsecond\nthird\n", 10, 0},
+		{"first\nsecond\nthird", 1, 13},
+		{"first\nsecond\nthird", 2, 6},
+		{strings.Repeat("a", 2*blockSize) + "\n" + strings.Repeat("b", blockSize) + "\n", 1, 2*blockSize + 1},
+	}
+	for _, test := range tests {
+		offset, err := TailOffset(strings.NewReader(test.data), test.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if offset != test.offset {
+			t.Fatalf("expected offset %d of the last %d lines of %q, got %d", test.offset, test.n, test.data, offset)
+		}
+	}
+	if _, err := TailOffset(strings.NewReader("line\n"), 0); err != ErrNonPositiveLinesNumber {
+		t.Fatalf("expected error %v, got %v", ErrNonPositiveLinesNumber, err)
+	}
+}
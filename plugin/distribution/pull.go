@@ -81,7 +81,7 @@ func Pull(name string, rs registry.Service, metaheader http.Header, authConfig *
 		return nil, err
 	}
 
-	endpoints, err := rs.LookupPullEndpoints(repoInfo.Hostname())
+	endpoints, err := rs.LookupPullEndpoints(repoInfo)
 	if err != nil {
 		logrus.Debugf("pull.go: error in LookupPullEndpoints: %v", err)
 		return nil, err
@@ -63,6 +63,52 @@ func (p *plugin) Name() string {
 	return name
 }
 
+// legacyInterfaces are the interfaces of pkg/plugins implemented by the
+// plugins of a capability.
+var legacyInterfaces = map[string]string{
+	"volumedriver":  "VolumeDriver",
+	"networkdriver": "NetworkDriver",
+	"ipamdriver":    "IpamDriver",
+	"authz":         "authz",
+}
+
+// names returns the names a plugin is looked up with: its name, and its name
+// without the latest tag.
+func (p *plugin) names() []string {
+	names := []string{p.Name()}
+	if p.P.Tag == reference.DefaultTag {
+		names = append(names, p.P.Name)
+	}
+	return names
+}
+
+// register exposes the socket of an enabled plugin to the subsystems looking
+// up their plugins with pkg/plugins, such as the network drivers and the
+// authorization plugins, which then use it like a legacy plugin.
+func (pm *Manager) register(p *plugin, addr string) {
+	var implements []string
+	for _, typ := range p.P.Manifest.Interface.Types {
+		if typ.Prefix != "docker" {
+			continue
+		}
+		if iface, ok := legacyInterfaces[typ.Capability]; ok {
+			implements = append(implements, iface)
+		} else {
+			implements = append(implements, typ.Capability)
+		}
+	}
+	for _, name := range p.names() {
+		plugins.Register(name, addr, implements)
+	}
+}
+
+// unregister hides a disabled plugin from the subsystems using pkg/plugins.
+func (pm *Manager) unregister(p *plugin) {
+	for _, name := range p.names() {
+		plugins.Unregister(name)
+	}
+}
+
 func (pm *Manager) newPlugin(ref reference.Named, id string) *plugin {
 	p := &plugin{
 		P: types.Plugin{
@@ -100,6 +146,8 @@ type Manager struct {
 	handleLegacy     bool
 	liveRestore      bool
 	shutdown         bool
+	cgroupParent     string // cgroup parent of the cgroups, or systemd slice of the scopes, of the plugins
+	systemdCgroup    bool
 }
 
 // GetManager returns the singleton plugin Manager
@@ -107,9 +155,11 @@ func GetManager() *Manager {
 	return manager
 }
 
-// Init (was NewManager) instantiates the singleton Manager.
+// Init (was NewManager) instantiates the singleton Manager. The plugins run
+// in their own cgroup under cgroupParent, or in their own scope in the
+// cgroupParent slice with the systemd cgroup driver.
 // TODO: revert this to NewManager once we get rid of all the singletons.
-func Init(root, execRoot string, remote libcontainerd.Remote, rs registry.Service, liveRestore bool) (err error) {
+func Init(root, execRoot string, remote libcontainerd.Remote, rs registry.Service, liveRestore bool, cgroupParent string, systemdCgroup bool) (err error) {
 	if manager != nil {
 		return nil
 	}
@@ -131,6 +181,8 @@ func Init(root, execRoot string, remote libcontainerd.Remote, rs registry.Servic
 		registryService: rs,
 		handleLegacy:    true,
 		liveRestore:     liveRestore,
+		cgroupParent:    cgroupParent,
+		systemdCgroup:   systemdCgroup,
 	}
 	if err := os.MkdirAll(manager.runRoot, 0700); err != nil {
 		return err
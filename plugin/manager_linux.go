@@ -30,31 +30,46 @@ func (pm *Manager) enable(p *plugin) error {
 		return err
 	}
 
-	socket := p.P.Manifest.Interface.Socket
-	p.client, err = plugins.NewClient("unix://"+filepath.Join(p.runtimeSourcePath, socket), nil)
-	if err != nil {
+	if err := pm.activate(p); err != nil {
 		return err
 	}
 
-	//TODO: check net.Dial
-
 	pm.Lock() // fixme: lock single record
 	p.P.Active = true
 	pm.save()
 	pm.Unlock()
 
+	return nil
+}
+
+// activate connects to the socket of a running plugin, and hands it to the
+// subsystems of its capabilities.
+func (pm *Manager) activate(p *plugin) error {
+	addr := "unix://" + filepath.Join(p.runtimeSourcePath, p.P.Manifest.Interface.Socket)
+	client, err := plugins.NewClient(addr, nil)
+	if err != nil {
+		return err
+	}
+	p.client = client
+
+	//TODO: check net.Dial
+
 	for _, typ := range p.P.Manifest.Interface.Types {
 		if handler := pm.handlers[typ.String()]; handler != nil {
 			handler(p.Name(), p.Client())
 		}
 	}
-
+	pm.register(p, addr)
 	return nil
 }
 
 func (pm *Manager) restore(p *plugin) error {
 	p.restartManager = restartmanager.New(container.RestartPolicy{Name: "always"}, 0)
-	return pm.containerdClient.Restore(p.P.ID, libcontainerd.WithRestartManager(p.restartManager))
+	if err := pm.containerdClient.Restore(p.P.ID, libcontainerd.WithRestartManager(p.restartManager)); err != nil {
+		return err
+	}
+	// A plugin which kept running while the daemon was down is used again.
+	return pm.activate(p)
 }
 
 func (pm *Manager) initSpec(p *plugin) (*specs.Spec, error) {
@@ -112,10 +127,19 @@ func (pm *Manager) initSpec(p *plugin) (*specs.Spec, error) {
 		Env:      envs,
 	}
 
+	// Like the containers, the plugins run in their own cgroup, or their own
+	// systemd scope, and are accounted for apart from the daemon.
+	cgroupsPath := filepath.Join(pm.cgroupParent, "plugins", p.P.ID)
+	if pm.systemdCgroup {
+		cgroupsPath = pm.cgroupParent + ":docker-plugin:" + p.P.ID
+	}
+	s.Linux.CgroupsPath = &cgroupsPath
+
 	return &s, nil
 }
 
 func (pm *Manager) disable(p *plugin) error {
+	pm.unregister(p)
 	if err := p.restartManager.Cancel(); err != nil {
 		logrus.Error(err)
 	}
@@ -21,12 +21,34 @@ type ServiceOptions struct {
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
 	V2Only bool `json:"disable-legacy-registry,omitempty"`
+
+	// Registries holds the configuration of registries, and of namespaces
+	// of registries, keyed by `host[:port][/namespace]`. It can only be set
+	// in the configuration file.
+	Registries map[string]RegistryOptions `json:"registries,omitempty"`
+}
+
+// RegistryOptions holds the configuration of a registry, or of a namespace
+// of a registry.
+type RegistryOptions struct {
+	// Mirrors are tried in order before the registry when pulling.
+	Mirrors []string `json:"mirrors,omitempty"`
+	// CAFile is the path to a bundle of CA certificates trusted for the
+	// registry. It can't be set for a namespace.
+	CAFile string `json:"ca-file,omitempty"`
+	// Insecure allows plain HTTP, and HTTPS without certificate
+	// verification, for the registry. It can't be set for a namespace.
+	Insecure bool `json:"insecure,omitempty"`
 }
 
 // serviceConfig holds daemon configuration for the registry service.
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+
+	// Registries holds the validated per-registry and per-namespace
+	// configuration, keyed by normalized `host[:port][/namespace]`.
+	Registries map[string]RegistryOptions
 }
 
 var (
@@ -97,7 +119,32 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// and Mirrors are only for the official registry anyways.
 			Mirrors: options.Mirrors,
 		},
-		V2Only: options.V2Only,
+		V2Only:     options.V2Only,
+		Registries: make(map[string]RegistryOptions, len(options.Registries)),
+	}
+	for key, registry := range options.Registries {
+		host, namespace, err := splitRegistryKey(key)
+		if err != nil {
+			// the configuration was checked with ValidateRegistries
+			continue
+		}
+		mirrors := make([]string, 0, len(registry.Mirrors))
+		for _, mirror := range registry.Mirrors {
+			if mirror, err := ValidateMirror(mirror); err == nil {
+				mirrors = append(mirrors, mirror)
+			}
+		}
+		registry.Mirrors = mirrors
+		if namespace != "" {
+			config.Registries[host+"/"+namespace] = registry
+			continue
+		}
+		config.Registries[host] = registry
+		if host == IndexName {
+			config.Mirrors = append(config.Mirrors, mirrors...)
+		} else if registry.Insecure {
+			options.InsecureRegistries = append(options.InsecureRegistries, host)
+		}
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
 	for _, r := range options.InsecureRegistries {
@@ -195,6 +242,85 @@ func ValidateMirror(val string) (string, error) {
 	return fmt.Sprintf("%s://%s/", uri.Scheme, uri.Host), nil
 }
 
+// ValidateRegistries validates the configuration of the registries and of
+// their namespaces.
+func ValidateRegistries(registries map[string]RegistryOptions) error {
+	for key, registry := range registries {
+		host, namespace, err := splitRegistryKey(key)
+		if err != nil {
+			return err
+		}
+		for _, mirror := range registry.Mirrors {
+			if _, err := ValidateMirror(mirror); err != nil {
+				return fmt.Errorf("invalid mirror for registry %s: %v", key, err)
+			}
+		}
+		if namespace != "" && (registry.CAFile != "" || registry.Insecure) {
+			return fmt.Errorf("invalid configuration for %s: ca-file and insecure can only be set for a registry, not for a namespace", key)
+		}
+		if host == IndexName && registry.Insecure {
+			return fmt.Errorf("invalid configuration for %s: the official registry can't be insecure", key)
+		}
+	}
+	return nil
+}
+
+// splitRegistryKey splits a key of the registries configuration into the
+// normalized registry host name and the namespace, which may be empty.
+func splitRegistryKey(key string) (host, namespace string, err error) {
+	if err := validateNoScheme(key); err != nil {
+		return "", "", fmt.Errorf("invalid registry %s: it must not contain a scheme", key)
+	}
+	host = key
+	if i := strings.IndexRune(key, '/'); i != -1 {
+		host, namespace = key[:i], strings.Trim(key[i+1:], "/")
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("invalid registry %s: the registry host name is missing", key)
+	}
+	host, err = ValidateIndexName(host)
+	if err != nil {
+		return "", "", err
+	}
+	return host, namespace, nil
+}
+
+// registryOptions returns the configuration of the registry hostname.
+func (config *serviceConfig) registryOptions(hostname string) (RegistryOptions, bool) {
+	if hostname == DefaultV1Registry.Host {
+		hostname = IndexName
+	}
+	registry, ok := config.Registries[hostname]
+	return registry, ok
+}
+
+// mirrorsFor returns the mirrors to pull the repository name from: those of
+// the longest configured namespace containing the repository, else those of
+// its registry.
+func (config *serviceConfig) mirrorsFor(name reference.Named) []string {
+	hostname := name.Hostname()
+	if hostname == DefaultV1Registry.Host {
+		hostname = IndexName
+	}
+	repoName := hostname + "/" + name.RemoteName()
+	var match string
+	for key := range config.Registries {
+		if len(key) > len(match) && strings.HasPrefix(repoName+"/", key+"/") && key != hostname {
+			match = key
+		}
+	}
+	if match != "" {
+		return config.Registries[match].Mirrors
+	}
+	if hostname == IndexName {
+		return config.Mirrors
+	}
+	if registry, ok := config.Registries[hostname]; ok {
+		return registry.Mirrors
+	}
+	return nil
+}
+
 // ValidateIndexName validates an index name.
 func ValidateIndexName(val string) (string, error) {
 	if val == reference.LegacyDefaultHostname {
@@ -1,7 +1,10 @@
 package registry
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/docker/docker/reference"
 )
 
 func TestValidateMirror(t *testing.T) {
@@ -47,3 +50,140 @@ func TestValidateMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRegistries(t *testing.T) {
+	valid := []map[string]RegistryOptions{
+		{"docker.io": {Mirrors: []string{"https://mirror-1.com"}}},
+		{"docker.io/library": {Mirrors: []string{"http://mirror-1.com"}}},
+		{"registry.example.com:5000": {CAFile: "/etc/ca.pem", Insecure: true}},
+		{"registry.example.com/team": {Mirrors: []string{"https://mirror-1.com"}}},
+	}
+	invalid := []map[string]RegistryOptions{
+		{"https://registry.example.com": {}},
+		{"/team": {}},
+		{"-registry.example.com": {}},
+		{"docker.io": {Mirrors: []string{"ftp://mirror-1.com"}}},
+		{"docker.io": {Insecure: true}},
+		{"registry.example.com/team": {CAFile: "/etc/ca.pem"}},
+		{"registry.example.com/team": {Insecure: true}},
+	}
+
+	for _, registries := range valid {
+		if err := ValidateRegistries(registries); err != nil {
+			t.Errorf("ValidateRegistries(%v) got %v", registries, err)
+		}
+	}
+	for _, registries := range invalid {
+		if err := ValidateRegistries(registries); err == nil {
+			t.Errorf("ValidateRegistries(%v) expected an error", registries)
+		}
+	}
+}
+
+func TestMirrorsFor(t *testing.T) {
+	config := newServiceConfig(ServiceOptions{
+		Mirrors: []string{"https://flag-mirror.com"},
+		Registries: map[string]RegistryOptions{
+			"index.docker.io":               {Mirrors: []string{"https://hub-mirror.com"}},
+			"docker.io/library":             {Mirrors: []string{"https://library-mirror.com"}},
+			"registry.example.com":          {Mirrors: []string{"https://example-mirror.com"}},
+			"registry.example.com/team":     {Mirrors: []string{"https://team-mirror.com"}},
+			"registry.example.com/team/app": {Mirrors: []string{"https://app-mirror.com"}},
+		},
+	})
+
+	tests := map[string][]string{
+		"ubuntu":                                {"https://library-mirror.com/"},
+		"docker.io/library/ubuntu":              {"https://library-mirror.com/"},
+		"user/app":                              {"https://flag-mirror.com", "https://hub-mirror.com/"},
+		"registry.example.com/other/app":        {"https://example-mirror.com/"},
+		"registry.example.com/team/web":         {"https://team-mirror.com/"},
+		"registry.example.com/team/app":         {"https://app-mirror.com/"},
+		"registry.example.com/team/application": {"https://team-mirror.com/"},
+		"registry.example.com/teammate/app":     {"https://example-mirror.com/"},
+		"other.example.com/team/app":            nil,
+	}
+	for name, expected := range tests {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mirrors := config.mirrorsFor(ref); !reflect.DeepEqual(mirrors, expected) {
+			t.Errorf("mirrorsFor(%s) got %v, expected %v", name, mirrors, expected)
+		}
+	}
+}
+
+func TestRegistriesInsecure(t *testing.T) {
+	config := newServiceConfig(ServiceOptions{
+		Registries: map[string]RegistryOptions{
+			"insecure.example.com": {Insecure: true},
+			"secure.example.com":   {Mirrors: []string{"https://mirror-1.com"}},
+		},
+	})
+
+	if isSecureIndex(config, "insecure.example.com") {
+		t.Error("insecure.example.com should be insecure")
+	}
+	if !isSecureIndex(config, "secure.example.com") {
+		t.Error("secure.example.com should be secure")
+	}
+}
+
+func TestReloadRegistries(t *testing.T) {
+	s := NewService(ServiceOptions{Mirrors: []string{"https://flag-mirror.com"}})
+	ref, err := reference.ParseNamed("ubuntu")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.ReloadRegistries(map[string]RegistryOptions{
+		"docker.io/library": {Mirrors: []string{"https://library-mirror.com"}},
+	})
+	endpoints, err := s.LookupPullEndpoints(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) == 0 || !endpoints[0].Mirror || endpoints[0].URL.Host != "library-mirror.com" {
+		t.Fatalf("expected library-mirror.com to be the first endpoint, got %v", endpoints)
+	}
+
+	s.ReloadRegistries(nil)
+	endpoints, err = s.LookupPullEndpoints(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) == 0 || !endpoints[0].Mirror || endpoints[0].URL.Host != "flag-mirror.com" {
+		t.Fatalf("expected the mirror of the flags to be kept, got %v", endpoints)
+	}
+}
+
+func TestReloadMirrors(t *testing.T) {
+	s := NewService(ServiceOptions{Mirrors: []string{"https://flag-mirror.com"}})
+	ref, err := reference.ParseNamed("ubuntu")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ReloadMirrors([]string{"ftp://invalid-mirror.com"}); err == nil {
+		t.Fatal("expected an error for an invalid mirror")
+	}
+	if err := s.ReloadMirrors([]string{"https://new-mirror.com"}); err != nil {
+		t.Fatal(err)
+	}
+	endpoints, err := s.LookupPullEndpoints(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) == 0 || !endpoints[0].Mirror || endpoints[0].URL.Host != "new-mirror.com" {
+		t.Fatalf("expected new-mirror.com to be the first endpoint, got %v", endpoints)
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.URL.Host == "flag-mirror.com" {
+			t.Fatalf("expected flag-mirror.com to be replaced, got %v", endpoints)
+		}
+	}
+	if mirrors := s.ServiceConfig().Mirrors; len(mirrors) != 1 || mirrors[0] != "https://new-mirror.com/" {
+		t.Fatalf("expected the mirrors of the service configuration to be reloaded, got %v", mirrors)
+	}
+}
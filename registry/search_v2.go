@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/engine-api/types"
+	registrytypes "github.com/docker/engine-api/types/registry"
+)
+
+// catalogPageSize is the number of repositories requested per page of the
+// catalog of a v2 registry.
+const catalogPageSize = 100
+
+// catalogScope is the token scope required to list the catalog of a v2
+// registry.
+type catalogScope struct{}
+
+func (catalogScope) String() string {
+	return "registry:catalog:*"
+}
+
+// searchV2 searches the catalog of the v2 registry at endpoint for the
+// repositories whose name contains term, and returns at most limit results.
+// The catalog lists neither descriptions nor stars, so the results only hold
+// the names of the repositories, prefixed with the registry host.
+func searchV2(ctx context.Context, endpoint APIEndpoint, term string, limit int, authConfig *types.AuthConfig, userAgent string, headers http.Header) (*registrytypes.SearchResults, error) {
+	if limit < 1 || limit > 100 {
+		return nil, fmt.Errorf("Limit %d is outside the range of [1, 100]", limit)
+	}
+
+	modifiers := DockerHeaders(userAgent, headers)
+	authTransport := transport.NewTransport(NewTransport(endpoint.TLSConfig), modifiers...)
+
+	challengeManager, _, err := PingV2Registry(endpoint, authTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialAuthConfig := *authConfig
+	creds := loginCredentialStore{authConfig: &credentialAuthConfig}
+	tokenHandler := auth.NewTokenHandlerWithOptions(auth.TokenHandlerOptions{
+		Transport:   authTransport,
+		Credentials: creds,
+		ClientID:    AuthClientID,
+		Scopes:      []auth.Scope{catalogScope{}},
+	})
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler))
+
+	reg, err := client.NewRegistry(ctx, strings.TrimRight(endpoint.URL.String(), "/"), transport.NewTransport(authTransport, modifiers...))
+	if err != nil {
+		return nil, err
+	}
+
+	term = strings.ToLower(term)
+	results := []registrytypes.SearchResult{}
+	entries := make([]string, catalogPageSize)
+	last := ""
+	for len(results) < limit {
+		n, err := reg.Repositories(ctx, entries, last)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		for _, name := range entries[:n] {
+			if strings.Contains(name, term) && len(results) < limit {
+				results = append(results, registrytypes.SearchResult{Name: endpoint.URL.Host + "/" + name})
+			}
+		}
+		if err == io.EOF || n == 0 {
+			break
+		}
+		last = entries[n-1]
+	}
+	logrus.Debugf("Found %d repositories matching %q in the catalog of %s", len(results), term, endpoint.URL)
+
+	return &registrytypes.SearchResults{
+		Query:      term,
+		NumResults: len(results),
+		Results:    results,
+	}, nil
+}
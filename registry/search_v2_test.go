@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/engine-api/types"
+)
+
+// newCatalogServer returns a v2 registry serving a paginated catalog of
+// repos.
+func newCatalogServer(t *testing.T, repos []string) *httptest.Server {
+	sort.Strings(repos)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(DefaultRegistryVersionHeader, "registry/2.0")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v2/_catalog", func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.URL.Query().Get("n"))
+		if err != nil {
+			t.Fatalf("Invalid page size %q", r.URL.Query().Get("n"))
+		}
+		last := r.URL.Query().Get("last")
+		start := sort.SearchStrings(repos, last)
+		if last != "" && start < len(repos) && repos[start] == last {
+			start++
+		}
+		end := start + n
+		if end >= len(repos) {
+			end = len(repos)
+		} else {
+			w.Header().Set("Link", `</v2/_catalog?last=`+repos[end-1]+`&n=`+strconv.Itoa(n)+`>; rel="next"`)
+		}
+		w.Header().Set(DefaultRegistryVersionHeader, "registry/2.0")
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": repos[start:end]})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestSearchV2(t *testing.T) {
+	var repos []string
+	for i := 0; i < 250; i++ {
+		repos = append(repos, "team/app"+strconv.Itoa(i))
+	}
+	repos = append(repos, "other/busybox", "team/busybox")
+
+	server := newCatalogServer(t, repos)
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := APIEndpoint{URL: u, Version: APIVersion2}
+
+	results, err := searchV2(context.Background(), endpoint, "BusyBox", 25, &types.AuthConfig{}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.NumResults != 2 || len(results.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", results.NumResults)
+	}
+	for i, name := range []string{"other/busybox", "team/busybox"} {
+		if expected := u.Host + "/" + name; results.Results[i].Name != expected {
+			t.Fatalf("Expected result %d to be %s, got %s", i, expected, results.Results[i].Name)
+		}
+	}
+
+	results, err = searchV2(context.Background(), endpoint, "team/", 30, &types.AuthConfig{}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.NumResults != 30 {
+		t.Fatalf("Expected the results to be limited to 30, got %d", results.NumResults)
+	}
+
+	if _, err := searchV2(context.Background(), endpoint, "team/", 101, &types.AuthConfig{}, "", nil); err == nil {
+		t.Fatal("Expected an error for a limit above 100")
+	}
+}
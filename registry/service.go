@@ -2,10 +2,13 @@ package registry
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"golang.org/x/net/context"
 
@@ -23,32 +26,72 @@ const (
 // Service is the interface defining what a registry service should implement.
 type Service interface {
 	Auth(ctx context.Context, authConfig *types.AuthConfig, userAgent string) (status, token string, err error)
-	LookupPullEndpoints(hostname string) (endpoints []APIEndpoint, err error)
+	LookupPullEndpoints(name reference.Named) (endpoints []APIEndpoint, err error)
 	LookupPushEndpoints(hostname string) (endpoints []APIEndpoint, err error)
 	ResolveRepository(name reference.Named) (*RepositoryInfo, error)
 	ResolveIndex(name string) (*registrytypes.IndexInfo, error)
 	Search(ctx context.Context, term string, limit int, authConfig *types.AuthConfig, userAgent string, headers map[string][]string) (*registrytypes.SearchResults, error)
 	ServiceConfig() *registrytypes.ServiceConfig
 	TLSConfig(hostname string) (*tls.Config, error)
+	ReloadRegistries(registries map[string]RegistryOptions)
+	ReloadMirrors(mirrors []string) error
 }
 
 // DefaultService is a registry service. It tracks configuration data such as a list
 // of mirrors.
 type DefaultService struct {
-	config *serviceConfig
+	mu      sync.RWMutex
+	options ServiceOptions
+	config  *serviceConfig
 }
 
 // NewService returns a new instance of DefaultService ready to be
 // installed into an engine.
 func NewService(options ServiceOptions) *DefaultService {
 	return &DefaultService{
-		config: newServiceConfig(options),
+		options: options,
+		config:  newServiceConfig(options),
 	}
 }
 
+// serviceConfig returns the current configuration of the service.
+func (s *DefaultService) serviceConfig() *serviceConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// ReloadRegistries replaces the configuration of the registries and of
+// their namespaces. The other options of the service are kept.
+func (s *DefaultService) ReloadRegistries(registries map[string]RegistryOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options.Registries = registries
+	s.config = newServiceConfig(s.options)
+}
+
+// ReloadMirrors replaces the mirrors of the official registry. The other
+// options of the service are kept.
+func (s *DefaultService) ReloadMirrors(mirrors []string) error {
+	validated := make([]string, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		m, err := ValidateMirror(mirror)
+		if err != nil {
+			return err
+		}
+		validated = append(validated, m)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options.Mirrors = validated
+	s.config = newServiceConfig(s.options)
+	return nil
+}
+
 // ServiceConfig returns the public registry service configuration.
 func (s *DefaultService) ServiceConfig() *registrytypes.ServiceConfig {
-	return &s.config.ServiceConfig
+	return &s.serviceConfig().ServiceConfig
 }
 
 // Auth contacts the public registry with the provided credentials,
@@ -112,20 +155,40 @@ func splitReposSearchTerm(reposName string) (string, string) {
 }
 
 // Search queries the public registry for images matching the specified
-// search terms, and returns the results.
+// search terms, and returns the results. The other registries are searched
+// through their v2 catalog, and then through the legacy search API unless
+// legacy registries are disabled.
 func (s *DefaultService) Search(ctx context.Context, term string, limit int, authConfig *types.AuthConfig, userAgent string, headers map[string][]string) (*registrytypes.SearchResults, error) {
-	// TODO Use ctx when searching for repositories
 	if err := validateNoScheme(term); err != nil {
 		return nil, err
 	}
 
 	indexName, remoteName := splitReposSearchTerm(term)
 
-	index, err := newIndexInfo(s.config, indexName)
+	index, err := newIndexInfo(s.serviceConfig(), indexName)
 	if err != nil {
 		return nil, err
 	}
 
+	if !index.Official {
+		endpoints, err := s.lookupV2Endpoints(index.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, endpoint := range endpoints {
+			var results *registrytypes.SearchResults
+			results, err = searchV2(ctx, endpoint, remoteName, limit, authConfig, userAgent, http.Header(headers))
+			if err == nil {
+				return results, nil
+			}
+			logrus.Debugf("Error searching the catalog of %s: %v", endpoint.URL, err)
+		}
+		if s.serviceConfig().V2Only {
+			return nil, err
+		}
+	}
+
+	// TODO Use ctx when searching for repositories
 	// *TODO: Search multiple indexes.
 	endpoint, err := NewV1Endpoint(index, userAgent, http.Header(headers))
 	if err != nil {
@@ -152,12 +215,12 @@ func (s *DefaultService) Search(ctx context.Context, term string, limit int, aut
 // ResolveRepository splits a repository name into its components
 // and configuration of the associated registry.
 func (s *DefaultService) ResolveRepository(name reference.Named) (*RepositoryInfo, error) {
-	return newRepositoryInfo(s.config, name)
+	return newRepositoryInfo(s.serviceConfig(), name)
 }
 
 // ResolveIndex takes indexName and returns index info
 func (s *DefaultService) ResolveIndex(name string) (*registrytypes.IndexInfo, error) {
-	return newIndexInfo(s.config, name)
+	return newIndexInfo(s.serviceConfig(), name)
 }
 
 // APIEndpoint represents a remote API endpoint
@@ -177,25 +240,44 @@ func (e APIEndpoint) ToV1Endpoint(userAgent string, metaHeaders http.Header) (*V
 
 // TLSConfig constructs a client TLS configuration based on server defaults
 func (s *DefaultService) TLSConfig(hostname string) (*tls.Config, error) {
-	return newTLSConfig(hostname, isSecureIndex(s.config, hostname))
+	config := s.serviceConfig()
+	isSecure := isSecureIndex(config, hostname)
+	tlsConfig, err := newTLSConfig(hostname, isSecure)
+	if err != nil {
+		return nil, err
+	}
+	if registry, ok := config.registryOptions(hostname); ok && isSecure && registry.CAFile != "" {
+		data, err := ioutil.ReadFile(registry.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificates of %s: %v", hostname, err)
+		}
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no CA certificate found in %s for %s", registry.CAFile, hostname)
+		}
+	}
+	return tlsConfig, nil
 }
 
 func (s *DefaultService) tlsConfigForMirror(mirrorURL *url.URL) (*tls.Config, error) {
 	return s.TLSConfig(mirrorURL.Host)
 }
 
-// LookupPullEndpoints creates a list of endpoints to try to pull from, in order of preference.
+// LookupPullEndpoints creates a list of endpoints to try to pull the repository name from,
+// in order of preference.
 // It gives preference to v2 endpoints over v1, mirrors over the actual
 // registry, and HTTPS over plain HTTP.
-func (s *DefaultService) LookupPullEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
-	return s.lookupEndpoints(hostname)
+func (s *DefaultService) LookupPullEndpoints(name reference.Named) (endpoints []APIEndpoint, err error) {
+	return s.lookupEndpoints(name.Hostname(), s.serviceConfig().mirrorsFor(name))
 }
 
 // LookupPushEndpoints creates a list of endpoints to try to push to, in order of preference.
 // It gives preference to v2 endpoints over v1, and HTTPS over plain HTTP.
 // Mirrors are not included.
 func (s *DefaultService) LookupPushEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
-	allEndpoints, err := s.lookupEndpoints(hostname)
+	allEndpoints, err := s.lookupEndpoints(hostname, nil)
 	if err == nil {
 		for _, endpoint := range allEndpoints {
 			if !endpoint.Mirror {
@@ -206,13 +288,13 @@ func (s *DefaultService) LookupPushEndpoints(hostname string) (endpoints []APIEn
 	return endpoints, err
 }
 
-func (s *DefaultService) lookupEndpoints(hostname string) (endpoints []APIEndpoint, err error) {
-	endpoints, err = s.lookupV2Endpoints(hostname)
+func (s *DefaultService) lookupEndpoints(hostname string, mirrors []string) (endpoints []APIEndpoint, err error) {
+	endpoints, err = s.lookupV2Endpoints(hostname, mirrors)
 	if err != nil {
 		return nil, err
 	}
 
-	if s.config.V2Only {
+	if s.serviceConfig().V2Only {
 		return endpoints, nil
 	}
 
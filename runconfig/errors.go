@@ -19,6 +19,8 @@ var (
 	ErrConflictNetworkAndDNS = fmt.Errorf("Conflicting options: dns and the network mode")
 	// ErrConflictNetworkHostname conflict between the hostname and the network mode
 	ErrConflictNetworkHostname = fmt.Errorf("Conflicting options: hostname and the network mode")
+	// ErrConflictNetworkDomainname conflict between the domainname and the network mode
+	ErrConflictNetworkDomainname = fmt.Errorf("Conflicting options: domainname and the network mode")
 	// ErrConflictHostNetworkAndLinks conflict between --net=host and links
 	ErrConflictHostNetworkAndLinks = fmt.Errorf("Conflicting options: host type networking can't be used with links. This would result in undefined behavior")
 	// ErrConflictContainerNetworkAndMac conflict between the mac address and the network mode
@@ -37,4 +39,8 @@ var (
 	ErrUnsupportedNetworkAndAlias = fmt.Errorf("Network-scoped alias is supported only for containers in user defined networks")
 	// ErrConflictUTSHostname conflict between the hostname and the UTS mode
 	ErrConflictUTSHostname = fmt.Errorf("Conflicting options: hostname and the UTS mode")
+	// ErrConflictUTSDomainname conflict between the domainname and the UTS mode
+	ErrConflictUTSDomainname = fmt.Errorf("Conflicting options: domainname and the UTS mode")
+	// ErrConflictContainerNetworkAndUTS conflict between the host UTS mode and the container network mode
+	ErrConflictContainerNetworkAndUTS = fmt.Errorf("Conflicting options: host UTS mode and the container type network mode")
 )
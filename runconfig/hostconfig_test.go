@@ -66,6 +66,8 @@ func TestIpcModeTest(t *testing.T) {
 		"something:weird":          {true, false, false, false},
 		":weird":                   {true, false, false, true},
 		"host":                     {false, true, false, true},
+		"private":                  {true, false, false, true},
+		"shareable":                {true, false, false, true},
 		"container:name":           {false, false, true, true},
 		"container:name:something": {false, false, true, false},
 		"container:":               {false, false, true, false},
@@ -220,3 +222,24 @@ func TestDecodeHostConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateNetModeHostnameAndUTS(t *testing.T) {
+	tests := []struct {
+		config     *container.Config
+		hostConfig *container.HostConfig
+		err        error
+	}{
+		{&container.Config{Hostname: "web", Domainname: "example.com"}, &container.HostConfig{NetworkMode: "bridge"}, nil},
+		{&container.Config{Hostname: "web"}, &container.HostConfig{NetworkMode: "container:db"}, ErrConflictNetworkHostname},
+		{&container.Config{Domainname: "example.com"}, &container.HostConfig{NetworkMode: "container:db"}, ErrConflictNetworkDomainname},
+		{&container.Config{}, &container.HostConfig{NetworkMode: "bridge", UTSMode: "host"}, nil},
+		{&container.Config{Hostname: "web"}, &container.HostConfig{NetworkMode: "bridge", UTSMode: "host"}, ErrConflictUTSHostname},
+		{&container.Config{Domainname: "example.com"}, &container.HostConfig{NetworkMode: "bridge", UTSMode: "host"}, ErrConflictUTSDomainname},
+		{&container.Config{}, &container.HostConfig{NetworkMode: "container:db", UTSMode: "host"}, ErrConflictContainerNetworkAndUTS},
+	}
+	for _, test := range tests {
+		if err := ValidateNetMode(test.config, test.hostConfig); err != test.err {
+			t.Fatalf("expected %v for %+v and %+v, got %v", test.err, test.config, test.hostConfig, err)
+		}
+	}
+}
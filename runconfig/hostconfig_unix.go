@@ -40,10 +40,22 @@ func ValidateNetMode(c *container.Config, hc *container.HostConfig) error {
 		return ErrConflictNetworkHostname
 	}
 
+	if hc.NetworkMode.IsContainer() && c.Domainname != "" {
+		return ErrConflictNetworkDomainname
+	}
+
 	if hc.UTSMode.IsHost() && c.Hostname != "" {
 		return ErrConflictUTSHostname
 	}
 
+	if hc.UTSMode.IsHost() && c.Domainname != "" {
+		return ErrConflictUTSDomainname
+	}
+
+	if hc.UTSMode.IsHost() && hc.NetworkMode.IsContainer() {
+		return ErrConflictContainerNetworkAndUTS
+	}
+
 	if hc.NetworkMode.IsHost() && len(hc.Links) > 0 {
 		return ErrConflictHostNetworkAndLinks
 	}
@@ -20,6 +20,11 @@ import (
 // As of #16585, it's up to application inside docker to validate or not
 // environment variables, that's why we just strip leading whitespace and
 // nothing more.
+//
+// The values are expanded: `$NAME` and `${NAME}` are replaced by the value
+// of the variable NAME set by a previous line of the file, or else in the
+// environment, `${NAME:-default}` by default if the value is unset or empty,
+// and `$$` by `$`.
 func ParseEnvFile(filename string) ([]string, error) {
 	fh, err := os.Open(filename)
 	if err != nil {
@@ -28,6 +33,7 @@ func ParseEnvFile(filename string) ([]string, error) {
 	defer fh.Close()
 
 	lines := []string{}
+	defined := make(map[string]string)
 	scanner := bufio.NewScanner(fh)
 	for scanner.Scan() {
 		// trim the line from all leading whitespace first
@@ -45,16 +51,42 @@ func ParseEnvFile(filename string) ([]string, error) {
 			if len(data) > 1 {
 
 				// pass the value through, no trimming
-				lines = append(lines, fmt.Sprintf("%s=%s", variable, data[1]))
+				value := expandEnvValue(data[1], defined)
+				defined[variable] = value
+				lines = append(lines, fmt.Sprintf("%s=%s", variable, value))
 			} else {
 				// if only a pass-through variable is given, clean it up.
-				lines = append(lines, fmt.Sprintf("%s=%s", strings.TrimSpace(line), os.Getenv(line)))
+				variable = strings.TrimSpace(line)
+				defined[variable] = os.Getenv(line)
+				lines = append(lines, fmt.Sprintf("%s=%s", variable, defined[variable]))
 			}
 		}
 	}
 	return lines, scanner.Err()
 }
 
+// expandEnvValue expands the references to the variables in value, looking
+// them up in defined, and then in the environment.
+func expandEnvValue(value string, defined map[string]string) string {
+	return os.Expand(value, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		var fallback string
+		if i := strings.Index(name, ":-"); i >= 0 {
+			name, fallback = name[:i], name[i+2:]
+		}
+		v, ok := defined[name]
+		if !ok {
+			v = os.Getenv(name)
+		}
+		if v == "" {
+			return fallback
+		}
+		return v
+	})
+}
+
 var whiteSpaces = " \t"
 
 // ErrBadEnvVariable typed error for bad environment variable
@@ -140,3 +140,39 @@ another invalid line`
 		t.Fatalf("Expected [%v], got [%v]", expectedMessage, err.Error())
 	}
 }
+
+// ParseEnvFile expands the references to variables in the values
+func TestParseEnvFileExpansion(t *testing.T) {
+	os.Setenv("ENVFILE_TEST_HOME", "/home/alice")
+	defer os.Unsetenv("ENVFILE_TEST_HOME")
+
+	content := `HOST=db.example.com
+URL=postgres://$HOST:${PORT:-5432}/app
+CONFIG=${ENVFILE_TEST_HOME}/.config
+PRICE=$$5
+UNSET=[$ENVFILE_TEST_UNSET]
+HOST=db2.example.com
+BACKUP=$HOST
+`
+	tmpFile := tmpFileWithContent(content, t)
+	defer os.Remove(tmpFile)
+
+	lines, err := ParseEnvFile(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLines := []string{
+		"HOST=db.example.com",
+		"URL=postgres://db.example.com:5432/app",
+		"CONFIG=/home/alice/.config",
+		"PRICE=$5",
+		"UNSET=[]",
+		"HOST=db2.example.com",
+		"BACKUP=db2.example.com",
+	}
+
+	if !reflect.DeepEqual(lines, expectedLines) {
+		t.Fatalf("expected %v, got %v", expectedLines, lines)
+	}
+}
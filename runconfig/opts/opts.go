@@ -5,6 +5,7 @@ import (
 	fopts "github.com/docker/docker/opts"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -46,20 +47,52 @@ func doesEnvExist(name string) bool {
 	return false
 }
 
+// HostGateway is the IP address of an extra host resolved by the daemon to
+// the gateway of its default bridge network, that is to the host.
+const HostGateway = "host-gateway"
+
 // ValidateExtraHost validates that the specified string is a valid extrahost and returns it.
-// ExtraHost is in the form of name:ip where the ip has to be a valid ip (ipv4 or ipv6).
+// ExtraHost is in the form of name:ip where the ip has to be a valid ip (ipv4 or ipv6),
+// or host-gateway.
 func ValidateExtraHost(val string) (string, error) {
 	// allow for IPv6 addresses in extra hosts by only splitting on first ":"
 	arr := strings.SplitN(val, ":", 2)
 	if len(arr) != 2 || len(arr[0]) == 0 {
 		return "", fmt.Errorf("bad format for add-host: %q", val)
 	}
+	if arr[1] == HostGateway {
+		return val, nil
+	}
 	if _, err := fopts.ValidateIPAddress(arr[1]); err != nil {
 		return "", fmt.Errorf("invalid IP address in add-host: %q", arr[1])
 	}
 	return val, nil
 }
 
+// dscpClasses are the DSCP values of the class selectors and of the
+// expedited forwarding class. The assured forwarding classes are computed.
+var dscpClasses = map[string]int{
+	"CS0": 0, "CS1": 8, "CS2": 16, "CS3": 24, "CS4": 32, "CS5": 40, "CS6": 48, "CS7": 56,
+	"EF": 46,
+}
+
+// ParseDSCP parses a DSCP value, from 0 to 63, or the name of a class like EF,
+// AF41 or CS3.
+func ParseDSCP(val string) (int, error) {
+	name := strings.ToUpper(strings.TrimSpace(val))
+	if v, ok := dscpClasses[name]; ok {
+		return v, nil
+	}
+	if len(name) == 4 && strings.HasPrefix(name, "AF") && name[2] >= '1' && name[2] <= '4' && name[3] >= '1' && name[3] <= '3' {
+		return int(name[2]-'0')*8 + int(name[3]-'0')*2, nil
+	}
+	v, err := strconv.Atoi(name)
+	if err != nil || v < 0 || v > 63 {
+		return 0, fmt.Errorf("invalid DSCP value %s: use a value from 0 to 63, or a class like EF, AF41 or CS3", val)
+	}
+	return v, nil
+}
+
 // ValidateMACAddress validates a MAC address.
 func ValidateMACAddress(val string) (string, error) {
 	_, err := net.ParseMAC(strings.TrimSpace(val))
@@ -67,6 +67,7 @@ func TestValidateExtraHosts(t *testing.T) {
 		`thathost:10.0.2.1`,
 		`anipv6host:2003:ab34:e::1`,
 		`ipv6local:::1`,
+		`host.docker.internal:host-gateway`,
 	}
 
 	invalid := map[string]string{
@@ -74,6 +75,7 @@ func TestValidateExtraHosts(t *testing.T) {
 		`thathost-nosemicolon10.0.0.1`: `bad format`,
 		`anipv6host:::::1`:             `invalid IP`,
 		`ipv6local:::0::`:              `invalid IP`,
+		`gateway:host-gateway6`:        `invalid IP`,
 	}
 
 	for _, extrahost := range valid {
@@ -93,6 +95,33 @@ func TestValidateExtraHosts(t *testing.T) {
 	}
 }
 
+func TestParseDSCP(t *testing.T) {
+	valid := map[string]int{
+		"0":    0,
+		"46":   46,
+		"63":   63,
+		"EF":   46,
+		"af41": 34,
+		"AF13": 14,
+		"CS3":  24,
+	}
+	for val, expected := range valid {
+		v, err := ParseDSCP(val)
+		if err != nil {
+			t.Fatalf("ParseDSCP(%q) should succeed: error %v", val, err)
+		}
+		if v != expected {
+			t.Fatalf("ParseDSCP(%q) should be %d, got %d", val, expected, v)
+		}
+	}
+
+	for _, val := range []string{"64", "-1", "AF51", "AF14", "CS8", "best-effort"} {
+		if _, err := ParseDSCP(val); err == nil || !strings.Contains(err.Error(), "invalid DSCP value") {
+			t.Fatalf("ParseDSCP(%q) should have failed validation, got %v", val, err)
+		}
+	}
+}
+
 func TestValidateMACAddress(t *testing.T) {
 	if _, err := ValidateMACAddress(`92:d0:c6:0a:29:33`); err != nil {
 		t.Fatalf("ValidateMACAddress(`92:d0:c6:0a:29:33`) got %s", err)
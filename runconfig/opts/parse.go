@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -27,10 +28,12 @@ type ContainerOptions struct {
 	flAttach            opts.ListOpts
 	flVolumes           opts.ListOpts
 	flTmpfs             opts.ListOpts
+	flSecrets           SecretOpt
 	flBlkioWeightDevice WeightdeviceOpt
 	flDeviceReadBps     ThrottledeviceOpt
 	flDeviceWriteBps    ThrottledeviceOpt
 	flLinks             opts.ListOpts
+	flRequires          opts.ListOpts
 	flAliases           opts.ListOpts
 	flLinkLocalIPs      opts.ListOpts
 	flDeviceReadIOps    ThrottledeviceOpt
@@ -38,8 +41,10 @@ type ContainerOptions struct {
 	flEnv               opts.ListOpts
 	flLabels            opts.ListOpts
 	flDevices           opts.ListOpts
+	flDeviceCgroupRules opts.ListOpts
 	flUlimits           *UlimitOpt
 	flSysctls           *opts.MapOpts
+	flNetworkOpts       *opts.MapOpts
 	flPublish           opts.ListOpts
 	flExpose            opts.ListOpts
 	flDNS               opts.ListOpts
@@ -103,6 +108,9 @@ type ContainerOptions struct {
 	flHealthTimeout     time.Duration
 	flHealthRetries     int
 	flRuntime           string
+	flCgroupDelegate    bool
+	flSystemd           string
+	flDSCP              string
 
 	Image string
 	Args  []string
@@ -124,6 +132,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 		flDeviceWriteBps:    NewThrottledeviceOpt(ValidateThrottleBpsDevice),
 		flDeviceWriteIOps:   NewThrottledeviceOpt(ValidateThrottleIOpsDevice),
 		flDevices:           opts.NewListOpts(ValidateDevice),
+		flDeviceCgroupRules: opts.NewListOpts(ValidateDeviceCgroupRule),
 		flEnv:               opts.NewListOpts(ValidateEnv),
 		flEnvFile:           opts.NewListOpts(nil),
 		flExpose:            opts.NewListOpts(nil),
@@ -133,11 +142,13 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 		flLabelsFile:        opts.NewListOpts(nil),
 		flLinkLocalIPs:      opts.NewListOpts(nil),
 		flLinks:             opts.NewListOpts(ValidateLink),
+		flRequires:          opts.NewListOpts(nil),
 		flLoggingOpts:       opts.NewListOpts(nil),
 		flPublish:           opts.NewListOpts(nil),
 		flSecurityOpt:       opts.NewListOpts(nil),
 		flStorageOpt:        opts.NewListOpts(nil),
 		flSysctls:           opts.NewMapOpts(nil, opts.ValidateSysctl),
+		flNetworkOpts:       opts.NewMapOpts(nil, nil),
 		flTmpfs:             opts.NewListOpts(nil),
 		flUlimits:           NewUlimitOpt(nil),
 		flVolumes:           opts.NewListOpts(nil),
@@ -147,6 +158,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	// General purpose flags
 	flags.VarP(&copts.flAttach, "attach", "a", "Attach to STDIN, STDOUT or STDERR")
 	flags.Var(&copts.flDevices, "device", "Add a host device to the container")
+	flags.Var(&copts.flDeviceCgroupRules, "device-cgroup-rule", "Add a rule to the cgroup allowed devices list")
 	flags.VarP(&copts.flEnv, "env", "e", "Set environment variables")
 	flags.Var(&copts.flEnvFile, "env-file", "Read in a file of environment variables")
 	flags.StringVar(&copts.flEntrypoint, "entrypoint", "", "Overwrite the default ENTRYPOINT of the image")
@@ -180,6 +192,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.StringVar(&copts.flIPv4Address, "ip", "", "Container IPv4 address (e.g. 172.30.100.104)")
 	flags.StringVar(&copts.flIPv6Address, "ip6", "", "Container IPv6 address (e.g. 2001:db8::33)")
 	flags.Var(&copts.flLinks, "link", "Add link to another container")
+	flags.Var(&copts.flRequires, "requires", "Require another container to be running to start")
 	flags.Var(&copts.flLinkLocalIPs, "link-local-ip", "Container IPv4/IPv6 link-local addresses")
 	flags.StringVar(&copts.flMacAddress, "mac-address", "", "Container MAC address (e.g. 92:d0:c6:0a:29:33)")
 	flags.VarP(&copts.flPublish, "publish", "p", "Publish a container's port(s) to the host")
@@ -192,6 +205,8 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Var(&copts.flAliases, "net-alias", "Add network-scoped alias for the container")
 	flags.Var(&copts.flAliases, "network-alias", "Add network-scoped alias for the container")
 	flags.MarkHidden("net-alias")
+	flags.Var(copts.flNetworkOpts, "network-opt", "Set network driver options for the container endpoint")
+	flags.StringVar(&copts.flDSCP, "dscp", "", "Set the DSCP mark of the egress traffic of the container (0 to 63, or a class like EF or AF41)")
 
 	// Logging and storage
 	flags.StringVar(&copts.flLoggingDriver, "log-driver", "", "Logging driver for container")
@@ -199,6 +214,7 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Var(&copts.flLoggingOpts, "log-opt", "Log driver options")
 	flags.Var(&copts.flStorageOpt, "storage-opt", "Set storage driver options per container")
 	flags.Var(&copts.flTmpfs, "tmpfs", "Mount a tmpfs directory")
+	flags.Var(&copts.flSecrets, "secret", "Mount a secret into the container")
 	flags.Var(&copts.flVolumesFrom, "volumes-from", "Mount volumes from the specified container(s)")
 	flags.VarP(&copts.flVolumes, "volume", "v", "Bind mount a volume")
 
@@ -235,11 +251,13 @@ func AddFlags(flags *pflag.FlagSet) *ContainerOptions {
 	flags.Int64Var(&copts.flPidsLimit, "pids-limit", 0, "Tune container pids limit (set -1 for unlimited)")
 
 	// Low-level execution (cgroups, namespaces, ...)
+	flags.BoolVar(&copts.flCgroupDelegate, "cgroup-delegate", false, "Delegate the cgroup of the container to the container")
 	flags.StringVar(&copts.flCgroupParent, "cgroup-parent", "", "Optional parent cgroup for the container")
 	flags.StringVar(&copts.flIpcMode, "ipc", "", "IPC namespace to use")
 	flags.StringVar(&copts.flIsolation, "isolation", "", "Container isolation technology")
 	flags.StringVar(&copts.flPidMode, "pid", "", "PID namespace to use")
 	flags.StringVar(&copts.flShmSize, "shm-size", "", "Size of /dev/shm, default value is 64MB")
+	flags.StringVar(&copts.flSystemd, "systemd", "false", "Set up the container to run systemd: true if its command is an init system, always, or false")
 	flags.StringVar(&copts.flUTSMode, "uts", "", "UTS namespace to use")
 	flags.StringVar(&copts.flRuntime, "runtime", "", "Runtime to use for this container")
 	return copts
@@ -439,11 +457,25 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		return nil, nil, nil, fmt.Errorf("--userns: invalid USER mode")
 	}
 
+	systemdMode := container.SystemdMode(copts.flSystemd)
+	if !systemdMode.Valid() {
+		return nil, nil, nil, fmt.Errorf("--systemd: invalid systemd mode, use true, false or always")
+	}
+
 	restartPolicy, err := ParseRestartPolicy(copts.flRestartPolicy)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	var dscp *int
+	if copts.flDSCP != "" {
+		v, err := ParseDSCP(copts.flDSCP)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("--dscp: %v", err)
+		}
+		dscp = &v
+	}
+
 	loggingOpts, err := parseLoggingOpts(copts.flLoggingDriver, copts.flLoggingOpts.GetAll())
 	if err != nil {
 		return nil, nil, nil, err
@@ -517,6 +549,7 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		IOMaximumBandwidth:   uint64(maxIOBandwidth),
 		Ulimits:              copts.flUlimits.GetList(),
 		Devices:              deviceMappings,
+		DeviceCgroupRules:    copts.flDeviceCgroupRules.GetAll(),
 	}
 
 	config := &container.Config{
@@ -553,6 +586,7 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		Privileged:      copts.flPrivileged,
 		PortBindings:    portBindings,
 		Links:           copts.flLinks.GetAll(),
+		Requires:        copts.flRequires.GetAll(),
 		PublishAllPorts: copts.flPublishAll,
 		// Make sure the dns fields are never nil.
 		// New containers don't ever have those fields nil,
@@ -582,8 +616,12 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		ShmSize:        shmSize,
 		Resources:      resources,
 		Tmpfs:          tmpfs,
+		Secrets:        copts.flSecrets.Value(),
 		Sysctls:        copts.flSysctls.GetAll(),
 		Runtime:        copts.flRuntime,
+		CgroupDelegate: copts.flCgroupDelegate,
+		Systemd:        systemdMode,
+		DSCP:           dscp,
 	}
 
 	// When allocating stdin in attached mode, close stdin at client disconnect
@@ -630,6 +668,18 @@ func Parse(flags *pflag.FlagSet, copts *ContainerOptions) (*container.Config, *c
 		networkingConfig.EndpointsConfig[string(hostConfig.NetworkMode)] = epConfig
 	}
 
+	if networkOpts := copts.flNetworkOpts.GetAll(); len(networkOpts) > 0 {
+		if hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsNone() || hostConfig.NetworkMode.IsContainer() {
+			return nil, nil, nil, fmt.Errorf("--network-opt: network driver options are not supported with the network %s", hostConfig.NetworkMode)
+		}
+		epConfig := networkingConfig.EndpointsConfig[string(hostConfig.NetworkMode)]
+		if epConfig == nil {
+			epConfig = &networktypes.EndpointSettings{}
+		}
+		epConfig.DriverOpts = networkOpts
+		networkingConfig.EndpointsConfig[string(hostConfig.NetworkMode)] = epConfig
+	}
+
 	return config, hostConfig, networkingConfig, nil
 }
 
@@ -839,6 +889,20 @@ func ValidDeviceMode(mode string) bool {
 	return true
 }
 
+// deviceCgroupRuleRegexp matches the rules of the devices cgroup, like
+// "c 189:* rmw": the type of the devices, a, b or c, their major and minor
+// numbers or *, and the access.
+var deviceCgroupRuleRegexp = regexp.MustCompile(`^[abc] (\d+|\*):(\d+|\*) [rwm]{1,3}$`)
+
+// ValidateDeviceCgroupRule validates a rule of the devices cgroup, like
+// "c 189:* rmw".
+func ValidateDeviceCgroupRule(val string) (string, error) {
+	if !deviceCgroupRuleRegexp.MatchString(val) || !ValidDeviceMode(strings.Fields(val)[2]) {
+		return val, fmt.Errorf("invalid device cgroup rule %q, use the format \"<type> <major>:<minor> <access>\", like \"c 189:* rmw\"", val)
+	}
+	return val, nil
+}
+
 // ValidateDevice validates a path for devices
 // It will make sure 'val' is in the form:
 //    [host-dir:]container-path[:mode]
@@ -54,6 +54,36 @@ func TestParseRunLinks(t *testing.T) {
 	}
 }
 
+func TestParseRunRequires(t *testing.T) {
+	if _, hostConfig := mustParse(t, "--requires db --requires cache"); len(hostConfig.Requires) != 2 || hostConfig.Requires[0] != "db" || hostConfig.Requires[1] != "cache" {
+		t.Fatalf("Error parsing requires. Expected []string{\"db\", \"cache\"}, received: %v", hostConfig.Requires)
+	}
+	if _, hostConfig := mustParse(t, ""); len(hostConfig.Requires) != 0 {
+		t.Fatalf("Error parsing requires. No requirement expected, received: %v", hostConfig.Requires)
+	}
+}
+
+func TestParseSystemd(t *testing.T) {
+	if _, hostConfig := mustParse(t, "--systemd=always"); !hostConfig.Systemd.IsAlways() {
+		t.Fatalf("Expected systemd to be always run, got %q", hostConfig.Systemd)
+	}
+	if _, hostConfig := mustParse(t, ""); hostConfig.Systemd.IsAlways() || hostConfig.Systemd.IsDetected() {
+		t.Fatalf("Expected systemd not to be run by default, got %q", hostConfig.Systemd)
+	}
+	if _, _, err := parse(t, "--systemd=sometimes"); err == nil || !strings.Contains(err.Error(), "invalid systemd mode") {
+		t.Fatalf("Expected an invalid systemd mode error, got %v", err)
+	}
+}
+
+func TestParseCgroupDelegate(t *testing.T) {
+	if _, hostConfig := mustParse(t, "--cgroup-delegate"); !hostConfig.CgroupDelegate {
+		t.Fatal("Expected the cgroup to be delegated")
+	}
+	if _, hostConfig := mustParse(t, ""); hostConfig.CgroupDelegate {
+		t.Fatal("Expected the cgroup not to be delegated by default")
+	}
+}
+
 func TestParseRunAttach(t *testing.T) {
 	if config, _ := mustParse(t, "-a stdin"); !config.AttachStdin || config.AttachStdout || config.AttachStderr {
 		t.Fatalf("Error parsing attach flags. Expect only Stdin enabled. Received: in: %v, out: %v, err: %v", config.AttachStdin, config.AttachStdout, config.AttachStderr)
@@ -363,6 +393,32 @@ func TestParseWithMacAddress(t *testing.T) {
 	}
 }
 
+func TestParseWithNetworkOpts(t *testing.T) {
+	_, _, networkingConfig, err := parseRun([]string{"--network=mynet", "--network-opt=foo=bar", "--network-opt=baz=qux", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	epConfig := networkingConfig.EndpointsConfig["mynet"]
+	if epConfig == nil || len(epConfig.DriverOpts) != 2 || epConfig.DriverOpts["foo"] != "bar" || epConfig.DriverOpts["baz"] != "qux" {
+		t.Fatalf("Expected the driver options foo=bar and baz=qux for mynet, got %v", epConfig)
+	}
+	if _, _, _, err := parseRun([]string{"--network=host", "--network-opt=foo=bar", "img", "cmd"}); err == nil || !strings.Contains(err.Error(), "not supported with the network host") {
+		t.Fatalf("Expected an error with the host network, got %v", err)
+	}
+}
+
+func TestParseWithDSCP(t *testing.T) {
+	if _, hostconfig := mustParse(t, "--dscp=EF"); hostconfig.DSCP == nil || *hostconfig.DSCP != 46 {
+		t.Fatalf("Expected the config to have 46 as DSCP, got %v", hostconfig.DSCP)
+	}
+	if _, hostconfig := mustParse(t, ""); hostconfig.DSCP != nil {
+		t.Fatalf("Expected the config to have no DSCP, got %v", *hostconfig.DSCP)
+	}
+	if _, _, _, err := parseRun([]string{"--dscp=64", "img", "cmd"}); err == nil || !strings.Contains(err.Error(), "--dscp: invalid DSCP value") {
+		t.Fatalf("Expected an error with the DSCP 64, got %v", err)
+	}
+}
+
 func TestParseWithMemory(t *testing.T) {
 	invalidMemory := "--memory=invalid"
 	validMemory := "--memory=1G"
@@ -766,6 +822,19 @@ func TestParseLink(t *testing.T) {
 	}
 }
 
+func TestValidateDeviceCgroupRule(t *testing.T) {
+	for _, rule := range []string{"c 189:* rmw", "b 8:0 r", "a *:* rwm", "c 1:3 mr"} {
+		if _, err := ValidateDeviceCgroupRule(rule); err != nil {
+			t.Fatalf("ValidateDeviceCgroupRule(%q) should succeed, got %v", rule, err)
+		}
+	}
+	for _, rule := range []string{"", "c 189 rmw", "d 1:3 rwm", "c 1:3", "c 1:3 rwx", "c 1:3 rr", "c x:3 r", " c 1:3 r"} {
+		if _, err := ValidateDeviceCgroupRule(rule); err == nil {
+			t.Fatalf("ValidateDeviceCgroupRule(%q) should fail", rule)
+		}
+	}
+}
+
 func TestValidateDevice(t *testing.T) {
 	valid := []string{
 		"/home",
@@ -0,0 +1,86 @@
+package opts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/engine-api/types/container"
+)
+
+// SecretOpt defines a list of references to the secrets of a container,
+// given as `<name>` or `source=<name>,target=<path>,uid=<uid>,gid=<gid>,mode=<mode>`.
+type SecretOpt struct {
+	values []container.SecretReference
+}
+
+// Set parses a secret reference and appends it to SecretOpt
+func (o *SecretOpt) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+
+	ref := container.SecretReference{}
+	if len(fields) == 1 && !strings.Contains(fields[0], "=") {
+		ref.Name = fields[0]
+	} else {
+		for _, field := range fields {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+			}
+			key, value := parts[0], parts[1]
+			switch strings.ToLower(key) {
+			case "source", "src", "name":
+				ref.Name = value
+			case "target":
+				ref.Target = value
+			case "uid":
+				if ref.UID, err = strconv.Atoi(value); err != nil || ref.UID < 0 {
+					return fmt.Errorf("invalid uid '%s' of secret", value)
+				}
+			case "gid":
+				if ref.GID, err = strconv.Atoi(value); err != nil || ref.GID < 0 {
+					return fmt.Errorf("invalid gid '%s' of secret", value)
+				}
+			case "mode":
+				mode, err := strconv.ParseUint(value, 8, 32)
+				if err != nil || mode&^0777 != 0 {
+					return fmt.Errorf("invalid mode '%s' of secret: it must be an octal file mode", value)
+				}
+				ref.Mode = os.FileMode(mode)
+			default:
+				return fmt.Errorf("unexpected key '%s' in '%s'", key, field)
+			}
+		}
+	}
+
+	if ref.Name == "" {
+		return fmt.Errorf("the source of the secret is required")
+	}
+	o.values = append(o.values, ref)
+	return nil
+}
+
+// Type returns the option type
+func (o *SecretOpt) Type() string {
+	return "secret"
+}
+
+// String returns the names of the secrets as a string.
+func (o *SecretOpt) String() string {
+	var names []string
+	for _, ref := range o.values {
+		names = append(names, ref.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Value returns the references to the secrets
+func (o *SecretOpt) Value() []container.SecretReference {
+	return o.values
+}
@@ -0,0 +1,50 @@
+package opts
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSecretOptSet(t *testing.T) {
+	var opt SecretOpt
+	for _, value := range []string{
+		"db-password",
+		"source=tls-key,target=/etc/ssl/key.pem,uid=100,gid=101,mode=0400",
+	} {
+		if err := opt.Set(value); err != nil {
+			t.Fatalf("expected %q to be valid: %v", value, err)
+		}
+	}
+
+	refs := opt.Value()
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 secrets, got %v", refs)
+	}
+	if refs[0].Name != "db-password" || refs[0].Target != "" || refs[0].Mode != 0 {
+		t.Fatalf("unexpected reference %+v", refs[0])
+	}
+	if refs[1].Name != "tls-key" || refs[1].Target != "/etc/ssl/key.pem" || refs[1].UID != 100 || refs[1].GID != 101 || refs[1].Mode != os.FileMode(0400) {
+		t.Fatalf("unexpected reference %+v", refs[1])
+	}
+	if opt.String() != "db-password, tls-key" {
+		t.Fatalf("unexpected string %q", opt.String())
+	}
+}
+
+func TestSecretOptSetInvalid(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"target=/etc/key",
+		"source=key,size=1",
+		"source=key,uid=-1",
+		"source=key,gid=root",
+		"source=key,mode=644x",
+		"source=key,mode=4755",
+		"source=key,target",
+	} {
+		var opt SecretOpt
+		if err := opt.Set(value); err == nil {
+			t.Fatalf("expected %q to be invalid", value)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package runconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+	"github.com/docker/engine-api/types/versions"
+)
+
+// ConfigTemplate is the configuration of a container, without what is
+// specific to it, such as its name and its addresses, to create other
+// containers with the same configuration.
+type ConfigTemplate struct {
+	// APIVersion is the version of the API the configuration is expressed in.
+	APIVersion       string `json:"ApiVersion"`
+	Config           *container.Config
+	HostConfig       *container.HostConfig
+	NetworkingConfig *networktypes.NetworkingConfig `json:",omitempty"`
+}
+
+// NewConfigTemplate returns the template of the configuration of an
+// inspected container, expressed in the API version apiVersion.
+func NewConfigTemplate(c types.ContainerJSON, apiVersion string) *ConfigTemplate {
+	t := &ConfigTemplate{
+		APIVersion: apiVersion,
+		Config:     c.Config,
+		HostConfig: c.HostConfig,
+	}
+	if t.Config != nil {
+		config := *t.Config
+		if config.Hostname == stringid.TruncateID(c.ID) {
+			config.Hostname = ""
+		}
+		config.MacAddress = ""
+		t.Config = &config
+	}
+	if t.HostConfig != nil {
+		hostConfig := *t.HostConfig
+		hostConfig.ContainerIDFile = ""
+		t.HostConfig = &hostConfig
+	}
+
+	// Only the endpoint on the network the container is created on can be
+	// configured at creation. Its addresses and the alias of the short ID
+	// of the container are not kept.
+	if t.HostConfig != nil && t.HostConfig.NetworkMode.IsUserDefined() && c.NetworkSettings != nil {
+		n := t.HostConfig.NetworkMode.NetworkName()
+		if settings, ok := c.NetworkSettings.Networks[n]; ok {
+			endpoint := &networktypes.EndpointSettings{IPAMConfig: settings.IPAMConfig, Links: settings.Links}
+			for _, alias := range settings.Aliases {
+				if alias != stringid.TruncateID(c.ID) {
+					endpoint.Aliases = append(endpoint.Aliases, alias)
+				}
+			}
+			t.NetworkingConfig = &networktypes.NetworkingConfig{
+				EndpointsConfig: map[string]*networktypes.EndpointSettings{n: endpoint},
+			}
+		}
+	}
+	return t
+}
+
+// DecodeConfigTemplate decodes a template of the configuration of a
+// container and validates it against the API version apiVersion: the
+// template must not be expressed in a newer version, nor have fields
+// unknown to this version.
+func DecodeConfigTemplate(src io.Reader, apiVersion string) (*ConfigTemplate, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(src).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid configuration template: %v", err)
+	}
+	var t ConfigTemplate
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("invalid configuration template: %v", err)
+	}
+
+	if t.APIVersion == "" {
+		return nil, fmt.Errorf("invalid configuration template: no ApiVersion")
+	}
+	if versions.GreaterThan(t.APIVersion, apiVersion) {
+		return nil, fmt.Errorf("the configuration template is for API version %s, newer than the API version %s in use", t.APIVersion, apiVersion)
+	}
+	if unknown := unknownFields(raw, reflect.TypeOf(t), ""); len(unknown) > 0 {
+		return nil, fmt.Errorf("invalid configuration template: unknown fields %s for API version %s", strings.Join(unknown, ", "), apiVersion)
+	}
+	if t.Config == nil || t.Config.Image == "" {
+		return nil, fmt.Errorf("invalid configuration template: no image")
+	}
+	if t.HostConfig == nil {
+		t.HostConfig = &container.HostConfig{}
+	}
+	return &t, nil
+}
+
+// unknownFields returns the paths of the fields of the JSON object raw that
+// do not match a field of the struct type t. Like encoding/json, the names
+// are matched case-insensitively.
+func unknownFields(raw json.RawMessage, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		// Not an object, such as null.
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type)
+	jsonFields(t, fields)
+
+	var unknown []string
+	for name, value := range object {
+		ft, ok := fields[strings.ToLower(name)]
+		if !ok {
+			unknown = append(unknown, prefix+name)
+			continue
+		}
+		unknown = append(unknown, unknownFields(value, ft, prefix+name+".")...)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// jsonFields adds the lower-cased JSON names of the fields of the struct
+// type t, including those of its embedded structs, to fields.
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && name == f.Name {
+			jsonFields(f.Type, fields)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+}
@@ -0,0 +1,78 @@
+package runconfig
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+	"github.com/docker/engine-api/types/container"
+	networktypes "github.com/docker/engine-api/types/network"
+)
+
+func TestConfigTemplateRoundTrip(t *testing.T) {
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID: "4fa6e0f0c6786287e131c3852c58a2e01cc697a68231826813597e4994f1d6e2",
+			HostConfig: &container.HostConfig{
+				NetworkMode:     "backend",
+				ContainerIDFile: "/tmp/web.cid",
+				SecurityOpt:     []string{"no-new-privileges"},
+				Resources:       container.Resources{Memory: 1 << 29},
+			},
+		},
+		Config: &container.Config{
+			Hostname:   "4fa6e0f0c678",
+			MacAddress: "02:42:ac:11:00:02",
+			Image:      "nginx",
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*networktypes.EndpointSettings{
+				"backend": {IPAddress: "10.0.0.2", Aliases: []string{"web", "4fa6e0f0c678"}},
+			},
+		},
+	}
+
+	b, err := json.Marshal(NewConfigTemplate(c, "1.25"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DecodeConfigTemplate(strings.NewReader(string(b)), "1.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Config.Hostname != "" || tmpl.Config.MacAddress != "" || tmpl.HostConfig.ContainerIDFile != "" {
+		t.Fatalf("expected the template not to keep what is specific to the container, got %+v, %+v", tmpl.Config, tmpl.HostConfig)
+	}
+	if tmpl.Config.Image != "nginx" || tmpl.HostConfig.Memory != 1<<29 || len(tmpl.HostConfig.SecurityOpt) != 1 {
+		t.Fatalf("unexpected template %+v, %+v", tmpl.Config, tmpl.HostConfig)
+	}
+	endpoint := tmpl.NetworkingConfig.EndpointsConfig["backend"]
+	if endpoint == nil || endpoint.IPAddress != "" || len(endpoint.Aliases) != 1 || endpoint.Aliases[0] != "web" {
+		t.Fatalf("unexpected endpoint %+v", endpoint)
+	}
+	if c.Config.Hostname != "4fa6e0f0c678" || c.HostConfig.ContainerIDFile != "/tmp/web.cid" {
+		t.Fatal("expected the inspected container to be unchanged")
+	}
+}
+
+func TestDecodeConfigTemplateInvalid(t *testing.T) {
+	for template, expected := range map[string]string{
+		`{"Config": {"Image": "nginx"}}`:                                                 "no ApiVersion",
+		`{"ApiVersion": "1.26", "Config": {"Image": "nginx"}}`:                           "newer than the API version 1.25",
+		`{"ApiVersion": "1.24"}`:                                                         "no image",
+		`{"ApiVersion": "1.24", "Config": {"Image": "nginx", "Imag": "x"}}`:              "unknown fields Config.Imag",
+		`{"ApiVersion": "1.24", "Config": {"Image": "nginx"}, "HostConfig": {"Mem": 1}}`: "unknown fields HostConfig.Mem",
+		`{"ApiVersion": "1.24", "Config": {"Image": 1}}`:                                 "invalid configuration template",
+	} {
+		_, err := DecodeConfigTemplate(strings.NewReader(template), "1.25")
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected an error containing %q for %s, got %v", expected, template, err)
+		}
+	}
+
+	// The fields of the embedded resources, in any case, are known.
+	if _, err := DecodeConfigTemplate(strings.NewReader(`{"ApiVersion": "1.24", "Config": {"image": "nginx"}, "HostConfig": {"memory": 1, "CpuShares": 2}}`), "1.25"); err != nil {
+		t.Fatal(err)
+	}
+}
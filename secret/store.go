@@ -0,0 +1,174 @@
+// Package secret stores the secrets of the daemon, which are delivered to
+// the containers at runtime, in a tmpfs, and never written into their layers.
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/utils"
+)
+
+// MaxSize is the maximum size of the data of a secret.
+const MaxSize = 500 * 1024
+
+var (
+	// ErrDoesNotExist is returned if a secret is not found in the store.
+	ErrDoesNotExist = errors.New("secret does not exist")
+	// ErrNameConflict is returned if a secret of the same name exists.
+	ErrNameConflict = errors.New("a secret of the same name exists")
+)
+
+// Secret is a secret, with its data.
+type Secret struct {
+	ID        string
+	Name      string
+	Labels    map[string]string
+	CreatedAt time.Time
+	Data      []byte
+}
+
+// Store stores the secrets in a directory only readable by root, one file
+// per secret.
+type Store struct {
+	mu      sync.RWMutex
+	root    string
+	secrets map[string]*Secret // by ID
+}
+
+// NewStore creates a Store of the secrets in root, loading those already
+// there.
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	s := &Store{root: root, secrets: make(map[string]*Secret)}
+
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(root, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var secret Secret
+		if err := json.Unmarshal(data, &secret); err != nil {
+			return nil, fmt.Errorf("invalid secret %s: %v", f.Name(), err)
+		}
+		s.secrets[secret.ID] = &secret
+	}
+	return s, nil
+}
+
+// Create adds a secret. Its name must be unique.
+func (s *Store) Create(name string, data []byte, labels map[string]string) (*Secret, error) {
+	if !utils.RestrictedVolumeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid secret name %q: only %s are allowed", name, utils.RestrictedNameChars)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("secret %s has no data", name)
+	}
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("secret %s is too large: its data must be at most %d bytes", name, MaxSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.getLocked(name) != nil {
+		return nil, ErrNameConflict
+	}
+	secret := &Secret{
+		ID:        stringid.GenerateRandomID(),
+		Name:      name,
+		Labels:    labels,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	b, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutils.AtomicWriteFile(filepath.Join(s.root, secret.ID), b, 0600); err != nil {
+		return nil, err
+	}
+	s.secrets[secret.ID] = secret
+	return secret, nil
+}
+
+// Get returns a secret by name, ID or unique prefix of its ID.
+func (s *Store) Get(nameOrID string) (*Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if secret := s.getLocked(nameOrID); secret != nil {
+		return secret, nil
+	}
+	return nil, ErrDoesNotExist
+}
+
+func (s *Store) getLocked(nameOrID string) *Secret {
+	if secret, ok := s.secrets[nameOrID]; ok {
+		return secret
+	}
+	var match *Secret
+	for _, secret := range s.secrets {
+		if secret.Name == nameOrID {
+			return secret
+		}
+		if strings.HasPrefix(secret.ID, nameOrID) {
+			if match != nil {
+				// An ambiguous prefix matches no secret.
+				return nil
+			}
+			match = secret
+		}
+	}
+	return match
+}
+
+// List returns the secrets, sorted by name.
+func (s *Store) List() []*Secret {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	secrets := make([]*Secret, 0, len(s.secrets))
+	for _, secret := range s.secrets {
+		secrets = append(secrets, secret)
+	}
+	sort.Sort(byName(secrets))
+	return secrets
+}
+
+// Remove removes a secret by name, ID or unique prefix of its ID.
+func (s *Store) Remove(nameOrID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret := s.getLocked(nameOrID)
+	if secret == nil {
+		return ErrDoesNotExist
+	}
+	if err := os.Remove(filepath.Join(s.root, secret.ID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(s.secrets, secret.ID)
+	return nil
+}
+
+type byName []*Secret
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
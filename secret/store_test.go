@@ -0,0 +1,107 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestStoreCreateAndReload(t *testing.T) {
+	root, err := ioutil.TempDir("", "secret-store-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.Create("db-password", []byte("s3cr3t"), map[string]string{"app": "db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("db-password", []byte("other"), nil); err != ErrNameConflict {
+		t.Fatalf("expected %v, got %v", ErrNameConflict, err)
+	}
+
+	fi, err := os.Stat(root + "/" + created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("expected the secret to be only readable by root, got %v", fi.Mode())
+	}
+
+	reloaded, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []string{"db-password", created.ID, created.ID[:12]} {
+		secret, err := reloaded.Get(ref)
+		if err != nil {
+			t.Fatalf("expected secret %s to be found: %v", ref, err)
+		}
+		if string(secret.Data) != "s3cr3t" || secret.Labels["app"] != "db" {
+			t.Fatalf("unexpected secret %+v", secret)
+		}
+	}
+}
+
+func TestStoreCreateInvalid(t *testing.T) {
+	root, err := ioutil.TempDir("", "secret-store-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("../escape", []byte("data"), nil); err == nil {
+		t.Fatal("expected an error for an invalid name")
+	}
+	if _, err := s.Create("empty", nil, nil); err == nil {
+		t.Fatal("expected an error for a secret without data")
+	}
+	if _, err := s.Create("large", make([]byte, MaxSize+1), nil); err == nil {
+		t.Fatal("expected an error for a secret too large")
+	}
+}
+
+func TestStoreListAndRemove(t *testing.T) {
+	root, err := ioutil.TempDir("", "secret-store-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ssh-key", "api-token", "tls-key"} {
+		if _, err := s.Create(name, []byte(name), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list := s.List()
+	if len(list) != 3 || list[0].Name != "api-token" || list[2].Name != "tls-key" {
+		t.Fatalf("expected the secrets sorted by name, got %v", list)
+	}
+
+	if err := s.Remove("ssh-key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("ssh-key"); err != ErrDoesNotExist {
+		t.Fatalf("expected %v, got %v", ErrDoesNotExist, err)
+	}
+	reloaded, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.List()) != 2 {
+		t.Fatalf("expected the removed secret not to be reloaded, got %v", reloaded.List())
+	}
+}
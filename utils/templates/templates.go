@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"text/template"
+
+	"github.com/docker/docker/pkg/stringutils"
 )
 
 // basicFunctions are the set of initial
@@ -18,6 +20,11 @@ var basicFunctions = template.FuncMap{
 	"title": strings.Title,
 	"lower": strings.ToLower,
 	"upper": strings.ToUpper,
+	// truncate keeps the first n characters of a string,
+	// like in `{{truncate .ID 12}}`.
+	"truncate": func(s string, n int) string {
+		return stringutils.Truncate(s, n)
+	},
 }
 
 // Parse creates a new annonymous template with the basic functions
@@ -36,3 +36,19 @@ func TestNewParse(t *testing.T) {
 		t.Fatalf("expected %s, got %s", want, b.String())
 	}
 }
+
+func TestParseTruncateFunction(t *testing.T) {
+	tm, err := Parse(`{{truncate . 5}}|{{truncate . 20}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := tm.Execute(&b, "abcdefghij"); err != nil {
+		t.Fatal(err)
+	}
+	want := "abcde|abcdefghij"
+	if b.String() != want {
+		t.Fatalf("expected %s, got %s", want, b.String())
+	}
+}